@@ -0,0 +1,258 @@
+package main
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+	"strings"
+	"time"
+
+	"feed-bower-api/internal/repository"
+	"feed-bower-api/internal/service"
+	dynamodbpkg "feed-bower-api/pkg/dynamodb"
+)
+
+// Scheduled job names
+const (
+	jobFetchFeeds       = "fetch_feeds"
+	jobCleanupOrphans   = "cleanup_orphans"
+	jobPurgeOldArticles = "purge_old_articles"
+	jobExpireGuests     = "expire_guests"
+	jobMigrateFeeds     = "migrate_feeds"
+	jobReindexSearch    = "reindex_search"
+)
+
+// JobEvent is an EventBridge payload that runs a job, for example
+// {"job": "purge_old_articles", "dry_run": true, "params": {"max_age_days": 90}}
+type JobEvent struct {
+	Job    string          `json:"job"`
+	DryRun bool            `json:"dry_run,omitempty"`
+	Params json.RawMessage `json:"params,omitempty"`
+}
+
+// JobResult is the structured result of a job run, returned to the Lambda caller
+type JobResult struct {
+	Job        string      `json:"job"`
+	DryRun     bool        `json:"dry_run"`
+	Status     string      `json:"status"`
+	StartedAt  int64       `json:"started_at"`
+	FinishedAt int64       `json:"finished_at"`
+	DurationMs int64       `json:"duration_ms"`
+	Result     interface{} `json:"result"`
+}
+
+// jobServices holds the services jobs run with
+type jobServices struct {
+	scheduler service.SchedulerService
+	guests    service.GuestService
+}
+
+// jobRunner runs a job with its JSON params
+type jobRunner func(ctx context.Context, services *jobServices, params json.RawMessage, dryRun bool) (interface{}, error)
+
+// jobs maps job names to their runners
+var jobs = map[string]jobRunner{
+	jobFetchFeeds: typedJob(func(ctx context.Context, services *jobServices, opts *service.FetchRunOptions, dryRun bool) (*service.FetchRunSummary, error) {
+		opts.DryRun = dryRun
+		return services.scheduler.FetchFeeds(ctx, opts)
+	}),
+	jobCleanupOrphans: typedJob(func(ctx context.Context, services *jobServices, opts *service.CleanupOptions, dryRun bool) (*service.CleanupSummary, error) {
+		opts.DryRun = dryRun
+		return services.scheduler.CleanupOrphanedArticles(ctx, opts)
+	}),
+	jobPurgeOldArticles: typedJob(func(ctx context.Context, services *jobServices, opts *service.PurgeOptions, dryRun bool) (*service.PurgeSummary, error) {
+		opts.DryRun = dryRun
+		return services.scheduler.PurgeOldArticles(ctx, opts)
+	}),
+	jobExpireGuests: typedJob(func(ctx context.Context, services *jobServices, opts *service.ExpireGuestsOptions, dryRun bool) (*service.ExpireGuestsSummary, error) {
+		opts.DryRun = dryRun
+		return services.guests.ExpireGuests(ctx, opts)
+	}),
+	jobMigrateFeeds: typedJob(func(ctx context.Context, services *jobServices, opts *service.MigrateFeedsOptions, dryRun bool) (*service.MigrateFeedsSummary, error) {
+		opts.DryRun = dryRun
+		return services.scheduler.MigrateFeeds(ctx, opts)
+	}),
+	jobReindexSearch: typedJob(func(ctx context.Context, services *jobServices, opts *service.ReindexSearchOptions, dryRun bool) (*service.ReindexSearchSummary, error) {
+		opts.DryRun = dryRun
+		return services.scheduler.ReindexSearch(ctx, opts)
+	}),
+}
+
+// typedJob adapts a job taking typed params to a jobRunner. Unknown params are rejected, so that
+// a misspelled param doesn't silently fall back to its default.
+func typedJob[P any, R any](run func(ctx context.Context, services *jobServices, params *P, dryRun bool) (R, error)) jobRunner {
+	return func(ctx context.Context, services *jobServices, raw json.RawMessage, dryRun bool) (interface{}, error) {
+		params := new(P)
+		if trimmed := bytes.TrimSpace(raw); len(trimmed) > 0 && !bytes.Equal(trimmed, []byte("null")) {
+			decoder := json.NewDecoder(bytes.NewReader(raw))
+			decoder.DisallowUnknownFields()
+			if err := decoder.Decode(params); err != nil {
+				return nil, fmt.Errorf("invalid params: %w", err)
+			}
+		}
+		return run(ctx, services, params, dryRun)
+	}
+}
+
+// parseJobEvent returns the job an EventBridge event runs, and false if the event is not a job.
+// The legacy {"mode": "scheduler"} event runs fetch_feeds.
+func parseJobEvent(event map[string]interface{}) (*JobEvent, bool, error) {
+	if _, exists := event["job"]; exists {
+		data, err := json.Marshal(event)
+		if err != nil {
+			return nil, true, fmt.Errorf("failed to marshal job event: %w", err)
+		}
+		var jobEvent JobEvent
+		if err := json.Unmarshal(data, &jobEvent); err != nil {
+			return nil, true, fmt.Errorf("invalid job event: %w", err)
+		}
+		return &jobEvent, true, nil
+	}
+
+	if mode, exists := event["mode"]; exists && mode == "scheduler" {
+		return &JobEvent{Job: jobFetchFeeds}, true, nil
+	}
+
+	return nil, false, nil
+}
+
+// parseJobArgs returns the job run by command line arguments, and false if they don't run one:
+// --job=<name> [--dry-run] [--params=<json>], or the legacy --mode=scheduler
+func parseJobArgs(args []string) (*JobEvent, bool, error) {
+	var jobEvent *JobEvent
+	dryRun := false
+	var params json.RawMessage
+
+	for _, arg := range args {
+		switch {
+		case arg == "--mode=scheduler":
+			jobEvent = &JobEvent{Job: jobFetchFeeds}
+		case strings.HasPrefix(arg, "--job="):
+			jobEvent = &JobEvent{Job: strings.TrimPrefix(arg, "--job=")}
+		case arg == "--dry-run":
+			dryRun = true
+		case strings.HasPrefix(arg, "--params="):
+			params = json.RawMessage(strings.TrimPrefix(arg, "--params="))
+		}
+	}
+
+	if jobEvent == nil {
+		if dryRun || params != nil {
+			return nil, false, fmt.Errorf("--dry-run and --params require --job")
+		}
+		return nil, false, nil
+	}
+
+	jobEvent.DryRun = dryRun
+	jobEvent.Params = params
+	return jobEvent, true, nil
+}
+
+// runJob runs a scheduled job and returns its result
+func runJob(ctx context.Context, config *Config, event *JobEvent) (*JobResult, error) {
+	run, exists := jobs[event.Job]
+	if !exists {
+		return nil, fmt.Errorf("unknown job %q", event.Job)
+	}
+
+	log.Printf("🕐 Running job %s (dry run: %v)", event.Job, event.DryRun)
+
+	services, err := newJobServices(ctx, config)
+	if err != nil {
+		return nil, err
+	}
+
+	startedAt := time.Now()
+	result, err := run(ctx, services, event.Params, event.DryRun)
+	if err != nil {
+		return nil, fmt.Errorf("job %s failed: %w", event.Job, err)
+	}
+	finishedAt := time.Now()
+
+	log.Printf("✅ Job %s completed in %s", event.Job, finishedAt.Sub(startedAt).Round(time.Millisecond))
+	return &JobResult{
+		Job:        event.Job,
+		DryRun:     event.DryRun,
+		Status:     "success",
+		StartedAt:  startedAt.Unix(),
+		FinishedAt: finishedAt.Unix(),
+		DurationMs: finishedAt.Sub(startedAt).Milliseconds(),
+		Result:     result,
+	}, nil
+}
+
+// newJobServices initializes the services scheduled jobs run with
+func newJobServices(ctx context.Context, config *Config) (*jobServices, error) {
+	// Initialize DynamoDB client
+	dbConfig := &dynamodbpkg.Config{
+		EndpointURL: config.DynamoDBEndpoint,
+		TablePrefix: config.TablePrefix,
+		TableSuffix: config.TableSuffix,
+	}
+
+	dbClient, err := dynamodbpkg.NewClient(ctx, dbConfig)
+	if err != nil {
+		return nil, fmt.Errorf("failed to create DynamoDB client: %w", err)
+	}
+
+	// Initialize repositories
+	userRepo := repository.NewUserRepository(dbClient)
+	feedRepo := repository.NewFeedRepository(dbClient)
+	articleRepo := repository.NewArticleRepository(dbClient)
+	bowerRepo := repository.NewBowerRepository(dbClient)
+	chickRepo := repository.NewChickRepository(dbClient)
+	readStateRepo := repository.NewReadStateRepository(dbClient)
+	playbackRepo := repository.NewPlaybackRepository(dbClient)
+
+	// Initialize services
+	rssService := service.NewRSSService()
+	schedulerService := service.NewSchedulerServiceWithConfig(feedRepo, articleRepo, rssService, &service.SchedulerConfig{
+		Workers:      config.SchedulerWorkers,
+		PerHostLimit: config.SchedulerPerHostLimit,
+	})
+
+	// Let bowers filter articles before they are stored
+	if ss, ok := schedulerService.(interface {
+		SetBowerRepository(repository.BowerRepository)
+	}); ok {
+		ss.SetBowerRepository(bowerRepo)
+	}
+
+	// Subscribe feeds that have a WebSub hub, so their new articles are pushed between runs
+	if config.WebSubCallbackBaseURL != "" {
+		webSubService := service.NewWebSubService(feedRepo, articleRepo, rssService, config.WebSubCallbackBaseURL)
+		if ss, ok := schedulerService.(interface {
+			SetWebSubService(service.WebSubService)
+		}); ok {
+			ss.SetWebSubService(webSubService)
+		}
+	}
+
+	// Let cleanups remove likes, read state and playback positions of the articles they delete
+	if ss, ok := schedulerService.(interface {
+		SetUserDataRepositories(repository.ChickRepository, repository.ReadStateRepository, repository.PlaybackRepository)
+	}); ok {
+		ss.SetUserDataRepositories(chickRepo, readStateRepo, playbackRepo)
+	}
+
+	// Expired guests' bowers are deleted along with their feeds and articles
+	bowerService := service.NewBowerService(bowerRepo, feedRepo)
+	if bs, ok := bowerService.(interface {
+		SetArticleRepositories(repository.ArticleRepository, repository.ChickRepository, repository.ReadStateRepository)
+	}); ok {
+		bs.SetArticleRepositories(articleRepo, chickRepo, readStateRepo)
+	}
+	if bs, ok := bowerService.(interface {
+		SetPlaybackRepository(repository.PlaybackRepository)
+	}); ok {
+		bs.SetPlaybackRepository(playbackRepo)
+	}
+	guestService := service.NewGuestService(userRepo, bowerRepo, chickRepo, readStateRepo, playbackRepo, bowerService)
+
+	return &jobServices{
+		scheduler: schedulerService,
+		guests:    guestService,
+	}, nil
+}
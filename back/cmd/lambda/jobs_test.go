@@ -0,0 +1,135 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	"feed-bower-api/internal/service"
+)
+
+func TestParseJobEvent(t *testing.T) {
+	tests := []struct {
+		name   string
+		event  string
+		isJob  bool
+		job    string
+		dryRun bool
+		params string
+	}{
+		{
+			name:   "job with params",
+			event:  `{"job":"purge_old_articles","dry_run":true,"params":{"max_age_days":30}}`,
+			isJob:  true,
+			job:    jobPurgeOldArticles,
+			dryRun: true,
+			params: `{"max_age_days":30}`,
+		},
+		{
+			name:  "legacy scheduler mode",
+			event: `{"mode":"scheduler"}`,
+			isJob: true,
+			job:   jobFetchFeeds,
+		},
+		{
+			name:  "API Gateway request",
+			event: `{"httpMethod":"GET","path":"/health"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var event map[string]interface{}
+			if err := json.Unmarshal([]byte(tt.event), &event); err != nil {
+				t.Fatalf("Invalid test event: %v", err)
+			}
+
+			jobEvent, isJob, err := parseJobEvent(event)
+			if err != nil {
+				t.Fatalf("parseJobEvent() unexpected error: %v", err)
+			}
+			if isJob != tt.isJob {
+				t.Fatalf("parseJobEvent() isJob = %v, expected %v", isJob, tt.isJob)
+			}
+			if !isJob {
+				return
+			}
+			if jobEvent.Job != tt.job || jobEvent.DryRun != tt.dryRun || string(jobEvent.Params) != tt.params {
+				t.Errorf("parseJobEvent() = %+v, expected job %s, dry run %v, params %s", jobEvent, tt.job, tt.dryRun, tt.params)
+			}
+		})
+	}
+
+	if _, isJob, err := parseJobEvent(map[string]interface{}{"job": 1}); !isJob || err == nil {
+		t.Error("Expected an error for an invalid job name")
+	}
+}
+
+func TestParseJobArgs(t *testing.T) {
+	jobEvent, isJob, err := parseJobArgs([]string{"--job=expire_guests", "--dry-run", `--params={"max_age_days":7}`})
+	if err != nil || !isJob {
+		t.Fatalf("parseJobArgs() = %v, %v, expected a job", isJob, err)
+	}
+	if jobEvent.Job != jobExpireGuests || !jobEvent.DryRun || string(jobEvent.Params) != `{"max_age_days":7}` {
+		t.Errorf("parseJobArgs() = %+v", jobEvent)
+	}
+
+	jobEvent, isJob, err = parseJobArgs([]string{"--mode=scheduler"})
+	if err != nil || !isJob || jobEvent.Job != jobFetchFeeds {
+		t.Errorf("Expected --mode=scheduler to run fetch_feeds, got %+v, %v, %v", jobEvent, isJob, err)
+	}
+
+	if _, isJob, err := parseJobArgs(nil); isJob || err != nil {
+		t.Errorf("Expected no job without arguments, got %v, %v", isJob, err)
+	}
+	if _, _, err := parseJobArgs([]string{"--dry-run"}); err == nil {
+		t.Error("Expected an error for --dry-run without --job")
+	}
+}
+
+func TestTypedJob(t *testing.T) {
+	var got *service.PurgeOptions
+	run := typedJob(func(ctx context.Context, services *jobServices, opts *service.PurgeOptions, dryRun bool) (*service.PurgeSummary, error) {
+		opts.DryRun = dryRun
+		got = opts
+		return &service.PurgeSummary{DryRun: dryRun}, nil
+	})
+
+	result, err := run(context.Background(), nil, json.RawMessage(`{"max_age_days":14}`), true)
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if got.MaxAgeDays != 14 || !got.DryRun {
+		t.Errorf("Expected the params and dry run flag to be passed, got %+v", got)
+	}
+	if summary, ok := result.(*service.PurgeSummary); !ok || !summary.DryRun {
+		t.Errorf("Expected the typed result, got %#v", result)
+	}
+
+	// Params are optional
+	for _, params := range []string{"", "null"} {
+		if _, err := run(context.Background(), nil, json.RawMessage(params), false); err != nil {
+			t.Errorf("Unexpected error for params %q: %v", params, err)
+		}
+		if got.MaxAgeDays != 0 {
+			t.Errorf("Expected default params for %q, got %+v", params, got)
+		}
+	}
+
+	// Misspelled params are rejected rather than ignored
+	if _, err := run(context.Background(), nil, json.RawMessage(`{"max_age":14}`), false); err == nil {
+		t.Error("Expected an error for an unknown param")
+	}
+}
+
+func TestRunJob_UnknownJob(t *testing.T) {
+	if _, err := runJob(context.Background(), &Config{}, &JobEvent{Job: "reticulate_splines"}); err == nil {
+		t.Error("Expected an error for an unknown job")
+	}
+
+	for _, name := range []string{jobFetchFeeds, jobCleanupOrphans, jobPurgeOldArticles, jobExpireGuests} {
+		if _, exists := jobs[name]; !exists {
+			t.Errorf("Expected job %s to be registered", name)
+		}
+	}
+}
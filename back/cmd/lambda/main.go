@@ -7,6 +7,7 @@ import (
 	"log"
 	"net/http"
 	"os"
+	"strconv"
 	"time"
 
 	"github.com/aws/aws-lambda-go/events"
@@ -43,6 +44,13 @@ type Config struct {
 	BedrockAgentAlias string
 	BedrockRegion     string
 
+	// Scheduler
+	SchedulerWorkers      int
+	SchedulerPerHostLimit int
+
+	// WebSub
+	WebSubCallbackBaseURL string // Public URL of the API that hubs call back; WebSub is off if empty
+
 	// Server
 	Port        string
 	Environment string
@@ -54,21 +62,24 @@ type Config struct {
 // loadConfig loads configuration from environment variables
 func loadConfig() *Config {
 	config := &Config{
-		DynamoDBEndpoint:  getEnv("DYNAMODB_ENDPOINT", ""),
-		TablePrefix:       getEnv("DYNAMODB_TABLE_PREFIX", ""),
-		TableSuffix:       getEnv("DYNAMODB_TABLE_SUFFIX", ""),
-		JWTSecret:         getEnv("JWT_SECRET", "default-secret-change-in-production"),
-		CognitoUserPoolID: getEnv("COGNITO_USER_POOL_ID", ""),
-		CognitoRegion:     getEnv("COGNITO_REGION", "ap-northeast-1"),
-		CognitoClientID:   getEnv("COGNITO_CLIENT_ID", ""),
-		CognitoEndpoint:   getEnv("COGNITO_ENDPOINT", ""),
-		UseCognito:        getEnv("USE_COGNITO", "false") == "true",
-		BedrockAgentID:    getEnv("BEDROCK_AGENT_ID", ""),
-		BedrockAgentAlias: getEnv("BEDROCK_AGENT_ALIAS", "production"),
-		BedrockRegion:     getEnv("BEDROCK_REGION", "ap-northeast-1"),
-		Port:              getEnv("PORT", "8080"),
-		Environment:       getEnv("ENVIRONMENT", "development"),
-		LogLevel:          getEnv("LOG_LEVEL", "info"),
+		DynamoDBEndpoint:      getEnv("DYNAMODB_ENDPOINT", ""),
+		TablePrefix:           getEnv("DYNAMODB_TABLE_PREFIX", ""),
+		TableSuffix:           getEnv("DYNAMODB_TABLE_SUFFIX", ""),
+		JWTSecret:             getEnv("JWT_SECRET", "default-secret-change-in-production"),
+		CognitoUserPoolID:     getEnv("COGNITO_USER_POOL_ID", ""),
+		CognitoRegion:         getEnv("COGNITO_REGION", "ap-northeast-1"),
+		CognitoClientID:       getEnv("COGNITO_CLIENT_ID", ""),
+		CognitoEndpoint:       getEnv("COGNITO_ENDPOINT", ""),
+		UseCognito:            getEnv("USE_COGNITO", "false") == "true",
+		BedrockAgentID:        getEnv("BEDROCK_AGENT_ID", ""),
+		BedrockAgentAlias:     getEnv("BEDROCK_AGENT_ALIAS", "production"),
+		BedrockRegion:         getEnv("BEDROCK_REGION", "ap-northeast-1"),
+		SchedulerWorkers:      getEnvInt("SCHEDULER_WORKERS", 10),
+		SchedulerPerHostLimit: getEnvInt("SCHEDULER_PER_HOST_LIMIT", 2),
+		WebSubCallbackBaseURL: getEnv("WEBSUB_CALLBACK_BASE_URL", ""),
+		Port:                  getEnv("PORT", "8080"),
+		Environment:           getEnv("ENVIRONMENT", "development"),
+		LogLevel:              getEnv("LOG_LEVEL", "info"),
 	}
 
 	// Validate required configuration
@@ -95,6 +106,17 @@ func getEnv(key, defaultValue string) string {
 	return defaultValue
 }
 
+// getEnvInt gets an integer environment variable with a default value
+func getEnvInt(key string, defaultValue int) int {
+	if value := os.Getenv(key); value != "" {
+		if parsed, err := strconv.Atoi(value); err == nil {
+			return parsed
+		}
+		log.Printf("⚠️  Invalid integer for %s: %q, using default %d", key, value, defaultValue)
+	}
+	return defaultValue
+}
+
 // setupRouter creates and configures the HTTP router
 func setupRouter(config *Config) (*mux.Router, error) {
 	ctx := context.Background()
@@ -123,6 +145,8 @@ func setupRouter(config *Config) (*mux.Router, error) {
 	feedRepo := repository.NewFeedRepository(dbClient)
 	articleRepo := repository.NewArticleRepository(dbClient)
 	chickRepo := repository.NewChickRepository(dbClient)
+	readStateRepo := repository.NewReadStateRepository(dbClient)
+	playbackRepo := repository.NewPlaybackRepository(dbClient)
 
 	// Initialize services
 	var authService service.AuthService
@@ -151,8 +175,40 @@ func setupRouter(config *Config) (*mux.Router, error) {
 		log.Println("✅ FeedService linked to BowerService for auto-registration")
 	}
 
+	// Let BowerService cascade deletions to articles, likes, read state and playback positions
+	if bs, ok := bowerService.(interface {
+		SetArticleRepositories(repository.ArticleRepository, repository.ChickRepository, repository.ReadStateRepository)
+	}); ok {
+		bs.SetArticleRepositories(articleRepo, chickRepo, readStateRepo)
+	}
+	if bs, ok := bowerService.(interface {
+		SetPlaybackRepository(repository.PlaybackRepository)
+	}); ok {
+		bs.SetPlaybackRepository(playbackRepo)
+	}
+
 	chickService := service.NewChickService(chickRepo, articleRepo, feedRepo, bowerRepo)
-	articleService := service.NewArticleService(articleRepo, feedRepo, bowerRepo, chickRepo, chickService)
+	articleService := service.NewArticleService(articleRepo, feedRepo, bowerRepo, chickRepo, readStateRepo, chickService)
+
+	// Let ArticleService extract full article content on demand
+	if as, ok := articleService.(interface{ SetRSSService(service.RSSService) }); ok {
+		as.SetRSSService(rssService)
+	}
+
+	// Let ArticleService save and resume podcast playback positions
+	if as, ok := articleService.(interface {
+		SetPlaybackRepository(repository.PlaybackRepository)
+	}); ok {
+		as.SetPlaybackRepository(playbackRepo)
+	}
+
+	// WebSub hubs push new articles of the feeds they publish
+	webSubService := service.NewWebSubService(feedRepo, articleRepo, rssService, config.WebSubCallbackBaseURL)
+	if ws, ok := webSubService.(interface {
+		SetBowerRepository(repository.BowerRepository)
+	}); ok {
+		ws.SetBowerRepository(bowerRepo)
+	}
 
 	// Development user should be created using scripts/create-dev-user.sh
 
@@ -162,6 +218,7 @@ func setupRouter(config *Config) (*mux.Router, error) {
 	feedHandler := handler.NewFeedHandler(feedService)
 	articleHandler := handler.NewArticleHandler(articleService)
 	chickHandler := handler.NewChickHandler(chickService)
+	webSubHandler := handler.NewWebSubHandler(webSubService)
 
 	// Create router
 	router := mux.NewRouter()
@@ -190,8 +247,11 @@ func setupRouter(config *Config) (*mux.Router, error) {
 			"/api/auth/guest",
 			"/api/auth/register",
 			"/api/auth/login",
-			"/api/feeds/validate",    // Public endpoint for feed validation
-			"/api/feeds/preview-url", // Public endpoint for feed preview
+			"/api/feeds/validate",     // Public endpoint for feed validation
+			"/api/feeds/preview-url",  // Public endpoint for feed preview
+			"/api/bowers/*/feed.atom", // Bower output feeds (private bowers check ?token=)
+			"/api/bowers/*/feed.json",
+			service.WebSubCallbackPath, // WebSub hubs (content is checked against its signature)
 		},
 	}
 
@@ -209,6 +269,7 @@ func setupRouter(config *Config) (*mux.Router, error) {
 	feedHandler.RegisterRoutes(router)
 	articleHandler.RegisterRoutes(router)
 	chickHandler.RegisterRoutes(router)
+	webSubHandler.RegisterRoutes(router)
 
 	return router, nil
 }
@@ -236,40 +297,6 @@ func isLambdaEnvironment() bool {
 	return os.Getenv("AWS_LAMBDA_FUNCTION_NAME") != ""
 }
 
-// runScheduler runs the feed fetch scheduler
-func runScheduler(config *Config) error {
-	log.Println("🕐 Running in scheduler mode")
-
-	// Initialize DynamoDB client
-	dbConfig := &dynamodbpkg.Config{
-		EndpointURL: config.DynamoDBEndpoint,
-		TablePrefix: config.TablePrefix,
-		TableSuffix: config.TableSuffix,
-	}
-
-	dbClient, err := dynamodbpkg.NewClient(context.Background(), dbConfig)
-	if err != nil {
-		return fmt.Errorf("failed to create DynamoDB client: %w", err)
-	}
-
-	// Initialize repositories
-	feedRepo := repository.NewFeedRepository(dbClient)
-	articleRepo := repository.NewArticleRepository(dbClient)
-
-	// Initialize services
-	rssService := service.NewRSSService()
-	schedulerService := service.NewSchedulerService(feedRepo, articleRepo, rssService)
-
-	// Run the scheduler
-	ctx := context.Background()
-	if err := schedulerService.FetchAllFeeds(ctx); err != nil {
-		return fmt.Errorf("scheduler failed: %w", err)
-	}
-
-	log.Println("✅ Scheduler completed successfully")
-	return nil
-}
-
 func main() {
 	// Load .env file if not in Lambda environment
 	if !isLambdaEnvironment() {
@@ -287,11 +314,18 @@ func main() {
 	log.SetFlags(log.LstdFlags | log.Lshortfile)
 	log.Printf("Starting Feed Bower API - Environment: %s", config.Environment)
 
-	// Check for scheduler mode
-	if len(os.Args) > 1 && os.Args[1] == "--mode=scheduler" {
-		if err := runScheduler(config); err != nil {
-			log.Fatalf("Scheduler error: %v", err)
+	// Check for a scheduled job run from the command line
+	jobEvent, isJob, err := parseJobArgs(os.Args[1:])
+	if err != nil {
+		log.Fatalf("Invalid arguments: %v", err)
+	}
+	if isJob {
+		result, err := runJob(context.Background(), config, jobEvent)
+		if err != nil {
+			log.Fatalf("Job error: %v", err)
 		}
+		output, _ := json.MarshalIndent(result, "", "  ")
+		fmt.Println(string(output))
 		return
 	}
 
@@ -307,15 +341,34 @@ func main() {
 
 		// Create a handler that can handle both API Gateway and EventBridge events
 		handler := func(ctx context.Context, event interface{}) (interface{}, error) {
-			// Check if this is an EventBridge event (scheduler mode)
+			// Check if this is an EventBridge event running a scheduled job
 			if eventMap, ok := event.(map[string]interface{}); ok {
-				if mode, exists := eventMap["mode"]; exists && mode == "scheduler" {
-					log.Println("🕐 EventBridge scheduler event detected")
-					if err := runScheduler(config); err != nil {
-						log.Printf("❌ Scheduler error: %v", err)
+				jobEvent, isJob, err := parseJobEvent(eventMap)
+				if isJob {
+					if err != nil {
+						log.Printf("❌ Job error: %v", err)
+						return nil, err
+					}
+					log.Printf("🕐 EventBridge job event detected: %s", jobEvent.Job)
+					result, err := runJob(ctx, config, jobEvent)
+					if err != nil {
+						log.Printf("❌ Job error: %v", err)
+						return nil, err
+					}
+					return result, nil
+				}
+
+				// Check if this is a batch of Articles table stream records to index
+				articleIDs, isStream, err := parseStreamEvent(eventMap)
+				if isStream {
+					if err == nil {
+						err = syncSearchIndex(ctx, config, articleIDs)
+					}
+					if err != nil {
+						log.Printf("❌ Search index sync error: %v", err)
 						return nil, err
 					}
-					return map[string]string{"status": "success", "message": "Scheduler completed"}, nil
+					return nil, nil
 				}
 			}
 
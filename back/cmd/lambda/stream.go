@@ -0,0 +1,61 @@
+package main
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"log"
+
+	"github.com/aws/aws-lambda-go/events"
+)
+
+// dynamoDBStreamSource is the event source of DynamoDB stream records
+const dynamoDBStreamSource = "aws:dynamodb"
+
+// parseStreamEvent returns the IDs of the articles changed in a batch of Articles table stream
+// records, and false if the event is not a DynamoDB stream batch. The stream only carries keys,
+// so the articles are read back when they are indexed.
+func parseStreamEvent(event map[string]interface{}) ([]string, bool, error) {
+	records, ok := event["Records"].([]interface{})
+	if !ok || len(records) == 0 {
+		return nil, false, nil
+	}
+	if first, ok := records[0].(map[string]interface{}); !ok || first["eventSource"] != dynamoDBStreamSource {
+		return nil, false, nil
+	}
+
+	data, err := json.Marshal(event)
+	if err != nil {
+		return nil, true, fmt.Errorf("failed to marshal stream event: %w", err)
+	}
+	var streamEvent events.DynamoDBEvent
+	if err := json.Unmarshal(data, &streamEvent); err != nil {
+		return nil, true, fmt.Errorf("invalid stream event: %w", err)
+	}
+
+	articleIDs := make([]string, 0, len(streamEvent.Records))
+	for _, record := range streamEvent.Records {
+		key, exists := record.Change.Keys["article_id"]
+		if !exists || key.DataType() != events.DataTypeString {
+			return nil, true, fmt.Errorf("stream record %s has no article_id key", record.EventID)
+		}
+		articleIDs = append(articleIDs, key.String())
+	}
+	return articleIDs, true, nil
+}
+
+// syncSearchIndex indexes the articles changed in a batch of stream records. An error fails the
+// batch, so that Lambda retries it.
+func syncSearchIndex(ctx context.Context, config *Config, articleIDs []string) error {
+	services, err := newJobServices(ctx, config)
+	if err != nil {
+		return err
+	}
+
+	if err := services.scheduler.SyncSearchIndex(ctx, articleIDs); err != nil {
+		return err
+	}
+
+	log.Printf("🔎 Synced %d changed articles to the search index", len(articleIDs))
+	return nil
+}
@@ -0,0 +1,61 @@
+package main
+
+import (
+	"encoding/json"
+	"reflect"
+	"testing"
+)
+
+func TestParseStreamEvent(t *testing.T) {
+	tests := []struct {
+		name       string
+		event      string
+		isStream   bool
+		articleIDs []string
+		wantErr    bool
+	}{
+		{
+			name: "articles table records",
+			event: `{"Records":[
+				{"eventID":"1","eventName":"INSERT","eventSource":"aws:dynamodb","dynamodb":{"Keys":{"article_id":{"S":"a1"}},"StreamViewType":"KEYS_ONLY"}},
+				{"eventID":"2","eventName":"REMOVE","eventSource":"aws:dynamodb","dynamodb":{"Keys":{"article_id":{"S":"a2"}},"StreamViewType":"KEYS_ONLY"}}
+			]}`,
+			isStream:   true,
+			articleIDs: []string{"a1", "a2"},
+		},
+		{
+			name:     "record without an article key",
+			event:    `{"Records":[{"eventID":"1","eventSource":"aws:dynamodb","dynamodb":{"Keys":{"feed_id":{"S":"f1"}}}}]}`,
+			isStream: true,
+			wantErr:  true,
+		},
+		{
+			name:  "records of another source",
+			event: `{"Records":[{"eventSource":"aws:sqs","body":"{}"}]}`,
+		},
+		{
+			name:  "job event",
+			event: `{"job":"reindex_search"}`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var event map[string]interface{}
+			if err := json.Unmarshal([]byte(tt.event), &event); err != nil {
+				t.Fatalf("Invalid test event: %v", err)
+			}
+
+			articleIDs, isStream, err := parseStreamEvent(event)
+			if isStream != tt.isStream {
+				t.Fatalf("parseStreamEvent() isStream = %v, expected %v", isStream, tt.isStream)
+			}
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("parseStreamEvent() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if !reflect.DeepEqual(articleIDs, tt.articleIDs) {
+				t.Errorf("parseStreamEvent() = %v, expected %v", articleIDs, tt.articleIDs)
+			}
+		})
+	}
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/gorilla/mux"
 
@@ -39,13 +40,27 @@ func (h *ArticleHandler) RegisterRoutes(router *mux.Router) {
 	articleRouter.HandleFunc("/{id}/like", h.UnlikeArticle).Methods("DELETE", "OPTIONS")
 	articleRouter.HandleFunc("/{id}/read", h.MarkAsRead).Methods("POST", "OPTIONS")
 	articleRouter.HandleFunc("/{id}/unread", h.MarkAsUnread).Methods("POST", "OPTIONS")
+	articleRouter.HandleFunc("/{id}/playback", h.GetPlaybackPosition).Methods("GET", "OPTIONS")
+	articleRouter.HandleFunc("/{id}/playback", h.SavePlaybackPosition).Methods("PUT", "OPTIONS")
+
+	// Bower output feeds are served without authentication; private bowers require ?token=
+	router.HandleFunc("/api/bowers/{id}/feed.atom", h.GetBowerAtomFeed).Methods("GET", "OPTIONS")
+	router.HandleFunc("/api/bowers/{id}/feed.json", h.GetBowerJSONFeed).Methods("GET", "OPTIONS")
+}
+
+// SavePlaybackPositionRequest represents the request to save a podcast episode's playback position
+type SavePlaybackPositionRequest struct {
+	Position  int64 `json:"position" validate:"min=0"`           // Seconds from the start
+	Duration  int64 `json:"duration,omitempty" validate:"min=0"` // Seconds; defaults to the episode's duration
+	Completed bool  `json:"completed,omitempty"`
 }
 
 // ArticleListResponse represents the response for article listing
 type ArticleListResponse struct {
-	Articles []ArticleResponse `json:"articles"`
-	Total    int               `json:"total"`
-	HasMore  bool              `json:"has_more"`
+	Articles   []ArticleResponse `json:"articles"`
+	Total      int               `json:"total"`
+	HasMore    bool              `json:"has_more"`
+	NextCursor string            `json:"next_cursor,omitempty"` // Pass as ?cursor= to get the next page
 }
 
 // ListArticles lists articles with filtering and pagination
@@ -66,10 +81,17 @@ func (h *ArticleHandler) ListArticles(w http.ResponseWriter, r *http.Request) {
 		limit = 100
 	}
 
+	lastKey, err := DecodeCursor(GetQueryParam(r, "cursor", ""))
+	if err != nil {
+		response.BadRequest(w, "Invalid cursor")
+		return
+	}
+
 	// Create request
 	req := &service.GetArticlesRequest{
 		Limit:     limit,
 		Tab:       tab,
+		LastKey:   lastKey,
 		SortBy:    sortBy,
 		SortOrder: sortOrder,
 	}
@@ -80,25 +102,19 @@ func (h *ArticleHandler) ListArticles(w http.ResponseWriter, r *http.Request) {
 
 	articleListResp, err := h.articleService.GetArticles(r.Context(), user.UserID, req)
 	if err != nil {
+		if strings.Contains(err.Error(), "malformed cursor") {
+			response.BadRequest(w, "Invalid cursor")
+			return
+		}
 		response.InternalServerError(w, "Failed to list articles: "+err.Error())
 		return
 	}
 
-	articleResponses := make([]ArticleResponse, len(articleListResp.Articles))
-	for i, article := range articleListResp.Articles {
-		articleResponses[i] = h.toArticleResponse(&article)
-	}
-
-	resp := &ArticleListResponse{
-		Articles: articleResponses,
-		Total:    articleListResp.Total,
-		HasMore:  articleListResp.HasMore,
-	}
-
-	response.Success(w, resp)
+	h.writeArticleList(w, articleListResp)
 }
 
-// GetArticle retrieves an article by ID
+// GetArticle retrieves an article by ID. With ?full=true the response includes full_content,
+// the sanitized HTML of the article's page, when the feed is in full text mode.
 func (h *ArticleHandler) GetArticle(w http.ResponseWriter, r *http.Request) {
 	user, ok := GetRequiredUserFromContext(w, r)
 	if !ok {
@@ -112,13 +128,27 @@ func (h *ArticleHandler) GetArticle(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	article, err := h.articleService.GetArticleByID(r.Context(), articleID, user.UserID)
+	// ?full=true includes the content extracted from the article's page
+	full := GetQueryParamBool(r, "full", false)
+
+	var article *model.Article
+	var err error
+	if full {
+		article, err = h.articleService.GetFullArticle(r.Context(), articleID, user.UserID)
+	} else {
+		article, err = h.articleService.GetArticleByID(r.Context(), articleID, user.UserID)
+	}
 	if err != nil {
 		response.NotFound(w, "Article not found")
 		return
 	}
 
-	response.Success(w, h.toArticleResponse(article))
+	articleResponse := h.toArticleResponse(article)
+	if full {
+		articleResponse.FullContent = article.FullContent
+	}
+
+	response.Success(w, articleResponse)
 }
 
 // LikeArticle likes an article
@@ -200,7 +230,7 @@ func (h *ArticleHandler) MarkAsRead(w http.ResponseWriter, r *http.Request) {
 
 // MarkAsUnread marks an article as unread
 func (h *ArticleHandler) MarkAsUnread(w http.ResponseWriter, r *http.Request) {
-	_, ok := GetRequiredUserFromContext(w, r)
+	user, ok := GetRequiredUserFromContext(w, r)
 	if !ok {
 		return
 	}
@@ -212,11 +242,87 @@ func (h *ArticleHandler) MarkAsUnread(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	// Note: The service doesn't have MarkAsUnread method as read status is managed client-side
-	// This endpoint exists for future implementation
+	err := h.articleService.MarkArticleAsUnread(r.Context(), user.UserID, articleID)
+	if err != nil {
+		response.InternalServerError(w, "Failed to mark article as unread: "+err.Error())
+		return
+	}
+
 	response.Success(w, map[string]string{"message": "Article marked as unread"})
 }
 
+// GetPlaybackPosition returns where the user should resume a podcast episode
+func (h *ArticleHandler) GetPlaybackPosition(w http.ResponseWriter, r *http.Request) {
+	user, ok := GetRequiredUserFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	vars := mux.Vars(r)
+	articleID := vars["id"]
+	if articleID == "" {
+		response.BadRequest(w, "Article ID is required")
+		return
+	}
+
+	position, err := h.articleService.GetPlaybackPosition(r.Context(), user.UserID, articleID)
+	if err != nil {
+		h.writePlaybackError(w, "Failed to get playback position: ", err)
+		return
+	}
+
+	response.Success(w, position)
+}
+
+// SavePlaybackPosition saves how far the user has played a podcast episode
+func (h *ArticleHandler) SavePlaybackPosition(w http.ResponseWriter, r *http.Request) {
+	user, ok := GetRequiredUserFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	vars := mux.Vars(r)
+	articleID := vars["id"]
+	if articleID == "" {
+		response.BadRequest(w, "Article ID is required")
+		return
+	}
+
+	var req SavePlaybackPositionRequest
+	if !ParseJSONBodySecure(w, r, &req) {
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, err.Error())
+		return
+	}
+
+	position, err := h.articleService.SavePlaybackPosition(r.Context(), user.UserID, articleID, &service.SavePlaybackPositionRequest{
+		Position:  req.Position,
+		Duration:  req.Duration,
+		Completed: req.Completed,
+	})
+	if err != nil {
+		h.writePlaybackError(w, "Failed to save playback position: ", err)
+		return
+	}
+
+	response.Success(w, position)
+}
+
+// writePlaybackError maps playback errors to responses
+func (h *ArticleHandler) writePlaybackError(w http.ResponseWriter, prefix string, err error) {
+	switch {
+	case strings.HasPrefix(err.Error(), "article access check failed"):
+		response.NotFound(w, "Article not found")
+	case err.Error() == "article is not a podcast episode", err.Error() == "position and duration cannot be negative":
+		response.BadRequest(w, err.Error())
+	default:
+		response.InternalServerError(w, prefix+err.Error())
+	}
+}
+
 // ListLikedArticles lists articles liked by the user
 func (h *ArticleHandler) ListLikedArticles(w http.ResponseWriter, r *http.Request) {
 	user, ok := GetRequiredUserFromContext(w, r)
@@ -258,7 +364,8 @@ func (h *ArticleHandler) ListLikedArticles(w http.ResponseWriter, r *http.Reques
 	response.Success(w, resp)
 }
 
-// SearchArticles searches for articles
+// SearchArticles searches for articles. The q parameter supports filters such as
+// title:, feed:, after:, before:, liked:, read: and has:image.
 func (h *ArticleHandler) SearchArticles(w http.ResponseWriter, r *http.Request) {
 	user, ok := GetRequiredUserFromContext(w, r)
 	if !ok {
@@ -278,34 +385,58 @@ func (h *ArticleHandler) SearchArticles(w http.ResponseWriter, r *http.Request)
 		limit = 100
 	}
 
+	lastKey, err := DecodeCursor(GetQueryParam(r, "cursor", ""))
+	if err != nil {
+		response.BadRequest(w, "Invalid cursor")
+		return
+	}
+
 	// Create search request
 	req := &service.SearchArticlesRequest{
-		Query: query,
-		Limit: limit,
+		Query:   query,
+		Limit:   limit,
+		LastKey: lastKey,
 	}
 
 	if bowerID != "" {
 		req.BowerID = &bowerID
 	}
 
-	articles, err := h.articleService.SearchArticles(r.Context(), user.UserID, req)
+	articleListResp, err := h.articleService.SearchArticles(r.Context(), user.UserID, req)
 	if err != nil {
-		response.InternalServerError(w, "Failed to search articles: "+err.Error())
+		switch {
+		case strings.HasPrefix(err.Error(), "invalid search query"), err.Error() == "search query is required":
+			response.BadRequest(w, err.Error())
+		case err.Error() == "access denied: bower is private":
+			response.Forbidden(w, "Access denied")
+		default:
+			response.InternalServerError(w, "Failed to search articles: "+err.Error())
+		}
 		return
 	}
 
-	articleResponses := make([]ArticleResponse, len(articles))
-	for i, article := range articles {
-		articleResponses[i] = h.toArticleResponse(article)
+	h.writeArticleList(w, articleListResp)
+}
+
+// writeArticleList writes a page of articles with the cursor of the next page
+func (h *ArticleHandler) writeArticleList(w http.ResponseWriter, articleListResp *service.ArticleListResponse) {
+	nextCursor, err := EncodeCursor(articleListResp.LastKey)
+	if err != nil {
+		response.InternalServerError(w, err.Error())
+		return
 	}
 
-	resp := &ArticleListResponse{
-		Articles: articleResponses,
-		Total:    len(articles),
-		HasMore:  false, // Search doesn't support pagination yet
+	articleResponses := make([]ArticleResponse, len(articleListResp.Articles))
+	for i, article := range articleListResp.Articles {
+		articleResponses[i] = h.toArticleResponse(&article)
 	}
 
-	response.Success(w, resp)
+	response.Success(w, &ArticleListResponse{
+		Articles:   articleResponses,
+		Total:      articleListResp.Total,
+		HasMore:    articleListResp.HasMore,
+		NextCursor: nextCursor,
+	})
 }
 
 // toArticleResponse converts a model.Article to ArticleResponse
@@ -316,16 +447,69 @@ func (h *ArticleHandler) toArticleResponse(article *model.Article) ArticleRespon
 	}
 
 	return ArticleResponse{
-		ArticleID:   article.ArticleID,
-		FeedID:      article.FeedID,
-		Title:       article.Title,
-		Content:     article.Content,
-		URL:         article.URL,
-		ImageURL:    imageURL,
-		PublishedAt: article.PublishedAt,
-		CreatedAt:   article.CreatedAt,
-		Bower:       article.Bower,
-		Liked:       article.Liked,
-		Read:        article.Read,
+		ArticleID:     article.ArticleID,
+		FeedID:        article.FeedID,
+		SourceFeedIDs: article.SourceFeedIDs,
+		Title:         article.Title,
+		Content:       article.Content,
+		ContentHTML:   article.ContentHTML,
+		URL:           article.URL,
+		ImageURL:      imageURL,
+		Author:        article.Author,
+		Media:         article.Media,
+		Episode:       article.Episode,
+		PublishedAt:   article.PublishedAt,
+		CreatedAt:     article.CreatedAt,
+		Bower:         article.Bower,
+		Liked:         article.Liked,
+		Read:          article.Read,
+		Score:         article.Score,
+		Playback:      article.Playback,
+	}
+}
+
+// GetBowerAtomFeed serves a bower's articles as an Atom feed
+func (h *ArticleHandler) GetBowerAtomFeed(w http.ResponseWriter, r *http.Request) {
+	h.serveBowerOutputFeed(w, r, "application/atom+xml; charset=utf-8", service.MarshalAtomOutputFeed)
+}
+
+// GetBowerJSONFeed serves a bower's articles as a JSON Feed
+func (h *ArticleHandler) GetBowerJSONFeed(w http.ResponseWriter, r *http.Request) {
+	h.serveBowerOutputFeed(w, r, "application/feed+json; charset=utf-8", service.MarshalJSONOutputFeed)
+}
+
+// serveBowerOutputFeed loads a bower's output feed and writes it using the given renderer
+func (h *ArticleHandler) serveBowerOutputFeed(w http.ResponseWriter, r *http.Request, contentType string, render func(*service.BowerOutputFeed, string) ([]byte, error)) {
+	bowerID := mux.Vars(r)["id"]
+	if bowerID == "" {
+		response.BadRequest(w, "Bower ID is required")
+		return
+	}
+
+	token := GetQueryParam(r, "token", "")
+	feed, err := h.articleService.GetBowerOutputFeed(r.Context(), bowerID, token)
+	if err != nil {
+		if err.Error() == "access denied: invalid feed token" {
+			response.Forbidden(w, err.Error())
+			return
+		}
+		response.NotFound(w, "Bower not found")
+		return
+	}
+
+	selfURL := GetRequestBaseURL(r) + r.URL.RequestURI()
+	data, err := render(feed, selfURL)
+	if err != nil {
+		response.InternalServerErrorWithErr(w, "Failed to render feed", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", contentType)
+	if feed.Bower.IsPublic {
+		w.Header().Set("Cache-Control", "public, max-age=300")
+	} else {
+		w.Header().Set("Cache-Control", "private, max-age=300")
 	}
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
 }
@@ -1,8 +1,12 @@
 package handler
 
 import (
+	"errors"
+	"io"
 	"log"
 	"net/http"
+	"net/url"
+	"strings"
 
 	"github.com/gorilla/mux"
 
@@ -37,6 +41,10 @@ func (h *BowerHandler) RegisterRoutes(router *mux.Router) {
 	bowerRouter.HandleFunc("/{id}", h.DeleteBower).Methods("DELETE", "OPTIONS")
 	bowerRouter.HandleFunc("/public", h.ListPublicBowers).Methods("GET", "OPTIONS")
 	bowerRouter.HandleFunc("/search", h.SearchBowers).Methods("GET", "OPTIONS")
+	bowerRouter.HandleFunc("/import/opml", h.ImportOPML).Methods("POST", "OPTIONS")
+	bowerRouter.HandleFunc("/export/opml", h.ExportOPML).Methods("GET", "OPTIONS")
+	bowerRouter.HandleFunc("/{id}/feed-token", h.RotateFeedToken).Methods("POST", "OPTIONS")
+	bowerRouter.HandleFunc("/{id}/feed-token", h.RevokeFeedToken).Methods("DELETE", "OPTIONS")
 }
 
 // CreateBowerRequest represents the request to create a bower
@@ -48,6 +56,8 @@ type CreateBowerRequest struct {
 	IsPublic          bool     `json:"is_public"`
 	AutoRegisterFeeds bool     `json:"auto_register_feeds"`
 	MaxAutoFeeds      int      `json:"max_auto_feeds" validate:"omitempty,min=1,max=10"`
+
+	FilterRules *model.FilterRules `json:"filter_rules,omitempty"`
 }
 
 // UpdateBowerRequest represents the request to update a bower
@@ -57,20 +67,25 @@ type UpdateBowerRequest struct {
 	EggColors *[]string `json:"egg_colors,omitempty"`
 	Color     *string   `json:"color,omitempty" validate:"omitempty,hexcolor"`
 	IsPublic  *bool     `json:"is_public,omitempty"`
+
+	FilterRules *model.FilterRules `json:"filter_rules,omitempty"`
 }
 
 // BowerResponse represents a bower in API responses
 type BowerResponse struct {
-	BowerID   string         `json:"bower_id"`
-	UserID    string         `json:"user_id"`
-	Name      string         `json:"name"`
-	Keywords  []string       `json:"keywords"`
-	EggColors []string       `json:"egg_colors"`
-	Color     string         `json:"color"`
-	IsPublic  bool           `json:"is_public"`
-	CreatedAt int64          `json:"created_at"`
-	UpdatedAt int64          `json:"updated_at"`
-	Feeds     []FeedResponse `json:"feeds"`
+	BowerID      string         `json:"bower_id"`
+	UserID       string         `json:"user_id"`
+	Name         string         `json:"name"`
+	Keywords     []string       `json:"keywords"`
+	EggColors    []string       `json:"egg_colors"`
+	Color        string         `json:"color"`
+	IsPublic     bool           `json:"is_public"`
+	HasFeedToken bool           `json:"has_feed_token"`
+	CreatedAt    int64          `json:"created_at"`
+	UpdatedAt    int64          `json:"updated_at"`
+	Feeds        []FeedResponse `json:"feeds"`
+
+	FilterRules *model.FilterRules `json:"filter_rules,omitempty"`
 }
 
 // CreateBowerResponse represents the response when creating a bower
@@ -90,6 +105,15 @@ type FeedResponse struct {
 	Category    string `json:"category"`
 	LastUpdated int64  `json:"last_updated"`
 	CreatedAt   int64  `json:"created_at"`
+	FullText    bool   `json:"full_text"` // New articles' full content is extracted from their pages
+
+	// Fetch health
+	HealthStatus        string `json:"health_status"`
+	ConsecutiveFailures int    `json:"consecutive_failures"`
+	LastError           string `json:"last_error,omitempty"`
+	LastErrorAt         int64  `json:"last_error_at,omitempty"`
+	LastHTTPStatus      int    `json:"last_http_status,omitempty"`
+	NextFetchAt         int64  `json:"next_fetch_at,omitempty"`
 }
 
 // CreateBower creates a new bower
@@ -127,11 +151,16 @@ func (h *BowerHandler) CreateBower(w http.ResponseWriter, r *http.Request) {
 		IsPublic:          req.IsPublic,
 		AutoRegisterFeeds: req.AutoRegisterFeeds,
 		MaxAutoFeeds:      req.MaxAutoFeeds,
+		FilterRules:       req.FilterRules,
 	}
 
 	result, err := h.bowerService.CreateBower(r.Context(), user.UserID, serviceReq)
 	if err != nil {
 		log.Printf("CreateBower: Service error: %v", err)
+		if strings.HasPrefix(err.Error(), "invalid request: invalid filter rules") {
+			response.BadRequest(w, strings.TrimPrefix(err.Error(), "invalid request: "))
+			return
+		}
 		response.InternalServerErrorWithErr(w, "Failed to create bower", err)
 		return
 	}
@@ -231,11 +260,12 @@ func (h *BowerHandler) UpdateBower(w http.ResponseWriter, r *http.Request) {
 
 	// Convert to service request
 	serviceReq := &service.UpdateBowerRequest{
-		Name:      req.Name,
-		Keywords:  req.Keywords,
-		EggColors: req.EggColors,
-		Color:     req.Color,
-		IsPublic:  req.IsPublic,
+		Name:        req.Name,
+		Keywords:    req.Keywords,
+		EggColors:   req.EggColors,
+		Color:       req.Color,
+		IsPublic:    req.IsPublic,
+		FilterRules: req.FilterRules,
 	}
 
 	bower, err := h.bowerService.UpdateBower(r.Context(), user.UserID, bowerID, serviceReq)
@@ -244,6 +274,10 @@ func (h *BowerHandler) UpdateBower(w http.ResponseWriter, r *http.Request) {
 			response.Forbidden(w, err.Error())
 			return
 		}
+		if strings.HasPrefix(err.Error(), "invalid filter rules") {
+			response.BadRequest(w, err.Error())
+			return
+		}
 		response.InternalServerError(w, "Failed to update bower: "+err.Error())
 		return
 	}
@@ -273,7 +307,7 @@ func (h *BowerHandler) DeleteBower(w http.ResponseWriter, r *http.Request) {
 
 	log.Printf("DeleteBower: Attempting to delete bower: %s", bowerID)
 
-	err := h.bowerService.DeleteBower(r.Context(), user.UserID, bowerID)
+	result, err := h.bowerService.DeleteBower(r.Context(), user.UserID, bowerID)
 	if err != nil {
 		log.Printf("DeleteBower: Service error: %v", err)
 		if err.Error() == "access denied: not bower owner" {
@@ -285,7 +319,7 @@ func (h *BowerHandler) DeleteBower(w http.ResponseWriter, r *http.Request) {
 	}
 
 	log.Printf("DeleteBower: Successfully deleted bower: %s", bowerID)
-	response.NoContent(w)
+	response.Success(w, result)
 }
 
 // ListPublicBowers lists public bowers
@@ -341,6 +375,159 @@ func (h *BowerHandler) SearchBowers(w http.ResponseWriter, r *http.Request) {
 	response.Success(w, bowerResponses)
 }
 
+// ImportOPML imports bowers and feeds from an OPML document.
+// The document can be sent as the raw request body or as a multipart "file" upload.
+func (h *BowerHandler) ImportOPML(w http.ResponseWriter, r *http.Request) {
+	user, ok := GetRequiredUserFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	data, err := readOPMLBody(w, r)
+	if err != nil {
+		response.BadRequest(w, err.Error())
+		return
+	}
+
+	result, err := h.bowerService.ImportOPML(r.Context(), user.UserID, data)
+	if err != nil {
+		if strings.HasPrefix(err.Error(), "invalid OPML") {
+			response.BadRequest(w, err.Error())
+			return
+		}
+		response.InternalServerErrorWithErr(w, "Failed to import OPML", err)
+		return
+	}
+
+	response.Success(w, result)
+}
+
+// ExportOPML exports the user's bowers and feeds as an OPML file
+func (h *BowerHandler) ExportOPML(w http.ResponseWriter, r *http.Request) {
+	user, ok := GetRequiredUserFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	data, err := h.bowerService.ExportOPML(r.Context(), user.UserID)
+	if err != nil {
+		response.InternalServerErrorWithErr(w, "Failed to export OPML", err)
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/x-opml; charset=utf-8")
+	w.Header().Set("Content-Disposition", `attachment; filename="feed-bower.opml"`)
+	w.WriteHeader(http.StatusOK)
+	w.Write(data)
+}
+
+// FeedTokenResponse represents a newly issued output feed token with ready-to-use feed URLs
+type FeedTokenResponse struct {
+	Token   string `json:"token"`
+	AtomURL string `json:"atom_url"`
+	JSONURL string `json:"json_url"`
+}
+
+// RotateFeedToken issues a new secret token for the bower's output feeds, revoking any previous one
+func (h *BowerHandler) RotateFeedToken(w http.ResponseWriter, r *http.Request) {
+	user, ok := GetRequiredUserFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	bowerID := mux.Vars(r)["id"]
+	if bowerID == "" {
+		response.BadRequest(w, "Bower ID is required")
+		return
+	}
+
+	token, err := h.bowerService.RotateFeedToken(r.Context(), user.UserID, bowerID)
+	if err != nil {
+		if err.Error() == "access denied: not bower owner" {
+			response.Forbidden(w, err.Error())
+			return
+		}
+		response.InternalServerErrorWithErr(w, "Failed to rotate feed token", err)
+		return
+	}
+
+	feedURL := GetRequestBaseURL(r) + "/api/bowers/" + url.PathEscape(bowerID) + "/feed"
+	query := "?token=" + url.QueryEscape(token)
+	response.Created(w, &FeedTokenResponse{
+		Token:   token,
+		AtomURL: feedURL + ".atom" + query,
+		JSONURL: feedURL + ".json" + query,
+	})
+}
+
+// RevokeFeedToken revokes the bower's output feed token
+func (h *BowerHandler) RevokeFeedToken(w http.ResponseWriter, r *http.Request) {
+	user, ok := GetRequiredUserFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	bowerID := mux.Vars(r)["id"]
+	if bowerID == "" {
+		response.BadRequest(w, "Bower ID is required")
+		return
+	}
+
+	if err := h.bowerService.RevokeFeedToken(r.Context(), user.UserID, bowerID); err != nil {
+		if err.Error() == "access denied: not bower owner" {
+			response.Forbidden(w, err.Error())
+			return
+		}
+		response.InternalServerErrorWithErr(w, "Failed to revoke feed token", err)
+		return
+	}
+
+	response.NoContent(w)
+}
+
+// readOPMLBody reads an OPML document from a raw or multipart request body
+func readOPMLBody(w http.ResponseWriter, r *http.Request) ([]byte, error) {
+	const (
+		maxOPMLSize       = 1 << 20  // 1MB
+		maxMultipartExtra = 64 << 10 // Boundaries, part headers and other form fields
+	)
+
+	if strings.HasPrefix(r.Header.Get("Content-Type"), "multipart/form-data") {
+		// Cap the whole upload, since the parts past the memory limit are spooled to disk
+		r.Body = http.MaxBytesReader(w, r.Body, maxOPMLSize+maxMultipartExtra)
+		if err := r.ParseMultipartForm(maxOPMLSize); err != nil {
+			var maxBytesErr *http.MaxBytesError
+			if errors.As(err, &maxBytesErr) {
+				return nil, errors.New("OPML file too large")
+			}
+			return nil, errors.New("invalid multipart form")
+		}
+		file, _, err := r.FormFile("file")
+		if err != nil {
+			return nil, errors.New("OPML file is required")
+		}
+		defer file.Close()
+
+		data, err := io.ReadAll(io.LimitReader(file, maxOPMLSize+1))
+		if err != nil {
+			return nil, errors.New("failed to read OPML file")
+		}
+		if len(data) > maxOPMLSize {
+			return nil, errors.New("OPML file too large")
+		}
+		return data, nil
+	}
+
+	data, err := DefaultSecureBodyReader().ReadBody(r)
+	if err != nil {
+		if strings.Contains(err.Error(), "exceeds maximum size") {
+			return nil, errors.New("OPML file too large")
+		}
+		return nil, errors.New("failed to read request body")
+	}
+	return data, nil
+}
+
 // toBowerResponse converts a model.Bower to BowerResponse
 func (h *BowerHandler) toBowerResponse(bower *model.Bower) *BowerResponse {
 	feeds := make([]FeedResponse, len(bower.Feeds))
@@ -354,19 +541,29 @@ func (h *BowerHandler) toBowerResponse(bower *model.Bower) *BowerResponse {
 			Category:    feed.Category,
 			LastUpdated: feed.LastUpdated,
 			CreatedAt:   feed.CreatedAt,
+			FullText:    feed.FullText,
+
+			HealthStatus:        feed.GetHealthStatus(),
+			ConsecutiveFailures: feed.ConsecutiveFailures,
+			LastError:           feed.LastError,
+			LastErrorAt:         feed.LastErrorAt,
+			LastHTTPStatus:      feed.LastHTTPStatus,
+			NextFetchAt:         feed.NextFetchAt,
 		}
 	}
 
 	return &BowerResponse{
-		BowerID:   bower.BowerID,
-		UserID:    bower.UserID,
-		Name:      bower.Name,
-		Keywords:  bower.Keywords,
-		EggColors: bower.EggColors,
-		Color:     bower.Color,
-		IsPublic:  bower.IsPublic,
-		CreatedAt: bower.CreatedAt,
-		UpdatedAt: bower.UpdatedAt,
-		Feeds:     feeds,
+		BowerID:      bower.BowerID,
+		UserID:       bower.UserID,
+		Name:         bower.Name,
+		Keywords:     bower.Keywords,
+		EggColors:    bower.EggColors,
+		Color:        bower.Color,
+		IsPublic:     bower.IsPublic,
+		HasFeedToken: bower.HasFeedToken(),
+		CreatedAt:    bower.CreatedAt,
+		UpdatedAt:    bower.UpdatedAt,
+		Feeds:        feeds,
+		FilterRules:  bower.FilterRules,
 	}
 }
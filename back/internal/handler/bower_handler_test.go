@@ -0,0 +1,40 @@
+package handler
+
+import (
+	"bytes"
+	"mime/multipart"
+	"net/http/httptest"
+	"strings"
+	"testing"
+)
+
+func TestReadOPMLBody_Multipart(t *testing.T) {
+	upload := func(data []byte) ([]byte, error) {
+		var body bytes.Buffer
+		form := multipart.NewWriter(&body)
+		part, err := form.CreateFormFile("file", "feeds.opml")
+		if err != nil {
+			t.Fatalf("Failed to create form file: %v", err)
+		}
+		part.Write(data)
+		form.Close()
+
+		req := httptest.NewRequest("POST", "/api/bowers/import", &body)
+		req.Header.Set("Content-Type", form.FormDataContentType())
+		return readOPMLBody(httptest.NewRecorder(), req)
+	}
+
+	opml := []byte(`<opml version="2.0"><body><outline xmlUrl="https://example.com/feed.xml"/></body></opml>`)
+	data, err := upload(opml)
+	if err != nil {
+		t.Fatalf("readOPMLBody() unexpected error: %v", err)
+	}
+	if !bytes.Equal(data, opml) {
+		t.Errorf("Expected the uploaded file, got %q", data)
+	}
+
+	// Uploads past the limit are rejected before they are spooled to disk
+	if _, err := upload([]byte(strings.Repeat("a", 3<<20))); err == nil || err.Error() != "OPML file too large" {
+		t.Errorf("Expected a large upload to be rejected as too large, got %v", err)
+	}
+}
@@ -2,6 +2,7 @@ package handler
 
 import (
 	"net/http"
+	"strings"
 
 	"github.com/gorilla/mux"
 
@@ -32,6 +33,7 @@ func (h *FeedHandler) RegisterRoutes(router *mux.Router) {
 	feedRouter.HandleFunc("", h.ListFeeds).Methods("GET", "OPTIONS")
 	feedRouter.HandleFunc("", h.AddFeed).Methods("POST", "OPTIONS")
 	feedRouter.HandleFunc("/{id}", h.GetFeed).Methods("GET", "OPTIONS")
+	feedRouter.HandleFunc("/{id}", h.UpdateFeed).Methods("PUT", "OPTIONS")
 	feedRouter.HandleFunc("/{id}", h.DeleteFeed).Methods("DELETE", "OPTIONS")
 	feedRouter.HandleFunc("/{id}/preview", h.PreviewFeed).Methods("GET", "OPTIONS")
 	feedRouter.HandleFunc("/preview-url", h.PreviewFeedByURL).Methods("GET", "OPTIONS")
@@ -47,6 +49,15 @@ type AddFeedRequest struct {
 	URL     string `json:"url" validate:"required,url"`
 }
 
+// UpdateFeedRequest represents the request to update a bower's subscription to a feed
+type UpdateFeedRequest struct {
+	BowerID  string  `json:"bower_id,omitempty"`
+	URL      *string `json:"url,omitempty" validate:"omitempty,url"`
+	Title    *string `json:"title,omitempty" validate:"omitempty,min=1,max=200"`
+	Category *string `json:"category,omitempty" validate:"omitempty,max=50"`
+	FullText *bool   `json:"full_text,omitempty"`
+}
+
 // ValidateFeedURLRequest represents the request to validate a feed URL
 type ValidateFeedURLRequest struct {
 	URL string `json:"url" validate:"required,url"`
@@ -88,23 +99,32 @@ type Summary struct {
 
 // FeedPreviewResponse represents a feed preview
 type FeedPreviewResponse struct {
-	Feed     *FeedResponse     `json:"feed"`
-	Articles []ArticleResponse `json:"articles"`
+	Feed       *FeedResponse           `json:"feed"`
+	Articles   []ArticleResponse       `json:"articles"`
+	Candidates []service.FeedCandidate `json:"candidates,omitempty"`
 }
 
 // ArticleResponse represents an article in API responses
 type ArticleResponse struct {
-	ArticleID   string `json:"article_id"`
-	FeedID      string `json:"feed_id"`
-	Title       string `json:"title"`
-	Content     string `json:"content"`
-	URL         string `json:"url"`
-	ImageURL    string `json:"image_url,omitempty"`
-	PublishedAt int64  `json:"published_at"`
-	CreatedAt   int64  `json:"created_at"`
-	Bower       string `json:"bower,omitempty"`
-	Liked       bool   `json:"liked"`
-	Read        bool   `json:"read"`
+	ArticleID     string                  `json:"article_id"`
+	FeedID        string                  `json:"feed_id"`
+	SourceFeedIDs []string                `json:"source_feed_ids,omitempty"` // Other feeds carrying the same article
+	Title         string                  `json:"title"`
+	Content       string                  `json:"content"`
+	ContentHTML   string                  `json:"content_html,omitempty"` // Sanitized HTML from the feed
+	URL           string                  `json:"url"`
+	ImageURL      string                  `json:"image_url,omitempty"`
+	Author        string                  `json:"author,omitempty"`
+	Media         []model.ArticleMedia    `json:"media,omitempty"`   // Images, audio and video from the feed
+	Episode       *model.Episode          `json:"episode,omitempty"` // Set for podcast episodes
+	PublishedAt   int64                   `json:"published_at"`
+	CreatedAt     int64                   `json:"created_at"`
+	Bower         string                  `json:"bower,omitempty"`
+	Liked         bool                    `json:"liked"`
+	Read          bool                    `json:"read"`
+	Score         *model.ArticleScore     `json:"score,omitempty"`        // Explains the ranking on the important tab
+	Playback      *model.PlaybackPosition `json:"playback,omitempty"`     // Where the user left off a podcast episode
+	FullContent   string                  `json:"full_content,omitempty"` // Sanitized HTML of the article's page, with ?full=true
 }
 
 // AddFeed adds a new feed to a bower
@@ -205,6 +225,55 @@ func (h *FeedHandler) ListFeeds(w http.ResponseWriter, r *http.Request) {
 	response.Success(w, feedResponses)
 }
 
+// UpdateFeed updates a bower's subscription to a feed
+func (h *FeedHandler) UpdateFeed(w http.ResponseWriter, r *http.Request) {
+	user, ok := GetRequiredUserFromContext(w, r)
+	if !ok {
+		return
+	}
+
+	vars := mux.Vars(r)
+	feedID := vars["id"]
+	if feedID == "" {
+		response.BadRequest(w, "Feed ID is required")
+		return
+	}
+
+	var req UpdateFeedRequest
+	if !ParseJSONBodySecure(w, r, &req) {
+		return
+	}
+
+	if err := h.validator.Validate(&req); err != nil {
+		response.ValidationError(w, err.Error())
+		return
+	}
+
+	feed, err := h.feedService.UpdateFeed(r.Context(), user.UserID, feedID, &service.UpdateFeedRequest{
+		BowerID:  req.BowerID,
+		URL:      req.URL,
+		Title:    req.Title,
+		Category: req.Category,
+		FullText: req.FullText,
+	})
+	if err != nil {
+		if err.Error() == "access denied: not bower owner" {
+			response.Forbidden(w, err.Error())
+			return
+		}
+		if strings.HasPrefix(err.Error(), "bower ID is required") ||
+			strings.HasPrefix(err.Error(), "invalid feed URL") ||
+			strings.HasSuffix(err.Error(), "cannot be empty") {
+			response.BadRequest(w, err.Error())
+			return
+		}
+		response.InternalServerError(w, "Failed to update feed: "+err.Error())
+		return
+	}
+
+	response.Success(w, h.toFeedResponse(feed))
+}
+
 // DeleteFeed deletes a feed
 func (h *FeedHandler) DeleteFeed(w http.ResponseWriter, r *http.Request) {
 	user, ok := GetRequiredUserFromContext(w, r)
@@ -219,13 +288,17 @@ func (h *FeedHandler) DeleteFeed(w http.ResponseWriter, r *http.Request) {
 		return
 	}
 
-	err := h.feedService.DeleteFeed(r.Context(), user.UserID, feedID)
+	// Feeds are shared between bowers, so bower_id selects the subscription to remove
+	bowerID := GetQueryParam(r, "bower_id", "")
+
+	err := h.feedService.DeleteFeed(r.Context(), user.UserID, feedID, bowerID)
 	if err != nil {
 		if err.Error() == "access denied: not bower owner" {
 			response.Forbidden(w, err.Error())
 			return
 		}
-		if err.Error() == "cannot delete the last feed in a bower" {
+		if err.Error() == "cannot delete the last feed in a bower" ||
+			strings.HasPrefix(err.Error(), "bower ID is required") {
 			response.BadRequest(w, err.Error())
 			return
 		}
@@ -326,7 +399,8 @@ func (h *FeedHandler) PreviewFeedByURL(w http.ResponseWriter, r *http.Request) {
 			Description: preview.Feed.Description,
 			Category:    preview.Feed.Category,
 		},
-		Articles: articleResponses,
+		Articles:   articleResponses,
+		Candidates: preview.Candidates,
 	}
 
 	response.Success(w, previewResp)
@@ -465,6 +539,14 @@ func (h *FeedHandler) toFeedResponse(feed *model.Feed) *FeedResponse {
 		Category:    feed.Category,
 		LastUpdated: feed.LastUpdated,
 		CreatedAt:   feed.CreatedAt,
+		FullText:    feed.FullText,
+
+		HealthStatus:        feed.GetHealthStatus(),
+		ConsecutiveFailures: feed.ConsecutiveFailures,
+		LastError:           feed.LastError,
+		LastErrorAt:         feed.LastErrorAt,
+		LastHTTPStatus:      feed.LastHTTPStatus,
+		NextFetchAt:         feed.NextFetchAt,
 	}
 }
 
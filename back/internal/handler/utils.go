@@ -2,7 +2,9 @@ package handler
 
 import (
 	"context"
+	"encoding/base64"
 	"encoding/json"
+	"errors"
 	"fmt"
 	"io"
 	"net/http"
@@ -10,6 +12,9 @@ import (
 	"strings"
 	"time"
 
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
 	"feed-bower-api/internal/middleware"
 	"feed-bower-api/internal/model"
 	"feed-bower-api/pkg/response"
@@ -121,6 +126,63 @@ func GetQueryParamBool(r *http.Request, key string, defaultValue bool) bool {
 	return boolValue
 }
 
+// GetRequestBaseURL returns the scheme and host the client used to reach the API,
+// honoring X-Forwarded-Proto when running behind API Gateway or a proxy
+func GetRequestBaseURL(r *http.Request) string {
+	scheme := "http"
+	if r.TLS != nil {
+		scheme = "https"
+	}
+	if proto := r.Header.Get("X-Forwarded-Proto"); proto == "http" || proto == "https" {
+		scheme = proto
+	}
+	return scheme + "://" + r.Host
+}
+
+// EncodeCursor encodes a pagination key as an opaque string for clients. A nil key encodes
+// to an empty string, meaning there are no more pages.
+func EncodeCursor(lastKey map[string]types.AttributeValue) (string, error) {
+	if lastKey == nil {
+		return "", nil
+	}
+
+	var values map[string]interface{}
+	if err := attributevalue.UnmarshalMap(lastKey, &values); err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+
+	data, err := json.Marshal(values)
+	if err != nil {
+		return "", fmt.Errorf("failed to encode cursor: %w", err)
+	}
+
+	return base64.RawURLEncoding.EncodeToString(data), nil
+}
+
+// DecodeCursor decodes a cursor created by EncodeCursor. An empty cursor decodes to a nil key.
+func DecodeCursor(cursor string) (map[string]types.AttributeValue, error) {
+	if cursor == "" {
+		return nil, nil
+	}
+
+	data, err := base64.RawURLEncoding.DecodeString(cursor)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+
+	var values map[string]interface{}
+	if err := json.Unmarshal(data, &values); err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+
+	lastKey, err := attributevalue.MarshalMap(values)
+	if err != nil {
+		return nil, errors.New("invalid cursor")
+	}
+
+	return lastKey, nil
+}
+
 // SecureJSONParser provides secure JSON parsing with configurable limits
 type SecureJSONParser struct {
 	MaxBodySize int64 // Maximum request body size in bytes
@@ -4,9 +4,12 @@ import (
 	"io"
 	"net/http"
 	"net/http/httptest"
+	"reflect"
 	"strings"
 	"testing"
 	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 )
 
 func TestParseJSONBodySecure_ValidJSON(t *testing.T) {
@@ -270,3 +273,35 @@ func (sr *slowReader) Read(p []byte) (n int, err error) {
 func (sr *slowReader) Close() error {
 	return nil
 }
+
+func TestCursor_RoundTrip(t *testing.T) {
+	lastKey := map[string]types.AttributeValue{
+		"article_id":   &types.AttributeValueMemberS{Value: "article-1"},
+		"published_at": &types.AttributeValueMemberN{Value: "1767225600"},
+	}
+
+	cursor, err := EncodeCursor(lastKey)
+	if err != nil {
+		t.Fatalf("EncodeCursor() unexpected error: %v", err)
+	}
+
+	decoded, err := DecodeCursor(cursor)
+	if err != nil {
+		t.Fatalf("DecodeCursor() unexpected error: %v", err)
+	}
+	if !reflect.DeepEqual(decoded, lastKey) {
+		t.Errorf("Expected round trip to preserve the key, got %#v", decoded)
+	}
+
+	if cursor, _ := EncodeCursor(nil); cursor != "" {
+		t.Errorf("Expected empty cursor for the last page, got %q", cursor)
+	}
+	if key, err := DecodeCursor(""); key != nil || err != nil {
+		t.Errorf("Expected nil key for an empty cursor, got %v, %v", key, err)
+	}
+	for _, invalid := range []string{"not base64!", "bm90IGpzb24"} {
+		if _, err := DecodeCursor(invalid); err == nil {
+			t.Errorf("Expected error for invalid cursor %q", invalid)
+		}
+	}
+}
@@ -0,0 +1,107 @@
+package handler
+
+import (
+	"log"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/gorilla/mux"
+
+	"feed-bower-api/internal/service"
+	"feed-bower-api/pkg/response"
+)
+
+// maxWebSubContentSize is the largest content accepted from a WebSub hub
+const maxWebSubContentSize = 10 * 1024 * 1024
+
+// WebSubHandler handles callbacks from WebSub hubs. Hubs are not users, so these routes
+// skip authentication: verifications are checked against the requested subscription and
+// pushed content against its signature.
+type WebSubHandler struct {
+	webSubService service.WebSubService
+}
+
+// NewWebSubHandler creates a new WebSub handler
+func NewWebSubHandler(webSubService service.WebSubService) *WebSubHandler {
+	return &WebSubHandler{
+		webSubService: webSubService,
+	}
+}
+
+// RegisterRoutes registers WebSub routes
+func (h *WebSubHandler) RegisterRoutes(router *mux.Router) {
+	router.HandleFunc(service.WebSubCallbackPath+"{feed_id}", h.VerifyIntent).Methods("GET")
+	router.HandleFunc(service.WebSubCallbackPath+"{feed_id}", h.ReceiveContent).Methods("POST")
+}
+
+// VerifyIntent answers a hub's verification of a subscription by echoing its challenge
+func (h *WebSubHandler) VerifyIntent(w http.ResponseWriter, r *http.Request) {
+	feedID := mux.Vars(r)["feed_id"]
+
+	query := r.URL.Query()
+	leaseSeconds, _ := strconv.ParseInt(query.Get("hub.lease_seconds"), 10, 64)
+	challenge, err := h.webSubService.VerifyIntent(r.Context(), feedID, &service.WebSubVerification{
+		Mode:         query.Get("hub.mode"),
+		Topic:        query.Get("hub.topic"),
+		Challenge:    query.Get("hub.challenge"),
+		LeaseSeconds: leaseSeconds,
+		Reason:       query.Get("hub.reason"),
+	})
+	if err != nil {
+		switch {
+		case err.Error() == "WebSub subscription not requested":
+			response.NotFound(w, err.Error())
+		case err.Error() == "hub.challenge is required", strings.HasPrefix(err.Error(), "invalid hub.mode"):
+			response.BadRequest(w, err.Error())
+		default:
+			response.InternalServerError(w, "Failed to verify WebSub subscription: "+err.Error())
+		}
+		return
+	}
+
+	w.Header().Set("Content-Type", "text/plain; charset=utf-8")
+	w.WriteHeader(http.StatusOK)
+	w.Write([]byte(challenge))
+}
+
+// ReceiveContent saves the new articles of content pushed by a hub
+func (h *WebSubHandler) ReceiveContent(w http.ResponseWriter, r *http.Request) {
+	feedID := mux.Vars(r)["feed_id"]
+
+	reader := &SecureBodyReader{MaxSize: maxWebSubContentSize, MaxReadTime: 30 * time.Second}
+	body, err := reader.ReadBody(r)
+	if err != nil {
+		if strings.Contains(err.Error(), "exceeds maximum size") {
+			response.Error(w, http.StatusRequestEntityTooLarge, "PAYLOAD_TOO_LARGE", "Content too large")
+			return
+		}
+		response.BadRequest(w, "Failed to read request body")
+		return
+	}
+
+	result, err := h.webSubService.ReceiveContent(r.Context(), feedID, &service.WebSubDelivery{
+		ContentType: r.Header.Get("Content-Type"),
+		Signature:   r.Header.Get("X-Hub-Signature"),
+		Body:        body,
+	})
+	if err != nil {
+		switch {
+		case err.Error() == "unknown WebSub feed":
+			// Gone tells the hub to stop pushing the feed
+			response.Error(w, http.StatusGone, "GONE", "Feed no longer exists")
+		case err.Error() == "invalid WebSub signature":
+			// Content that fails verification is acknowledged but ignored, as the spec requires
+			log.Printf("⚠️  Ignoring WebSub content with an invalid signature for feed %s", feedID)
+			w.WriteHeader(http.StatusAccepted)
+		case strings.HasPrefix(err.Error(), "failed to parse pushed content"):
+			response.BadRequest(w, err.Error())
+		default:
+			response.InternalServerError(w, "Failed to save pushed content: "+err.Error())
+		}
+		return
+	}
+
+	response.Success(w, result)
+}
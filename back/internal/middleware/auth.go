@@ -5,6 +5,7 @@ import (
 	"encoding/json"
 	"log"
 	"net/http"
+	"path"
 	"strings"
 
 	"feed-bower-api/internal/service"
@@ -13,7 +14,7 @@ import (
 // AuthConfig holds authentication configuration
 type AuthConfig struct {
 	AuthService service.AuthService
-	SkipPaths   []string // Paths that don't require authentication
+	SkipPaths   []string // Path prefixes, or path.Match patterns such as "/api/bowers/*/feed.atom", that don't require authentication
 }
 
 // UserContextKey is the key for storing user in context
@@ -163,10 +164,17 @@ func OptionalAuth(config *AuthConfig) func(http.Handler) http.Handler {
 	}
 }
 
-// shouldSkipAuth checks if the given path should skip authentication
-func shouldSkipAuth(path string, skipPaths []string) bool {
+// shouldSkipAuth checks if the given path should skip authentication.
+// Skip paths containing wildcards are matched as whole-path patterns; all others are prefixes.
+func shouldSkipAuth(requestPath string, skipPaths []string) bool {
 	for _, skipPath := range skipPaths {
-		if strings.HasPrefix(path, skipPath) {
+		if strings.ContainsAny(skipPath, "*?[") {
+			if matched, err := path.Match(skipPath, requestPath); err == nil && matched {
+				return true
+			}
+			continue
+		}
+		if strings.HasPrefix(requestPath, skipPath) {
 			return true
 		}
 	}
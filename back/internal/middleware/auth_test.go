@@ -142,6 +142,28 @@ func TestAuth_SkipPath(t *testing.T) {
 	}
 }
 
+func TestShouldSkipAuth_Patterns(t *testing.T) {
+	skipPaths := []string{"/health", "/api/bowers/*/feed.atom"}
+
+	tests := []struct {
+		path     string
+		expected bool
+	}{
+		{"/health", true},
+		{"/api/bowers/abc/feed.atom", true},
+		{"/api/bowers/abc/feed.json", false},
+		{"/api/bowers/abc", false},
+		{"/api/bowers/abc/def/feed.atom", false},
+		{"/api/bowers/abc/feed.atom/extra", false},
+	}
+
+	for _, tt := range tests {
+		if got := shouldSkipAuth(tt.path, skipPaths); got != tt.expected {
+			t.Errorf("shouldSkipAuth(%q) = %v, expected %v", tt.path, got, tt.expected)
+		}
+	}
+}
+
 // Helper function to get user from context (same as in utils.go)
 func GetUserFromContext(ctx context.Context) (*model.User, bool) {
 	user, ok := ctx.Value(UserKey).(*model.User)
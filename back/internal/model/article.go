@@ -4,21 +4,93 @@ import (
 	"time"
 )
 
+// articleAliasSeparator separates the canonical article ID and the feed ID in alias IDs
+const articleAliasSeparator = "#"
+
 // Article represents a single article from an RSS/Atom feed
 type Article struct {
-	ArticleID   string  `json:"article_id" dynamodbav:"article_id" validate:"required"`
-	FeedID      string  `json:"feed_id" dynamodbav:"feed_id" validate:"required"`
-	Title       string  `json:"title" dynamodbav:"title" validate:"required,min=1,max=500"`
-	Content     string  `json:"content" dynamodbav:"content" validate:"max=10000"`
-	URL         string  `json:"url" dynamodbav:"url" validate:"required,url"`
-	ImageURL    *string `json:"image_url,omitempty" dynamodbav:"image_url,omitempty" validate:"omitempty,url"`
-	PublishedAt int64   `json:"published_at" dynamodbav:"published_at" validate:"required"`
-	CreatedAt   int64   `json:"created_at" dynamodbav:"created_at"`
+	ArticleID   string         `json:"article_id" dynamodbav:"article_id" validate:"required"`
+	FeedID      string         `json:"feed_id" dynamodbav:"feed_id" validate:"required"`
+	Title       string         `json:"title" dynamodbav:"title" validate:"required,min=1,max=500"`
+	Content     string         `json:"content" dynamodbav:"content" validate:"max=10000"`          // Plain-text summary
+	ContentHTML string         `json:"content_html,omitempty" dynamodbav:"content_html,omitempty"` // Sanitized HTML from the feed
+	URL         string         `json:"url" dynamodbav:"url" validate:"required,url"`
+	ImageURL    *string        `json:"image_url,omitempty" dynamodbav:"image_url,omitempty" validate:"omitempty,url"`
+	Author      string         `json:"author,omitempty" dynamodbav:"author,omitempty"`   // Author names, comma separated
+	Media       []ArticleMedia `json:"media,omitempty" dynamodbav:"media,omitempty"`     // Images, audio and video from the feed
+	Episode     *Episode       `json:"episode,omitempty" dynamodbav:"episode,omitempty"` // Set for podcast episodes
+	PublishedAt int64          `json:"published_at" dynamodbav:"published_at" validate:"required"`
+	CreatedAt   int64          `json:"created_at" dynamodbav:"created_at"`
+
+	// Sanitized HTML of the main content of the article's page, for feeds in full text mode
+	FullContent string `json:"-" dynamodbav:"full_content,omitempty"`
+
+	// Deduplication: near-duplicates from other feeds are merged into one article whose
+	// SourceFeedIDs lists the other feeds. Each of those feeds gets an alias item pointing
+	// at the article through DuplicateOf, so that it is listed under the feed.
+	CanonicalURL  string   `json:"canonical_url,omitempty" dynamodbav:"canonical_url,omitempty"`
+	TitleHash     string   `json:"-" dynamodbav:"title_hash,omitempty"`
+	ContentHash   string   `json:"-" dynamodbav:"content_hash,omitempty"`
+	SourceFeedIDs []string `json:"source_feed_ids,omitempty" dynamodbav:"source_feed_ids,stringset,omitempty"`
+	DuplicateOf   string   `json:"-" dynamodbav:"duplicate_of,omitempty"`
 
 	// These fields are computed/joined from other tables and not stored in Articles table
-	Liked bool   `json:"liked" dynamodbav:"-"`
-	Bower string `json:"bower" dynamodbav:"-"`
-	Read  bool   `json:"read" dynamodbav:"-"`
+	Liked bool          `json:"liked" dynamodbav:"-"`
+	Bower string        `json:"bower" dynamodbav:"-"`
+	Read  bool          `json:"read" dynamodbav:"-"`
+	Score *ArticleScore `json:"score,omitempty" dynamodbav:"-"` // Set on the important tab
+
+	Playback *PlaybackPosition `json:"playback,omitempty" dynamodbav:"-"` // Set for podcast episodes the user has played
+}
+
+// Media kinds
+const (
+	MediumImage    = "image"
+	MediumAudio    = "audio"
+	MediumVideo    = "video"
+	MediumDocument = "document"
+)
+
+// ArticleMedia is an image, audio or video file attached to an article by its feed
+type ArticleMedia struct {
+	URL    string `json:"url" dynamodbav:"url"`
+	Type   string `json:"type,omitempty" dynamodbav:"type,omitempty"` // MIME type
+	Medium string `json:"medium" dynamodbav:"medium"`                 // One of the Medium constants
+	Size   int64  `json:"size,omitempty" dynamodbav:"size,omitempty"` // Bytes
+	Width  int    `json:"width,omitempty" dynamodbav:"width,omitempty"`
+	Height int    `json:"height,omitempty" dynamodbav:"height,omitempty"`
+
+	Duration int64 `json:"duration,omitempty" dynamodbav:"duration,omitempty"` // Seconds, for audio and video
+}
+
+// Podcast episode types
+const (
+	EpisodeTypeFull    = "full"
+	EpisodeTypeTrailer = "trailer"
+	EpisodeTypeBonus   = "bonus"
+)
+
+// Episode holds the podcast metadata of an article with an audio or video enclosure
+type Episode struct {
+	Duration int64  `json:"duration,omitempty" dynamodbav:"duration,omitempty"` // Seconds
+	Season   int    `json:"season,omitempty" dynamodbav:"season,omitempty"`
+	Number   int    `json:"number,omitempty" dynamodbav:"number,omitempty"`
+	Type     string `json:"type,omitempty" dynamodbav:"type,omitempty"` // One of the EpisodeType constants
+	Explicit bool   `json:"explicit,omitempty" dynamodbav:"explicit,omitempty"`
+}
+
+// ArticleScore explains how important an article is estimated to be for a user
+type ArticleScore struct {
+	Total   float64       `json:"total"` // Weighted sum of the factor values, from 0 to 1
+	Factors []ScoreFactor `json:"factors"`
+}
+
+// ScoreFactor is one signal contributing to an article's score
+type ScoreFactor struct {
+	Name   string  `json:"name"`
+	Value  float64 `json:"value"` // Strength of the signal, from 0 to 1
+	Weight float64 `json:"weight"`
+	Reason string  `json:"reason"`
 }
 
 // NewArticle creates a new Article instance with current timestamps
@@ -36,6 +108,58 @@ func NewArticle(feedID, title, content, url string, publishedAt time.Time) *Arti
 	}
 }
 
+// NewArticleAlias creates the alias item that lists an article under another feed.
+// Aliases carry no dedup keys, so they never match duplicate lookups themselves.
+func NewArticleAlias(article *Article, feedID string) *Article {
+	return &Article{
+		ArticleID:   ArticleAliasID(article.ArticleID, feedID),
+		FeedID:      feedID,
+		Title:       article.Title,
+		URL:         article.URL,
+		PublishedAt: article.PublishedAt,
+		CreatedAt:   time.Now().Unix(),
+		DuplicateOf: article.ArticleID,
+	}
+}
+
+// ArticleAliasID returns the ID of the alias item listing an article under a feed
+func ArticleAliasID(articleID, feedID string) string {
+	return articleID + articleAliasSeparator + feedID
+}
+
+// IsAlias checks if the item is an alias of an article from another feed
+func (a *Article) IsAlias() bool {
+	return a.DuplicateOf != ""
+}
+
+// FeedIDs returns the feed the article was first stored for, followed by the feeds it was merged from
+func (a *Article) FeedIDs() []string {
+	return append([]string{a.FeedID}, a.SourceFeedIDs...)
+}
+
+// HasFeed checks if the article belongs to a feed, directly or as a merged duplicate
+func (a *Article) HasFeed(feedID string) bool {
+	if a.FeedID == feedID {
+		return true
+	}
+	for _, sourceFeedID := range a.SourceFeedIDs {
+		if sourceFeedID == feedID {
+			return true
+		}
+	}
+	return false
+}
+
+// EpisodeMedia returns the audio or video file of a podcast episode, or nil if there is none
+func (a *Article) EpisodeMedia() *ArticleMedia {
+	for i := range a.Media {
+		if a.Media[i].Medium == MediumAudio || a.Media[i].Medium == MediumVideo {
+			return &a.Media[i]
+		}
+	}
+	return nil
+}
+
 // SetImageURL sets the image URL for the article
 func (a *Article) SetImageURL(imageURL string) {
 	if imageURL != "" {
@@ -1,6 +1,7 @@
 package model
 
 import (
+	"crypto/subtle"
 	"time"
 )
 
@@ -22,10 +23,32 @@ type Bower struct {
 	Likes       *int     `json:"likes,omitempty" dynamodbav:"likes,omitempty"`
 	LikedBy     []string `json:"liked_by,omitempty" dynamodbav:"liked_by,omitempty"`
 
+	// Rules narrowing down the articles listed in the bower; nil lists every article
+	FilterRules *FilterRules `json:"filter_rules,omitempty" dynamodbav:"filter_rules,omitempty"`
+
+	// Secret token granting read access to the bower's output feed; never serialized to clients
+	FeedToken string `json:"-" dynamodbav:"feed_token,omitempty"`
+
 	// Feeds are not stored in the bower table but retrieved via relationship
 	Feeds []Feed `json:"feeds,omitempty" dynamodbav:"-"`
 }
 
+// FilterRules select the articles of a bower's feeds that are listed in the bower.
+// Patterns are phrases matched case-insensitively against an article's title and content,
+// or regular expressions when written between slashes, e.g. "/^\[PR\]/".
+type FilterRules struct {
+	Include       []string `json:"include,omitempty" dynamodbav:"include,omitempty"`             // Articles must match one of these, if any
+	Exclude       []string `json:"exclude,omitempty" dynamodbav:"exclude,omitempty"`             // Articles must match none of these
+	MutedAuthors  []string `json:"muted_authors,omitempty" dynamodbav:"muted_authors,omitempty"` // Author names, case-insensitive
+	MutedDomains  []string `json:"muted_domains,omitempty" dynamodbav:"muted_domains,omitempty"` // Article URL hosts, including subdomains
+	ApplyAtIngest bool     `json:"apply_at_ingest" dynamodbav:"apply_at_ingest"`                 // Skip storing articles no subscribing bower lists
+}
+
+// IsEmpty checks if the rules filter nothing
+func (r *FilterRules) IsEmpty() bool {
+	return r == nil || len(r.Include)+len(r.Exclude)+len(r.MutedAuthors)+len(r.MutedDomains) == 0
+}
+
 // NewBower creates a new Bower instance with current timestamps
 func NewBower(userID, name string, keywords []string, eggColors []string, color string, isPublic bool) *Bower {
 	now := time.Now().Unix()
@@ -85,3 +108,22 @@ func (b *Bower) HasKeyword(keyword string) bool {
 	}
 	return false
 }
+
+// HasFeedToken reports whether an output feed token has been issued
+func (b *Bower) HasFeedToken() bool {
+	return b.FeedToken != ""
+}
+
+// CheckFeedToken reports whether token matches the bower's output feed token
+func (b *Bower) CheckFeedToken(token string) bool {
+	if b.FeedToken == "" || token == "" {
+		return false
+	}
+	return subtle.ConstantTimeCompare([]byte(b.FeedToken), []byte(token)) == 1
+}
+
+// CanReadOutputFeed reports whether the output feed may be served for the given token.
+// Public bowers are always readable; private bowers require a matching feed token.
+func (b *Bower) CanReadOutputFeed(token string) bool {
+	return b.IsPublic || b.CheckFeedToken(token)
+}
@@ -1,22 +1,61 @@
 package model
 
 import (
+	"net/url"
+	"strings"
 	"time"
+
+	"github.com/google/uuid"
 )
 
-// Feed represents an RSS/Atom feed
+// Feed represents an RSS/Atom feed. Feeds are canonical: there is one per URL, shared by every
+// bower that subscribes to it, so each URL is fetched once.
 type Feed struct {
 	FeedID      string `json:"feed_id" dynamodbav:"feed_id" validate:"required"`
-	BowerID     string `json:"bower_id" dynamodbav:"bower_id" validate:"required"`
+	BowerID     string `json:"bower_id,omitempty" dynamodbav:"-"`  // Set when the feed is read through a bower's subscription
+	FullText    bool   `json:"full_text,omitempty" dynamodbav:"-"` // Set when the bower's subscription extracts full article content
 	URL         string `json:"url" dynamodbav:"url" validate:"required,url"`
 	Title       string `json:"title" dynamodbav:"title" validate:"required,min=1,max=200"`
 	Description string `json:"description" dynamodbav:"description" validate:"max=1000"`
 	Category    string `json:"category" dynamodbav:"category" validate:"max=50"`
 	LastUpdated int64  `json:"last_updated" dynamodbav:"last_updated"`
 	CreatedAt   int64  `json:"created_at" dynamodbav:"created_at"`
+
+	// Number of bowers subscribed to the feed; feeds without subscribers are not fetched
+	SubscriberCount int `json:"-" dynamodbav:"subscriber_count"`
+
+	// Bower that owned the feed before feeds were shared, until the feed migration turns it into a subscription
+	LegacyBowerID string `json:"-" dynamodbav:"bower_id,omitempty"`
+
+	// HTTP cache validators from the last successful fetch (used for conditional GET)
+	ETag         string `json:"etag,omitempty" dynamodbav:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty" dynamodbav:"last_modified,omitempty"`
+
+	// Adaptive polling schedule
+	NextFetchAt   int64    `json:"next_fetch_at,omitempty" dynamodbav:"next_fetch_at,omitempty"`
+	FetchInterval int64    `json:"fetch_interval,omitempty" dynamodbav:"fetch_interval,omitempty"` // Learned interval in seconds
+	TTL           int      `json:"ttl,omitempty" dynamodbav:"feed_ttl,omitempty"`                  // RSS <ttl> in minutes
+	SkipHours     []int    `json:"skip_hours,omitempty" dynamodbav:"skip_hours,omitempty"`         // RSS <skipHours> (GMT)
+	SkipDays      []string `json:"skip_days,omitempty" dynamodbav:"skip_days,omitempty"`           // RSS <skipDays>
+
+	// Fetch health
+	HealthStatus        string `json:"health_status,omitempty" dynamodbav:"health_status,omitempty"`
+	ConsecutiveFailures int    `json:"consecutive_failures,omitempty" dynamodbav:"consecutive_failures,omitempty"`
+	LastError           string `json:"last_error,omitempty" dynamodbav:"last_error,omitempty"`
+	LastErrorAt         int64  `json:"last_error_at,omitempty" dynamodbav:"last_error_at,omitempty"`
+	LastHTTPStatus      int    `json:"last_http_status,omitempty" dynamodbav:"last_http_status,omitempty"`
+
+	// WebSub push subscription (https://www.w3.org/TR/websub/)
+	HubURL               string `json:"hub_url,omitempty" dynamodbav:"hub_url,omitempty"`
+	TopicURL             string `json:"topic_url,omitempty" dynamodbav:"topic_url,omitempty"` // URL the hub knows the feed by
+	WebSubSecret         string `json:"-" dynamodbav:"websub_secret,omitempty"`               // Key of the signatures of pushed content
+	WebSubState          string `json:"websub_state,omitempty" dynamodbav:"websub_state,omitempty"`
+	WebSubRequestedAt    int64  `json:"websub_requested_at,omitempty" dynamodbav:"websub_requested_at,omitempty"`
+	WebSubLeaseExpiresAt int64  `json:"websub_lease_expires_at,omitempty" dynamodbav:"websub_lease_expires_at,omitempty"`
 }
 
-// NewFeed creates a new Feed instance with current timestamps
+// NewFeed creates a new Feed instance with current timestamps.
+// bowerID may be empty when creating a canonical feed that is not yet subscribed.
 func NewFeed(bowerID, url, title, description, category string) *Feed {
 	now := time.Now().Unix()
 	return &Feed{
@@ -30,11 +69,238 @@ func NewFeed(bowerID, url, title, description, category string) *Feed {
 	}
 }
 
+// CanonicalFeedID returns the ID of the canonical feed for a URL. IDs are derived from the URL, so
+// that concurrent creations of the feed for the same URL collide instead of creating duplicates.
+func CanonicalFeedID(feedURL string) string {
+	return uuid.NewSHA1(uuid.NameSpaceURL, []byte(normalizeFeedURL(feedURL))).String()
+}
+
+// normalizeFeedURL lowercases the scheme and host of a feed URL and drops its fragment and default
+// port, which never change the feed served. URLs that cannot be parsed are only trimmed.
+func normalizeFeedURL(feedURL string) string {
+	trimmed := strings.TrimSpace(feedURL)
+	u, err := url.Parse(trimmed)
+	if err != nil || u.Host == "" {
+		return trimmed
+	}
+
+	u.Scheme = strings.ToLower(u.Scheme)
+	host := strings.ToLower(u.Hostname())
+	if port := u.Port(); port != "" && !(u.Scheme == "http" && port == "80") && !(u.Scheme == "https" && port == "443") {
+		host += ":" + port
+	} else if strings.Contains(host, ":") {
+		host = "[" + host + "]" // IPv6
+	}
+	u.Host = host
+	u.Fragment = ""
+	u.RawFragment = ""
+
+	return u.String()
+}
+
 // UpdateLastUpdated updates the LastUpdated field to current time
 func (f *Feed) UpdateLastUpdated() {
 	f.LastUpdated = time.Now().Unix()
 }
 
+// SetCacheValidators stores the ETag and Last-Modified values returned by the feed server.
+// It returns true if either value changed.
+func (f *Feed) SetCacheValidators(etag, lastModified string) bool {
+	changed := f.ETag != etag || f.LastModified != lastModified
+	f.ETag = etag
+	f.LastModified = lastModified
+	return changed
+}
+
+// IsDue checks if the feed should be fetched at the given time.
+// Dead feeds are only due once DeadFeedProbeInterval after their last failure.
+func (f *Feed) IsDue(now time.Time) bool {
+	return f.NextFetchAt == 0 || now.Unix() >= f.NextFetchAt
+}
+
+// GetFetchInterval returns the learned polling interval, or the default if none has been learned yet
+func (f *Feed) GetFetchInterval() time.Duration {
+	if f.FetchInterval <= 0 {
+		return DefaultFetchInterval * time.Second
+	}
+	return time.Duration(f.FetchInterval) * time.Second
+}
+
+// SetPollingHints stores the polling hints published by the feed itself
+func (f *Feed) SetPollingHints(ttl int, skipHours []int, skipDays []string) {
+	f.TTL = ttl
+	f.SkipHours = skipHours
+	f.SkipDays = skipDays
+}
+
+// ScheduleNextFetch records the polling interval and sets the next fetch time.
+// The next fetch is never earlier than notBefore and is moved out of any skipped hours or days.
+func (f *Feed) ScheduleNextFetch(now time.Time, interval time.Duration, notBefore time.Time) {
+	f.FetchInterval = int64(interval / time.Second)
+	f.setNextFetch(now.Add(interval), notBefore)
+}
+
+// ScheduleRetry sets the next fetch time after a failed fetch without changing the learned interval
+func (f *Feed) ScheduleRetry(now time.Time, backoff time.Duration, notBefore time.Time) {
+	f.setNextFetch(now.Add(backoff), notBefore)
+}
+
+// setNextFetch sets NextFetchAt to next (or notBefore if later), moved out of skipped hours and days
+func (f *Feed) setNextFetch(next, notBefore time.Time) {
+	if next.Before(notBefore) {
+		next = notBefore
+	}
+
+	// Move forward hour by hour until we're outside skipHours/skipDays (bounded to one week)
+	for i := 0; i < 7*24 && f.isSkipped(next); i++ {
+		next = next.UTC().Truncate(time.Hour).Add(time.Hour)
+	}
+
+	f.NextFetchAt = next.Unix()
+}
+
+// isSkipped checks if the given time falls within the feed's skipHours or skipDays
+func (f *Feed) isSkipped(t time.Time) bool {
+	t = t.UTC()
+	for _, hour := range f.SkipHours {
+		if t.Hour() == hour {
+			return true
+		}
+	}
+	for _, day := range f.SkipDays {
+		if strings.EqualFold(t.Weekday().String(), strings.TrimSpace(day)) {
+			return true
+		}
+	}
+	return false
+}
+
+// RecordFetchSuccess resets the failure count after a successful fetch
+func (f *Feed) RecordFetchSuccess(httpStatus int) {
+	f.HealthStatus = FeedHealthActive
+	f.ConsecutiveFailures = 0
+	f.LastHTTPStatus = httpStatus
+}
+
+// RecordFetchFailure records a failed fetch and marks the feed dead after MaxConsecutiveFailures
+func (f *Feed) RecordFetchFailure(errMsg string, httpStatus int, now time.Time) {
+	f.ConsecutiveFailures++
+	f.LastError = errMsg
+	f.LastErrorAt = now.Unix()
+	f.LastHTTPStatus = httpStatus
+
+	if f.ConsecutiveFailures >= MaxConsecutiveFailures {
+		f.HealthStatus = FeedHealthDead
+	} else {
+		f.HealthStatus = FeedHealthFailing
+	}
+}
+
+// FailureBackoff returns the exponential retry delay for the current failure count,
+// or DeadFeedProbeInterval once the feed is dead
+func (f *Feed) FailureBackoff() time.Duration {
+	if f.IsDead() {
+		return time.Duration(DeadFeedProbeInterval) * time.Second
+	}
+
+	backoff := time.Duration(MinFetchInterval) * time.Second
+	maxBackoff := time.Duration(MaxFetchInterval) * time.Second
+	for i := 1; i < f.ConsecutiveFailures && backoff < maxBackoff; i++ {
+		backoff *= 2
+	}
+	if backoff > maxBackoff {
+		backoff = maxBackoff
+	}
+	return backoff
+}
+
+// ResetHealth clears the feed's failures so that it is fetched on the next run
+func (f *Feed) ResetHealth() {
+	f.HealthStatus = FeedHealthActive
+	f.ConsecutiveFailures = 0
+	f.NextFetchAt = 0
+}
+
+// IsDead checks if the feed has been disabled after repeated failures
+func (f *Feed) IsDead() bool {
+	return f.HealthStatus == FeedHealthDead
+}
+
+// GetHealthStatus returns the feed's health status, treating feeds that were never fetched as active
+func (f *Feed) GetHealthStatus() string {
+	if f.HealthStatus == "" {
+		return FeedHealthActive
+	}
+	return f.HealthStatus
+}
+
+// SetWebSubHub stores the WebSub hub the feed advertises and its topic, the feed's self URL or,
+// without one, the URL it is fetched from. A different hub or topic starts a new subscription.
+func (f *Feed) SetWebSubHub(hubURL, selfURL string) {
+	topicURL := selfURL
+	if topicURL == "" {
+		topicURL = f.URL
+	}
+	if hubURL == "" {
+		topicURL = ""
+	}
+	if hubURL == f.HubURL && topicURL == f.TopicURL {
+		return
+	}
+
+	f.HubURL = hubURL
+	f.TopicURL = topicURL
+	f.WebSubSecret = ""
+	f.WebSubState = ""
+	f.WebSubRequestedAt = 0
+	f.WebSubLeaseExpiresAt = 0
+}
+
+// NeedsWebSubSubscription checks if the feed's hub should be asked to push its content: the feed
+// has not been subscribed yet, its lease is about to expire, or the last request went unanswered
+// or was refused. Requests are not repeated within WebSubRetryInterval.
+func (f *Feed) NeedsWebSubSubscription(now time.Time) bool {
+	if f.HubURL == "" || f.IsDead() {
+		return false
+	}
+	if now.Unix() < f.WebSubRequestedAt+WebSubRetryInterval {
+		return false
+	}
+	if f.WebSubState == WebSubStateSubscribed {
+		return now.Unix() >= f.WebSubLeaseExpiresAt-WebSubRenewBefore
+	}
+	return true
+}
+
+// RequestWebSub records that the hub was asked to subscribe. A subscription being renewed
+// stays active until the hub verifies the renewal.
+func (f *Feed) RequestWebSub(now time.Time) {
+	f.WebSubRequestedAt = now.Unix()
+	if !f.HasActiveWebSub(now) {
+		f.WebSubState = WebSubStatePending
+	}
+}
+
+// ConfirmWebSub records the hub's verification of the subscription, with the lease it granted.
+// A lease of 0 means the one requested.
+func (f *Feed) ConfirmWebSub(leaseSeconds int64, now time.Time) {
+	if leaseSeconds <= 0 {
+		leaseSeconds = WebSubLeaseSeconds
+	}
+	f.WebSubState = WebSubStateSubscribed
+	f.WebSubLeaseExpiresAt = now.Unix() + leaseSeconds
+}
+
+// HasActiveWebSub checks if the hub pushes the feed's new content
+func (f *Feed) HasActiveWebSub(now time.Time) bool {
+	return f.WebSubState == WebSubStateSubscribed && now.Unix() < f.WebSubLeaseExpiresAt
+}
+
+// GetNextFetchTime returns the NextFetchAt timestamp as time.Time
+func (f *Feed) GetNextFetchTime() time.Time {
+	return time.Unix(f.NextFetchAt, 0)
+}
+
 // IsStale checks if the feed hasn't been updated for more than the specified duration
 func (f *Feed) IsStale(maxAge time.Duration) bool {
 	lastUpdate := time.Unix(f.LastUpdated, 0)
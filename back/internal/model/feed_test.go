@@ -0,0 +1,221 @@
+package model
+
+import (
+	"testing"
+	"time"
+)
+
+func TestFeed_SetCacheValidators(t *testing.T) {
+	feed := NewFeed("bower-1", "https://example.com/feed.xml", "Test Feed", "", "")
+
+	if !feed.SetCacheValidators(`"v1"`, "Mon, 01 Jan 2024 00:00:00 GMT") {
+		t.Error("Expected validators to be reported as changed")
+	}
+	if feed.SetCacheValidators(`"v1"`, "Mon, 01 Jan 2024 00:00:00 GMT") {
+		t.Error("Expected unchanged validators to be reported as unchanged")
+	}
+	if feed.ETag != `"v1"` {
+		t.Errorf("Expected ETag \"v1\", got %s", feed.ETag)
+	}
+}
+
+func TestCanonicalFeedID(t *testing.T) {
+	id := CanonicalFeedID("https://Example.com:443/feed.xml#top")
+	if id != CanonicalFeedID(" https://example.com/feed.xml") {
+		t.Error("Expected URLs differing only in host case, default port and fragment to share an ID")
+	}
+	if id == CanonicalFeedID("https://example.com/other.xml") || id == CanonicalFeedID("http://example.com/feed.xml") {
+		t.Error("Expected different feed URLs to have different IDs")
+	}
+}
+
+func TestFeed_IsDue(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	feed := NewFeed("bower-1", "https://example.com/feed.xml", "Test Feed", "", "")
+
+	if !feed.IsDue(now) {
+		t.Error("New feed should be due")
+	}
+
+	feed.NextFetchAt = now.Add(time.Hour).Unix()
+	if feed.IsDue(now) {
+		t.Error("Feed scheduled in the future should not be due")
+	}
+
+	feed.NextFetchAt = now.Add(-time.Minute).Unix()
+	if !feed.IsDue(now) {
+		t.Error("Feed scheduled in the past should be due")
+	}
+}
+
+func TestFeed_ScheduleNextFetch(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC) // Thursday
+
+	tests := []struct {
+		name      string
+		skipHours []int
+		skipDays  []string
+		interval  time.Duration
+		notBefore time.Time
+		expected  time.Time
+	}{
+		{
+			name:     "plain interval",
+			interval: 2 * time.Hour,
+			expected: now.Add(2 * time.Hour),
+		},
+		{
+			name:      "not before is later than interval",
+			interval:  time.Hour,
+			notBefore: now.Add(3 * time.Hour),
+			expected:  now.Add(3 * time.Hour),
+		},
+		{
+			name:      "skip hours",
+			skipHours: []int{13, 14},
+			interval:  time.Hour,
+			expected:  time.Date(2026, 1, 1, 15, 0, 0, 0, time.UTC),
+		},
+		{
+			name:     "skip days",
+			skipDays: []string{"Friday"},
+			interval: 12 * time.Hour,
+			expected: time.Date(2026, 1, 3, 0, 0, 0, 0, time.UTC),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feed := NewFeed("bower-1", "https://example.com/feed.xml", "Test Feed", "", "")
+			feed.SetPollingHints(0, tt.skipHours, tt.skipDays)
+
+			feed.ScheduleNextFetch(now, tt.interval, tt.notBefore)
+
+			if feed.NextFetchAt != tt.expected.Unix() {
+				t.Errorf("Expected next fetch at %v, got %v", tt.expected, feed.GetNextFetchTime().UTC())
+			}
+			if feed.GetFetchInterval() != tt.interval {
+				t.Errorf("Expected interval %v, got %v", tt.interval, feed.GetFetchInterval())
+			}
+		})
+	}
+}
+
+func TestFeed_RecordFetchFailure(t *testing.T) {
+	now := time.Now()
+	feed := NewFeed("bower-1", "https://example.com/feed.xml", "Test Feed", "", "")
+
+	if feed.GetHealthStatus() != FeedHealthActive {
+		t.Errorf("Expected new feed to be active, got %s", feed.GetHealthStatus())
+	}
+
+	feed.RecordFetchFailure("HTTP error: 500", 500, now)
+	if feed.ConsecutiveFailures != 1 || feed.HealthStatus != FeedHealthFailing {
+		t.Errorf("Expected 1 failure and failing status, got %d %s", feed.ConsecutiveFailures, feed.HealthStatus)
+	}
+	if feed.LastError != "HTTP error: 500" || feed.LastErrorAt != now.Unix() || feed.LastHTTPStatus != 500 {
+		t.Errorf("Expected last error details to be recorded, got %+v", feed)
+	}
+
+	for i := 1; i < MaxConsecutiveFailures; i++ {
+		feed.RecordFetchFailure("HTTP error: 500", 500, now)
+	}
+	if !feed.IsDead() {
+		t.Errorf("Expected feed to be dead after %d failures", MaxConsecutiveFailures)
+	}
+	feed.ScheduleRetry(now, feed.FailureBackoff(), time.Time{})
+	if feed.IsDue(now.Add(48 * time.Hour)) {
+		t.Error("Dead feed should not be due before its next probe")
+	}
+	if !feed.IsDue(now.Add(DeadFeedProbeInterval * time.Second)) {
+		t.Error("Dead feed should be probed after DeadFeedProbeInterval")
+	}
+
+	feed.RecordFetchSuccess(200)
+	if feed.ConsecutiveFailures != 0 || feed.HealthStatus != FeedHealthActive || feed.LastHTTPStatus != 200 {
+		t.Errorf("Expected success to reset health, got %+v", feed)
+	}
+}
+
+func TestFeed_FailureBackoff(t *testing.T) {
+	base := time.Duration(MinFetchInterval) * time.Second
+	maxBackoff := time.Duration(MaxFetchInterval) * time.Second
+
+	tests := []struct {
+		failures int
+		expected time.Duration
+	}{
+		{1, base},
+		{2, base * 2},
+		{3, base * 4},
+		{4, base * 8},
+		{20, maxBackoff},
+	}
+
+	for _, tt := range tests {
+		feed := &Feed{ConsecutiveFailures: tt.failures}
+		if got := feed.FailureBackoff(); got != tt.expected {
+			t.Errorf("FailureBackoff() with %d failures = %v, expected %v", tt.failures, got, tt.expected)
+		}
+	}
+}
+
+func TestFeed_WebSub(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+	feed := NewFeed("bower-1", "https://example.com/feed.xml", "Test Feed", "", "")
+
+	if feed.NeedsWebSubSubscription(now) {
+		t.Error("Feed without a hub should not need a subscription")
+	}
+
+	feed.SetWebSubHub("https://hub.example.com/", "")
+	if feed.TopicURL != "https://example.com/feed.xml" {
+		t.Errorf("Expected the feed URL as topic, got %s", feed.TopicURL)
+	}
+	if !feed.NeedsWebSubSubscription(now) {
+		t.Error("Feed with a hub should need a subscription")
+	}
+
+	feed.RequestWebSub(now)
+	if feed.WebSubState != WebSubStatePending {
+		t.Errorf("Expected state %s, got %s", WebSubStatePending, feed.WebSubState)
+	}
+	if feed.NeedsWebSubSubscription(now.Add(time.Hour)) {
+		t.Error("Pending request should not be repeated right away")
+	}
+	if !feed.NeedsWebSubSubscription(now.Add(WebSubRetryInterval * time.Second)) {
+		t.Error("Unanswered request should be repeated after the retry interval")
+	}
+
+	feed.ConfirmWebSub(3*24*60*60, now)
+	if !feed.HasActiveWebSub(now) {
+		t.Error("Confirmed subscription should be active")
+	}
+	if feed.NeedsWebSubSubscription(now.Add(13 * time.Hour)) {
+		t.Error("Subscription should not be renewed long before the lease expires")
+	}
+	renewal := now.Add(25 * time.Hour)
+	if !feed.NeedsWebSubSubscription(renewal) {
+		t.Error("Subscription should be renewed before the lease expires")
+	}
+
+	feed.RequestWebSub(renewal)
+	if !feed.HasActiveWebSub(renewal) {
+		t.Error("Subscription should stay active while it is renewed")
+	}
+	if feed.HasActiveWebSub(now.Add(3 * 24 * time.Hour)) {
+		t.Error("Subscription should not be active after the lease expires")
+	}
+
+	// A new topic starts over
+	feed.WebSubSecret = "secret"
+	feed.SetWebSubHub("https://hub.example.com/", "https://example.com/self.xml")
+	if feed.WebSubState != "" || feed.WebSubSecret != "" || feed.TopicURL != "https://example.com/self.xml" {
+		t.Errorf("Expected a new subscription for the new topic, got state %q topic %q", feed.WebSubState, feed.TopicURL)
+	}
+
+	feed.SetWebSubHub("", "")
+	if feed.HubURL != "" || feed.TopicURL != "" || feed.NeedsWebSubSubscription(now) {
+		t.Error("Expected the subscription to be cleared when the feed drops its hub")
+	}
+}
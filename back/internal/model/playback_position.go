@@ -0,0 +1,34 @@
+package model
+
+import (
+	"time"
+)
+
+// PlaybackCompletionMargin is how close to the end, in seconds, an episode counts as played to completion
+const PlaybackCompletionMargin = 30
+
+// PlaybackPosition records how far a user has listened to or watched a podcast episode
+type PlaybackPosition struct {
+	UserID    string `json:"user_id" dynamodbav:"user_id" validate:"required"`
+	ArticleID string `json:"article_id" dynamodbav:"article_id" validate:"required"`
+	Position  int64  `json:"position" dynamodbav:"position"`                     // Seconds from the start
+	Duration  int64  `json:"duration,omitempty" dynamodbav:"duration,omitempty"` // Seconds
+	Completed bool   `json:"completed" dynamodbav:"completed"`
+	UpdatedAt int64  `json:"updated_at" dynamodbav:"updated_at"`
+}
+
+// NewPlaybackPosition creates a new PlaybackPosition for the current time. Positions past the duration
+// are clamped to it, and positions within PlaybackCompletionMargin of the end mark the episode completed.
+func NewPlaybackPosition(userID, articleID string, position, duration int64) *PlaybackPosition {
+	if duration > 0 && position > duration {
+		position = duration
+	}
+	return &PlaybackPosition{
+		UserID:    userID,
+		ArticleID: articleID,
+		Position:  position,
+		Duration:  duration,
+		Completed: duration > 0 && duration-position <= PlaybackCompletionMargin,
+		UpdatedAt: time.Now().Unix(),
+	}
+}
@@ -0,0 +1,33 @@
+package model
+
+import (
+	"testing"
+)
+
+func TestNewPlaybackPosition(t *testing.T) {
+	tests := []struct {
+		name          string
+		position      int64
+		duration      int64
+		wantPosition  int64
+		wantCompleted bool
+	}{
+		{"in progress", 600, 1800, 600, false},
+		{"near the end", 1780, 1800, 1780, true},
+		{"past the end", 2000, 1800, 1800, true},
+		{"unknown duration", 600, 0, 600, false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			position := NewPlaybackPosition("user-1", "article-1", tt.position, tt.duration)
+			if position.Position != tt.wantPosition || position.Completed != tt.wantCompleted {
+				t.Errorf("NewPlaybackPosition(%d, %d) = position %d, completed %v; want %d, %v",
+					tt.position, tt.duration, position.Position, position.Completed, tt.wantPosition, tt.wantCompleted)
+			}
+			if position.UpdatedAt == 0 {
+				t.Error("Expected UpdatedAt to be set")
+			}
+		})
+	}
+}
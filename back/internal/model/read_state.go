@@ -0,0 +1,21 @@
+package model
+
+import (
+	"time"
+)
+
+// ReadState records that a user has read an article
+type ReadState struct {
+	UserID    string `json:"user_id" dynamodbav:"user_id" validate:"required"`
+	ArticleID string `json:"article_id" dynamodbav:"article_id" validate:"required"`
+	ReadAt    int64  `json:"read_at" dynamodbav:"read_at"`
+}
+
+// NewReadState creates a new ReadState instance for the current time
+func NewReadState(userID, articleID string) *ReadState {
+	return &ReadState{
+		UserID:    userID,
+		ArticleID: articleID,
+		ReadAt:    time.Now().Unix(),
+	}
+}
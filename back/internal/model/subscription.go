@@ -0,0 +1,51 @@
+package model
+
+import (
+	"time"
+)
+
+// Subscription links a bower to a canonical feed and holds the bower's overrides for it
+type Subscription struct {
+	BowerID   string `json:"bower_id" dynamodbav:"bower_id" validate:"required"`
+	FeedID    string `json:"feed_id" dynamodbav:"feed_id" validate:"required"`
+	Title     string `json:"title,omitempty" dynamodbav:"title,omitempty" validate:"max=200"`      // Custom title, empty to use the feed's
+	Category  string `json:"category,omitempty" dynamodbav:"category,omitempty" validate:"max=50"` // Custom category, empty to use the feed's
+	FullText  bool   `json:"full_text,omitempty" dynamodbav:"full_text,omitempty"`                 // Extract the full content of new articles from their pages
+	CreatedAt int64  `json:"created_at" dynamodbav:"created_at"`
+}
+
+// NewSubscription creates a new Subscription instance with the current timestamp
+func NewSubscription(bowerID, feedID string) *Subscription {
+	return &Subscription{
+		BowerID:   bowerID,
+		FeedID:    feedID,
+		CreatedAt: time.Now().Unix(),
+	}
+}
+
+// NewLegacySubscription returns the subscription standing for the bower that owned a feed before
+// feeds were shared, or nil if the feed has no such bower
+func NewLegacySubscription(feed *Feed) *Subscription {
+	if feed.LegacyBowerID == "" {
+		return nil
+	}
+	return &Subscription{
+		BowerID:   feed.LegacyBowerID,
+		FeedID:    feed.FeedID,
+		CreatedAt: feed.CreatedAt,
+	}
+}
+
+// ApplyTo returns a copy of the canonical feed as seen from the subscribing bower
+func (s *Subscription) ApplyTo(feed *Feed) *Feed {
+	view := *feed
+	view.BowerID = s.BowerID
+	if s.Title != "" {
+		view.Title = s.Title
+	}
+	if s.Category != "" {
+		view.Category = s.Category
+	}
+	view.FullText = s.FullText
+	return &view
+}
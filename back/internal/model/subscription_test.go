@@ -0,0 +1,38 @@
+package model
+
+import "testing"
+
+func TestSubscription_ApplyTo(t *testing.T) {
+	feed := NewFeed("", "https://example.com/feed.xml", "Example Feed", "", "tech")
+	feed.FeedID = "feed-1"
+
+	subscription := NewSubscription("bower-1", feed.FeedID)
+	view := subscription.ApplyTo(feed)
+	if view.BowerID != "bower-1" || view.Title != "Example Feed" || view.Category != "tech" {
+		t.Errorf("Expected feed defaults with bower ID, got %+v", view)
+	}
+
+	subscription.Title = "My Example"
+	subscription.Category = "news"
+	view = subscription.ApplyTo(feed)
+	if view.Title != "My Example" || view.Category != "news" {
+		t.Errorf("Expected overrides to apply, got title=%s category=%s", view.Title, view.Category)
+	}
+	if feed.Title != "Example Feed" || feed.BowerID != "" {
+		t.Error("ApplyTo must not modify the canonical feed")
+	}
+}
+
+func TestNewLegacySubscription(t *testing.T) {
+	feed := NewFeed("", "https://example.com/feed.xml", "Example Feed", "", "tech")
+	feed.FeedID = "feed-1"
+	if NewLegacySubscription(feed) != nil {
+		t.Error("Expected no legacy subscription for a feed without a legacy bower")
+	}
+
+	feed.LegacyBowerID = "bower-1"
+	subscription := NewLegacySubscription(feed)
+	if subscription == nil || subscription.BowerID != "bower-1" || subscription.FeedID != "feed-1" || subscription.CreatedAt != feed.CreatedAt {
+		t.Errorf("Expected the legacy bower's subscription, got %+v", subscription)
+	}
+}
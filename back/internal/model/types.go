@@ -23,6 +23,33 @@ const (
 	ExperiencePerLevel = 10
 )
 
+// Feed polling constants (seconds)
+const (
+	DefaultFetchInterval = 60 * 60      // 1 hour
+	MinFetchInterval     = 15 * 60      // 15 minutes
+	MaxFetchInterval     = 24 * 60 * 60 // 1 day
+)
+
+// Feed health constants
+const (
+	FeedHealthActive       = "active"
+	FeedHealthFailing      = "failing"
+	FeedHealthDead         = "dead"
+	MaxConsecutiveFailures = 10               // Feeds are marked dead after this many failed fetches in a row
+	DeadFeedProbeInterval  = 7 * 24 * 60 * 60 // Dead feeds are still fetched once a week (seconds), in case they recover
+)
+
+// WebSub push subscription constants
+const (
+	WebSubStatePending    = "pending"    // Requested, waiting for the hub to verify the subscription
+	WebSubStateSubscribed = "subscribed" // Verified by the hub, which pushes new content until the lease expires
+	WebSubStateDenied     = "denied"     // Refused by the hub
+
+	WebSubLeaseSeconds  = 7 * 24 * 60 * 60 // Lease requested from hubs
+	WebSubRenewBefore   = 2 * 24 * 60 * 60 // Leases are renewed this long before they expire
+	WebSubRetryInterval = 12 * 60 * 60     // Unanswered or refused requests are sent again after this long
+)
+
 // Default colors for bowers
 var DefaultBowerColors = []string{
 	"#14b8a6", // Primary teal
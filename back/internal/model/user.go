@@ -1,9 +1,16 @@
 package model
 
 import (
+	"strings"
 	"time"
 )
 
+// Guest users get a generated email of the form guest_<id>@feed-bower.local
+const (
+	GuestEmailPrefix = "guest_"
+	GuestEmailSuffix = "@feed-bower.local"
+)
+
 // User represents a user in the system
 type User struct {
 	UserID       string `json:"user_id" dynamodbav:"user_id" validate:"required"`
@@ -13,6 +20,7 @@ type User struct {
 	Language     string `json:"language" dynamodbav:"language" validate:"required,oneof=ja en"`
 	CreatedAt    int64  `json:"created_at" dynamodbav:"created_at"`
 	UpdatedAt    int64  `json:"updated_at" dynamodbav:"updated_at"`
+	LastActiveAt int64  `json:"last_active_at,omitempty" dynamodbav:"last_active_at,omitempty"` // Last authenticated request, recorded about hourly
 }
 
 // NewUser creates a new User instance with current timestamps
@@ -25,6 +33,7 @@ func NewUser(email, passwordHash, name, language string) *User {
 		Language:     language,
 		CreatedAt:    now,
 		UpdatedAt:    now,
+		LastActiveAt: now,
 	}
 }
 
@@ -33,7 +42,18 @@ func (u *User) UpdateTimestamp() {
 	u.UpdatedAt = time.Now().Unix()
 }
 
+// LastActivity returns when the user was last active: the latest of their last authenticated
+// request, last update and creation. Users stored before requests were recorded have only the latter.
+func (u *User) LastActivity() int64 {
+	return max(u.LastActiveAt, u.UpdatedAt, u.CreatedAt)
+}
+
 // IsValidLanguage checks if the language is valid
 func (u *User) IsValidLanguage() bool {
 	return u.Language == "ja" || u.Language == "en"
 }
+
+// IsGuest checks if the user is a temporary guest user
+func (u *User) IsGuest() bool {
+	return strings.HasPrefix(u.Email, GuestEmailPrefix) && strings.HasSuffix(u.Email, GuestEmailSuffix)
+}
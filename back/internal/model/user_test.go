@@ -66,3 +66,22 @@ func TestUser_IsValidLanguage(t *testing.T) {
 		}
 	}
 }
+
+func TestUser_IsGuest(t *testing.T) {
+	tests := []struct {
+		email    string
+		expected bool
+	}{
+		{"guest_0123456789abcdef@feed-bower.local", true},
+		{"guest_someone@example.com", false},
+		{"user@feed-bower.local", false},
+		{"", false},
+	}
+
+	for _, test := range tests {
+		user := &User{Email: test.email}
+		if result := user.IsGuest(); result != test.expected {
+			t.Errorf("For email %s, expected %v, got %v", test.email, test.expected, result)
+		}
+	}
+}
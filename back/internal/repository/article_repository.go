@@ -4,7 +4,9 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"log"
 	"sort"
+	"strconv"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
@@ -22,25 +24,102 @@ type ArticleRepository interface {
 	GetByID(ctx context.Context, articleID string) (*model.Article, error)
 	GetByFeedID(ctx context.Context, feedID string, limit int32, lastKey map[string]types.AttributeValue) ([]*model.Article, map[string]types.AttributeValue, error)
 	GetByFeedIDs(ctx context.Context, feedIDs []string, limit int32, lastKey map[string]types.AttributeValue) ([]*model.Article, map[string]types.AttributeValue, error)
+	GetEpisodesByFeedIDs(ctx context.Context, feedIDs []string, limit int32, lastKey map[string]types.AttributeValue) ([]*model.Article, map[string]types.AttributeValue, error)
 	GetByURL(ctx context.Context, url string) (*model.Article, error)
+	GetByCanonicalURL(ctx context.Context, canonicalURL string) (*model.Article, error)
+	GetByTitleHash(ctx context.Context, titleHash string, limit int32) ([]*model.Article, error)
+	AddSourceFeed(ctx context.Context, articleID, feedID string) error
+	RemoveSourceFeed(ctx context.Context, articleID, feedID string) error
+	SetFullContent(ctx context.Context, articleID, fullContent string) error
+	SetImageURL(ctx context.Context, articleID, imageURL string) error
 	Update(ctx context.Context, article *model.Article) error
 	Delete(ctx context.Context, articleID string) error
 	List(ctx context.Context, limit int32, lastKey map[string]types.AttributeValue) ([]*model.Article, map[string]types.AttributeValue, error)
-	Search(ctx context.Context, query string, feedIDs []string, limit int32) ([]*model.Article, error)
+	Search(ctx context.Context, query string, feedIDs []string, limit int32, lastKey map[string]types.AttributeValue) ([]*model.Article, map[string]types.AttributeValue, error)
+	IndexArticles(ctx context.Context, articles []*model.Article) error
+	SyncSearchIndex(ctx context.Context, articleIDs []string) error
 	BatchCreate(ctx context.Context, articles []*model.Article) error
+	BatchDelete(ctx context.Context, articleIDs []string) error
+}
+
+// maxFilteredFeedReads is how many pages of a feed's items a filtered listing reads before it
+// returns a short page, so that a filter matching few items doesn't read the whole feed
+const maxFilteredFeedReads = 10
+
+// ArticlePosition is where an article item is listed among the articles of several feeds: newest
+// first, then by ID. A position without an ID is listed before every item published at its time.
+type ArticlePosition struct {
+	PublishedAt int64  `dynamodbav:"published_at"`
+	ArticleID   string `dynamodbav:"article_id,omitempty"`
+}
+
+// ArticlePositionOf returns the position of an article item
+func ArticlePositionOf(article *model.Article) ArticlePosition {
+	return ArticlePosition{PublishedAt: article.PublishedAt, ArticleID: article.ArticleID}
+}
+
+// RanksBefore reports whether the position is listed before another position
+func (p ArticlePosition) RanksBefore(other ArticlePosition) bool {
+	if p.PublishedAt != other.PublishedAt {
+		return p.PublishedAt > other.PublishedAt
+	}
+	return p.ArticleID < other.ArticleID
+}
+
+// Key returns the position as the key a page of articles continues after
+func (p ArticlePosition) Key() map[string]types.AttributeValue {
+	key, _ := attributevalue.MarshalMap(p)
+	return key
+}
+
+// ParseArticleKey returns the position a page of articles continues after, or nil for the first page
+func ParseArticleKey(lastKey map[string]types.AttributeValue) (*ArticlePosition, error) {
+	if lastKey == nil {
+		return nil, nil
+	}
+
+	var position ArticlePosition
+	if _, exists := lastKey["published_at"]; !exists {
+		return nil, errors.New("malformed cursor")
+	}
+	if err := attributevalue.UnmarshalMap(lastKey, &position); err != nil {
+		return nil, errors.New("malformed cursor")
+	}
+	return &position, nil
+}
+
+// ArticleRepositoryConfig holds configuration for the article search index
+type ArticleRepositoryConfig struct {
+	SearchIndex SearchIndex // Index used by Search, kept up to date by SyncSearchIndex. Defaults to the shared DynamoDB index.
 }
 
 // articleRepository implements ArticleRepository interface
 type articleRepository struct {
 	client *dynamodbpkg.Client
 	tables *dynamodbpkg.TableNames
+
+	searchIndex SearchIndex
 }
 
 // NewArticleRepository creates a new article repository
 func NewArticleRepository(client *dynamodbpkg.Client) ArticleRepository {
+	return NewArticleRepositoryWithConfig(client, nil)
+}
+
+// NewArticleRepositoryWithConfig creates a new article repository with a custom search index
+func NewArticleRepositoryWithConfig(client *dynamodbpkg.Client, config *ArticleRepositoryConfig) ArticleRepository {
+	searchIndex := SearchIndex(nil)
+	if config != nil {
+		searchIndex = config.SearchIndex
+	}
+	if searchIndex == nil {
+		searchIndex = NewDynamoDBSearchIndex(client)
+	}
+
 	return &articleRepository{
-		client: client,
-		tables: client.GetTableNames(),
+		client:      client,
+		tables:      client.GetTableNames(),
+		searchIndex: searchIndex,
 	}
 }
 
@@ -111,7 +190,8 @@ func (r *articleRepository) GetByID(ctx context.Context, articleID string) (*mod
 	return &article, nil
 }
 
-// GetByFeedID retrieves articles by feed ID using GSI, sorted by published_at descending
+// GetByFeedID retrieves the items of a feed using GSI, sorted by published_at descending.
+// Aliases of articles merged from other feeds are returned as is.
 func (r *articleRepository) GetByFeedID(ctx context.Context, feedID string, limit int32, lastKey map[string]types.AttributeValue) ([]*model.Article, map[string]types.AttributeValue, error) {
 	if feedID == "" {
 		return nil, nil, errors.New("feedID cannot be empty")
@@ -153,8 +233,33 @@ func (r *articleRepository) GetByFeedID(ctx context.Context, feedID string, limi
 	return articles, result.LastEvaluatedKey, nil
 }
 
-// GetByFeedIDs retrieves articles from multiple feeds, sorted by published_at descending
+// GetByFeedIDs retrieves the articles of several feeds, newest first. Pages continue from the
+// returned key, which is the position of the last article of the page.
 func (r *articleRepository) GetByFeedIDs(ctx context.Context, feedIDs []string, limit int32, lastKey map[string]types.AttributeValue) ([]*model.Article, map[string]types.AttributeValue, error) {
+	return r.getByFeedIDs(ctx, feedIDs, limit, lastKey, "")
+}
+
+// GetEpisodesByFeedIDs retrieves the podcast episodes of several feeds, newest first. Pages
+// continue from the returned key like GetByFeedIDs. Feeds with few episodes are read a few pages
+// at a time, so a page may be short, or even empty, and still have a key.
+func (r *articleRepository) GetEpisodesByFeedIDs(ctx context.Context, feedIDs []string, limit int32, lastKey map[string]types.AttributeValue) ([]*model.Article, map[string]types.AttributeValue, error) {
+	// Aliases don't copy the episode, so they are read and resolved before filtering
+	articles, nextKey, err := r.getByFeedIDs(ctx, feedIDs, limit, lastKey, "attribute_exists(episode) OR attribute_exists(duplicate_of)")
+	if err != nil {
+		return nil, nil, err
+	}
+
+	episodes := make([]*model.Article, 0, len(articles))
+	for _, article := range articles {
+		if article.Episode != nil {
+			episodes = append(episodes, article)
+		}
+	}
+	return episodes, nextKey, nil
+}
+
+// getByFeedIDs retrieves the articles of several feeds that match a filter expression, newest first
+func (r *articleRepository) getByFeedIDs(ctx context.Context, feedIDs []string, limit int32, lastKey map[string]types.AttributeValue, filter string) ([]*model.Article, map[string]types.AttributeValue, error) {
 	if len(feedIDs) == 0 {
 		return nil, nil, errors.New("feedIDs cannot be empty")
 	}
@@ -162,53 +267,140 @@ func (r *articleRepository) GetByFeedIDs(ctx context.Context, feedIDs []string,
 		limit = 50 // Default limit
 	}
 
-	// Use FeedIdPublishedAtIndex GSI to query articles by feed_id
-	// Query each feed_id separately and merge results
+	after, err := ParseArticleKey(lastKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// Any feed may hold the next articles, so query the next items of every feed and merge them
+	// before the cut
 	allArticles := make([]*model.Article, 0)
+	more := false
+	var horizon *ArticlePosition
 
 	for _, feedID := range feedIDs {
-		input := &dynamodb.QueryInput{
-			TableName:              aws.String(r.tables.Articles),
-			IndexName:              aws.String("FeedIdPublishedAtIndex"),
-			KeyConditionExpression: aws.String("feed_id = :feed_id"),
-			ExpressionAttributeValues: map[string]types.AttributeValue{
-				":feed_id": &types.AttributeValueMemberS{Value: feedID},
-			},
-			ScanIndexForward: aws.Bool(false), // Sort by published_at descending
-			Limit:            aws.Int32(limit),
+		articles, feedMore, feedHorizon, err := r.queryFeedArticles(ctx, feedID, after, int(limit), filter)
+		if err != nil {
+			return nil, nil, err
+		}
+		allArticles = append(allArticles, articles...)
+		more = more || feedMore
+		if feedHorizon != nil && (horizon == nil || feedHorizon.RanksBefore(*horizon)) {
+			horizon = feedHorizon
 		}
+	}
+
+	allArticles, next := pageArticles(allArticles, int(limit), more, horizon)
+
+	var nextKey map[string]types.AttributeValue
+	if next != nil {
+		nextKey = next.Key()
+	}
+
+	// Aliases are resolved after the cut, so that the key stays the position of an item of the index
+	allArticles, err = r.resolveAliases(ctx, allArticles)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	return allArticles, nextKey, nil
+}
+
+// pageArticles sorts the merged items of several feeds and cuts them to a page. Items are first
+// cut at the horizon, if any, since a feed whose read stopped there may have unread items listed
+// before the others. It returns the position the next page continues after, or nil for the last page.
+func pageArticles(articles []*model.Article, limit int, more bool, horizon *ArticlePosition) ([]*model.Article, *ArticlePosition) {
+	sort.Slice(articles, func(i, j int) bool {
+		return ArticlePositionOf(articles[i]).RanksBefore(ArticlePositionOf(articles[j]))
+	})
+
+	if horizon != nil {
+		articles = articles[:sort.Search(len(articles), func(i int) bool {
+			return !ArticlePositionOf(articles[i]).RanksBefore(*horizon)
+		})]
+	}
+
+	// Limit to requested number
+	if len(articles) > limit {
+		articles = articles[:limit]
+		last := ArticlePositionOf(articles[len(articles)-1])
+		return articles, &last
+	}
+
+	switch {
+	case horizon != nil:
+		return articles, horizon
+	case more && len(articles) > 0:
+		last := ArticlePositionOf(articles[len(articles)-1])
+		return articles, &last
+	default:
+		return articles, nil
+	}
+}
 
+// queryFeedArticles returns at least limit items of a feed that come after the given position, if
+// the feed has that many, and whether the feed has more. Items published at the same time as the
+// last one are all returned, since the index does not order them by ID. Only items matching the
+// filter expression, if any, are returned. A filtered read stops after maxFilteredFeedReads pages
+// and returns the horizon it reached: every item of the feed listed before it has been read.
+func (r *articleRepository) queryFeedArticles(ctx context.Context, feedID string, after *ArticlePosition, limit int, filter string) ([]*model.Article, bool, *ArticlePosition, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(r.tables.Articles),
+		IndexName:              aws.String("FeedIdPublishedAtIndex"),
+		KeyConditionExpression: aws.String("feed_id = :feed_id"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":feed_id": &types.AttributeValueMemberS{Value: feedID},
+		},
+		ScanIndexForward: aws.Bool(false), // Sort by published_at descending
+		Limit:            aws.Int32(int32(limit)),
+	}
+	if filter != "" {
+		input.FilterExpression = aws.String(filter)
+	}
+	if after != nil {
+		input.KeyConditionExpression = aws.String("feed_id = :feed_id AND published_at <= :published_at")
+		input.ExpressionAttributeValues[":published_at"] = &types.AttributeValueMemberN{Value: strconv.FormatInt(after.PublishedAt, 10)}
+	}
+
+	articles := make([]*model.Article, 0, limit)
+	for reads := 1; ; reads++ {
 		result, err := r.client.Query(ctx, input)
 		if err != nil {
-			return nil, nil, fmt.Errorf("failed to query articles for feed %s: %w", feedID, err)
+			return nil, false, nil, fmt.Errorf("failed to query articles for feed %s: %w", feedID, err)
 		}
 
 		for _, item := range result.Items {
 			var article model.Article
-			err = attributevalue.UnmarshalMap(item, &article)
-			if err != nil {
-				return nil, nil, fmt.Errorf("failed to unmarshal article: %w", err)
+			if err := attributevalue.UnmarshalMap(item, &article); err != nil {
+				return nil, false, nil, fmt.Errorf("failed to unmarshal article: %w", err)
 			}
-			allArticles = append(allArticles, &article)
+			if after != nil && !after.RanksBefore(ArticlePositionOf(&article)) {
+				continue
+			}
+			if len(articles) >= limit && article.PublishedAt != articles[len(articles)-1].PublishedAt {
+				return articles, true, nil, nil
+			}
+			articles = append(articles, &article)
 		}
 
-		// Stop if we have enough articles
-		if len(allArticles) >= int(limit) {
-			break
+		if len(result.LastEvaluatedKey) == 0 {
+			return articles, false, nil, nil
 		}
-	}
 
-	// Sort all articles by published_at descending
-	sort.Slice(allArticles, func(i, j int) bool {
-		return allArticles[i].PublishedAt > allArticles[j].PublishedAt
-	})
-
-	// Limit to requested number
-	if len(allArticles) > int(limit) {
-		allArticles = allArticles[:limit]
+		// Items published at the time the read stopped at may be unread, so the horizon is listed
+		// before them. It must be listed after the position the read started from, or the next
+		// page would start over.
+		if filter != "" && reads >= maxFilteredFeedReads {
+			var reached ArticlePosition
+			if err := attributevalue.UnmarshalMap(result.LastEvaluatedKey, &reached); err != nil {
+				return nil, false, nil, fmt.Errorf("failed to unmarshal query key: %w", err)
+			}
+			if after == nil || reached.PublishedAt < after.PublishedAt {
+				return articles, true, &ArticlePosition{PublishedAt: reached.PublishedAt}, nil
+			}
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
 	}
-
-	return allArticles, nil, nil
 }
 
 // GetByURL retrieves an article by its URL (for duplicate checking)
@@ -247,6 +439,269 @@ func (r *articleRepository) GetByURL(ctx context.Context, url string) (*model.Ar
 	return &article, nil
 }
 
+// GetByCanonicalURL retrieves the article stored for a canonical URL using GSI.
+// The index only projects the attributes needed to detect duplicates: feed_id,
+// source_feed_ids, published_at and content_hash.
+func (r *articleRepository) GetByCanonicalURL(ctx context.Context, canonicalURL string) (*model.Article, error) {
+	if canonicalURL == "" {
+		return nil, errors.New("canonicalURL cannot be empty")
+	}
+
+	articles, err := r.queryDedupIndex(ctx, "CanonicalUrlIndex", "canonical_url", canonicalURL, 1)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query articles by canonical URL: %w", err)
+	}
+	if len(articles) == 0 {
+		return nil, fmt.Errorf("article with canonical URL %s not found", canonicalURL)
+	}
+
+	return articles[0], nil
+}
+
+// GetByTitleHash retrieves articles with the same normalized title using GSI,
+// with the same attributes as GetByCanonicalURL
+func (r *articleRepository) GetByTitleHash(ctx context.Context, titleHash string, limit int32) ([]*model.Article, error) {
+	if titleHash == "" {
+		return nil, errors.New("titleHash cannot be empty")
+	}
+	if limit <= 0 {
+		limit = 10 // Default limit
+	}
+
+	articles, err := r.queryDedupIndex(ctx, "TitleHashIndex", "title_hash", titleHash, limit)
+	if err != nil {
+		return nil, fmt.Errorf("failed to query articles by title hash: %w", err)
+	}
+
+	return articles, nil
+}
+
+// queryDedupIndex queries one of the sparse indexes used to detect duplicates
+func (r *articleRepository) queryDedupIndex(ctx context.Context, indexName, keyName, value string, limit int32) ([]*model.Article, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(r.tables.Articles),
+		IndexName:              aws.String(indexName),
+		KeyConditionExpression: aws.String(keyName + " = :value"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":value": &types.AttributeValueMemberS{Value: value},
+		},
+		Limit: aws.Int32(limit),
+	}
+
+	result, err := r.client.Query(ctx, input)
+	if err != nil {
+		return nil, err
+	}
+
+	articles := make([]*model.Article, 0, len(result.Items))
+	for _, item := range result.Items {
+		var article model.Article
+		if err := attributevalue.UnmarshalMap(item, &article); err != nil {
+			return nil, fmt.Errorf("failed to unmarshal article: %w", err)
+		}
+		articles = append(articles, &article)
+	}
+
+	return articles, nil
+}
+
+// AddSourceFeed merges a feed into an article's source feeds and lists the article under
+// the feed through an alias item. Both are written in one transaction, so that an article is
+// never listed under a feed it was not merged from, or merged from a feed it is not listed under.
+func (r *articleRepository) AddSourceFeed(ctx context.Context, articleID, feedID string) error {
+	if articleID == "" {
+		return errors.New("articleID cannot be empty")
+	}
+	if feedID == "" {
+		return errors.New("feedID cannot be empty")
+	}
+
+	// The alias copies the fields the article is listed by
+	article, err := r.GetByID(ctx, articleID)
+	if err != nil {
+		return err
+	}
+
+	alias, err := attributevalue.MarshalMap(model.NewArticleAlias(article, feedID))
+	if err != nil {
+		return fmt.Errorf("failed to marshal article alias: %w", err)
+	}
+
+	input := &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{
+				Update: &types.Update{
+					TableName: aws.String(r.tables.Articles),
+					Key: map[string]types.AttributeValue{
+						"article_id": &types.AttributeValueMemberS{Value: articleID},
+					},
+					UpdateExpression:    aws.String("ADD source_feed_ids :feed_ids"),
+					ConditionExpression: aws.String("attribute_exists(article_id)"),
+					ExpressionAttributeValues: map[string]types.AttributeValue{
+						":feed_ids": &types.AttributeValueMemberSS{Value: []string{feedID}},
+					},
+				},
+			},
+			{
+				Put: &types.Put{
+					TableName: aws.String(r.tables.Articles),
+					Item:      alias,
+				},
+			},
+		},
+	}
+
+	if _, err := r.client.TransactWriteItems(ctx, input); err != nil {
+		if failedConditionIndex(err) == 0 {
+			return fmt.Errorf("article with ID %s not found", articleID)
+		}
+		return fmt.Errorf("failed to add source feed: %w", err)
+	}
+
+	return nil
+}
+
+// SetFullContent stores the content extracted from an article's page
+func (r *articleRepository) SetFullContent(ctx context.Context, articleID, fullContent string) error {
+	if articleID == "" {
+		return errors.New("articleID cannot be empty")
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.tables.Articles),
+		Key: map[string]types.AttributeValue{
+			"article_id": &types.AttributeValueMemberS{Value: articleID},
+		},
+		UpdateExpression:    aws.String("SET full_content = :full_content"),
+		ConditionExpression: aws.String("attribute_exists(article_id)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":full_content": &types.AttributeValueMemberS{Value: fullContent},
+		},
+	}
+
+	_, err := r.client.UpdateItem(ctx, input)
+	if err != nil {
+		var conditionalCheckErr *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionalCheckErr) {
+			return fmt.Errorf("article with ID %s not found", articleID)
+		}
+		return fmt.Errorf("failed to set full content: %w", err)
+	}
+
+	return nil
+}
+
+// SetImageURL stores the image of an article found on its page
+func (r *articleRepository) SetImageURL(ctx context.Context, articleID, imageURL string) error {
+	if articleID == "" {
+		return errors.New("articleID cannot be empty")
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.tables.Articles),
+		Key: map[string]types.AttributeValue{
+			"article_id": &types.AttributeValueMemberS{Value: articleID},
+		},
+		UpdateExpression:    aws.String("SET image_url = :image_url"),
+		ConditionExpression: aws.String("attribute_exists(article_id)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":image_url": &types.AttributeValueMemberS{Value: imageURL},
+		},
+	}
+
+	_, err := r.client.UpdateItem(ctx, input)
+	if err != nil {
+		var conditionalCheckErr *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionalCheckErr) {
+			return fmt.Errorf("article with ID %s not found", articleID)
+		}
+		return fmt.Errorf("failed to set image URL: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveSourceFeed removes a feed from an article's source feeds along with its alias item.
+// The alias is removed even if the article no longer exists.
+func (r *articleRepository) RemoveSourceFeed(ctx context.Context, articleID, feedID string) error {
+	if articleID == "" {
+		return errors.New("articleID cannot be empty")
+	}
+	if feedID == "" {
+		return errors.New("feedID cannot be empty")
+	}
+
+	_, err := r.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.tables.Articles),
+		Key: map[string]types.AttributeValue{
+			"article_id": &types.AttributeValueMemberS{Value: model.ArticleAliasID(articleID, feedID)},
+		},
+	})
+	if err != nil {
+		return fmt.Errorf("failed to delete article alias: %w", err)
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.tables.Articles),
+		Key: map[string]types.AttributeValue{
+			"article_id": &types.AttributeValueMemberS{Value: articleID},
+		},
+		UpdateExpression:    aws.String("DELETE source_feed_ids :feed_ids"),
+		ConditionExpression: aws.String("attribute_exists(article_id)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":feed_ids": &types.AttributeValueMemberSS{Value: []string{feedID}},
+		},
+	}
+
+	_, err = r.client.UpdateItem(ctx, input)
+	if err != nil {
+		var conditionalCheckErr *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionalCheckErr) {
+			return fmt.Errorf("article with ID %s not found", articleID)
+		}
+		return fmt.Errorf("failed to remove source feed: %w", err)
+	}
+
+	return nil
+}
+
+// resolveAliases replaces aliases with the articles they point at, keeping the first
+// occurrence of each article. Aliases of deleted articles are dropped.
+func (r *articleRepository) resolveAliases(ctx context.Context, articles []*model.Article) ([]*model.Article, error) {
+	var canonicalIDs []string
+	for _, article := range articles {
+		if article.IsAlias() {
+			canonicalIDs = append(canonicalIDs, article.DuplicateOf)
+		}
+	}
+	if len(canonicalIDs) == 0 {
+		return articles, nil
+	}
+
+	canonical, err := r.batchGetArticles(ctx, canonicalIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to resolve merged articles: %w", err)
+	}
+
+	resolved := make([]*model.Article, 0, len(articles))
+	seen := make(map[string]bool, len(articles))
+	for _, article := range articles {
+		if article.IsAlias() {
+			article = canonical[article.DuplicateOf]
+			if article == nil {
+				continue
+			}
+		}
+		if seen[article.ArticleID] {
+			continue
+		}
+		seen[article.ArticleID] = true
+		resolved = append(resolved, article)
+	}
+
+	return resolved, nil
+}
+
 // Update updates an existing article
 func (r *articleRepository) Update(ctx context.Context, article *model.Article) error {
 	if article == nil {
@@ -340,57 +795,146 @@ func (r *articleRepository) List(ctx context.Context, limit int32, lastKey map[s
 	return articles, result.LastEvaluatedKey, nil
 }
 
-// Search searches for articles by title or content
-func (r *articleRepository) Search(ctx context.Context, query string, feedIDs []string, limit int32) ([]*model.Article, error) {
+// Search searches the full-text index for articles of the given feeds, most relevant first.
+// Pages continue from the returned key, which is the rank of the last article of the page.
+func (r *articleRepository) Search(ctx context.Context, query string, feedIDs []string, limit int32, lastKey map[string]types.AttributeValue) ([]*model.Article, map[string]types.AttributeValue, error) {
 	if query == "" {
-		return nil, errors.New("query cannot be empty")
+		return nil, nil, errors.New("query cannot be empty")
 	}
 	if limit <= 0 {
 		limit = 50 // Default limit
 	}
 
-	var filterExpression string
-	expressionAttributeValues := map[string]types.AttributeValue{
-		":query": &types.AttributeValueMemberS{Value: query},
+	after, err := ParseSearchKey(lastKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// One hit more than the limit tells whether there is another page
+	hits, err := r.searchIndex.Search(ctx, query, feedIDs, after, int(limit)+1)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to search articles: %w", err)
+	}
+
+	var nextKey map[string]types.AttributeValue
+	if len(hits) > int(limit) {
+		hits = hits[:limit]
+		nextKey = hits[len(hits)-1].Key()
+	}
+
+	articleIDs := make([]string, len(hits))
+	for i, hit := range hits {
+		articleIDs[i] = hit.ArticleID
 	}
 
-	// Base search expression
-	searchExpression := "contains(title, :query) OR contains(content, :query)"
+	found, err := r.batchGetArticles(ctx, articleIDs)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get matching articles: %w", err)
+	}
 
-	if len(feedIDs) > 0 {
-		// Add feed ID filter if provided
-		feedFilterExpressions := make([]string, len(feedIDs))
-		for i, feedID := range feedIDs {
-			placeholder := fmt.Sprintf(":feed_id_%d", i)
-			feedFilterExpressions[i] = fmt.Sprintf("feed_id = %s", placeholder)
-			expressionAttributeValues[placeholder] = &types.AttributeValueMemberS{Value: feedID}
+	// Keep the ranking order and drop articles deleted by other processes from the index
+	articles := make([]*model.Article, 0, len(hits))
+	var missing []string
+	for _, articleID := range articleIDs {
+		article, ok := found[articleID]
+		if !ok {
+			missing = append(missing, articleID)
+			continue
 		}
-		feedFilter := fmt.Sprintf("(%s)", joinStrings(feedFilterExpressions, " OR "))
-		filterExpression = fmt.Sprintf("(%s) AND (%s)", searchExpression, feedFilter)
-	} else {
-		filterExpression = searchExpression
+		articles = append(articles, article)
 	}
+	r.removeFromIndex(ctx, missing)
 
-	input := &dynamodb.ScanInput{
-		TableName:                 aws.String(r.tables.Articles),
-		FilterExpression:          aws.String(filterExpression),
-		ExpressionAttributeValues: expressionAttributeValues,
-		Limit:                     aws.Int32(limit),
+	return articles, nextKey, nil
+}
+
+// IndexArticles adds stored articles to the search index, to backfill it or repair failed writes
+func (r *articleRepository) IndexArticles(ctx context.Context, articles []*model.Article) error {
+	if err := r.searchIndex.IndexArticles(ctx, articles); err != nil {
+		return fmt.Errorf("failed to index articles: %w", err)
 	}
+	return nil
+}
 
-	result, err := r.client.Scan(ctx, input)
+// SyncSearchIndex brings the search index up to date with the stored articles of the given IDs,
+// such as the articles of a batch of Articles table stream records. Articles that no longer exist
+// are removed from the index. Writes don't index articles themselves, so that indexing stays off
+// the feed fetch path.
+func (r *articleRepository) SyncSearchIndex(ctx context.Context, articleIDs []string) error {
+	articleIDs = uniqueStrings(articleIDs)
+	found, err := r.batchGetArticles(ctx, articleIDs)
 	if err != nil {
-		return nil, fmt.Errorf("failed to search articles: %w", err)
+		return fmt.Errorf("failed to get changed articles: %w", err)
 	}
 
-	articles := make([]*model.Article, 0, len(result.Items))
-	for _, item := range result.Items {
-		var article model.Article
-		err = attributevalue.UnmarshalMap(item, &article)
-		if err != nil {
-			return nil, fmt.Errorf("failed to unmarshal article: %w", err)
+	articles := make([]*model.Article, 0, len(found))
+	var removed []string
+	for _, articleID := range articleIDs {
+		if article, ok := found[articleID]; ok {
+			articles = append(articles, article)
+		} else {
+			removed = append(removed, articleID)
+		}
+	}
+
+	if err := r.searchIndex.RemoveArticles(ctx, removed); err != nil {
+		return fmt.Errorf("failed to remove articles from the search index: %w", err)
+	}
+	if err := r.searchIndex.IndexArticles(ctx, articles); err != nil {
+		return fmt.Errorf("failed to index articles: %w", err)
+	}
+	return nil
+}
+
+// removeFromIndex removes deleted articles from the search index
+func (r *articleRepository) removeFromIndex(ctx context.Context, articleIDs []string) {
+	if len(articleIDs) == 0 {
+		return
+	}
+	if err := r.searchIndex.RemoveArticles(ctx, articleIDs); err != nil {
+		log.Printf("[ArticleRepository] SEARCH_INDEX_FAILED | removed=%d error=%v", len(articleIDs), err)
+	}
+}
+
+// batchGetArticles retrieves articles by ID, keyed by ID. Missing articles are left out.
+func (r *articleRepository) batchGetArticles(ctx context.Context, articleIDs []string) (map[string]*model.Article, error) {
+	articles := make(map[string]*model.Article, len(articleIDs))
+
+	// DynamoDB batch get can handle up to 100 keys at a time
+	const batchSize = 100
+
+	for i := 0; i < len(articleIDs); i += batchSize {
+		end := i + batchSize
+		if end > len(articleIDs) {
+			end = len(articleIDs)
+		}
+
+		keys := make([]map[string]types.AttributeValue, 0, end-i)
+		for _, articleID := range articleIDs[i:end] {
+			keys = append(keys, map[string]types.AttributeValue{
+				"article_id": &types.AttributeValueMemberS{Value: articleID},
+			})
+		}
+
+		requestItems := map[string]types.KeysAndAttributes{
+			r.tables.Articles: {Keys: keys},
+		}
+		for len(requestItems) > 0 {
+			result, err := r.client.BatchGetItem(ctx, &dynamodb.BatchGetItemInput{RequestItems: requestItems})
+			if err != nil {
+				return nil, fmt.Errorf("failed to batch get articles: %w", err)
+			}
+
+			for _, item := range result.Responses[r.tables.Articles] {
+				var article model.Article
+				if err := attributevalue.UnmarshalMap(item, &article); err != nil {
+					return nil, fmt.Errorf("failed to unmarshal article: %w", err)
+				}
+				articles[article.ArticleID] = &article
+			}
+
+			requestItems = result.UnprocessedKeys
 		}
-		articles = append(articles, &article)
 	}
 
 	return articles, nil
@@ -420,6 +964,25 @@ func (r *articleRepository) BatchCreate(ctx context.Context, articles []*model.A
 	return nil
 }
 
+// BatchDelete deletes multiple articles by ID. Missing articles are ignored, so it is safe to retry.
+func (r *articleRepository) BatchDelete(ctx context.Context, articleIDs []string) error {
+	keys := make([]map[string]types.AttributeValue, 0, len(articleIDs))
+	for _, articleID := range articleIDs {
+		if articleID == "" {
+			continue
+		}
+		keys = append(keys, map[string]types.AttributeValue{
+			"article_id": &types.AttributeValueMemberS{Value: articleID},
+		})
+	}
+
+	if err := batchDeleteKeys(ctx, r.client, r.tables.Articles, keys); err != nil {
+		return fmt.Errorf("failed to batch delete articles: %w", err)
+	}
+
+	return nil
+}
+
 // batchWriteArticles writes a batch of articles (up to 25 items)
 func (r *articleRepository) batchWriteArticles(ctx context.Context, articles []*model.Article) error {
 	writeRequests := make([]types.WriteRequest, 0, len(articles))
@@ -456,19 +1019,3 @@ func (r *articleRepository) batchWriteArticles(ctx context.Context, articles []*
 
 	return nil
 }
-
-// Helper function to join strings with a separator
-func joinStrings(strs []string, sep string) string {
-	if len(strs) == 0 {
-		return ""
-	}
-	if len(strs) == 1 {
-		return strs[0]
-	}
-
-	result := strs[0]
-	for i := 1; i < len(strs); i++ {
-		result += sep + strs[i]
-	}
-	return result
-}
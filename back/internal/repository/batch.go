@@ -0,0 +1,96 @@
+package repository
+
+import (
+	"context"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	dynamodbpkg "feed-bower-api/pkg/dynamodb"
+)
+
+// articleIdIndex is the GSI on article_id used to find per-user rows (likes, read state, playback) for an article
+const articleIdIndex = "ArticleIdIndex"
+
+// batchDeleteKeys deletes items by primary key, 25 at a time, retrying unprocessed items.
+// Deleting keys that do not exist is not an error.
+func batchDeleteKeys(ctx context.Context, client *dynamodbpkg.Client, tableName string, keys []map[string]types.AttributeValue) error {
+	// DynamoDB batch write can handle up to 25 items at a time
+	const batchSize = 25
+
+	for i := 0; i < len(keys); i += batchSize {
+		end := i + batchSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		writeRequests := make([]types.WriteRequest, 0, end-i)
+		for _, key := range keys[i:end] {
+			writeRequests = append(writeRequests, types.WriteRequest{
+				DeleteRequest: &types.DeleteRequest{Key: key},
+			})
+		}
+
+		requestItems := map[string][]types.WriteRequest{tableName: writeRequests}
+		for len(requestItems) > 0 {
+			result, err := client.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{RequestItems: requestItems})
+			if err != nil {
+				return fmt.Errorf("failed to batch delete items: %w", err)
+			}
+			requestItems = result.UnprocessedItems
+		}
+	}
+
+	return nil
+}
+
+// queryUserArticleKeysByArticleID returns the (user_id, article_id) keys of all rows for an article
+func queryUserArticleKeysByArticleID(ctx context.Context, client *dynamodbpkg.Client, tableName string, articleID string) ([]map[string]types.AttributeValue, error) {
+	return queryUserArticleKeys(ctx, client, tableName, aws.String(articleIdIndex), "article_id", articleID)
+}
+
+// queryUserArticleKeysByUserID returns the (user_id, article_id) keys of all rows for a user
+func queryUserArticleKeysByUserID(ctx context.Context, client *dynamodbpkg.Client, tableName string, userID string) ([]map[string]types.AttributeValue, error) {
+	return queryUserArticleKeys(ctx, client, tableName, nil, "user_id", userID)
+}
+
+// queryUserArticleKeys returns the (user_id, article_id) keys of all rows whose attribute has the
+// given value, querying the table itself if indexName is nil
+func queryUserArticleKeys(ctx context.Context, client *dynamodbpkg.Client, tableName string, indexName *string, attribute, value string) ([]map[string]types.AttributeValue, error) {
+	keys := make([]map[string]types.AttributeValue, 0)
+
+	var lastKey map[string]types.AttributeValue
+	for {
+		input := &dynamodb.QueryInput{
+			TableName:              aws.String(tableName),
+			IndexName:              indexName,
+			KeyConditionExpression: aws.String(attribute + " = :value"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":value": &types.AttributeValueMemberS{Value: value},
+			},
+			ProjectionExpression: aws.String("user_id, article_id"),
+			ExclusiveStartKey:    lastKey,
+		}
+
+		result, err := client.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query by %s: %w", attribute, err)
+		}
+
+		for _, item := range result.Items {
+			keys = append(keys, map[string]types.AttributeValue{
+				"user_id":    item["user_id"],
+				"article_id": item["article_id"],
+			})
+		}
+
+		if len(result.LastEvaluatedKey) == 0 {
+			break
+		}
+		lastKey = result.LastEvaluatedKey
+	}
+
+	return keys, nil
+}
@@ -28,6 +28,9 @@ type ChickRepository interface {
 	GetLikedArticles(ctx context.Context, userID string, limit int32, lastKey map[string]types.AttributeValue) ([]*model.LikedArticle, map[string]types.AttributeValue, error)
 	IsArticleLiked(ctx context.Context, userID, articleID string) (bool, error)
 	GetLikedArticleCount(ctx context.Context, userID string) (int, error)
+	RemoveLikedArticlesByArticleID(ctx context.Context, articleID string) (int, error)
+	RemoveLikedArticlesByUserID(ctx context.Context, userID string) (int, error)
+	GetLikeCountByArticleID(ctx context.Context, articleID string) (int, error)
 }
 
 // chickRepository implements ChickRepository interface
@@ -315,3 +318,72 @@ func (r *chickRepository) GetLikedArticleCount(ctx context.Context, userID strin
 
 	return int(result.Count), nil
 }
+
+// RemoveLikedArticlesByArticleID removes every user's like of an article and returns how many were removed
+func (r *chickRepository) RemoveLikedArticlesByArticleID(ctx context.Context, articleID string) (int, error) {
+	if articleID == "" {
+		return 0, errors.New("articleID cannot be empty")
+	}
+
+	keys, err := queryUserArticleKeysByArticleID(ctx, r.client, r.tables.LikedArticles, articleID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find liked articles: %w", err)
+	}
+
+	if err := batchDeleteKeys(ctx, r.client, r.tables.LikedArticles, keys); err != nil {
+		return 0, fmt.Errorf("failed to remove liked articles: %w", err)
+	}
+
+	return len(keys), nil
+}
+
+// RemoveLikedArticlesByUserID removes all of a user's likes and returns how many were removed
+func (r *chickRepository) RemoveLikedArticlesByUserID(ctx context.Context, userID string) (int, error) {
+	if userID == "" {
+		return 0, errors.New("userID cannot be empty")
+	}
+
+	keys, err := queryUserArticleKeysByUserID(ctx, r.client, r.tables.LikedArticles, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find liked articles: %w", err)
+	}
+
+	if err := batchDeleteKeys(ctx, r.client, r.tables.LikedArticles, keys); err != nil {
+		return 0, fmt.Errorf("failed to remove liked articles: %w", err)
+	}
+
+	return len(keys), nil
+}
+
+// GetLikeCountByArticleID returns how many users like an article
+func (r *chickRepository) GetLikeCountByArticleID(ctx context.Context, articleID string) (int, error) {
+	if articleID == "" {
+		return 0, errors.New("articleID cannot be empty")
+	}
+
+	count := 0
+	var lastKey map[string]types.AttributeValue
+	for {
+		input := &dynamodb.QueryInput{
+			TableName:              aws.String(r.tables.LikedArticles),
+			IndexName:              aws.String(articleIdIndex),
+			KeyConditionExpression: aws.String("article_id = :article_id"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":article_id": &types.AttributeValueMemberS{Value: articleID},
+			},
+			Select:            types.SelectCount,
+			ExclusiveStartKey: lastKey,
+		}
+
+		result, err := r.client.Query(ctx, input)
+		if err != nil {
+			return 0, fmt.Errorf("failed to count likes: %w", err)
+		}
+		count += int(result.Count)
+
+		if len(result.LastEvaluatedKey) == 0 {
+			return count, nil
+		}
+		lastKey = result.LastEvaluatedKey
+	}
+}
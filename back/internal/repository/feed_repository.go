@@ -4,6 +4,8 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
+	"strings"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
@@ -15,7 +17,10 @@ import (
 	dynamodbpkg "feed-bower-api/pkg/dynamodb"
 )
 
-// FeedRepository defines the interface for feed data operations
+// FeedRepository defines the interface for feed data operations.
+// Feeds are canonical (one per URL); subscriptions link bowers to them. Feeds stored before
+// subscriptions existed keep their owning bower in bower_id, which is read as a subscription until
+// the feed migration turns it into one.
 type FeedRepository interface {
 	Create(ctx context.Context, feed *model.Feed) error
 	GetByID(ctx context.Context, feedID string) (*model.Feed, error)
@@ -23,8 +28,19 @@ type FeedRepository interface {
 	GetByURL(ctx context.Context, url string) (*model.Feed, error)
 	Update(ctx context.Context, feed *model.Feed) error
 	Delete(ctx context.Context, feedID string) error
+	DeleteIfUnsubscribed(ctx context.Context, feedID string) (bool, error)
 	List(ctx context.Context, limit int32, lastKey map[string]types.AttributeValue) ([]*model.Feed, map[string]types.AttributeValue, error)
 	GetStaleFeeds(ctx context.Context, maxAgeSeconds int64, limit int32) ([]*model.Feed, error)
+	GetDueFeeds(ctx context.Context, now int64, limit int32) ([]*model.Feed, error)
+
+	// Subscription operations
+	Subscribe(ctx context.Context, subscription *model.Subscription) error
+	Unsubscribe(ctx context.Context, bowerID, feedID string) error
+	GetSubscription(ctx context.Context, bowerID, feedID string) (*model.Subscription, error)
+	UpdateSubscription(ctx context.Context, subscription *model.Subscription) error
+	GetSubscriptionsByBowerID(ctx context.Context, bowerID string) ([]*model.Subscription, error)
+	GetSubscriptionsByFeedID(ctx context.Context, feedID string) ([]*model.Subscription, error)
+	MigrateLegacySubscription(ctx context.Context, feed *model.Feed) error
 }
 
 // feedRepository implements FeedRepository interface
@@ -108,48 +124,102 @@ func (r *feedRepository) GetByID(ctx context.Context, feedID string) (*model.Fee
 	return &feed, nil
 }
 
-// GetByBowerID retrieves feeds by bower ID using GSI
+// GetByBowerID retrieves the feeds a bower subscribes to, with the bower's overrides applied
 func (r *feedRepository) GetByBowerID(ctx context.Context, bowerID string) ([]*model.Feed, error) {
 	if bowerID == "" {
 		return nil, errors.New("bowerID cannot be empty")
 	}
 
-	input := &dynamodb.QueryInput{
-		TableName:              aws.String(r.tables.Feeds),
-		IndexName:              aws.String("BowerIdIndex"),
-		KeyConditionExpression: aws.String("bower_id = :bower_id"),
-		ExpressionAttributeValues: map[string]types.AttributeValue{
-			":bower_id": &types.AttributeValueMemberS{Value: bowerID},
-		},
+	subscriptions, err := r.GetSubscriptionsByBowerID(ctx, bowerID)
+	if err != nil {
+		return nil, err
 	}
 
-	result, err := r.client.Query(ctx, input)
+	feedIDs := make([]string, len(subscriptions))
+	for i, subscription := range subscriptions {
+		feedIDs[i] = subscription.FeedID
+	}
+
+	feedsByID, err := r.batchGetFeeds(ctx, feedIDs)
 	if err != nil {
-		return nil, fmt.Errorf("failed to query feeds by bower ID: %w", err)
+		return nil, err
 	}
 
-	feeds := make([]*model.Feed, 0, len(result.Items))
-	for _, item := range result.Items {
-		var feed model.Feed
-		err = attributevalue.UnmarshalMap(item, &feed)
-		if err != nil {
-			return nil, fmt.Errorf("failed to unmarshal feed: %w", err)
+	feeds := make([]*model.Feed, 0, len(subscriptions))
+	for _, subscription := range subscriptions {
+		feed, exists := feedsByID[subscription.FeedID]
+		if !exists {
+			continue // Feed was deleted
 		}
-		feeds = append(feeds, &feed)
+		feeds = append(feeds, subscription.ApplyTo(feed))
 	}
 
 	return feeds, nil
 }
 
-// GetByURL retrieves a feed by its URL (for duplicate checking)
+// batchGetFeeds retrieves feeds by ID, 100 at a time, retrying unprocessed keys
+func (r *feedRepository) batchGetFeeds(ctx context.Context, feedIDs []string) (map[string]*model.Feed, error) {
+	feeds := make(map[string]*model.Feed, len(feedIDs))
+
+	// DynamoDB batch get can handle up to 100 keys at a time
+	const batchSize = 100
+
+	for i := 0; i < len(feedIDs); i += batchSize {
+		end := i + batchSize
+		if end > len(feedIDs) {
+			end = len(feedIDs)
+		}
+
+		keys := make([]map[string]types.AttributeValue, 0, end-i)
+		for _, feedID := range feedIDs[i:end] {
+			keys = append(keys, map[string]types.AttributeValue{
+				"feed_id": &types.AttributeValueMemberS{Value: feedID},
+			})
+		}
+
+		requestItems := map[string]types.KeysAndAttributes{
+			r.tables.Feeds: {Keys: keys},
+		}
+		for len(requestItems) > 0 {
+			result, err := r.client.BatchGetItem(ctx, &dynamodb.BatchGetItemInput{RequestItems: requestItems})
+			if err != nil {
+				return nil, fmt.Errorf("failed to batch get feeds: %w", err)
+			}
+
+			for _, item := range result.Responses[r.tables.Feeds] {
+				var feed model.Feed
+				if err := attributevalue.UnmarshalMap(item, &feed); err != nil {
+					return nil, fmt.Errorf("failed to unmarshal feed: %w", err)
+				}
+				feeds[feed.FeedID] = &feed
+			}
+
+			requestItems = result.UnprocessedKeys
+		}
+	}
+
+	return feeds, nil
+}
+
+// GetByURL retrieves the canonical feed for a URL by its URL-derived ID, falling back to the
+// UrlIndex GSI for feeds created with random IDs
 func (r *feedRepository) GetByURL(ctx context.Context, url string) (*model.Feed, error) {
 	if url == "" {
 		return nil, errors.New("url cannot be empty")
 	}
 
-	input := &dynamodb.ScanInput{
-		TableName:        aws.String(r.tables.Feeds),
-		FilterExpression: aws.String("#url = :url"),
+	canonical, err := r.GetByID(ctx, model.CanonicalFeedID(url))
+	if err == nil {
+		return canonical, nil
+	}
+	if !strings.Contains(err.Error(), "not found") {
+		return nil, err
+	}
+
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(r.tables.Feeds),
+		IndexName:              aws.String("UrlIndex"),
+		KeyConditionExpression: aws.String("#url = :url"),
 		ExpressionAttributeNames: map[string]string{
 			"#url": "url", // 'url' might be a reserved keyword
 		},
@@ -159,9 +229,9 @@ func (r *feedRepository) GetByURL(ctx context.Context, url string) (*model.Feed,
 		Limit: aws.Int32(1),
 	}
 
-	result, err := r.client.Scan(ctx, input)
+	result, err := r.client.Query(ctx, input)
 	if err != nil {
-		return nil, fmt.Errorf("failed to scan feeds by URL: %w", err)
+		return nil, fmt.Errorf("failed to query feeds by URL: %w", err)
 	}
 
 	if len(result.Items) == 0 {
@@ -177,7 +247,8 @@ func (r *feedRepository) GetByURL(ctx context.Context, url string) (*model.Feed,
 	return &feed, nil
 }
 
-// Update updates an existing feed
+// Update updates an existing feed. The subscriber count belongs to Subscribe and Unsubscribe:
+// if it changed since the feed was read, the stored count is kept and the write is retried.
 func (r *feedRepository) Update(ctx context.Context, feed *model.Feed) error {
 	if feed == nil {
 		return errors.New("feed cannot be nil")
@@ -189,29 +260,50 @@ func (r *feedRepository) Update(ctx context.Context, feed *model.Feed) error {
 	// Update timestamp
 	feed.UpdateLastUpdated()
 
-	// Marshal feed to DynamoDB attribute values
-	item, err := attributevalue.MarshalMap(feed)
-	if err != nil {
-		return fmt.Errorf("failed to marshal feed: %w", err)
-	}
+	const maxAttempts = 3
 
-	// Update the item with condition that feed_id exists
-	input := &dynamodb.PutItemInput{
-		TableName:           aws.String(r.tables.Feeds),
-		Item:                item,
-		ConditionExpression: aws.String("attribute_exists(feed_id)"),
-	}
+	for attempt := 1; ; attempt++ {
+		// Marshal feed to DynamoDB attribute values
+		item, err := attributevalue.MarshalMap(feed)
+		if err != nil {
+			return fmt.Errorf("failed to marshal feed: %w", err)
+		}
+
+		// Update the item with condition that feed_id exists and no subscription changed in between
+		input := &dynamodb.PutItemInput{
+			TableName: aws.String(r.tables.Feeds),
+			Item:      item,
+			ConditionExpression: aws.String("attribute_exists(feed_id) AND " +
+				"(attribute_not_exists(subscriber_count) OR subscriber_count = :subscriber_count)"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":subscriber_count": &types.AttributeValueMemberN{Value: strconv.Itoa(feed.SubscriberCount)},
+			},
+			ReturnValuesOnConditionCheckFailure: types.ReturnValuesOnConditionCheckFailureAllOld,
+		}
+
+		_, err = r.client.PutItem(ctx, input)
+		if err == nil {
+			return nil
+		}
 
-	_, err = r.client.PutItem(ctx, input)
-	if err != nil {
 		var conditionalCheckErr *types.ConditionalCheckFailedException
-		if errors.As(err, &conditionalCheckErr) {
+		if !errors.As(err, &conditionalCheckErr) {
+			return fmt.Errorf("failed to update feed: %w", err)
+		}
+		if conditionalCheckErr.Item == nil {
 			return fmt.Errorf("feed with ID %s not found", feed.FeedID)
 		}
-		return fmt.Errorf("failed to update feed: %w", err)
-	}
+		if attempt >= maxAttempts {
+			return fmt.Errorf("failed to update feed: subscriber count of feed %s keeps changing", feed.FeedID)
+		}
 
-	return nil
+		var current model.Feed
+		if err := attributevalue.UnmarshalMap(conditionalCheckErr.Item, &current); err != nil {
+			return fmt.Errorf("failed to unmarshal feed: %w", err)
+		}
+		feed.SubscriberCount = current.SubscriberCount
+		feed.LegacyBowerID = current.LegacyBowerID
+	}
 }
 
 // Delete deletes a feed by its ID
@@ -240,6 +332,37 @@ func (r *feedRepository) Delete(ctx context.Context, feedID string) error {
 	return nil
 }
 
+// DeleteIfUnsubscribed deletes a feed only if no bower subscribes to it, legacy bower included.
+// It reports whether the feed was deleted.
+func (r *feedRepository) DeleteIfUnsubscribed(ctx context.Context, feedID string) (bool, error) {
+	if feedID == "" {
+		return false, errors.New("feedID cannot be empty")
+	}
+
+	input := &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.tables.Feeds),
+		Key: map[string]types.AttributeValue{
+			"feed_id": &types.AttributeValueMemberS{Value: feedID},
+		},
+		ConditionExpression: aws.String("attribute_exists(feed_id) AND attribute_not_exists(bower_id) AND " +
+			"(attribute_not_exists(subscriber_count) OR subscriber_count <= :zero)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":zero": &types.AttributeValueMemberN{Value: "0"},
+		},
+	}
+
+	_, err := r.client.DeleteItem(ctx, input)
+	if err != nil {
+		var conditionalCheckErr *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionalCheckErr) {
+			return false, nil
+		}
+		return false, fmt.Errorf("failed to delete feed: %w", err)
+	}
+
+	return true, nil
+}
+
 // List retrieves a paginated list of feeds
 func (r *feedRepository) List(ctx context.Context, limit int32, lastKey map[string]types.AttributeValue) ([]*model.Feed, map[string]types.AttributeValue, error) {
 	if limit <= 0 {
@@ -308,3 +431,460 @@ func (r *feedRepository) GetStaleFeeds(ctx context.Context, maxAgeSeconds int64,
 
 	return feeds, nil
 }
+
+// GetDueFeeds retrieves subscribed feeds, legacy ones included, whose next fetch time has passed
+// (or was never scheduled).
+// Dead feeds are included, since their next fetch time is their next recovery probe.
+func (r *feedRepository) GetDueFeeds(ctx context.Context, now int64, limit int32) ([]*model.Feed, error) {
+	if limit <= 0 {
+		limit = 50 // Default limit
+	}
+
+	feeds := make([]*model.Feed, 0)
+	var lastKey map[string]types.AttributeValue
+
+	// Scan Limit applies before the filter, so keep paging until we have enough due feeds
+	for {
+		input := &dynamodb.ScanInput{
+			TableName: aws.String(r.tables.Feeds),
+			FilterExpression: aws.String("(attribute_not_exists(next_fetch_at) OR next_fetch_at <= :now) AND " +
+				"(subscriber_count > :zero OR attribute_exists(bower_id))"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":now":  &types.AttributeValueMemberN{Value: fmt.Sprintf("%d", now)},
+				":zero": &types.AttributeValueMemberN{Value: "0"},
+			},
+			ExclusiveStartKey: lastKey,
+		}
+
+		result, err := r.client.Scan(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get due feeds: %w", err)
+		}
+
+		for _, item := range result.Items {
+			var feed model.Feed
+			err = attributevalue.UnmarshalMap(item, &feed)
+			if err != nil {
+				return nil, fmt.Errorf("failed to unmarshal feed: %w", err)
+			}
+			feeds = append(feeds, &feed)
+			if int32(len(feeds)) >= limit {
+				return feeds, nil
+			}
+		}
+
+		if len(result.LastEvaluatedKey) == 0 {
+			break
+		}
+		lastKey = result.LastEvaluatedKey
+	}
+
+	return feeds, nil
+}
+
+// Subscribe links a bower to a feed and increments the feed's subscriber count
+func (r *feedRepository) Subscribe(ctx context.Context, subscription *model.Subscription) error {
+	if subscription == nil {
+		return errors.New("subscription cannot be nil")
+	}
+	if subscription.BowerID == "" {
+		return errors.New("bower ID cannot be empty")
+	}
+	if subscription.FeedID == "" {
+		return errors.New("feed ID cannot be empty")
+	}
+
+	item, err := attributevalue.MarshalMap(subscription)
+	if err != nil {
+		return fmt.Errorf("failed to marshal subscription: %w", err)
+	}
+
+	input := &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{
+				Put: &types.Put{
+					TableName:           aws.String(r.tables.Subscriptions),
+					Item:                item,
+					ConditionExpression: aws.String("attribute_not_exists(bower_id)"),
+				},
+			},
+			{
+				Update: &types.Update{
+					TableName:           aws.String(r.tables.Feeds),
+					Key:                 feedKey(subscription.FeedID),
+					UpdateExpression:    aws.String("ADD subscriber_count :one"),
+					ConditionExpression: aws.String("attribute_exists(feed_id)"),
+					ExpressionAttributeValues: map[string]types.AttributeValue{
+						":one": &types.AttributeValueMemberN{Value: "1"},
+					},
+				},
+			},
+		},
+	}
+
+	_, err = r.client.TransactWriteItems(ctx, input)
+	if err != nil {
+		switch failedConditionIndex(err) {
+		case 0:
+			return fmt.Errorf("subscription to feed %s already exists in bower %s", subscription.FeedID, subscription.BowerID)
+		case 1:
+			return fmt.Errorf("feed with ID %s not found", subscription.FeedID)
+		}
+		return fmt.Errorf("failed to subscribe to feed: %w", err)
+	}
+
+	return nil
+}
+
+// Unsubscribe removes a bower's subscription to a feed and decrements the feed's subscriber count
+func (r *feedRepository) Unsubscribe(ctx context.Context, bowerID, feedID string) error {
+	if bowerID == "" {
+		return errors.New("bowerID cannot be empty")
+	}
+	if feedID == "" {
+		return errors.New("feedID cannot be empty")
+	}
+
+	input := &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{
+				Delete: &types.Delete{
+					TableName:           aws.String(r.tables.Subscriptions),
+					Key:                 subscriptionKey(bowerID, feedID),
+					ConditionExpression: aws.String("attribute_exists(bower_id)"),
+				},
+			},
+			{
+				Update: &types.Update{
+					TableName:           aws.String(r.tables.Feeds),
+					Key:                 feedKey(feedID),
+					UpdateExpression:    aws.String("ADD subscriber_count :minus_one"),
+					ConditionExpression: aws.String("attribute_exists(feed_id)"),
+					ExpressionAttributeValues: map[string]types.AttributeValue{
+						":minus_one": &types.AttributeValueMemberN{Value: "-1"},
+					},
+				},
+			},
+		},
+	}
+
+	_, err := r.client.TransactWriteItems(ctx, input)
+	if err == nil {
+		return nil
+	}
+
+	switch failedConditionIndex(err) {
+	case 0:
+		return r.removeLegacyBower(ctx, bowerID, feedID)
+	case 1:
+		// The feed is already gone; just remove the dangling subscription
+		_, err = r.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+			TableName: aws.String(r.tables.Subscriptions),
+			Key:       subscriptionKey(bowerID, feedID),
+		})
+		if err != nil {
+			return fmt.Errorf("failed to delete subscription: %w", err)
+		}
+		return nil
+	}
+	return fmt.Errorf("failed to unsubscribe from feed: %w", err)
+}
+
+// GetSubscription retrieves a bower's subscription to a feed
+func (r *feedRepository) GetSubscription(ctx context.Context, bowerID, feedID string) (*model.Subscription, error) {
+	if bowerID == "" {
+		return nil, errors.New("bowerID cannot be empty")
+	}
+	if feedID == "" {
+		return nil, errors.New("feedID cannot be empty")
+	}
+
+	input := &dynamodb.GetItemInput{
+		TableName: aws.String(r.tables.Subscriptions),
+		Key:       subscriptionKey(bowerID, feedID),
+	}
+
+	result, err := r.client.GetItem(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get subscription: %w", err)
+	}
+
+	if result.Item == nil {
+		feed, err := r.GetByID(ctx, feedID)
+		if err == nil && feed.LegacyBowerID == bowerID {
+			return model.NewLegacySubscription(feed), nil
+		}
+		return nil, fmt.Errorf("subscription to feed %s not found in bower %s", feedID, bowerID)
+	}
+
+	var subscription model.Subscription
+	if err := attributevalue.UnmarshalMap(result.Item, &subscription); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal subscription: %w", err)
+	}
+
+	return &subscription, nil
+}
+
+// UpdateSubscription updates a bower's overrides for a feed. A legacy bower's link is migrated to a
+// subscription first.
+func (r *feedRepository) UpdateSubscription(ctx context.Context, subscription *model.Subscription) error {
+	if subscription == nil {
+		return errors.New("subscription cannot be nil")
+	}
+	if subscription.BowerID == "" || subscription.FeedID == "" {
+		return errors.New("bower ID and feed ID cannot be empty")
+	}
+
+	feed, err := r.GetByID(ctx, subscription.FeedID)
+	if err == nil && feed.LegacyBowerID == subscription.BowerID {
+		if err := r.MigrateLegacySubscription(ctx, feed); err != nil {
+			return err
+		}
+	}
+
+	item, err := attributevalue.MarshalMap(subscription)
+	if err != nil {
+		return fmt.Errorf("failed to marshal subscription: %w", err)
+	}
+
+	input := &dynamodb.PutItemInput{
+		TableName:           aws.String(r.tables.Subscriptions),
+		Item:                item,
+		ConditionExpression: aws.String("attribute_exists(bower_id)"),
+	}
+
+	_, err = r.client.PutItem(ctx, input)
+	if err != nil {
+		var conditionalCheckErr *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionalCheckErr) {
+			return fmt.Errorf("subscription to feed %s not found in bower %s", subscription.FeedID, subscription.BowerID)
+		}
+		return fmt.Errorf("failed to update subscription: %w", err)
+	}
+
+	return nil
+}
+
+// GetSubscriptionsByBowerID retrieves all subscriptions of a bower, including the links of the
+// legacy feeds it owns
+func (r *feedRepository) GetSubscriptionsByBowerID(ctx context.Context, bowerID string) ([]*model.Subscription, error) {
+	if bowerID == "" {
+		return nil, errors.New("bowerID cannot be empty")
+	}
+
+	subscriptions, err := r.querySubscriptions(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(r.tables.Subscriptions),
+		KeyConditionExpression: aws.String("bower_id = :bower_id"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":bower_id": &types.AttributeValueMemberS{Value: bowerID},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	legacyFeeds, err := r.getLegacyFeeds(ctx, bowerID)
+	if err != nil {
+		return nil, err
+	}
+	for _, feed := range legacyFeeds {
+		subscriptions = append(subscriptions, model.NewLegacySubscription(feed))
+	}
+
+	return subscriptions, nil
+}
+
+// GetSubscriptionsByFeedID retrieves all subscriptions to a feed using GSI, including the link of
+// the feed's legacy bower
+func (r *feedRepository) GetSubscriptionsByFeedID(ctx context.Context, feedID string) ([]*model.Subscription, error) {
+	if feedID == "" {
+		return nil, errors.New("feedID cannot be empty")
+	}
+
+	subscriptions, err := r.querySubscriptions(ctx, &dynamodb.QueryInput{
+		TableName:              aws.String(r.tables.Subscriptions),
+		IndexName:              aws.String("FeedIdIndex"),
+		KeyConditionExpression: aws.String("feed_id = :feed_id"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":feed_id": &types.AttributeValueMemberS{Value: feedID},
+		},
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	feed, err := r.GetByID(ctx, feedID)
+	if err == nil && feed.LegacyBowerID != "" {
+		subscriptions = append(subscriptions, model.NewLegacySubscription(feed))
+	}
+
+	return subscriptions, nil
+}
+
+// MigrateLegacySubscription turns the link of a legacy feed's bower into a subscription, counting it
+// in the feed's subscriber count
+func (r *feedRepository) MigrateLegacySubscription(ctx context.Context, feed *model.Feed) error {
+	if feed == nil {
+		return errors.New("feed cannot be nil")
+	}
+	if feed.LegacyBowerID == "" {
+		return nil
+	}
+
+	item, err := attributevalue.MarshalMap(model.NewLegacySubscription(feed))
+	if err != nil {
+		return fmt.Errorf("failed to marshal subscription: %w", err)
+	}
+
+	input := &dynamodb.TransactWriteItemsInput{
+		TransactItems: []types.TransactWriteItem{
+			{
+				Put: &types.Put{
+					TableName:           aws.String(r.tables.Subscriptions),
+					Item:                item,
+					ConditionExpression: aws.String("attribute_not_exists(bower_id)"),
+				},
+			},
+			{
+				Update: &types.Update{
+					TableName:           aws.String(r.tables.Feeds),
+					Key:                 feedKey(feed.FeedID),
+					UpdateExpression:    aws.String("REMOVE bower_id ADD subscriber_count :one"),
+					ConditionExpression: aws.String("bower_id = :bower_id"),
+					ExpressionAttributeValues: map[string]types.AttributeValue{
+						":one":      &types.AttributeValueMemberN{Value: "1"},
+						":bower_id": &types.AttributeValueMemberS{Value: feed.LegacyBowerID},
+					},
+				},
+			},
+		},
+	}
+
+	_, err = r.client.TransactWriteItems(ctx, input)
+	switch failedConditionIndex(err) {
+	case -1:
+		if err != nil {
+			return fmt.Errorf("failed to migrate legacy subscription: %w", err)
+		}
+	case 0:
+		// The bower subscribed again after the link was read; the link is redundant
+		if err := r.removeLegacyBower(ctx, feed.LegacyBowerID, feed.FeedID); err != nil && !strings.Contains(err.Error(), "not found") {
+			return err
+		}
+	case 1:
+		// Already migrated
+	}
+
+	feed.LegacyBowerID = ""
+	return nil
+}
+
+// removeLegacyBower removes the link of a legacy feed to the bower that owned it
+func (r *feedRepository) removeLegacyBower(ctx context.Context, bowerID, feedID string) error {
+	_, err := r.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+		TableName:           aws.String(r.tables.Feeds),
+		Key:                 feedKey(feedID),
+		UpdateExpression:    aws.String("REMOVE bower_id"),
+		ConditionExpression: aws.String("bower_id = :bower_id"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":bower_id": &types.AttributeValueMemberS{Value: bowerID},
+		},
+	})
+	if err != nil {
+		var conditionalCheckErr *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionalCheckErr) {
+			return fmt.Errorf("subscription to feed %s not found in bower %s", feedID, bowerID)
+		}
+		return fmt.Errorf("failed to remove legacy bower: %w", err)
+	}
+	return nil
+}
+
+// getLegacyFeeds retrieves the legacy feeds a bower owns using GSI
+func (r *feedRepository) getLegacyFeeds(ctx context.Context, bowerID string) ([]*model.Feed, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(r.tables.Feeds),
+		IndexName:              aws.String("BowerIdIndex"),
+		KeyConditionExpression: aws.String("bower_id = :bower_id"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":bower_id": &types.AttributeValueMemberS{Value: bowerID},
+		},
+	}
+
+	feeds := make([]*model.Feed, 0)
+	for {
+		result, err := r.client.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query legacy feeds: %w", err)
+		}
+
+		for _, item := range result.Items {
+			var feed model.Feed
+			if err := attributevalue.UnmarshalMap(item, &feed); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal feed: %w", err)
+			}
+			feeds = append(feeds, &feed)
+		}
+
+		if len(result.LastEvaluatedKey) == 0 {
+			return feeds, nil
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+}
+
+// querySubscriptions runs a subscription query through all result pages
+func (r *feedRepository) querySubscriptions(ctx context.Context, input *dynamodb.QueryInput) ([]*model.Subscription, error) {
+	subscriptions := make([]*model.Subscription, 0)
+
+	for {
+		result, err := r.client.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query subscriptions: %w", err)
+		}
+
+		for _, item := range result.Items {
+			var subscription model.Subscription
+			if err := attributevalue.UnmarshalMap(item, &subscription); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal subscription: %w", err)
+			}
+			subscriptions = append(subscriptions, &subscription)
+		}
+
+		if len(result.LastEvaluatedKey) == 0 {
+			break
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+
+	return subscriptions, nil
+}
+
+// feedKey builds the primary key of a feed item
+func feedKey(feedID string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"feed_id": &types.AttributeValueMemberS{Value: feedID},
+	}
+}
+
+// subscriptionKey builds the primary key of a subscription item
+func subscriptionKey(bowerID, feedID string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"bower_id": &types.AttributeValueMemberS{Value: bowerID},
+		"feed_id":  &types.AttributeValueMemberS{Value: feedID},
+	}
+}
+
+// failedConditionIndex returns the index of the transaction item whose condition check failed, or -1
+func failedConditionIndex(err error) int {
+	var canceledErr *types.TransactionCanceledException
+	if !errors.As(err, &canceledErr) {
+		return -1
+	}
+	for i, reason := range canceledErr.CancellationReasons {
+		if aws.ToString(reason.Code) == "ConditionalCheckFailed" {
+			return i
+		}
+	}
+	return -1
+}
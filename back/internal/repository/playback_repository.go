@@ -0,0 +1,196 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"feed-bower-api/internal/model"
+	dynamodbpkg "feed-bower-api/pkg/dynamodb"
+)
+
+// PlaybackRepository defines the interface for per-user podcast playback position operations
+type PlaybackRepository interface {
+	Save(ctx context.Context, position *model.PlaybackPosition) error
+	Get(ctx context.Context, userID, articleID string) (*model.PlaybackPosition, error)
+	GetPositions(ctx context.Context, userID string, articleIDs []string) (map[string]*model.PlaybackPosition, error)
+	DeleteByArticleID(ctx context.Context, articleID string) (int, error)
+	DeleteByUserID(ctx context.Context, userID string) (int, error)
+}
+
+// playbackRepository implements PlaybackRepository interface
+type playbackRepository struct {
+	client *dynamodbpkg.Client
+	tables *dynamodbpkg.TableNames
+}
+
+// NewPlaybackRepository creates a new playback position repository
+func NewPlaybackRepository(client *dynamodbpkg.Client) PlaybackRepository {
+	return &playbackRepository{
+		client: client,
+		tables: client.GetTableNames(),
+	}
+}
+
+// Save stores a user's playback position for an episode, replacing the previous one
+func (r *playbackRepository) Save(ctx context.Context, position *model.PlaybackPosition) error {
+	if position == nil {
+		return errors.New("playback position cannot be nil")
+	}
+	if position.UserID == "" {
+		return errors.New("user ID cannot be empty")
+	}
+	if position.ArticleID == "" {
+		return errors.New("article ID cannot be empty")
+	}
+
+	item, err := attributevalue.MarshalMap(position)
+	if err != nil {
+		return fmt.Errorf("failed to marshal playback position: %w", err)
+	}
+
+	input := &dynamodb.PutItemInput{
+		TableName: aws.String(r.tables.PlaybackPositions),
+		Item:      item,
+	}
+
+	_, err = r.client.PutItem(ctx, input)
+	if err != nil {
+		return fmt.Errorf("failed to save playback position: %w", err)
+	}
+
+	return nil
+}
+
+// Get returns a user's playback position for an episode, or nil if the user has not played it
+func (r *playbackRepository) Get(ctx context.Context, userID, articleID string) (*model.PlaybackPosition, error) {
+	if userID == "" {
+		return nil, errors.New("userID cannot be empty")
+	}
+	if articleID == "" {
+		return nil, errors.New("articleID cannot be empty")
+	}
+
+	input := &dynamodb.GetItemInput{
+		TableName: aws.String(r.tables.PlaybackPositions),
+		Key:       playbackKey(userID, articleID),
+	}
+
+	result, err := r.client.GetItem(ctx, input)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get playback position: %w", err)
+	}
+	if result.Item == nil {
+		return nil, nil
+	}
+
+	var position model.PlaybackPosition
+	if err := attributevalue.UnmarshalMap(result.Item, &position); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal playback position: %w", err)
+	}
+
+	return &position, nil
+}
+
+// GetPositions returns the user's playback positions for the given episodes, keyed by article ID.
+// Episodes the user has not played are not included.
+func (r *playbackRepository) GetPositions(ctx context.Context, userID string, articleIDs []string) (map[string]*model.PlaybackPosition, error) {
+	if userID == "" {
+		return nil, errors.New("userID cannot be empty")
+	}
+
+	positions := make(map[string]*model.PlaybackPosition)
+
+	// DynamoDB batch get can handle up to 100 keys at a time
+	const batchSize = 100
+
+	seen := make(map[string]bool, len(articleIDs))
+	keys := make([]map[string]types.AttributeValue, 0, len(articleIDs))
+	for _, articleID := range articleIDs {
+		if articleID == "" || seen[articleID] {
+			continue
+		}
+		seen[articleID] = true
+		keys = append(keys, playbackKey(userID, articleID))
+	}
+
+	for i := 0; i < len(keys); i += batchSize {
+		end := i + batchSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		requestItems := map[string]types.KeysAndAttributes{
+			r.tables.PlaybackPositions: {Keys: keys[i:end]},
+		}
+
+		// Retry unprocessed keys until the batch is complete
+		for len(requestItems) > 0 {
+			result, err := r.client.BatchGetItem(ctx, &dynamodb.BatchGetItemInput{RequestItems: requestItems})
+			if err != nil {
+				return nil, fmt.Errorf("failed to batch get playback positions: %w", err)
+			}
+
+			for _, item := range result.Responses[r.tables.PlaybackPositions] {
+				var position model.PlaybackPosition
+				if err := attributevalue.UnmarshalMap(item, &position); err != nil {
+					return nil, fmt.Errorf("failed to unmarshal playback position: %w", err)
+				}
+				positions[position.ArticleID] = &position
+			}
+
+			requestItems = result.UnprocessedKeys
+		}
+	}
+
+	return positions, nil
+}
+
+// DeleteByArticleID removes every user's playback position for an episode and returns how many were removed
+func (r *playbackRepository) DeleteByArticleID(ctx context.Context, articleID string) (int, error) {
+	if articleID == "" {
+		return 0, errors.New("articleID cannot be empty")
+	}
+
+	keys, err := queryUserArticleKeysByArticleID(ctx, r.client, r.tables.PlaybackPositions, articleID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find playback positions: %w", err)
+	}
+
+	if err := batchDeleteKeys(ctx, r.client, r.tables.PlaybackPositions, keys); err != nil {
+		return 0, fmt.Errorf("failed to delete playback positions: %w", err)
+	}
+
+	return len(keys), nil
+}
+
+// DeleteByUserID removes all of a user's playback positions and returns how many were removed
+func (r *playbackRepository) DeleteByUserID(ctx context.Context, userID string) (int, error) {
+	if userID == "" {
+		return 0, errors.New("userID cannot be empty")
+	}
+
+	keys, err := queryUserArticleKeysByUserID(ctx, r.client, r.tables.PlaybackPositions, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find playback positions: %w", err)
+	}
+
+	if err := batchDeleteKeys(ctx, r.client, r.tables.PlaybackPositions, keys); err != nil {
+		return 0, fmt.Errorf("failed to delete playback positions: %w", err)
+	}
+
+	return len(keys), nil
+}
+
+// playbackKey builds the primary key of a playback position item
+func playbackKey(userID, articleID string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"user_id":    &types.AttributeValueMemberS{Value: userID},
+		"article_id": &types.AttributeValueMemberS{Value: articleID},
+	}
+}
@@ -0,0 +1,213 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"feed-bower-api/internal/model"
+	dynamodbpkg "feed-bower-api/pkg/dynamodb"
+)
+
+// ReadStateRepository defines the interface for per-user article read state operations
+type ReadStateRepository interface {
+	MarkRead(ctx context.Context, readState *model.ReadState) error
+	MarkUnread(ctx context.Context, userID, articleID string) error
+	IsRead(ctx context.Context, userID, articleID string) (bool, error)
+	GetReadArticleIDs(ctx context.Context, userID string, articleIDs []string) (map[string]bool, error)
+	DeleteByArticleID(ctx context.Context, articleID string) (int, error)
+	DeleteByUserID(ctx context.Context, userID string) (int, error)
+}
+
+// readStateRepository implements ReadStateRepository interface
+type readStateRepository struct {
+	client *dynamodbpkg.Client
+	tables *dynamodbpkg.TableNames
+}
+
+// NewReadStateRepository creates a new read state repository
+func NewReadStateRepository(client *dynamodbpkg.Client) ReadStateRepository {
+	return &readStateRepository{
+		client: client,
+		tables: client.GetTableNames(),
+	}
+}
+
+// MarkRead records that a user has read an article. Marking an already read article updates its read time.
+func (r *readStateRepository) MarkRead(ctx context.Context, readState *model.ReadState) error {
+	if readState == nil {
+		return errors.New("read state cannot be nil")
+	}
+	if readState.UserID == "" {
+		return errors.New("user ID cannot be empty")
+	}
+	if readState.ArticleID == "" {
+		return errors.New("article ID cannot be empty")
+	}
+
+	item, err := attributevalue.MarshalMap(readState)
+	if err != nil {
+		return fmt.Errorf("failed to marshal read state: %w", err)
+	}
+
+	input := &dynamodb.PutItemInput{
+		TableName: aws.String(r.tables.ReadStates),
+		Item:      item,
+	}
+
+	_, err = r.client.PutItem(ctx, input)
+	if err != nil {
+		return fmt.Errorf("failed to mark article as read: %w", err)
+	}
+
+	return nil
+}
+
+// MarkUnread removes a user's read state for an article. Marking an unread article is a no-op.
+func (r *readStateRepository) MarkUnread(ctx context.Context, userID, articleID string) error {
+	if userID == "" {
+		return errors.New("userID cannot be empty")
+	}
+	if articleID == "" {
+		return errors.New("articleID cannot be empty")
+	}
+
+	input := &dynamodb.DeleteItemInput{
+		TableName: aws.String(r.tables.ReadStates),
+		Key:       readStateKey(userID, articleID),
+	}
+
+	_, err := r.client.DeleteItem(ctx, input)
+	if err != nil {
+		return fmt.Errorf("failed to mark article as unread: %w", err)
+	}
+
+	return nil
+}
+
+// IsRead checks if an article has been read by a user
+func (r *readStateRepository) IsRead(ctx context.Context, userID, articleID string) (bool, error) {
+	if userID == "" {
+		return false, errors.New("userID cannot be empty")
+	}
+	if articleID == "" {
+		return false, errors.New("articleID cannot be empty")
+	}
+
+	input := &dynamodb.GetItemInput{
+		TableName: aws.String(r.tables.ReadStates),
+		Key:       readStateKey(userID, articleID),
+	}
+
+	result, err := r.client.GetItem(ctx, input)
+	if err != nil {
+		return false, fmt.Errorf("failed to check if article is read: %w", err)
+	}
+
+	return result.Item != nil, nil
+}
+
+// GetReadArticleIDs returns which of the given articles the user has read
+func (r *readStateRepository) GetReadArticleIDs(ctx context.Context, userID string, articleIDs []string) (map[string]bool, error) {
+	if userID == "" {
+		return nil, errors.New("userID cannot be empty")
+	}
+
+	readIDs := make(map[string]bool)
+
+	// DynamoDB batch get can handle up to 100 keys at a time
+	const batchSize = 100
+
+	seen := make(map[string]bool, len(articleIDs))
+	keys := make([]map[string]types.AttributeValue, 0, len(articleIDs))
+	for _, articleID := range articleIDs {
+		if articleID == "" || seen[articleID] {
+			continue
+		}
+		seen[articleID] = true
+		keys = append(keys, readStateKey(userID, articleID))
+	}
+
+	for i := 0; i < len(keys); i += batchSize {
+		end := i + batchSize
+		if end > len(keys) {
+			end = len(keys)
+		}
+
+		requestItems := map[string]types.KeysAndAttributes{
+			r.tables.ReadStates: {
+				Keys:                 keys[i:end],
+				ProjectionExpression: aws.String("article_id"),
+			},
+		}
+
+		// Retry unprocessed keys until the batch is complete
+		for len(requestItems) > 0 {
+			result, err := r.client.BatchGetItem(ctx, &dynamodb.BatchGetItemInput{RequestItems: requestItems})
+			if err != nil {
+				return nil, fmt.Errorf("failed to batch get read states: %w", err)
+			}
+
+			for _, item := range result.Responses[r.tables.ReadStates] {
+				var readState model.ReadState
+				if err := attributevalue.UnmarshalMap(item, &readState); err != nil {
+					return nil, fmt.Errorf("failed to unmarshal read state: %w", err)
+				}
+				readIDs[readState.ArticleID] = true
+			}
+
+			requestItems = result.UnprocessedKeys
+		}
+	}
+
+	return readIDs, nil
+}
+
+// DeleteByArticleID removes every user's read state for an article and returns how many were removed
+func (r *readStateRepository) DeleteByArticleID(ctx context.Context, articleID string) (int, error) {
+	if articleID == "" {
+		return 0, errors.New("articleID cannot be empty")
+	}
+
+	keys, err := queryUserArticleKeysByArticleID(ctx, r.client, r.tables.ReadStates, articleID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find read states: %w", err)
+	}
+
+	if err := batchDeleteKeys(ctx, r.client, r.tables.ReadStates, keys); err != nil {
+		return 0, fmt.Errorf("failed to delete read states: %w", err)
+	}
+
+	return len(keys), nil
+}
+
+// DeleteByUserID removes all of a user's read states and returns how many were removed
+func (r *readStateRepository) DeleteByUserID(ctx context.Context, userID string) (int, error) {
+	if userID == "" {
+		return 0, errors.New("userID cannot be empty")
+	}
+
+	keys, err := queryUserArticleKeysByUserID(ctx, r.client, r.tables.ReadStates, userID)
+	if err != nil {
+		return 0, fmt.Errorf("failed to find read states: %w", err)
+	}
+
+	if err := batchDeleteKeys(ctx, r.client, r.tables.ReadStates, keys); err != nil {
+		return 0, fmt.Errorf("failed to delete read states: %w", err)
+	}
+
+	return len(keys), nil
+}
+
+// readStateKey builds the primary key of a read state item
+func readStateKey(userID, articleID string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"user_id":    &types.AttributeValueMemberS{Value: userID},
+		"article_id": &types.AttributeValueMemberS{Value: articleID},
+	}
+}
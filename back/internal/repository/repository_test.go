@@ -4,6 +4,8 @@ import (
 	"context"
 	"testing"
 
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
 	"feed-bower-api/internal/model"
 	dynamodbpkg "feed-bower-api/pkg/dynamodb"
 )
@@ -19,6 +21,10 @@ func TestRepositoryInterfaces(t *testing.T) {
 	var _ FeedRepository = NewFeedRepository(client)
 	var _ ArticleRepository = NewArticleRepository(client)
 	var _ ChickRepository = NewChickRepository(client)
+	var _ ReadStateRepository = NewReadStateRepository(client)
+	var _ PlaybackRepository = NewPlaybackRepository(client)
+	var _ SearchIndex = NewEmbeddedSearchIndex()
+	var _ SearchIndex = NewDynamoDBSearchIndex(client)
 
 	t.Log("All repository interfaces are correctly implemented")
 }
@@ -141,3 +147,86 @@ func TestValidationErrors(t *testing.T) {
 
 	t.Log("All validation errors handled correctly")
 }
+
+// TestPageKeys verifies that page keys parse back to the position they were created from
+func TestPageKeys(t *testing.T) {
+	position := ArticlePosition{PublishedAt: 1767225600, ArticleID: "article-1"}
+	parsed, err := ParseArticleKey(position.Key())
+	if err != nil || parsed == nil || *parsed != position {
+		t.Errorf("Expected article key to parse back to %+v, got %+v (%v)", position, parsed, err)
+	}
+
+	hit := SearchHit{ArticleID: "article-1", Score: 3.2818, PublishedAt: 1767225600}
+	parsedHit, err := ParseSearchKey(hit.Key())
+	if err != nil || parsedHit == nil || *parsedHit != hit {
+		t.Errorf("Expected search key to parse back to %+v, got %+v (%v)", hit, parsedHit, err)
+	}
+
+	horizon := ArticlePosition{PublishedAt: 1767225600}
+	parsed, err = ParseArticleKey(horizon.Key())
+	if err != nil || parsed == nil || *parsed != horizon {
+		t.Errorf("Expected horizon key to parse back to %+v, got %+v (%v)", horizon, parsed, err)
+	}
+
+	if _, err := ParseArticleKey(map[string]types.AttributeValue{"offset": &types.AttributeValueMemberN{Value: "1"}}); err == nil {
+		t.Error("Expected a key without a published time to be rejected")
+	}
+
+	hits := []SearchHit{
+		{ArticleID: "b", Score: 2, PublishedAt: 10},
+		{ArticleID: "a", Score: 1, PublishedAt: 20},
+		{ArticleID: "c", Score: 1, PublishedAt: 20},
+		{ArticleID: "d", Score: 1, PublishedAt: 10},
+	}
+	got := hitsAfter(hits, &hits[1], 2)
+	if len(got) != 2 || got[0].ArticleID != "c" || got[1].ArticleID != "d" {
+		t.Errorf("Expected the two hits after a, got %+v", got)
+	}
+}
+
+// TestPageArticles verifies that merged feed items are cut at the horizon of a capped feed read
+func TestPageArticles(t *testing.T) {
+	newArticles := func() []*model.Article {
+		return []*model.Article{
+			{ArticleID: "c", PublishedAt: 10},
+			{ArticleID: "a", PublishedAt: 30},
+			{ArticleID: "d", PublishedAt: 20},
+			{ArticleID: "b", PublishedAt: 20},
+		}
+	}
+	ids := func(articles []*model.Article) string {
+		var result string
+		for _, article := range articles {
+			result += article.ArticleID
+		}
+		return result
+	}
+
+	tests := []struct {
+		name    string
+		limit   int
+		more    bool
+		horizon *ArticlePosition
+		want    string
+		next    *ArticlePosition
+	}{
+		{name: "last page", limit: 10, want: "abdc"},
+		{name: "cut by limit", limit: 2, want: "ab", next: &ArticlePosition{PublishedAt: 20, ArticleID: "b"}},
+		{name: "feed has more", limit: 4, more: true, want: "abdc", next: &ArticlePosition{PublishedAt: 10, ArticleID: "c"}},
+		{name: "cut at horizon", limit: 10, more: true, horizon: &ArticlePosition{PublishedAt: 20}, want: "a", next: &ArticlePosition{PublishedAt: 20}},
+		{name: "empty page before horizon", limit: 10, more: true, horizon: &ArticlePosition{PublishedAt: 40}, want: "", next: &ArticlePosition{PublishedAt: 40}},
+		{name: "cut by limit before horizon", limit: 1, more: true, horizon: &ArticlePosition{PublishedAt: 10}, want: "a", next: &ArticlePosition{PublishedAt: 30, ArticleID: "a"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			articles, next := pageArticles(newArticles(), tt.limit, tt.more, tt.horizon)
+			if got := ids(articles); got != tt.want {
+				t.Errorf("pageArticles() = %s, expected %s", got, tt.want)
+			}
+			if (next == nil) != (tt.next == nil) || (next != nil && *next != *tt.next) {
+				t.Errorf("pageArticles() next = %+v, expected %+v", next, tt.next)
+			}
+		})
+	}
+}
@@ -0,0 +1,124 @@
+package repository
+
+import (
+	"context"
+	"errors"
+	"sort"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"feed-bower-api/internal/model"
+	"feed-bower-api/pkg/search"
+)
+
+// SearchIndex defines the interface for the full-text index behind ArticleRepository.Search.
+// Implementations must be safe for concurrent use.
+type SearchIndex interface {
+	IndexArticles(ctx context.Context, articles []*model.Article) error
+	RemoveArticles(ctx context.Context, articleIDs []string) error
+	// Search returns up to limit hits of the given feeds that rank after the given hit, or from
+	// the first one if after is nil
+	Search(ctx context.Context, query string, feedIDs []string, after *SearchHit, limit int) ([]SearchHit, error)
+}
+
+// SearchHit is an article matching a search, with its relevance score. Hits rank by score, then
+// newest first, then by ID.
+type SearchHit struct {
+	ArticleID   string  `dynamodbav:"article_id"`
+	Score       float64 `dynamodbav:"score"`
+	PublishedAt int64   `dynamodbav:"published_at"`
+}
+
+// RanksBefore reports whether the hit ranks before another hit
+func (h SearchHit) RanksBefore(other SearchHit) bool {
+	if h.Score != other.Score {
+		return h.Score > other.Score
+	}
+	if h.PublishedAt != other.PublishedAt {
+		return h.PublishedAt > other.PublishedAt
+	}
+	return h.ArticleID < other.ArticleID
+}
+
+// Key returns the hit as the key a page of search results continues after
+func (h SearchHit) Key() map[string]types.AttributeValue {
+	key, _ := attributevalue.MarshalMap(h)
+	return key
+}
+
+// ParseSearchKey returns the hit a page of search results continues after, or nil for the first page
+func ParseSearchKey(lastKey map[string]types.AttributeValue) (*SearchHit, error) {
+	if lastKey == nil {
+		return nil, nil
+	}
+
+	var hit SearchHit
+	if err := attributevalue.UnmarshalMap(lastKey, &hit); err != nil || hit.ArticleID == "" {
+		return nil, errors.New("malformed cursor")
+	}
+	return &hit, nil
+}
+
+// hitsAfter returns up to limit ranked hits after the given hit. A limit of zero or less returns all.
+func hitsAfter(hits []SearchHit, after *SearchHit, limit int) []SearchHit {
+	if after != nil {
+		start := sort.Search(len(hits), func(i int) bool { return after.RanksBefore(hits[i]) })
+		hits = hits[start:]
+	}
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+	return hits
+}
+
+// embeddedSearchIndex implements SearchIndex in process memory
+type embeddedSearchIndex struct {
+	index *search.Index
+}
+
+// NewEmbeddedSearchIndex creates an empty in-memory search index
+func NewEmbeddedSearchIndex() SearchIndex {
+	return &embeddedSearchIndex{
+		index: search.NewIndex(),
+	}
+}
+
+// IndexArticles adds or replaces articles in the index. Aliases are skipped: the article they
+// point at is indexed under all of its feeds.
+func (i *embeddedSearchIndex) IndexArticles(ctx context.Context, articles []*model.Article) error {
+	docs := make([]search.Document, 0, len(articles))
+	for _, article := range articles {
+		if article == nil || article.ArticleID == "" || article.IsAlias() {
+			continue
+		}
+		docs = append(docs, search.Document{
+			ID:     article.ArticleID,
+			Scopes: article.FeedIDs(),
+			Title:  article.Title,
+			Body:   article.Content,
+			Time:   article.PublishedAt,
+		})
+	}
+
+	i.index.Add(docs...)
+	return nil
+}
+
+// RemoveArticles removes articles from the index
+func (i *embeddedSearchIndex) RemoveArticles(ctx context.Context, articleIDs []string) error {
+	i.index.Remove(articleIDs...)
+	return nil
+}
+
+// Search returns up to limit articles of the given feeds matching the query, most relevant first
+func (i *embeddedSearchIndex) Search(ctx context.Context, query string, feedIDs []string, after *SearchHit, limit int) ([]SearchHit, error) {
+	results := i.index.Search(search.ParseQuery(query), feedIDs, 0)
+
+	hits := make([]SearchHit, len(results))
+	for j, result := range results {
+		hits[j] = SearchHit{ArticleID: result.ID, Score: result.Score, PublishedAt: result.Time}
+	}
+
+	return hitsAfter(hits, after, limit), nil
+}
@@ -0,0 +1,567 @@
+package repository
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"sort"
+	"strconv"
+	"sync"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/aws"
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"feed-bower-api/internal/model"
+	dynamodbpkg "feed-bower-api/pkg/dynamodb"
+	"feed-bower-api/pkg/search"
+)
+
+// Key prefixes of the search index table. Postings are partitioned by feed, so that a search only
+// reads the postings of the feeds it is restricted to: searches always run within a bower's feeds,
+// and a partition per term alone would make every search read the postings of every user's
+// articles. Unlike an embedded index such as bleve, the table is shared by every Lambda instance
+// without being rebuilt from a scan of the Articles table.
+const (
+	searchTermKeyPrefix  = "term#"  // term#<feed_id>#<term>: the articles of a feed containing a term
+	searchDocKeyPrefix   = "doc#"   // doc#<article_id>: what an article was indexed with
+	searchStatsKeyPrefix = "stats#" // stats#<feed_id>: the number and total length of a feed's articles
+	searchStatsSortKey   = "stats"
+
+	// searchQueryWorkers is how many posting lists a search reads at once
+	searchQueryWorkers = 8
+
+	// maxIndexedBodyRunes is how much of an article's content is indexed. It bounds the postings
+	// written per article, which grow with its length since Japanese text is indexed by bigram.
+	maxIndexedBodyRunes = 2000
+
+	// Retries of unprocessed batch writes, which DynamoDB returns when the table is throttled
+	searchWriteMaxRetries = 8
+	searchWriteBaseDelay  = 50 * time.Millisecond
+	searchWriteMaxDelay   = 5 * time.Second
+)
+
+// searchIndexAPI is the part of the DynamoDB API the search index uses
+type searchIndexAPI interface {
+	GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error)
+	PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error)
+	DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error)
+	UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error)
+	BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error)
+	BatchGetItem(ctx context.Context, params *dynamodb.BatchGetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchGetItemOutput, error)
+	Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error)
+}
+
+// searchPosting is the item recording the positions of a term in an article of a feed
+type searchPosting struct {
+	TermKey        string  `dynamodbav:"term_key"`
+	ArticleID      string  `dynamodbav:"article_id"`
+	TitlePositions []int   `dynamodbav:"title_positions,omitempty"`
+	BodyPositions  []int   `dynamodbav:"body_positions,omitempty"`
+	Length         float64 `dynamodbav:"length"`
+	PublishedAt    int64   `dynamodbav:"published_at"`
+}
+
+// searchDoc is the item recording how an article was indexed, so that it can be removed
+type searchDoc struct {
+	TermKey     string   `dynamodbav:"term_key"`
+	ArticleID   string   `dynamodbav:"article_id"`
+	Scopes      []string `dynamodbav:"scopes,stringset,omitempty"`
+	Terms       []string `dynamodbav:"terms,stringset,omitempty"`
+	Length      float64  `dynamodbav:"length"`
+	ContentHash string   `dynamodbav:"content_hash"`
+}
+
+// searchStats is the item holding the corpus statistics of a feed
+type searchStats struct {
+	DocCount    int     `dynamodbav:"doc_count"`
+	TotalLength float64 `dynamodbav:"total_length"`
+}
+
+// dynamoDBSearchIndex implements SearchIndex in a DynamoDB table shared by all processes
+type dynamoDBSearchIndex struct {
+	client     searchIndexAPI
+	tableName  string
+	retryDelay time.Duration // First delay before retrying unprocessed writes
+}
+
+// NewDynamoDBSearchIndex creates a search index stored in the SearchIndex table
+func NewDynamoDBSearchIndex(client *dynamodbpkg.Client) SearchIndex {
+	return newDynamoDBSearchIndex(client, client.GetTableNames().SearchIndex)
+}
+
+// newDynamoDBSearchIndex creates a search index stored in a table
+func newDynamoDBSearchIndex(client searchIndexAPI, tableName string) *dynamoDBSearchIndex {
+	return &dynamoDBSearchIndex{
+		client:     client,
+		tableName:  tableName,
+		retryDelay: searchWriteBaseDelay,
+	}
+}
+
+// IndexArticles adds or replaces articles in the index. Aliases are skipped: the article they
+// point at is indexed under all of its feeds. Articles whose text did not change only have
+// their postings added to or removed from the feeds they joined or left.
+func (i *dynamoDBSearchIndex) IndexArticles(ctx context.Context, articles []*model.Article) error {
+	for _, article := range articles {
+		if article == nil || article.ArticleID == "" || article.IsAlias() {
+			continue
+		}
+		if err := i.indexArticle(ctx, article); err != nil {
+			return fmt.Errorf("failed to index article %s: %w", article.ArticleID, err)
+		}
+	}
+	return nil
+}
+
+// indexArticle adds or replaces one article in the index
+func (i *dynamoDBSearchIndex) indexArticle(ctx context.Context, article *model.Article) error {
+	old, err := i.getDoc(ctx, article.ArticleID)
+	if err != nil {
+		return err
+	}
+
+	postings, length := search.Analyze(article.Title, indexedBody(article.Content))
+	doc := &searchDoc{
+		TermKey:     searchDocKeyPrefix + article.ArticleID,
+		ArticleID:   article.ArticleID,
+		Scopes:      uniqueStrings(article.FeedIDs()),
+		Length:      length,
+		ContentHash: searchContentHash(article),
+	}
+	for term := range postings {
+		doc.Terms = append(doc.Terms, term)
+	}
+	sort.Strings(doc.Terms)
+
+	addedScopes, removedScopes := doc.Scopes, []string(nil)
+	if old != nil {
+		if old.ContentHash == doc.ContentHash {
+			addedScopes = subtractStrings(doc.Scopes, old.Scopes)
+			removedScopes = subtractStrings(old.Scopes, doc.Scopes)
+			if len(addedScopes) == 0 && len(removedScopes) == 0 {
+				return nil
+			}
+		} else {
+			removedScopes = old.Scopes
+		}
+		if err := i.deletePostings(ctx, old, removedScopes); err != nil {
+			return err
+		}
+	}
+
+	if err := i.writePostings(ctx, article, postings, length, addedScopes); err != nil {
+		return err
+	}
+
+	item, err := attributevalue.MarshalMap(doc)
+	if err != nil {
+		return fmt.Errorf("failed to marshal search document: %w", err)
+	}
+	if _, err := i.client.PutItem(ctx, &dynamodb.PutItemInput{
+		TableName: aws.String(i.tableName),
+		Item:      item,
+	}); err != nil {
+		return fmt.Errorf("failed to put search document: %w", err)
+	}
+
+	return nil
+}
+
+// RemoveArticles removes articles from the index. Unknown IDs are ignored.
+func (i *dynamoDBSearchIndex) RemoveArticles(ctx context.Context, articleIDs []string) error {
+	for _, articleID := range articleIDs {
+		doc, err := i.getDoc(ctx, articleID)
+		if err != nil {
+			return err
+		}
+		if doc == nil {
+			continue
+		}
+
+		if err := i.deletePostings(ctx, doc, doc.Scopes); err != nil {
+			return err
+		}
+		if _, err := i.client.DeleteItem(ctx, &dynamodb.DeleteItemInput{
+			TableName: aws.String(i.tableName),
+			Key:       searchIndexKey(doc.TermKey, articleID),
+		}); err != nil {
+			return fmt.Errorf("failed to delete search document: %w", err)
+		}
+	}
+	return nil
+}
+
+// Search returns up to limit articles of the given feeds matching the query, most relevant first.
+// Every match is scored, so that pages after the first rank the same way.
+func (i *dynamoDBSearchIndex) Search(ctx context.Context, query string, feedIDs []string, after *SearchHit, limit int) ([]SearchHit, error) {
+	parsed := search.ParseQuery(query)
+	if parsed.IsEmpty() {
+		return []SearchHit{}, nil
+	}
+	if len(feedIDs) == 0 {
+		return nil, errors.New("feedIDs cannot be empty")
+	}
+	feedIDs = uniqueStrings(feedIDs)
+
+	corpus, err := i.getCorpus(ctx, feedIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	var terms []string
+	for _, phrase := range append(append([][]string{}, parsed.Required...), parsed.Excluded...) {
+		terms = append(terms, phrase...)
+	}
+	postings, err := i.getPostings(ctx, feedIDs, uniqueStrings(terms))
+	if err != nil {
+		return nil, err
+	}
+	corpus.DocumentFrequency = func(term string) int { return len(postings[term]) }
+
+	// Every match contains the first required term
+	candidates := postings[parsed.Required[0][0]]
+	hits := make([]SearchHit, 0)
+	for articleID, candidate := range candidates {
+		lookup := func(term string) *search.Postings {
+			posting, ok := postings[term][articleID]
+			if !ok {
+				return nil
+			}
+			return &search.Postings{Title: posting.TitlePositions, Body: posting.BodyPositions}
+		}
+
+		score, ok := search.Score(parsed, lookup, candidate.Length, corpus)
+		if !ok {
+			continue
+		}
+		hits = append(hits, SearchHit{ArticleID: articleID, Score: score, PublishedAt: candidate.PublishedAt})
+	}
+
+	sort.Slice(hits, func(a, b int) bool { return hits[a].RanksBefore(hits[b]) })
+
+	return hitsAfter(hits, after, limit), nil
+}
+
+// getDoc retrieves what an article was indexed with, or nil if it is not indexed
+func (i *dynamoDBSearchIndex) getDoc(ctx context.Context, articleID string) (*searchDoc, error) {
+	result, err := i.client.GetItem(ctx, &dynamodb.GetItemInput{
+		TableName: aws.String(i.tableName),
+		Key:       searchIndexKey(searchDocKeyPrefix+articleID, articleID),
+	})
+	if err != nil {
+		return nil, fmt.Errorf("failed to get search document: %w", err)
+	}
+	if result.Item == nil {
+		return nil, nil
+	}
+
+	var doc searchDoc
+	if err := attributevalue.UnmarshalMap(result.Item, &doc); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal search document: %w", err)
+	}
+	return &doc, nil
+}
+
+// writePostings writes an article's postings under the given feeds and counts the article in
+// their statistics
+func (i *dynamoDBSearchIndex) writePostings(ctx context.Context, article *model.Article, postings map[string]*search.Postings, length float64, scopes []string) error {
+	requests := make([]types.WriteRequest, 0, len(postings)*len(scopes))
+	for _, scope := range scopes {
+		for term, p := range postings {
+			item, err := attributevalue.MarshalMap(&searchPosting{
+				TermKey:        searchTermKey(scope, term),
+				ArticleID:      article.ArticleID,
+				TitlePositions: p.Title,
+				BodyPositions:  p.Body,
+				Length:         length,
+				PublishedAt:    article.PublishedAt,
+			})
+			if err != nil {
+				return fmt.Errorf("failed to marshal search posting: %w", err)
+			}
+			requests = append(requests, types.WriteRequest{PutRequest: &types.PutRequest{Item: item}})
+		}
+	}
+
+	if err := i.batchWrite(ctx, requests); err != nil {
+		return err
+	}
+	return i.updateStats(ctx, scopes, 1, length)
+}
+
+// deletePostings deletes an indexed article's postings under the given feeds and removes the
+// article from their statistics
+func (i *dynamoDBSearchIndex) deletePostings(ctx context.Context, doc *searchDoc, scopes []string) error {
+	requests := make([]types.WriteRequest, 0, len(doc.Terms)*len(scopes))
+	for _, scope := range scopes {
+		for _, term := range doc.Terms {
+			requests = append(requests, types.WriteRequest{
+				DeleteRequest: &types.DeleteRequest{Key: searchIndexKey(searchTermKey(scope, term), doc.ArticleID)},
+			})
+		}
+	}
+
+	if err := i.batchWrite(ctx, requests); err != nil {
+		return err
+	}
+	return i.updateStats(ctx, scopes, -1, -doc.Length)
+}
+
+// updateStats adds to the article count and total length of feeds
+func (i *dynamoDBSearchIndex) updateStats(ctx context.Context, scopes []string, docs int, length float64) error {
+	for _, scope := range scopes {
+		_, err := i.client.UpdateItem(ctx, &dynamodb.UpdateItemInput{
+			TableName:        aws.String(i.tableName),
+			Key:              searchIndexKey(searchStatsKeyPrefix+scope, searchStatsSortKey),
+			UpdateExpression: aws.String("ADD doc_count :docs, total_length :length"),
+			ExpressionAttributeValues: map[string]types.AttributeValue{
+				":docs":   &types.AttributeValueMemberN{Value: strconv.Itoa(docs)},
+				":length": &types.AttributeValueMemberN{Value: strconv.FormatFloat(length, 'f', -1, 64)},
+			},
+		})
+		if err != nil {
+			return fmt.Errorf("failed to update search statistics: %w", err)
+		}
+	}
+	return nil
+}
+
+// batchWrite writes requests 25 at a time. Unprocessed items are retried with exponential backoff,
+// up to searchWriteMaxRetries times per batch.
+func (i *dynamoDBSearchIndex) batchWrite(ctx context.Context, requests []types.WriteRequest) error {
+	const batchSize = 25
+
+	for start := 0; start < len(requests); start += batchSize {
+		end := start + batchSize
+		if end > len(requests) {
+			end = len(requests)
+		}
+
+		requestItems := map[string][]types.WriteRequest{i.tableName: requests[start:end]}
+		delay := i.retryDelay
+		for retries := 0; ; retries++ {
+			result, err := i.client.BatchWriteItem(ctx, &dynamodb.BatchWriteItemInput{RequestItems: requestItems})
+			if err != nil {
+				return fmt.Errorf("failed to batch write search postings: %w", err)
+			}
+			requestItems = result.UnprocessedItems
+			if len(requestItems) == 0 {
+				break
+			}
+			if retries == searchWriteMaxRetries {
+				return fmt.Errorf("failed to batch write search postings: %d items unprocessed after %d retries", len(requestItems[i.tableName]), retries)
+			}
+
+			select {
+			case <-time.After(delay):
+			case <-ctx.Done():
+				return ctx.Err()
+			}
+			delay = min(delay*2, searchWriteMaxDelay)
+		}
+	}
+	return nil
+}
+
+// getCorpus sums the statistics of feeds
+func (i *dynamoDBSearchIndex) getCorpus(ctx context.Context, feedIDs []string) (search.Corpus, error) {
+	var corpus search.Corpus
+
+	// DynamoDB batch get can handle up to 100 keys at a time
+	const batchSize = 100
+
+	for start := 0; start < len(feedIDs); start += batchSize {
+		end := start + batchSize
+		if end > len(feedIDs) {
+			end = len(feedIDs)
+		}
+
+		keys := make([]map[string]types.AttributeValue, 0, end-start)
+		for _, feedID := range feedIDs[start:end] {
+			keys = append(keys, searchIndexKey(searchStatsKeyPrefix+feedID, searchStatsSortKey))
+		}
+
+		requestItems := map[string]types.KeysAndAttributes{i.tableName: {Keys: keys}}
+		for len(requestItems) > 0 {
+			result, err := i.client.BatchGetItem(ctx, &dynamodb.BatchGetItemInput{RequestItems: requestItems})
+			if err != nil {
+				return corpus, fmt.Errorf("failed to get search statistics: %w", err)
+			}
+
+			for _, item := range result.Responses[i.tableName] {
+				var stats searchStats
+				if err := attributevalue.UnmarshalMap(item, &stats); err != nil {
+					return corpus, fmt.Errorf("failed to unmarshal search statistics: %w", err)
+				}
+				corpus.Docs += stats.DocCount
+				corpus.TotalLength += stats.TotalLength
+			}
+
+			requestItems = result.UnprocessedKeys
+		}
+	}
+
+	return corpus, nil
+}
+
+// getPostings reads the postings of terms in feeds, by term and article ID. An article in several
+// of the feeds has the same postings in each.
+func (i *dynamoDBSearchIndex) getPostings(ctx context.Context, feedIDs []string, terms []string) (map[string]map[string]*searchPosting, error) {
+	type postingList struct {
+		term     string
+		postings []*searchPosting
+		err      error
+	}
+
+	keys := make(chan [2]string)
+	lists := make(chan postingList)
+
+	var wg sync.WaitGroup
+	for w := 0; w < searchQueryWorkers; w++ {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for key := range keys {
+				postings, err := i.queryPostings(ctx, searchTermKey(key[0], key[1]))
+				lists <- postingList{term: key[1], postings: postings, err: err}
+			}
+		}()
+	}
+	go func() {
+		defer close(keys)
+		for _, feedID := range feedIDs {
+			for _, term := range terms {
+				select {
+				case keys <- [2]string{feedID, term}:
+				case <-ctx.Done():
+					return
+				}
+			}
+		}
+	}()
+	go func() {
+		wg.Wait()
+		close(lists)
+	}()
+
+	postings := make(map[string]map[string]*searchPosting, len(terms))
+	var firstErr error
+	for list := range lists {
+		if list.err != nil {
+			if firstErr == nil {
+				firstErr = list.err
+			}
+			continue
+		}
+		if postings[list.term] == nil {
+			postings[list.term] = make(map[string]*searchPosting)
+		}
+		for _, posting := range list.postings {
+			postings[list.term][posting.ArticleID] = posting
+		}
+	}
+
+	if firstErr != nil {
+		return nil, firstErr
+	}
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	return postings, nil
+}
+
+// queryPostings reads all postings under a term key
+func (i *dynamoDBSearchIndex) queryPostings(ctx context.Context, termKey string) ([]*searchPosting, error) {
+	input := &dynamodb.QueryInput{
+		TableName:              aws.String(i.tableName),
+		KeyConditionExpression: aws.String("term_key = :term_key"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":term_key": &types.AttributeValueMemberS{Value: termKey},
+		},
+	}
+
+	postings := make([]*searchPosting, 0)
+	for {
+		result, err := i.client.Query(ctx, input)
+		if err != nil {
+			return nil, fmt.Errorf("failed to query search postings: %w", err)
+		}
+
+		for _, item := range result.Items {
+			var posting searchPosting
+			if err := attributevalue.UnmarshalMap(item, &posting); err != nil {
+				return nil, fmt.Errorf("failed to unmarshal search posting: %w", err)
+			}
+			postings = append(postings, &posting)
+		}
+
+		if len(result.LastEvaluatedKey) == 0 {
+			return postings, nil
+		}
+		input.ExclusiveStartKey = result.LastEvaluatedKey
+	}
+}
+
+// searchIndexKey builds the primary key of a search index item
+func searchIndexKey(termKey, articleID string) map[string]types.AttributeValue {
+	return map[string]types.AttributeValue{
+		"term_key":   &types.AttributeValueMemberS{Value: termKey},
+		"article_id": &types.AttributeValueMemberS{Value: articleID},
+	}
+}
+
+// searchTermKey returns the key of a term's postings in a feed
+func searchTermKey(feedID, term string) string {
+	return searchTermKeyPrefix + feedID + "#" + term
+}
+
+// indexedBody returns the part of an article's content that is indexed
+func indexedBody(content string) string {
+	runes := 0
+	for i := range content {
+		if runes == maxIndexedBodyRunes {
+			return content[:i]
+		}
+		runes++
+	}
+	return content
+}
+
+// searchContentHash identifies the indexed text of an article, to skip rewriting unchanged postings
+func searchContentHash(article *model.Article) string {
+	sum := sha256.Sum256([]byte(article.Title + "\x00" + article.Content + "\x00" + strconv.FormatInt(article.PublishedAt, 10)))
+	return hex.EncodeToString(sum[:])
+}
+
+// uniqueStrings returns values without duplicates, in their first order
+func uniqueStrings(values []string) []string {
+	seen := make(map[string]bool, len(values))
+	unique := make([]string, 0, len(values))
+	for _, value := range values {
+		if !seen[value] {
+			seen[value] = true
+			unique = append(unique, value)
+		}
+	}
+	return unique
+}
+
+// subtractStrings returns the values that are not in removed
+func subtractStrings(values, removed []string) []string {
+	removedSet := make(map[string]bool, len(removed))
+	for _, value := range removed {
+		removedSet[value] = true
+	}
+	var kept []string
+	for _, value := range values {
+		if !removedSet[value] {
+			kept = append(kept, value)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,340 @@
+package repository
+
+import (
+	"context"
+	"sort"
+	"strconv"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb"
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"feed-bower-api/internal/model"
+)
+
+// fakeSearchTable is an in-memory table keyed by term_key and article_id. It serves the requests
+// the search index makes, and can leave batch writes unprocessed as a throttled table does.
+type fakeSearchTable struct {
+	mu          sync.Mutex
+	items       map[string]map[string]map[string]types.AttributeValue
+	pageSize    int // Items per Query page; all if 0
+	throttled   int // Number of batch writes to leave unprocessed
+	batchWrites int
+}
+
+func newFakeSearchTable() *fakeSearchTable {
+	return &fakeSearchTable{items: make(map[string]map[string]map[string]types.AttributeValue)}
+}
+
+func stringAttr(item map[string]types.AttributeValue, name string) string {
+	if value, ok := item[name].(*types.AttributeValueMemberS); ok {
+		return value.Value
+	}
+	return ""
+}
+
+func (f *fakeSearchTable) get(key map[string]types.AttributeValue) map[string]types.AttributeValue {
+	return f.items[stringAttr(key, "term_key")][stringAttr(key, "article_id")]
+}
+
+func (f *fakeSearchTable) put(item map[string]types.AttributeValue) {
+	termKey := stringAttr(item, "term_key")
+	if f.items[termKey] == nil {
+		f.items[termKey] = make(map[string]map[string]types.AttributeValue)
+	}
+	f.items[termKey][stringAttr(item, "article_id")] = item
+}
+
+func (f *fakeSearchTable) delete(key map[string]types.AttributeValue) {
+	termKey := stringAttr(key, "term_key")
+	delete(f.items[termKey], stringAttr(key, "article_id"))
+	if len(f.items[termKey]) == 0 {
+		delete(f.items, termKey)
+	}
+}
+
+func (f *fakeSearchTable) GetItem(ctx context.Context, params *dynamodb.GetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.GetItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	return &dynamodb.GetItemOutput{Item: f.get(params.Key)}, nil
+}
+
+func (f *fakeSearchTable) PutItem(ctx context.Context, params *dynamodb.PutItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.PutItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.put(params.Item)
+	return &dynamodb.PutItemOutput{}, nil
+}
+
+func (f *fakeSearchTable) DeleteItem(ctx context.Context, params *dynamodb.DeleteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.DeleteItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+	f.delete(params.Key)
+	return &dynamodb.DeleteItemOutput{}, nil
+}
+
+// UpdateItem supports "ADD <attribute> :<value>, ..." on numbers
+func (f *fakeSearchTable) UpdateItem(ctx context.Context, params *dynamodb.UpdateItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.UpdateItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	item := f.get(params.Key)
+	if item == nil {
+		item = map[string]types.AttributeValue{"term_key": params.Key["term_key"], "article_id": params.Key["article_id"]}
+		f.put(item)
+	}
+	for _, clause := range strings.Split(strings.TrimPrefix(*params.UpdateExpression, "ADD "), ",") {
+		fields := strings.Fields(clause)
+		current := 0.0
+		if value, ok := item[fields[0]].(*types.AttributeValueMemberN); ok {
+			current, _ = strconv.ParseFloat(value.Value, 64)
+		}
+		added, _ := strconv.ParseFloat(params.ExpressionAttributeValues[fields[1]].(*types.AttributeValueMemberN).Value, 64)
+		item[fields[0]] = &types.AttributeValueMemberN{Value: strconv.FormatFloat(current+added, 'f', -1, 64)}
+	}
+	return &dynamodb.UpdateItemOutput{}, nil
+}
+
+func (f *fakeSearchTable) BatchWriteItem(ctx context.Context, params *dynamodb.BatchWriteItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchWriteItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	f.batchWrites++
+	if f.throttled > 0 {
+		f.throttled--
+		return &dynamodb.BatchWriteItemOutput{UnprocessedItems: params.RequestItems}, nil
+	}
+	for _, requests := range params.RequestItems {
+		for _, request := range requests {
+			if request.PutRequest != nil {
+				f.put(request.PutRequest.Item)
+			} else {
+				f.delete(request.DeleteRequest.Key)
+			}
+		}
+	}
+	return &dynamodb.BatchWriteItemOutput{}, nil
+}
+
+func (f *fakeSearchTable) BatchGetItem(ctx context.Context, params *dynamodb.BatchGetItemInput, optFns ...func(*dynamodb.Options)) (*dynamodb.BatchGetItemOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	responses := make(map[string][]map[string]types.AttributeValue)
+	for tableName, keys := range params.RequestItems {
+		for _, key := range keys.Keys {
+			if item := f.get(key); item != nil {
+				responses[tableName] = append(responses[tableName], item)
+			}
+		}
+	}
+	return &dynamodb.BatchGetItemOutput{Responses: responses}, nil
+}
+
+// Query supports "term_key = :term_key", returning items by article ID in pages of pageSize
+func (f *fakeSearchTable) Query(ctx context.Context, params *dynamodb.QueryInput, optFns ...func(*dynamodb.Options)) (*dynamodb.QueryOutput, error) {
+	f.mu.Lock()
+	defer f.mu.Unlock()
+
+	partition := f.items[stringAttr(params.ExpressionAttributeValues, ":term_key")]
+	articleIDs := make([]string, 0, len(partition))
+	for articleID := range partition {
+		if params.ExclusiveStartKey == nil || articleID > stringAttr(params.ExclusiveStartKey, "article_id") {
+			articleIDs = append(articleIDs, articleID)
+		}
+	}
+	sort.Strings(articleIDs)
+
+	output := &dynamodb.QueryOutput{}
+	for _, articleID := range articleIDs {
+		if f.pageSize > 0 && len(output.Items) == f.pageSize {
+			last := output.Items[len(output.Items)-1]
+			output.LastEvaluatedKey = map[string]types.AttributeValue{"term_key": last["term_key"], "article_id": last["article_id"]}
+			break
+		}
+		output.Items = append(output.Items, partition[articleID])
+	}
+	return output, nil
+}
+
+// countPartitions returns the number of item partitions whose key starts with prefix
+func (f *fakeSearchTable) countPartitions(prefix string) int {
+	count := 0
+	for termKey := range f.items {
+		if strings.HasPrefix(termKey, prefix) {
+			count++
+		}
+	}
+	return count
+}
+
+// stats returns the statistics item of a feed
+func (f *fakeSearchTable) stats(t *testing.T, feedID string) searchStats {
+	t.Helper()
+	var stats searchStats
+	if item := f.get(searchIndexKey(searchStatsKeyPrefix+feedID, searchStatsSortKey)); item != nil {
+		if err := attributevalue.UnmarshalMap(item, &stats); err != nil {
+			t.Fatalf("Failed to unmarshal statistics: %v", err)
+		}
+	}
+	return stats
+}
+
+func searchHitIDs(hits []SearchHit) []string {
+	ids := make([]string, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ArticleID
+	}
+	return ids
+}
+
+func TestDynamoDBSearchIndex(t *testing.T) {
+	ctx := context.Background()
+	table := newFakeSearchTable()
+	table.pageSize = 1 // Posting lists are read over several pages
+	index := newDynamoDBSearchIndex(table, "search-index")
+
+	now := time.Now().Unix()
+	newArticle := func(articleID, feedID, title, content string, publishedAt int64) *model.Article {
+		article := model.NewArticle(feedID, title, content, "https://example.com/"+articleID, time.Unix(publishedAt, 0))
+		article.ArticleID = articleID
+		return article
+	}
+	golang := newArticle("golang", "feed-a", "Go release notes", "The Go team released a new version", now)
+	rust := newArticle("rust", "feed-a", "Rust release", "A new Rust version", now-60)
+	other := newArticle("other", "feed-b", "Go conference", "Talks about Go", now-120)
+
+	if err := index.IndexArticles(ctx, []*model.Article{golang, rust, other}); err != nil {
+		t.Fatalf("IndexArticles() unexpected error: %v", err)
+	}
+	if stats := table.stats(t, "feed-a"); stats.DocCount != 2 || stats.TotalLength <= 0 {
+		t.Errorf("Expected feed-a statistics to count two articles, got %+v", stats)
+	}
+
+	search := func(query string, feedIDs []string, after *SearchHit, limit int) []SearchHit {
+		t.Helper()
+		hits, err := index.Search(ctx, query, feedIDs, after, limit)
+		if err != nil {
+			t.Fatalf("Search(%q) unexpected error: %v", query, err)
+		}
+		return hits
+	}
+
+	// Searches only read the postings of the given feeds
+	if got := strings.Join(searchHitIDs(search("go", []string{"feed-a"}, nil, 10)), ","); got != "golang" {
+		t.Errorf("Expected only feed-a's Go article, got %s", got)
+	}
+	if got := searchHitIDs(search("release", []string{"feed-a", "feed-b"}, nil, 10)); len(got) != 2 {
+		t.Errorf("Expected both release articles, got %v", got)
+	}
+	if got := searchHitIDs(search(`"new version" -rust`, []string{"feed-a"}, nil, 10)); len(got) != 1 || got[0] != "golang" {
+		t.Errorf("Expected the phrase query to exclude rust, got %v", got)
+	}
+
+	// Pages continue after the last hit
+	first := search("go", []string{"feed-a", "feed-b"}, nil, 1)
+	second := search("go", []string{"feed-a", "feed-b"}, &first[0], 1)
+	if len(first) != 1 || len(second) != 1 || first[0].ArticleID == second[0].ArticleID {
+		t.Errorf("Expected two different pages of one hit, got %v and %v", first, second)
+	}
+
+	// A merged article is indexed under its new feed without rewriting the other
+	golang.SourceFeedIDs = []string{"feed-b"}
+	table.batchWrites = 0
+	if err := index.IndexArticles(ctx, []*model.Article{golang}); err != nil {
+		t.Fatalf("IndexArticles() unexpected error: %v", err)
+	}
+	if got := strings.Join(searchHitIDs(search("notes", []string{"feed-b"}, nil, 10)), ","); got != "golang" {
+		t.Errorf("Expected the merged article to be found from feed-b, got %s", got)
+	}
+	if stats := table.stats(t, "feed-b"); stats.DocCount != 2 {
+		t.Errorf("Expected feed-b statistics to count the merged article, got %+v", stats)
+	}
+
+	// Unchanged articles are not written again
+	table.batchWrites = 0
+	if err := index.IndexArticles(ctx, []*model.Article{golang}); err != nil || table.batchWrites != 0 {
+		t.Errorf("Expected no writes for an unchanged article, got %d (%v)", table.batchWrites, err)
+	}
+
+	// Changed text replaces the old postings in every feed
+	golang.Title = "Go changelog"
+	if err := index.IndexArticles(ctx, []*model.Article{golang}); err != nil {
+		t.Fatalf("IndexArticles() unexpected error: %v", err)
+	}
+	if hits := search("notes", []string{"feed-a", "feed-b"}, nil, 10); len(hits) != 0 {
+		t.Errorf("Expected the old title to be unindexed, got %v", searchHitIDs(hits))
+	}
+	if got := searchHitIDs(search("changelog", []string{"feed-b"}, nil, 10)); len(got) != 1 {
+		t.Errorf("Expected the new title to be indexed, got %v", got)
+	}
+
+	// Removed articles leave no postings or statistics behind
+	if err := index.RemoveArticles(ctx, []string{"golang", "rust", "other", "unknown"}); err != nil {
+		t.Fatalf("RemoveArticles() unexpected error: %v", err)
+	}
+	if n := table.countPartitions(searchTermKeyPrefix) + table.countPartitions(searchDocKeyPrefix); n != 0 {
+		t.Errorf("Expected no postings or documents left, got %d partitions", n)
+	}
+	if stats := table.stats(t, "feed-a"); stats.DocCount != 0 || stats.TotalLength > 1e-9 {
+		t.Errorf("Expected empty feed-a statistics, got %+v", stats)
+	}
+}
+
+func TestDynamoDBSearchIndex_LongContent(t *testing.T) {
+	ctx := context.Background()
+	table := newFakeSearchTable()
+	index := newDynamoDBSearchIndex(table, "search-index")
+
+	// Only the start of long content is indexed, so a Japanese article writes a bounded number of bigrams
+	content := strings.Repeat("日本語の記事です。", 1000) + "最後の段落"
+	article := model.NewArticle("feed-a", "長い記事", content, "https://example.com/long", time.Now())
+	article.ArticleID = "long"
+	if err := index.IndexArticles(ctx, []*model.Article{article}); err != nil {
+		t.Fatalf("IndexArticles() unexpected error: %v", err)
+	}
+
+	if n := table.countPartitions(searchTermKeyPrefix); n == 0 || n > maxIndexedBodyRunes {
+		t.Errorf("Expected at most %d postings, got %d", maxIndexedBodyRunes, n)
+	}
+	if hits, _ := index.Search(ctx, "日本語", []string{"feed-a"}, nil, 10); len(hits) != 1 {
+		t.Errorf("Expected the start of the content to be searchable, got %v", hits)
+	}
+	if hits, _ := index.Search(ctx, "段落", []string{"feed-a"}, nil, 10); len(hits) != 0 {
+		t.Errorf("Expected the end of long content not to be indexed, got %v", hits)
+	}
+}
+
+func TestDynamoDBSearchIndex_ThrottledWrites(t *testing.T) {
+	ctx := context.Background()
+	table := newFakeSearchTable()
+	index := newDynamoDBSearchIndex(table, "search-index")
+	index.retryDelay = time.Millisecond
+
+	article := model.NewArticle("feed-a", "Throttled", "content", "https://example.com/throttled", time.Now())
+	article.ArticleID = "throttled"
+
+	// Unprocessed items are retried until the table accepts them
+	table.throttled = 3
+	if err := index.IndexArticles(ctx, []*model.Article{article}); err != nil {
+		t.Fatalf("IndexArticles() unexpected error: %v", err)
+	}
+	if hits, _ := index.Search(ctx, "throttled", []string{"feed-a"}, nil, 10); len(hits) != 1 {
+		t.Errorf("Expected the article to be indexed after retries, got %v", hits)
+	}
+
+	// A table that stays throttled fails after the retry limit
+	article.Title = "Still throttled"
+	table.throttled = searchWriteMaxRetries + 10
+	table.batchWrites = 0
+	if err := index.IndexArticles(ctx, []*model.Article{article}); err == nil {
+		t.Error("Expected an error when writes stay unprocessed")
+	}
+	if table.batchWrites != searchWriteMaxRetries+1 {
+		t.Errorf("Expected %d attempts, got %d", searchWriteMaxRetries+1, table.batchWrites)
+	}
+}
@@ -4,6 +4,7 @@ import (
 	"context"
 	"errors"
 	"fmt"
+	"strconv"
 
 	"github.com/aws/aws-sdk-go-v2/aws"
 	"github.com/aws/aws-sdk-go-v2/feature/dynamodb/attributevalue"
@@ -21,6 +22,7 @@ type UserRepository interface {
 	GetByID(ctx context.Context, userID string) (*model.User, error)
 	GetByEmail(ctx context.Context, email string) (*model.User, error)
 	Update(ctx context.Context, user *model.User) error
+	SetLastActive(ctx context.Context, userID string, lastActiveAt int64) error
 	Delete(ctx context.Context, userID string) error
 	List(ctx context.Context, limit int32, lastKey map[string]types.AttributeValue) ([]*model.User, map[string]types.AttributeValue, error)
 }
@@ -177,6 +179,36 @@ func (r *userRepository) Update(ctx context.Context, user *model.User) error {
 	return nil
 }
 
+// SetLastActive records when a user was last active, without rewriting the rest of the user
+func (r *userRepository) SetLastActive(ctx context.Context, userID string, lastActiveAt int64) error {
+	if userID == "" {
+		return errors.New("userID cannot be empty")
+	}
+
+	input := &dynamodb.UpdateItemInput{
+		TableName: aws.String(r.tables.Users),
+		Key: map[string]types.AttributeValue{
+			"user_id": &types.AttributeValueMemberS{Value: userID},
+		},
+		UpdateExpression:    aws.String("SET last_active_at = :last_active_at"),
+		ConditionExpression: aws.String("attribute_exists(user_id)"),
+		ExpressionAttributeValues: map[string]types.AttributeValue{
+			":last_active_at": &types.AttributeValueMemberN{Value: strconv.FormatInt(lastActiveAt, 10)},
+		},
+	}
+
+	_, err := r.client.UpdateItem(ctx, input)
+	if err != nil {
+		var conditionalCheckErr *types.ConditionalCheckFailedException
+		if errors.As(err, &conditionalCheckErr) {
+			return fmt.Errorf("user with ID %s not found", userID)
+		}
+		return fmt.Errorf("failed to set last activity: %w", err)
+	}
+
+	return nil
+}
+
 // Delete deletes a user by their ID
 func (r *userRepository) Delete(ctx context.Context, userID string) error {
 	if userID == "" {
@@ -0,0 +1,155 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"sort"
+
+	"feed-bower-api/internal/model"
+	"feed-bower-api/internal/repository"
+	"feed-bower-api/pkg/charset"
+	"feed-bower-api/pkg/httpclient"
+	"feed-bower-api/pkg/readability"
+)
+
+// Full text extraction limits
+const (
+	maxArticlePageSize    = 5 * 1024 * 1024 // 5MB read of an article page
+	maxFullContentSize    = 200 * 1024      // Largest extracted content stored; DynamoDB items are limited to 400KB
+	maxFullTextExtraction = 10              // Articles extracted per feed fetch, newest first
+)
+
+// FetchFullContent downloads an article's page and extracts its main content as sanitized HTML
+func (s *rssService) FetchFullContent(ctx context.Context, articleURL string) (string, error) {
+	if articleURL == "" {
+		return "", errors.New("article URL is required")
+	}
+
+	config := httpclient.DefaultSecureHTTPConfig()
+	if err := httpclient.ValidateURL(articleURL, config); err != nil {
+		return "", fmt.Errorf("invalid article URL: %w", err)
+	}
+
+	headers := map[string]string{
+		"Accept": "text/html, application/xhtml+xml;q=0.9",
+	}
+
+	resp, err := s.secureClient.Do(ctx, "GET", articleURL, headers)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch article page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", &HTTPError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxArticlePageSize))
+	if err != nil {
+		return "", fmt.Errorf("failed to read article page: %w", err)
+	}
+	contentType := resp.Header.Get("Content-Type")
+	if !isHTMLDocument(body, contentType) {
+		return "", errors.New("article page is not HTML")
+	}
+	body, err = charset.HTMLToUTF8(body, contentType)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode article page: %w", err)
+	}
+
+	// Resolve links against the page the request was redirected to
+	pageURL, err := url.Parse(articleURL)
+	if err != nil {
+		return "", fmt.Errorf("invalid article URL: %w", err)
+	}
+	if resp.Request != nil && resp.Request.URL != nil {
+		pageURL = resp.Request.URL
+	}
+
+	article, err := readability.Extract(bytes.NewReader(body), pageURL)
+	if err != nil {
+		return "", fmt.Errorf("failed to extract article content: %w", err)
+	}
+	if len(article.Content) > maxFullContentSize {
+		return "", fmt.Errorf("extracted content exceeds maximum size of %d bytes", maxFullContentSize)
+	}
+
+	return article.Content, nil
+}
+
+// wantsFullText reports whether any bower subscribed to a feed extracts full article content
+func wantsFullText(ctx context.Context, feedRepo repository.FeedRepository, feedID string) (bool, error) {
+	subscriptions, err := feedRepo.GetSubscriptionsByFeedID(ctx, feedID)
+	if err != nil {
+		return false, fmt.Errorf("failed to get feed subscriptions: %w", err)
+	}
+	for _, subscription := range subscriptions {
+		if subscription.FullText {
+			return true, nil
+		}
+	}
+	return false, nil
+}
+
+// extractFullContent extracts and stores the full content of a feed's newly saved articles, if a
+// subscribed bower asks for it. At most maxFullTextExtraction articles are extracted; failures are
+// logged and the article keeps only the feed's content. It returns the number of articles extracted.
+func extractFullContent(ctx context.Context, rssService RSSService, feedRepo repository.FeedRepository, articleRepo repository.ArticleRepository, feedID string, articles []*model.Article) int {
+	if len(articles) == 0 {
+		return 0
+	}
+
+	wanted, err := wantsFullText(ctx, feedRepo, feedID)
+	if err != nil {
+		log.Printf("⚠️ Skipping full text extraction for feed %s: %v", feedID, err)
+		return 0
+	}
+	if !wanted {
+		return 0
+	}
+
+	extracted := 0
+	for _, article := range newestArticles(articles, maxFullTextExtraction) {
+		if ctx.Err() != nil {
+			break
+		}
+		if err := storeFullContent(ctx, rssService, articleRepo, article); err != nil {
+			log.Printf("⚠️ Full text extraction failed for %s: %v", article.URL, err)
+			continue
+		}
+		extracted++
+	}
+
+	return extracted
+}
+
+// storeFullContent extracts an article's full content and stores it on the article
+func storeFullContent(ctx context.Context, rssService RSSService, articleRepo repository.ArticleRepository, article *model.Article) error {
+	content, err := rssService.FetchFullContent(ctx, article.URL)
+	if err != nil {
+		return err
+	}
+	if err := articleRepo.SetFullContent(ctx, article.ArticleID, content); err != nil {
+		return err
+	}
+	article.FullContent = content
+	return nil
+}
+
+// newestArticles returns up to limit of the most recently published articles
+func newestArticles(articles []*model.Article, limit int) []*model.Article {
+	newest := append([]*model.Article(nil), articles...)
+	sort.SliceStable(newest, func(i, j int) bool {
+		return newest[i].PublishedAt > newest[j].PublishedAt
+	})
+	if len(newest) > limit {
+		newest = newest[:limit]
+	}
+	return newest
+}
@@ -0,0 +1,115 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"feed-bower-api/internal/model"
+)
+
+// stubFullContentRSSService extracts fixed content for known article URLs
+type stubFullContentRSSService struct {
+	RSSService
+	pages   map[string]string
+	fetched []string
+}
+
+func (s *stubFullContentRSSService) FetchFullContent(ctx context.Context, articleURL string) (string, error) {
+	s.fetched = append(s.fetched, articleURL)
+	content, ok := s.pages[articleURL]
+	if !ok {
+		return "", errors.New("failed to extract article content: no readable content found")
+	}
+	return content, nil
+}
+
+func TestExtractFullContent(t *testing.T) {
+	ctx := context.Background()
+	repos := NewMockRepositories()
+
+	bower := model.NewBower("user-1", "Tech", []string{"go"}, nil, "#14b8a6", false)
+	repos.BowerRepo.Create(ctx, bower)
+	feed := model.NewFeed(bower.BowerID, "https://example.com/feed.xml", "Example", "", "tech")
+	repos.FeedRepo.Create(ctx, feed)
+
+	rss := &stubFullContentRSSService{pages: map[string]string{
+		"https://example.com/1": "<p>The whole first article.</p>",
+	}}
+
+	saved, err := saveFetchedArticles(ctx, repos.ArticleRepo, feed.FeedID, []*model.Article{
+		model.NewArticle(feed.FeedID, "First article", "Summary", "https://example.com/1", time.Now()),
+		model.NewArticle(feed.FeedID, "Second article", "Summary", "https://example.com/2", time.Now().Add(-time.Hour)),
+	})
+	if err != nil {
+		t.Fatalf("saveFetchedArticles() unexpected error: %v", err)
+	}
+
+	if extracted := extractFullContent(ctx, rss, repos.FeedRepo, repos.ArticleRepo, feed.FeedID, saved.Articles); extracted != 0 || len(rss.fetched) != 0 {
+		t.Fatalf("Expected no extraction without full text mode, got %d", extracted)
+	}
+
+	subscription, _ := repos.FeedRepo.GetSubscription(ctx, bower.BowerID, feed.FeedID)
+	subscription.FullText = true
+	repos.FeedRepo.UpdateSubscription(ctx, subscription)
+
+	if extracted := extractFullContent(ctx, rss, repos.FeedRepo, repos.ArticleRepo, feed.FeedID, saved.Articles); extracted != 1 {
+		t.Errorf("Expected the extractable article to be stored, got %d", extracted)
+	}
+	if len(rss.fetched) != 2 || rss.fetched[0] != "https://example.com/1" {
+		t.Errorf("Expected both articles fetched newest first, got %v", rss.fetched)
+	}
+
+	stored, _ := repos.ArticleRepo.GetByID(ctx, saved.Articles[0].ArticleID)
+	if stored.FullContent != "<p>The whole first article.</p>" || stored.Content != "Summary" {
+		t.Errorf("Expected full content stored next to the feed content, got %q / %q", stored.FullContent, stored.Content)
+	}
+}
+
+func TestArticleService_GetFullArticle(t *testing.T) {
+	ctx := context.Background()
+	repos := NewMockRepositories()
+
+	bower := model.NewBower("user-1", "Tech", []string{"go"}, nil, "#14b8a6", false)
+	repos.BowerRepo.Create(ctx, bower)
+	feed := model.NewFeed(bower.BowerID, "https://example.com/feed.xml", "Example", "", "tech")
+	repos.FeedRepo.Create(ctx, feed)
+
+	article := model.NewArticle(feed.FeedID, "Go 1.26", "Summary", "https://example.com/go", time.Now())
+	article.ArticleID = "article-1"
+	repos.ArticleRepo.Create(ctx, article)
+
+	rss := &stubFullContentRSSService{pages: map[string]string{"https://example.com/go": "<p>Full text.</p>"}}
+	service := NewArticleService(repos.ArticleRepo, repos.FeedRepo, repos.BowerRepo, repos.ChickRepo, repos.ReadStateRepo, nil)
+	service.(*articleService).SetRSSService(rss)
+
+	full, err := service.GetFullArticle(ctx, "article-1", "user-1")
+	if err != nil {
+		t.Fatalf("GetFullArticle() unexpected error: %v", err)
+	}
+	if full.FullContent != "" || len(rss.fetched) != 0 {
+		t.Errorf("Expected no extraction without full text mode, got %q", full.FullContent)
+	}
+
+	subscription, _ := repos.FeedRepo.GetSubscription(ctx, bower.BowerID, feed.FeedID)
+	subscription.FullText = true
+	repos.FeedRepo.UpdateSubscription(ctx, subscription)
+
+	for i := 0; i < 2; i++ {
+		full, err = service.GetFullArticle(ctx, "article-1", "user-1")
+		if err != nil {
+			t.Fatalf("GetFullArticle() unexpected error: %v", err)
+		}
+		if full.FullContent != "<p>Full text.</p>" {
+			t.Errorf("Expected the page's content to be extracted on demand, got %q", full.FullContent)
+		}
+	}
+	if len(rss.fetched) != 1 {
+		t.Errorf("Expected the extracted content to be stored and reused, fetched %d times", len(rss.fetched))
+	}
+
+	if _, err := service.GetFullArticle(ctx, "article-1", "user-2"); err == nil {
+		t.Error("Expected an error for a user without access")
+	}
+}
@@ -0,0 +1,301 @@
+package service
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"hash/fnv"
+	"math/bits"
+	"net"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"feed-bower-api/internal/model"
+	"feed-bower-api/internal/repository"
+	"feed-bower-api/pkg/search"
+)
+
+// Duplicate detection settings
+const (
+	dedupRecentArticles  = 200            // Newest items of a feed checked for articles fetched again
+	dedupTitleCandidates = 10             // Articles with the same title compared for near-duplicates
+	dedupMinTitleTerms   = 3              // Shorter titles such as "Weekly update" are too common to match on
+	dedupMinContentTerms = 8              // Shorter content is too short to compare
+	dedupTimeWindow      = 72 * time.Hour // Maximum time between near-duplicates' publication
+	dedupMaxDistance     = 16             // Maximum number of differing content hash bits of near-duplicates, of 64
+)
+
+// trackingParams are query parameters that identify where a visitor came from rather than the page.
+// Parameters starting with "utm_" are removed as well. Generic names such as "ref" are left alone,
+// since some sites use them to pick the page, e.g. a branch in a GitHub link.
+var trackingParams = map[string]bool{
+	"fbclid": true, "gclid": true, "dclid": true, "msclkid": true, "yclid": true, "igshid": true,
+	"mc_cid": true, "mc_eid": true, "_hsenc": true, "_hsmi": true, "mkt_tok": true,
+}
+
+// savedArticles counts what happened to a feed's fetched articles
+type savedArticles struct {
+	Created  int              // New articles
+	Merged   int              // Near-duplicates of other feeds' articles, now also listed under the feed
+	Articles []*model.Article // The new articles, with their IDs
+}
+
+// saveFetchedArticles stores the articles fetched from a feed. Articles the feed already has are
+// skipped, and near-duplicates of other feeds' articles are merged into the existing article.
+//
+// Articles are duplicates if their canonical URLs are equal, or if they have the same title,
+// were published within dedupTimeWindow of each other and have similar content, or link to the
+// same host when either has too little content to compare.
+func saveFetchedArticles(ctx context.Context, articleRepo repository.ArticleRepository, feedID string, articles []*model.Article) (*savedArticles, error) {
+	result := &savedArticles{}
+	if len(articles) == 0 {
+		return result, nil
+	}
+
+	// Canonical URLs of the feed's newest items, including aliases and articles stored
+	// before dedup keys existed
+	recent, _, err := articleRepo.GetByFeedID(ctx, feedID, dedupRecentArticles, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get recent articles: %w", err)
+	}
+	known := make(map[string]bool, len(recent)+len(articles))
+	for _, article := range recent {
+		known[canonicalizeURL(article.URL)] = true
+	}
+
+	newArticles := make([]*model.Article, 0, len(articles))
+	for _, article := range articles {
+		setDedupKeys(article)
+		if known[article.CanonicalURL] || hasNearDuplicate(newArticles, article) {
+			continue
+		}
+		known[article.CanonicalURL] = true
+
+		duplicate, err := findDuplicateArticle(ctx, articleRepo, article)
+		if err != nil {
+			return nil, err
+		}
+
+		switch {
+		case duplicate == nil:
+			newArticles = append(newArticles, article)
+		case duplicate.HasFeed(feedID):
+			// Already stored for this feed under another URL
+		default:
+			if err := articleRepo.AddSourceFeed(ctx, duplicate.ArticleID, feedID); err != nil {
+				return nil, fmt.Errorf("failed to merge duplicate article: %w", err)
+			}
+			result.Merged++
+		}
+	}
+
+	if len(newArticles) > 0 {
+		if err := articleRepo.BatchCreate(ctx, newArticles); err != nil {
+			return nil, err
+		}
+	}
+	result.Created = len(newArticles)
+	result.Articles = newArticles
+
+	return result, nil
+}
+
+// findDuplicateArticle looks up a stored article that the article duplicates, or returns nil
+func findDuplicateArticle(ctx context.Context, articleRepo repository.ArticleRepository, article *model.Article) (*model.Article, error) {
+	existing, err := articleRepo.GetByCanonicalURL(ctx, article.CanonicalURL)
+	if err != nil && !isNotFoundError(err) {
+		return nil, fmt.Errorf("failed to look up duplicates: %w", err)
+	}
+	if err == nil && existing != nil {
+		return existing, nil
+	}
+
+	if article.TitleHash == "" {
+		return nil, nil
+	}
+
+	candidates, err := articleRepo.GetByTitleHash(ctx, article.TitleHash, dedupTitleCandidates)
+	if err != nil {
+		return nil, fmt.Errorf("failed to look up duplicates: %w", err)
+	}
+	for _, candidate := range candidates {
+		if !isSameStory(article, candidate) {
+			continue
+		}
+		if candidate.CanonicalURL == "" && (article.ContentHash == "" || candidate.ContentHash == "") {
+			// The title index doesn't hold canonical URLs, which are compared when content is missing
+			candidate, err = articleRepo.GetByID(ctx, candidate.ArticleID)
+			if err != nil && !isNotFoundError(err) {
+				return nil, fmt.Errorf("failed to look up duplicates: %w", err)
+			}
+			if err != nil || candidate == nil {
+				continue
+			}
+		}
+		if hasSameContent(article, candidate) {
+			return candidate, nil
+		}
+	}
+
+	return nil, nil
+}
+
+// hasNearDuplicate reports whether any of the articles is a near-duplicate of the article
+func hasNearDuplicate(articles []*model.Article, article *model.Article) bool {
+	for _, other := range articles {
+		if isNearDuplicate(article, other) {
+			return true
+		}
+	}
+	return false
+}
+
+// isNearDuplicate reports whether two articles with the same title tell the same story
+func isNearDuplicate(a, b *model.Article) bool {
+	return isSameStory(a, b) && hasSameContent(a, b)
+}
+
+// isSameStory reports whether two articles have the same title and were published within
+// dedupTimeWindow of each other
+func isSameStory(a, b *model.Article) bool {
+	if a.TitleHash == "" || a.TitleHash != b.TitleHash {
+		return false
+	}
+
+	distance := time.Duration(a.PublishedAt-b.PublishedAt) * time.Second
+	if distance < 0 {
+		distance = -distance
+	}
+	return distance <= dedupTimeWindow
+}
+
+// hasSameContent reports whether two articles have similar content. Articles with too little
+// content to hash are only taken for the same if they link to the same host, so that short items
+// with a common title, such as "Release notes", are not merged across sites.
+func hasSameContent(a, b *model.Article) bool {
+	if a.ContentHash != "" && b.ContentHash != "" {
+		return similarContent(a.ContentHash, b.ContentHash)
+	}
+	host := canonicalHost(a.CanonicalURL)
+	return host != "" && host == canonicalHost(b.CanonicalURL)
+}
+
+// canonicalHost returns the host of a canonical URL, or "" if it has none
+func canonicalHost(canonicalURL string) string {
+	u, err := url.Parse(canonicalURL)
+	if err != nil {
+		return ""
+	}
+	return u.Host
+}
+
+// setDedupKeys computes the keys used to find duplicates of an article
+func setDedupKeys(article *model.Article) {
+	article.CanonicalURL = canonicalizeURL(article.URL)
+	article.TitleHash = titleHash(article.Title)
+	article.ContentHash = contentHash(article.Content)
+}
+
+// canonicalizeURL normalizes an article URL so that links to the same page compare equal:
+// https scheme, lowercase host without "www." or default port, sorted query without tracking
+// parameters, and no fragment or trailing slash. URLs that cannot be parsed are only trimmed.
+func canonicalizeURL(rawURL string) string {
+	trimmed := strings.TrimSpace(rawURL)
+	u, err := url.Parse(trimmed)
+	if err != nil || u.Host == "" {
+		return trimmed
+	}
+
+	if u.Scheme == "http" || u.Scheme == "https" {
+		u.Scheme = "https"
+	}
+
+	host := strings.TrimPrefix(strings.ToLower(u.Hostname()), "www.")
+	if port := u.Port(); port != "" && port != "80" && port != "443" {
+		host = net.JoinHostPort(host, port)
+	} else if strings.Contains(host, ":") {
+		host = "[" + host + "]" // IPv6
+	}
+	u.Host = host
+	u.User = nil
+	u.Fragment = ""
+	u.RawFragment = ""
+
+	query := u.Query()
+	for key := range query {
+		lower := strings.ToLower(key)
+		if strings.HasPrefix(lower, "utm_") || trackingParams[lower] {
+			query.Del(key)
+		}
+	}
+	u.RawQuery = query.Encode() // Encode sorts by key
+	u.ForceQuery = false
+
+	u.Path = strings.TrimSuffix(u.Path, "/")
+	u.RawPath = strings.TrimSuffix(u.RawPath, "/")
+
+	return u.String()
+}
+
+// titleHash hashes the normalized terms of a title, so that titles differing only in case,
+// punctuation or character width hash the same. Titles too short to identify a story have no hash.
+func titleHash(title string) string {
+	terms := search.Terms(title)
+	if len(terms) < dedupMinTitleTerms {
+		return ""
+	}
+
+	sum := sha256.Sum256([]byte(strings.Join(terms, " ")))
+	return hex.EncodeToString(sum[:16])
+}
+
+// contentHash computes a 64-bit SimHash of the content's terms: similar texts get hashes
+// that differ in few bits. Content too short to compare has no hash.
+func contentHash(content string) string {
+	terms := search.Terms(content)
+	if len(terms) < dedupMinContentTerms {
+		return ""
+	}
+
+	var weights [64]int
+	for _, term := range terms {
+		h := fnv.New64a()
+		h.Write([]byte(term))
+		sum := h.Sum64()
+		for bit := 0; bit < 64; bit++ {
+			if sum&(1<<bit) != 0 {
+				weights[bit]++
+			} else {
+				weights[bit]--
+			}
+		}
+	}
+
+	var hash uint64
+	for bit, weight := range weights {
+		if weight > 0 {
+			hash |= 1 << bit
+		}
+	}
+
+	return fmt.Sprintf("%016x", hash)
+}
+
+// similarContent reports whether two content hashes differ in at most dedupMaxDistance bits.
+// A missing hash is similar to nothing.
+func similarContent(a, b string) bool {
+	if a == "" || b == "" {
+		return false
+	}
+
+	x, errA := strconv.ParseUint(a, 16, 64)
+	y, errB := strconv.ParseUint(b, 16, 64)
+	if errA != nil || errB != nil {
+		return a == b
+	}
+
+	return bits.OnesCount64(x^y) <= dedupMaxDistance
+}
@@ -0,0 +1,168 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"feed-bower-api/internal/model"
+)
+
+func TestCanonicalizeURL(t *testing.T) {
+	tests := []struct {
+		name string
+		url  string
+		want string
+	}{
+		{"tracking params", "https://example.com/post?utm_source=rss&utm_medium=feed&id=1", "https://example.com/post?id=1"},
+		{"http and www", "http://www.Example.com/post", "https://example.com/post"},
+		{"sorted query", "https://example.com/post?b=2&a=1&fbclid=abc", "https://example.com/post?a=1&b=2"},
+		{"fragment and trailing slash", "https://example.com/post/#comments", "https://example.com/post"},
+		{"default port", "https://example.com:443/post", "https://example.com/post"},
+		{"other port", "http://example.com:8080/post", "https://example.com:8080/post"},
+		{"root", "https://example.com/", "https://example.com"},
+		{"page-selecting ref is kept", "https://github.com/owner/repo/blob/README.md?ref=dev", "https://github.com/owner/repo/blob/README.md?ref=dev"},
+		{"path case is kept", "https://example.com/Post", "https://example.com/Post"},
+		{"not a URL", " urn:isbn:123 ", "urn:isbn:123"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := canonicalizeURL(tt.url); got != tt.want {
+				t.Errorf("canonicalizeURL(%q) = %q, want %q", tt.url, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestDedupHashes(t *testing.T) {
+	if titleHash("Golang 1.26 Released!") != titleHash("golang １．２６ released") {
+		t.Error("Expected titles differing in case, punctuation and width to hash the same")
+	}
+	if titleHash("Weekly update") != "" {
+		t.Error("Expected short titles to have no hash")
+	}
+
+	original := contentHash("The Go team announced the release of Go 1.26 today, with faster builds, generic type aliases and a new garbage collector.")
+	edited := contentHash("The Go team announced the release of Go 1.26 today, with faster builds, generic type aliases and a new garbage collector. Read more on the blog.")
+	different := contentHash("A recipe for sourdough bread with a long cold fermentation, a crisp crust and an open crumb that keeps for days.")
+	if !similarContent(original, edited) {
+		t.Errorf("Expected lightly edited content to be similar: %s, %s", original, edited)
+	}
+	if similarContent(original, different) {
+		t.Errorf("Expected unrelated content to differ: %s, %s", original, different)
+	}
+	if similarContent(original, "") {
+		t.Error("Expected a missing content hash to be similar to nothing")
+	}
+
+	// Items too short to hash are only the same story on the same site
+	article := &model.Article{Title: "Release notes for version 2", URL: "https://example.com/releases/2"}
+	sameSite := &model.Article{Title: "Release notes for version 2", URL: "https://www.example.com/releases/2/"}
+	otherSite := &model.Article{Title: "Release notes for version 2", URL: "https://example.org/releases/2"}
+	for _, a := range []*model.Article{article, sameSite, otherSite} {
+		setDedupKeys(a)
+	}
+	if !isNearDuplicate(article, sameSite) {
+		t.Error("Expected short items with the same title on the same site to be duplicates")
+	}
+	if isNearDuplicate(article, otherSite) {
+		t.Error("Expected short items with the same title on other sites not to be duplicates")
+	}
+}
+
+func TestSaveFetchedArticles_MergesDuplicatesAcrossFeeds(t *testing.T) {
+	ctx := context.Background()
+	repos := NewMockRepositories()
+	now := time.Now()
+
+	bower := model.NewBower("user-1", "Tech", []string{"go"}, nil, "#14b8a6", false)
+	repos.BowerRepo.Create(ctx, bower)
+	otherBower := model.NewBower("user-2", "News", []string{"go"}, nil, "#14b8a6", false)
+	repos.BowerRepo.Create(ctx, otherBower)
+	feed := model.NewFeed(bower.BowerID, "https://example.com/feed.xml", "Example", "", "tech")
+	repos.FeedRepo.Create(ctx, feed)
+	syndicated := model.NewFeed(otherBower.BowerID, "https://news.example.org/feed.xml", "News", "", "tech")
+	repos.FeedRepo.Create(ctx, syndicated)
+
+	content := "The Go team announced the release of Go 1.26 today, with faster builds and generic type aliases."
+	saved, err := saveFetchedArticles(ctx, repos.ArticleRepo, feed.FeedID, []*model.Article{
+		model.NewArticle(feed.FeedID, "Go 1.26 is released", content, "https://example.com/go-1-26", now),
+		model.NewArticle(feed.FeedID, "Go 1.26 is released", content, "https://example.com/go-1-26?utm_source=rss", now),
+		model.NewArticle(feed.FeedID, "Rust 2026 edition announced", "", "https://example.com/rust", now),
+	})
+	if err != nil {
+		t.Fatalf("saveFetchedArticles() unexpected error: %v", err)
+	}
+	if saved.Created != 2 || saved.Merged != 0 {
+		t.Fatalf("Expected 2 new articles without the tracking link copy, got %+v", saved)
+	}
+
+	// The same stories from another feed: one by URL with tracking params, one linking to another
+	// page of the same site, and an unrelated article with the same title a week later
+	saved, err = saveFetchedArticles(ctx, repos.ArticleRepo, syndicated.FeedID, []*model.Article{
+		model.NewArticle(syndicated.FeedID, "Go 1.26 is released", content+" Via Example.", "http://www.example.com/go-1-26/?utm_campaign=x", now),
+		model.NewArticle(syndicated.FeedID, "RUST 2026 Edition Announced", "", "https://example.com/news/rust-2026", now.Add(3*time.Hour)),
+		model.NewArticle(syndicated.FeedID, "Go 1.26 is released", "", "https://news.example.org/go-recap", now.Add(7*24*time.Hour)),
+	})
+	if err != nil {
+		t.Fatalf("saveFetchedArticles() unexpected error: %v", err)
+	}
+	if saved.Created != 1 || saved.Merged != 2 {
+		t.Fatalf("Expected 2 merged articles and 1 new, got %+v", saved)
+	}
+
+	articles, _, err := repos.ArticleRepo.GetByFeedIDs(ctx, []string{feed.FeedID, syndicated.FeedID}, 10, nil)
+	if err != nil {
+		t.Fatalf("GetByFeedIDs() unexpected error: %v", err)
+	}
+	if len(articles) != 3 {
+		t.Fatalf("Expected each story listed once across both feeds, got %d articles", len(articles))
+	}
+	for _, article := range articles {
+		if article.FeedID == feed.FeedID && (len(article.SourceFeedIDs) != 1 || article.SourceFeedIDs[0] != syndicated.FeedID) {
+			t.Errorf("Expected article %q to list the syndicating feed as a source, got %v", article.Title, article.SourceFeedIDs)
+		}
+	}
+
+	// Fetching again stores nothing
+	saved, err = saveFetchedArticles(ctx, repos.ArticleRepo, syndicated.FeedID, []*model.Article{
+		model.NewArticle(syndicated.FeedID, "Go 1.26 is released", content, "https://example.com/go-1-26", now),
+	})
+	if err != nil || saved.Created != 0 || saved.Merged != 0 {
+		t.Errorf("Expected a repeated fetch to store nothing, got %+v, %v", saved, err)
+	}
+
+	// A user subscribed only to the syndicating feed can open the merged article
+	articleService := NewArticleService(repos.ArticleRepo, repos.FeedRepo, repos.BowerRepo, repos.ChickRepo, repos.ReadStateRepo, nil)
+	resp, err := articleService.GetArticles(ctx, "user-2", &GetArticlesRequest{BowerID: &otherBower.BowerID})
+	if err != nil {
+		t.Fatalf("GetArticles() unexpected error: %v", err)
+	}
+	if len(resp.Articles) != 3 || resp.Articles[0].Bower != "News" {
+		t.Fatalf("Expected the merged articles listed in the syndicating bower, got %v", articleIDs(resp.Articles))
+	}
+	merged := resp.Articles[len(resp.Articles)-1]
+	if _, err := articleService.GetArticleByID(ctx, merged.ArticleID, "user-2"); err != nil {
+		t.Errorf("GetArticleByID() unexpected error for a merged article: %v", err)
+	}
+
+	// Deleting the original bower hands the merged articles over to the syndicating feed
+	bowers := NewBowerService(repos.BowerRepo, repos.FeedRepo)
+	bowers.(*bowerService).SetArticleRepositories(repos.ArticleRepo, repos.ChickRepo, repos.ReadStateRepo)
+	if _, err := bowers.DeleteBower(ctx, "user-1", bower.BowerID); err != nil {
+		t.Fatalf("DeleteBower() unexpected error: %v", err)
+	}
+	articles, _, _ = repos.ArticleRepo.GetByFeedIDs(ctx, []string{syndicated.FeedID}, 10, nil)
+	if len(articles) != 3 {
+		t.Fatalf("Expected the syndicating feed to keep its 3 articles, got %d", len(articles))
+	}
+	for _, article := range articles {
+		if article.FeedID != syndicated.FeedID || len(article.SourceFeedIDs) != 0 {
+			t.Errorf("Expected article %q to belong to the syndicating feed only, got %s %v", article.Title, article.FeedID, article.SourceFeedIDs)
+		}
+	}
+	if len(repos.ArticleRepo.articles) != 3 {
+		t.Errorf("Expected aliases to be removed with the feed, got %d items", len(repos.ArticleRepo.articles))
+	}
+}
@@ -0,0 +1,357 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/http"
+	"net/url"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"feed-bower-api/internal/model"
+	"feed-bower-api/internal/repository"
+	"feed-bower-api/pkg/charset"
+	"feed-bower-api/pkg/httpclient"
+	"feed-bower-api/pkg/sanitize"
+)
+
+// Media limits
+const (
+	maxArticleMedia        = 20         // Media stored per article
+	maxPageImageLookupSize = 512 * 1024 // Preview images are declared in the head, so only the start of a page is read
+	maxPageImageLookups    = 10         // Articles per feed fetch whose page is looked up, newest first
+)
+
+// errNoPageImage is returned when a page does not declare a preview image
+var errNoPageImage = errors.New("page has no preview image")
+
+var metaTagRegex = regexp.MustCompile(`(?is)<meta\b[^>]*>`)
+
+// pageImageProperties are the meta tags declaring a page's preview image, in order of preference
+var pageImageProperties = []string{"og:image:secure_url", "og:image", "og:image:url", "twitter:image", "twitter:image:src"}
+
+// mediaExtensions maps file extensions to media kinds, for media without a MIME type
+var mediaExtensions = map[string]string{
+	".avif": model.MediumImage, ".gif": model.MediumImage, ".jpeg": model.MediumImage, ".jpg": model.MediumImage,
+	".png": model.MediumImage, ".svg": model.MediumImage, ".webp": model.MediumImage,
+	".aac": model.MediumAudio, ".m4a": model.MediumAudio, ".mp3": model.MediumAudio, ".oga": model.MediumAudio,
+	".ogg": model.MediumAudio, ".opus": model.MediumAudio, ".wav": model.MediumAudio,
+	".m4v": model.MediumVideo, ".mov": model.MediumVideo, ".mp4": model.MediumVideo, ".webm": model.MediumVideo,
+}
+
+// FetchPageImage returns the preview image an article's page declares with og:image or twitter:image
+func (s *rssService) FetchPageImage(ctx context.Context, pageURL string) (string, error) {
+	if pageURL == "" {
+		return "", errors.New("page URL is required")
+	}
+
+	config := httpclient.DefaultSecureHTTPConfig()
+	if err := httpclient.ValidateURL(pageURL, config); err != nil {
+		return "", fmt.Errorf("invalid page URL: %w", err)
+	}
+
+	headers := map[string]string{
+		"Accept": "text/html, application/xhtml+xml;q=0.9",
+	}
+
+	resp, err := s.secureClient.Do(ctx, "GET", pageURL, headers)
+	if err != nil {
+		return "", fmt.Errorf("failed to fetch page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return "", &HTTPError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxPageImageLookupSize))
+	if err != nil {
+		return "", fmt.Errorf("failed to read page: %w", err)
+	}
+	contentType := resp.Header.Get("Content-Type")
+	if !isHTMLDocument(body, contentType) {
+		return "", errors.New("page is not HTML")
+	}
+	body, err = charset.HTMLToUTF8(body, contentType)
+	if err != nil {
+		return "", fmt.Errorf("failed to decode page: %w", err)
+	}
+
+	// Resolve relative images against the page the request was redirected to
+	if resp.Request != nil && resp.Request.URL != nil {
+		pageURL = resp.Request.URL.String()
+	}
+
+	imageURL := ExtractPageImage(body, pageURL)
+	if imageURL == "" {
+		return "", errNoPageImage
+	}
+	return imageURL, nil
+}
+
+// ExtractPageImage returns the preview image declared by an HTML page's og:image or twitter:image
+// meta tags, resolved against the page URL
+func ExtractPageImage(page []byte, pageURL string) string {
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return ""
+	}
+
+	images := make(map[string]string)
+	for _, tag := range metaTagRegex.FindAll(page, -1) {
+		attrs := parseHTMLAttrs(tag)
+		property := strings.ToLower(attrs["property"])
+		if property == "" {
+			property = strings.ToLower(attrs["name"])
+		}
+		if _, exists := images[property]; !exists && attrs["content"] != "" {
+			images[property] = attrs["content"]
+		}
+	}
+
+	for _, property := range pageImageProperties {
+		if imageURL := sanitize.URL(images[property], base); imageURL != "" {
+			return imageURL
+		}
+	}
+	return ""
+}
+
+// itemMedia collects the media of a feed item, in order of preference for the article image:
+// Media RSS thumbnails, Media RSS content, enclosures and the iTunes episode image.
+// Top-level Media RSS elements come before those in media:group.
+func itemMedia(base *url.URL, thumbnails []MediaThumbnail, contents []MediaContent, groups []MediaGroup, enclosures []Enclosure, itunesImage string) []model.ArticleMedia {
+	for _, group := range groups {
+		thumbnails = append(thumbnails, group.Thumbnails...)
+		contents = append(contents, group.Contents...)
+	}
+	for _, content := range contents {
+		thumbnails = append(thumbnails, content.Thumbnails...)
+	}
+
+	var media []model.ArticleMedia
+	for _, thumbnail := range thumbnails {
+		media = appendMedia(media, mediaThumbnail(thumbnail), base)
+	}
+	for _, content := range contents {
+		media = appendMedia(media, model.ArticleMedia{
+			URL:      content.URL,
+			Type:     content.Type,
+			Medium:   content.Medium,
+			Size:     parseMediaInt(content.FileSize),
+			Width:    int(parseMediaInt(content.Width)),
+			Height:   int(parseMediaInt(content.Height)),
+			Duration: parseMediaInt(content.Duration),
+		}, base)
+	}
+	for _, enclosure := range enclosures {
+		media = appendMedia(media, model.ArticleMedia{
+			URL:  enclosure.URL,
+			Type: enclosure.Type,
+			Size: parseMediaInt(enclosure.Length),
+		}, base)
+	}
+	if itunesImage != "" {
+		media = appendMedia(media, model.ArticleMedia{URL: itunesImage, Medium: model.MediumImage}, base)
+	}
+
+	return media
+}
+
+// mediaThumbnail converts a Media RSS thumbnail
+func mediaThumbnail(thumbnail MediaThumbnail) model.ArticleMedia {
+	return model.ArticleMedia{
+		URL:    thumbnail.URL,
+		Medium: model.MediumImage,
+		Width:  int(parseMediaInt(thumbnail.Width)),
+		Height: int(parseMediaInt(thumbnail.Height)),
+	}
+}
+
+// appendMedia adds media to the list, resolving its URL against base and filling in its kind.
+// Media without an http(s) URL is skipped, as is media already listed, whose missing details
+// are filled in instead.
+func appendMedia(list []model.ArticleMedia, media model.ArticleMedia, base *url.URL) []model.ArticleMedia {
+	media.URL = sanitize.URL(media.URL, base)
+	if media.URL == "" {
+		return list
+	}
+	media.Type = strings.ToLower(strings.TrimSpace(strings.SplitN(media.Type, ";", 2)[0]))
+	media.Medium = mediumOf(media)
+
+	for i := range list {
+		if list[i].URL != media.URL {
+			continue
+		}
+		existing := &list[i]
+		if existing.Type == "" {
+			existing.Type = media.Type
+		}
+		if existing.Size == 0 {
+			existing.Size = media.Size
+		}
+		if existing.Width == 0 && existing.Height == 0 {
+			existing.Width, existing.Height = media.Width, media.Height
+		}
+		if existing.Duration == 0 {
+			existing.Duration = media.Duration
+		}
+		return list
+	}
+
+	if len(list) >= maxArticleMedia {
+		return list
+	}
+	return append(list, media)
+}
+
+// mediumOf returns the kind of media, from its declared medium, its MIME type or its file extension
+func mediumOf(media model.ArticleMedia) string {
+	switch medium := strings.ToLower(media.Medium); medium {
+	case model.MediumImage, model.MediumAudio, model.MediumVideo, model.MediumDocument:
+		return medium
+	}
+
+	if kind, _, found := strings.Cut(media.Type, "/"); found {
+		switch kind {
+		case model.MediumImage, model.MediumAudio, model.MediumVideo:
+			return kind
+		}
+		if media.Type != "application/octet-stream" {
+			return model.MediumDocument
+		}
+	}
+
+	if u, err := url.Parse(media.URL); err == nil {
+		if medium, ok := mediaExtensions[strings.ToLower(path.Ext(u.Path))]; ok {
+			return medium
+		}
+	}
+	return model.MediumDocument
+}
+
+// articleImage returns the first image in an article's media, or else the first image in its content
+func articleImage(media []model.ArticleMedia, content string, base *url.URL) *string {
+	for _, m := range media {
+		if m.Medium == model.MediumImage {
+			imageURL := m.URL
+			return &imageURL
+		}
+	}
+	if imageURL := sanitize.ImageURL(content, base); imageURL != "" {
+		return &imageURL
+	}
+	return nil
+}
+
+// itemEpisode returns the podcast metadata of an item whose media includes audio or video, or nil
+// for other items. The iTunes duration is preferred over the one declared on the media, and is
+// copied to the episode's audio or video file if that declares none.
+func itemEpisode(tags ITunesItem, media []model.ArticleMedia) *model.Episode {
+	var file *model.ArticleMedia
+	for i := range media {
+		if media[i].Medium == model.MediumAudio || media[i].Medium == model.MediumVideo {
+			file = &media[i]
+			break
+		}
+	}
+	if file == nil {
+		return nil
+	}
+
+	episode := &model.Episode{
+		Duration: parseEpisodeDuration(tags.ITunesDuration),
+		Season:   int(parseMediaInt(tags.ITunesSeason)),
+		Number:   int(parseMediaInt(tags.ITunesEpisode)),
+		Type:     model.EpisodeTypeFull,
+	}
+	if episode.Duration == 0 {
+		episode.Duration = file.Duration
+	} else if file.Duration == 0 {
+		file.Duration = episode.Duration
+	}
+
+	switch episodeType := strings.ToLower(strings.TrimSpace(tags.ITunesEpisodeType)); episodeType {
+	case model.EpisodeTypeTrailer, model.EpisodeTypeBonus:
+		episode.Type = episodeType
+	}
+
+	switch strings.ToLower(strings.TrimSpace(tags.ITunesExplicit)) {
+	case "true", "yes", "explicit":
+		episode.Explicit = true
+	}
+
+	return episode
+}
+
+// parseEpisodeDuration parses an iTunes duration given as seconds, "MM:SS" or "HH:MM:SS",
+// returning 0 if it is missing or invalid. Fractions of a second are dropped.
+func parseEpisodeDuration(value string) int64 {
+	value, _, _ = strings.Cut(strings.TrimSpace(value), ".")
+	if value == "" {
+		return 0
+	}
+
+	parts := strings.Split(value, ":")
+	if len(parts) > 3 {
+		return 0
+	}
+
+	var seconds int64
+	for i, part := range parts {
+		n, err := strconv.ParseInt(part, 10, 64)
+		if err != nil || n < 0 || (i > 0 && n >= 60) {
+			return 0
+		}
+		seconds = seconds*60 + n
+	}
+	return seconds
+}
+
+// parseMediaInt parses a numeric media attribute, returning 0 if it is missing or invalid
+func parseMediaInt(value string) int64 {
+	n, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+	if err != nil || n < 0 {
+		return 0
+	}
+	return n
+}
+
+// fillPageImages looks up the preview image of newly saved articles whose feed has no image for them.
+// At most maxPageImageLookups articles are looked up. It returns the number of images found.
+func fillPageImages(ctx context.Context, rssService RSSService, articleRepo repository.ArticleRepository, articles []*model.Article) int {
+	var missing []*model.Article
+	for _, article := range articles {
+		if article.ImageURL == nil && article.URL != "" {
+			missing = append(missing, article)
+		}
+	}
+
+	found := 0
+	for _, article := range newestArticles(missing, maxPageImageLookups) {
+		if ctx.Err() != nil {
+			break
+		}
+
+		imageURL, err := rssService.FetchPageImage(ctx, article.URL)
+		if err != nil {
+			if !errors.Is(err, errNoPageImage) {
+				log.Printf("⚠️ Page image lookup failed for %s: %v", article.URL, err)
+			}
+			continue
+		}
+		if err := articleRepo.SetImageURL(ctx, article.ArticleID, imageURL); err != nil {
+			log.Printf("⚠️ Failed to store page image for %s: %v", article.URL, err)
+			continue
+		}
+		article.SetImageURL(imageURL)
+		found++
+	}
+
+	return found
+}
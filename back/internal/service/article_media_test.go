@@ -0,0 +1,362 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"feed-bower-api/internal/model"
+)
+
+func TestRSSService_ParseRSSMedia(t *testing.T) {
+	service := NewRSSService()
+
+	rssData := `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:media="http://search.yahoo.com/mrss/" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd">
+  <channel>
+    <title>Media Feed</title>
+    <link>https://example.com/</link>
+    <item>
+      <title>Episode 1</title>
+      <link>https://example.com/episodes/1</link>
+      <description>Show notes</description>
+      <enclosure url="/audio/1.mp3" length="12345678" type="audio/mpeg"/>
+      <itunes:image href="https://example.com/art/1.jpg"/>
+    </item>
+    <item>
+      <title>Photo story</title>
+      <link>https://example.com/photos</link>
+      <description><![CDATA[<p>Story <img src="https://example.com/inline.png"></p>]]></description>
+      <media:group>
+        <media:content url="https://example.com/video.mp4" type="video/mp4" fileSize="2048" width="1280" height="720">
+          <media:thumbnail url="https://example.com/video.jpg" width="320" height="180"/>
+        </media:content>
+      </media:group>
+      <media:content url="https://example.com/large.jpg" medium="image" width="1600" height="900"/>
+    </item>
+    <item>
+      <title>Inline image</title>
+      <link>https://example.com/inline</link>
+      <description><![CDATA[<img src="https://stats.wordpress.com/b.gif"><img src="https://example.com/photo?id=1">]]></description>
+    </item>
+  </channel>
+</rss>`
+
+	feedData, err := service.ParseRSSFeed([]byte(rssData))
+	if err != nil {
+		t.Fatalf("ParseRSSFeed() unexpected error: %v", err)
+	}
+	if len(feedData.Articles) != 3 {
+		t.Fatalf("Expected 3 articles, got %d", len(feedData.Articles))
+	}
+
+	episode := feedData.Articles[0]
+	if len(episode.Media) != 2 {
+		t.Fatalf("Expected the enclosure and iTunes image, got %+v", episode.Media)
+	}
+	audio := model.ArticleMedia{URL: "https://example.com/audio/1.mp3", Type: "audio/mpeg", Medium: model.MediumAudio, Size: 12345678}
+	if episode.Media[0] != audio {
+		t.Errorf("Unexpected enclosure: %+v", episode.Media[0])
+	}
+	if episode.ImageURL == nil || *episode.ImageURL != "https://example.com/art/1.jpg" {
+		t.Errorf("Expected the iTunes image as article image, got %v", episode.ImageURL)
+	}
+	if episode.Content != "Show notes" {
+		t.Errorf("Expected media elements not to be read as content, got %q", episode.Content)
+	}
+
+	story := feedData.Articles[1]
+	if len(story.Media) != 3 {
+		t.Fatalf("Expected video, thumbnail and image, got %+v", story.Media)
+	}
+	video := model.ArticleMedia{URL: "https://example.com/video.mp4", Type: "video/mp4", Medium: model.MediumVideo, Size: 2048, Width: 1280, Height: 720}
+	if story.Media[1].URL != "https://example.com/large.jpg" || story.Media[1].Medium != model.MediumImage {
+		t.Errorf("Unexpected image: %+v", story.Media[1])
+	}
+	if story.Media[2] != video {
+		t.Errorf("Unexpected video: %+v", story.Media[2])
+	}
+	if story.ImageURL == nil || *story.ImageURL != "https://example.com/video.jpg" {
+		t.Errorf("Expected the thumbnail as article image, got %v", story.ImageURL)
+	}
+
+	inline := feedData.Articles[2]
+	if inline.ImageURL == nil || *inline.ImageURL != "https://example.com/photo?id=1" {
+		t.Errorf("Expected the first content image after the tracking pixel, got %v", inline.ImageURL)
+	}
+
+	articles := ConvertToArticles("feed-1", feedData.Articles)
+	if len(articles[1].Media) != 3 {
+		t.Errorf("Expected media on the converted article, got %+v", articles[1].Media)
+	}
+}
+
+func TestRSSService_ParseAtomMedia(t *testing.T) {
+	service := NewRSSService()
+
+	atomData := `<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom" xmlns:media="http://search.yahoo.com/mrss/">
+  <title>Video Channel</title>
+  <link rel="alternate" href="https://videos.example.com/"/>
+  <entry>
+    <title>Talk</title>
+    <link rel="alternate" href="https://videos.example.com/watch/1"/>
+    <link rel="enclosure" href="https://videos.example.com/talk.webm" type="video/webm" length="4096"/>
+    <updated>2025-01-01T00:00:00Z</updated>
+    <content type="html">&lt;p&gt;A talk&lt;/p&gt;</content>
+    <media:group>
+      <media:thumbnail url="https://videos.example.com/talk.jpg" width="480" height="360"/>
+    </media:group>
+  </entry>
+</feed>`
+
+	feedData, err := service.ParseAtomFeed([]byte(atomData))
+	if err != nil {
+		t.Fatalf("ParseAtomFeed() unexpected error: %v", err)
+	}
+	entry := feedData.Articles[0]
+	if len(entry.Media) != 2 || entry.Media[1].Medium != model.MediumVideo || entry.Media[1].Size != 4096 {
+		t.Errorf("Expected the thumbnail and the enclosure link, got %+v", entry.Media)
+	}
+	if entry.ImageURL == nil || *entry.ImageURL != "https://videos.example.com/talk.jpg" {
+		t.Errorf("Expected the thumbnail as article image, got %v", entry.ImageURL)
+	}
+	if entry.ContentHTML != "<p>A talk</p>" {
+		t.Errorf("Expected the entry's content, got %q", entry.ContentHTML)
+	}
+}
+
+func TestMediumOf(t *testing.T) {
+	tests := []struct {
+		media model.ArticleMedia
+		want  string
+	}{
+		{model.ArticleMedia{URL: "https://example.com/a", Medium: "Video"}, model.MediumVideo},
+		{model.ArticleMedia{URL: "https://example.com/a", Type: "audio/x-m4a"}, model.MediumAudio},
+		{model.ArticleMedia{URL: "https://example.com/a.pdf", Type: "application/pdf"}, model.MediumDocument},
+		{model.ArticleMedia{URL: "https://example.com/a.mp3", Type: "application/octet-stream"}, model.MediumAudio},
+		{model.ArticleMedia{URL: "https://example.com/photo.JPG?w=100"}, model.MediumImage},
+		{model.ArticleMedia{URL: "https://example.com/download"}, model.MediumDocument},
+	}
+
+	for _, tt := range tests {
+		if got := mediumOf(tt.media); got != tt.want {
+			t.Errorf("mediumOf(%+v) = %q, want %q", tt.media, got, tt.want)
+		}
+	}
+}
+
+func TestExtractPageImage(t *testing.T) {
+	tests := []struct {
+		name string
+		page string
+		want string
+	}{
+		{
+			"og:image is resolved",
+			`<html><head><meta property="og:image" content="/images/cover.png"></head></html>`,
+			"https://example.com/images/cover.png",
+		},
+		{
+			"og:image is preferred over twitter:image",
+			`<meta name="twitter:image" content="https://example.com/twitter.png"><meta content="https://example.com/og.png" property="og:image">`,
+			"https://example.com/og.png",
+		},
+		{
+			"twitter:image is the fallback",
+			`<meta name="twitter:image" content="https://example.com/twitter.png">`,
+			"https://example.com/twitter.png",
+		},
+		{
+			"unsafe schemes are ignored",
+			`<meta property="og:image" content="javascript:alert(1)">`,
+			"",
+		},
+		{
+			"no preview image",
+			`<meta name="description" content="A page">`,
+			"",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ExtractPageImage([]byte(tt.page), "https://example.com/posts/1"); got != tt.want {
+				t.Errorf("ExtractPageImage() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+// stubPageImageRSSService returns fixed preview images for known page URLs
+type stubPageImageRSSService struct {
+	RSSService
+	images map[string]string
+}
+
+func (s *stubPageImageRSSService) FetchPageImage(ctx context.Context, pageURL string) (string, error) {
+	if imageURL, ok := s.images[pageURL]; ok {
+		return imageURL, nil
+	}
+	return "", errNoPageImage
+}
+
+func TestFillPageImages(t *testing.T) {
+	ctx := context.Background()
+	articleRepo := NewMockArticleRepository()
+
+	withImage := model.NewArticle("feed-1", "With image", "Content", "https://example.com/1", time.Now())
+	withImage.SetImageURL("https://example.com/feed-image.png")
+	withoutImage := model.NewArticle("feed-1", "Without image", "Content", "https://example.com/2", time.Now())
+	noPreview := model.NewArticle("feed-1", "No preview", "Content", "https://example.com/3", time.Now())
+
+	articles := []*model.Article{withImage, withoutImage, noPreview}
+	for i, article := range articles {
+		article.ArticleID = "article-" + string(rune('1'+i))
+		articleRepo.Create(ctx, article)
+	}
+
+	rss := &stubPageImageRSSService{images: map[string]string{
+		"https://example.com/1": "https://example.com/og-1.png",
+		"https://example.com/2": "https://example.com/og-2.png",
+	}}
+
+	if found := fillPageImages(ctx, rss, articleRepo, articles); found != 1 {
+		t.Errorf("Expected one page image, got %d", found)
+	}
+
+	stored, _ := articleRepo.GetByID(ctx, withoutImage.ArticleID)
+	if stored.ImageURL == nil || *stored.ImageURL != "https://example.com/og-2.png" {
+		t.Errorf("Expected the page image to be stored, got %v", stored.ImageURL)
+	}
+	if *withImage.ImageURL != "https://example.com/feed-image.png" {
+		t.Errorf("Expected the feed's image to be kept, got %s", *withImage.ImageURL)
+	}
+	if noPreview.ImageURL != nil {
+		t.Errorf("Expected no image for a page without preview, got %s", *noPreview.ImageURL)
+	}
+}
+
+func TestRSSService_ParsePodcastEpisodes(t *testing.T) {
+	service := NewRSSService()
+
+	rssData := `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd" xmlns:media="http://search.yahoo.com/mrss/">
+  <channel>
+    <title>Podcast</title>
+    <link>https://podcast.example.com/</link>
+    <item>
+      <title>Episode 12: Generics</title>
+      <itunes:title>Generics</itunes:title>
+      <itunes:author>Go Hosts</itunes:author>
+      <link>https://podcast.example.com/12</link>
+      <description>Notes</description>
+      <enclosure url="https://podcast.example.com/12.mp3" length="1000" type="audio/mpeg"/>
+      <itunes:duration>1:02:03</itunes:duration>
+      <itunes:season>2</itunes:season>
+      <itunes:episode>12</itunes:episode>
+      <itunes:episodeType>Bonus</itunes:episodeType>
+      <itunes:explicit>yes</itunes:explicit>
+    </item>
+    <item>
+      <itunes:title>Trailer</itunes:title>
+      <itunes:summary>Coming soon</itunes:summary>
+      <media:content url="https://podcast.example.com/trailer.mp4" type="video/mp4" duration="95"/>
+      <itunes:episodeType>trailer</itunes:episodeType>
+    </item>
+    <item>
+      <title>Blog post</title>
+      <description>No audio</description>
+      <itunes:duration>10:00</itunes:duration>
+    </item>
+  </channel>
+</rss>`
+
+	feedData, err := service.ParseRSSFeed([]byte(rssData))
+	if err != nil {
+		t.Fatalf("ParseRSSFeed() unexpected error: %v", err)
+	}
+	if len(feedData.Articles) != 3 {
+		t.Fatalf("Expected 3 articles, got %d", len(feedData.Articles))
+	}
+
+	full := feedData.Articles[0]
+	if full.Title != "Episode 12: Generics" {
+		t.Errorf("Expected the item's own title over itunes:title, got %q", full.Title)
+	}
+	if len(full.Authors) != 1 || full.Authors[0].Name != "Go Hosts" {
+		t.Errorf("Expected the iTunes author, got %+v", full.Authors)
+	}
+	expected := model.Episode{Duration: 3723, Season: 2, Number: 12, Type: model.EpisodeTypeBonus, Explicit: true}
+	if full.Episode == nil || *full.Episode != expected {
+		t.Errorf("Expected episode %+v, got %+v", expected, full.Episode)
+	}
+	if full.Media[0].Duration != 3723 {
+		t.Errorf("Expected the iTunes duration on the enclosure, got %d", full.Media[0].Duration)
+	}
+
+	trailer := feedData.Articles[1]
+	if trailer.Title != "Trailer" || trailer.Content != "Coming soon" {
+		t.Errorf("Expected the iTunes title and summary as fallbacks, got %q / %q", trailer.Title, trailer.Content)
+	}
+	if trailer.Episode == nil || trailer.Episode.Duration != 95 || trailer.Episode.Type != model.EpisodeTypeTrailer {
+		t.Errorf("Expected a trailer with the media duration, got %+v", trailer.Episode)
+	}
+
+	if feedData.Articles[2].Episode != nil {
+		t.Errorf("Expected no episode without audio or video, got %+v", feedData.Articles[2].Episode)
+	}
+
+	articles := ConvertToArticles("feed-1", feedData.Articles)
+	if articles[0].Episode == nil || articles[0].EpisodeMedia() == nil {
+		t.Error("Expected the episode on the converted article")
+	}
+}
+
+func TestRSSService_ParseJSONFeedEpisode(t *testing.T) {
+	service := NewRSSService()
+
+	jsonData := `{
+  "version": "https://jsonfeed.org/version/1.1",
+  "title": "JSON Podcast",
+  "items": [{
+    "id": "1",
+    "url": "https://example.com/1",
+    "title": "Episode 1",
+    "content_text": "Notes",
+    "attachments": [{"url": "https://example.com/1.m4a", "mime_type": "audio/x-m4a", "duration_in_seconds": 1800}]
+  }]
+}`
+
+	feedData, err := service.ParseJSONFeed([]byte(jsonData))
+	if err != nil {
+		t.Fatalf("ParseJSONFeed() unexpected error: %v", err)
+	}
+	episode := feedData.Articles[0].Episode
+	if episode == nil || episode.Duration != 1800 || episode.Type != model.EpisodeTypeFull {
+		t.Errorf("Expected a full episode with the attachment's duration, got %+v", episode)
+	}
+}
+
+func TestParseEpisodeDuration(t *testing.T) {
+	tests := []struct {
+		value string
+		want  int64
+	}{
+		{"3600", 3600},
+		{"45:30", 2730},
+		{"1:02:03", 3723},
+		{" 01:00:00 ", 3600},
+		{"125.5", 125},
+		{"", 0},
+		{"1:75", 0},
+		{"1:2:3:4", 0},
+		{"abc", 0},
+	}
+
+	for _, tt := range tests {
+		if got := parseEpisodeDuration(tt.value); got != tt.want {
+			t.Errorf("parseEpisodeDuration(%q) = %d, want %d", tt.value, got, tt.want)
+		}
+	}
+}
@@ -0,0 +1,154 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"feed-bower-api/internal/model"
+	"feed-bower-api/internal/repository"
+)
+
+var (
+	errNotEpisode          = errors.New("article is not a podcast episode")
+	errPlaybackUnavailable = errors.New("playback positions are not available")
+)
+
+// SavePlaybackPositionRequest represents the request to save how far a user has played an episode
+type SavePlaybackPositionRequest struct {
+	Position  int64 `json:"position"`           // Seconds from the start
+	Duration  int64 `json:"duration,omitempty"` // Seconds; defaults to the episode's duration from the feed
+	Completed bool  `json:"completed,omitempty"`
+}
+
+// SetPlaybackRepository enables saving and resuming podcast playback positions
+func (s *articleService) SetPlaybackRepository(playbackRepo repository.PlaybackRepository) {
+	s.playbackRepo = playbackRepo
+}
+
+// GetPlaybackPosition returns where the user should resume a podcast episode. Episodes the user
+// has not played resume from the start.
+func (s *articleService) GetPlaybackPosition(ctx context.Context, userID string, articleID string) (*model.PlaybackPosition, error) {
+	article, err := s.getEpisode(ctx, userID, articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	position, err := s.playbackRepo.Get(ctx, userID, articleID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get playback position: %w", err)
+	}
+	if position == nil {
+		position = &model.PlaybackPosition{UserID: userID, ArticleID: articleID, Duration: article.Episode.Duration}
+	}
+
+	return position, nil
+}
+
+// SavePlaybackPosition stores how far the user has played a podcast episode, so playback resumes
+// there on any device
+func (s *articleService) SavePlaybackPosition(ctx context.Context, userID string, articleID string, req *SavePlaybackPositionRequest) (*model.PlaybackPosition, error) {
+	if req == nil {
+		return nil, errors.New("save playback position request is required")
+	}
+	if req.Position < 0 || req.Duration < 0 {
+		return nil, errors.New("position and duration cannot be negative")
+	}
+
+	article, err := s.getEpisode(ctx, userID, articleID)
+	if err != nil {
+		return nil, err
+	}
+
+	duration := req.Duration
+	if duration == 0 {
+		duration = article.Episode.Duration
+	}
+
+	position := model.NewPlaybackPosition(userID, articleID, req.Position, duration)
+	if req.Completed {
+		position.Completed = true
+	}
+
+	if err := s.playbackRepo.Save(ctx, position); err != nil {
+		return nil, fmt.Errorf("failed to save playback position: %w", err)
+	}
+
+	return position, nil
+}
+
+// getEpisode returns a podcast episode the user has access to
+func (s *articleService) getEpisode(ctx context.Context, userID string, articleID string) (*model.Article, error) {
+	if userID == "" {
+		return nil, errors.New("user ID is required")
+	}
+	if articleID == "" {
+		return nil, errors.New("article ID is required")
+	}
+	if s.playbackRepo == nil {
+		return nil, errPlaybackUnavailable
+	}
+
+	// Check if article exists and user has access
+	article, err := s.GetArticleByID(ctx, articleID, userID)
+	if err != nil {
+		return nil, fmt.Errorf("article access check failed: %w", err)
+	}
+	if article.Episode == nil {
+		return nil, errNotEpisode
+	}
+
+	return article, nil
+}
+
+// getPodcastEpisodes returns the podcast episodes of the user's feeds, newest first. Pages continue
+// from the returned key like the all tab.
+func (s *articleService) getPodcastEpisodes(ctx context.Context, userID string, req *GetArticlesRequest) ([]*model.Article, map[string]types.AttributeValue, error) {
+	access, err := s.accessibleFeeds(ctx, userID, req.BowerID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(access.FeedIDs) == 0 {
+		return []*model.Article{}, nil, nil
+	}
+
+	episodes, nextKey, err := s.articleRepo.GetEpisodesByFeedIDs(ctx, access.FeedIDs, req.Limit, req.LastKey)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get episodes: %w", err)
+	}
+
+	// Pages can come out shorter than the limit when bower filter rules exclude episodes, or when
+	// the feeds have few episodes among their articles
+	return access.filter(episodes), nextKey, nil
+}
+
+// attachPlaybackPositions sets the user's playback position on the podcast episodes they have played
+func (s *articleService) attachPlaybackPositions(ctx context.Context, userID string, articles []*model.Article) error {
+	if s.playbackRepo == nil {
+		return nil
+	}
+
+	var episodeIDs []string
+	for _, article := range articles {
+		if article.Episode != nil {
+			episodeIDs = append(episodeIDs, article.ArticleID)
+		}
+	}
+	if len(episodeIDs) == 0 {
+		return nil
+	}
+
+	positions, err := s.playbackRepo.GetPositions(ctx, userID, episodeIDs)
+	if err != nil {
+		return fmt.Errorf("failed to get playback positions: %w", err)
+	}
+
+	for _, article := range articles {
+		article.Playback = positions[article.ArticleID]
+	}
+
+	return nil
+}
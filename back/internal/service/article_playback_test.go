@@ -0,0 +1,127 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"reflect"
+	"testing"
+	"time"
+
+	"feed-bower-api/internal/model"
+)
+
+// newPodcastTestService creates an article service with one bower and feed holding a podcast
+// episode ("episode-1") and a regular article ("article-1")
+func newPodcastTestService(t *testing.T) (ArticleService, *MockRepositories) {
+	t.Helper()
+	ctx := context.Background()
+	repos := NewMockRepositories()
+
+	bower := model.NewBower("user-1", "Podcasts", []string{"go"}, nil, "#14b8a6", false)
+	repos.BowerRepo.Create(ctx, bower)
+	feed := model.NewFeed(bower.BowerID, "https://example.com/podcast.xml", "Podcast", "", "tech")
+	repos.FeedRepo.Create(ctx, feed)
+
+	episode := model.NewArticle(feed.FeedID, "Episode 1", "Notes", "https://example.com/episodes/1", time.Now().Add(-time.Hour))
+	episode.ArticleID = "episode-1"
+	episode.Media = []model.ArticleMedia{{URL: "https://example.com/1.mp3", Medium: model.MediumAudio}}
+	episode.Episode = &model.Episode{Duration: 600, Type: model.EpisodeTypeFull}
+	repos.ArticleRepo.Create(ctx, episode)
+
+	article := model.NewArticle(feed.FeedID, "Blog post", "Text", "https://example.com/posts/1", time.Now())
+	article.ArticleID = "article-1"
+	repos.ArticleRepo.Create(ctx, article)
+
+	service := NewArticleService(repos.ArticleRepo, repos.FeedRepo, repos.BowerRepo, repos.ChickRepo, repos.ReadStateRepo, nil)
+	service.(*articleService).SetPlaybackRepository(repos.PlaybackRepo)
+	return service, repos
+}
+
+func TestArticleService_PlaybackPosition(t *testing.T) {
+	ctx := context.Background()
+	service, _ := newPodcastTestService(t)
+
+	position, err := service.GetPlaybackPosition(ctx, "user-1", "episode-1")
+	if err != nil {
+		t.Fatalf("GetPlaybackPosition() unexpected error: %v", err)
+	}
+	if position.Position != 0 || position.Duration != 600 || position.Completed {
+		t.Errorf("Expected an unplayed episode to resume from the start, got %+v", position)
+	}
+
+	saved, err := service.SavePlaybackPosition(ctx, "user-1", "episode-1", &SavePlaybackPositionRequest{Position: 120})
+	if err != nil {
+		t.Fatalf("SavePlaybackPosition() unexpected error: %v", err)
+	}
+	if saved.Duration != 600 || saved.Completed {
+		t.Errorf("Expected the episode's duration and an incomplete episode, got %+v", saved)
+	}
+
+	position, _ = service.GetPlaybackPosition(ctx, "user-1", "episode-1")
+	if position.Position != 120 {
+		t.Errorf("Expected to resume at 120s, got %d", position.Position)
+	}
+
+	saved, _ = service.SavePlaybackPosition(ctx, "user-1", "episode-1", &SavePlaybackPositionRequest{Position: 590})
+	if !saved.Completed {
+		t.Errorf("Expected an episode played to its last seconds to be completed, got %+v", saved)
+	}
+
+	article, _ := service.GetArticleByID(ctx, "episode-1", "user-1")
+	if article.Playback == nil || article.Playback.Position != 590 {
+		t.Errorf("Expected the playback position on the article, got %+v", article.Playback)
+	}
+
+	if _, err := service.SavePlaybackPosition(ctx, "user-1", "article-1", &SavePlaybackPositionRequest{Position: 10}); err == nil || err.Error() != "article is not a podcast episode" {
+		t.Errorf("Expected an error for an article without audio, got %v", err)
+	}
+	if _, err := service.SavePlaybackPosition(ctx, "user-1", "episode-1", &SavePlaybackPositionRequest{Position: -1}); err == nil {
+		t.Error("Expected an error for a negative position")
+	}
+	if _, err := service.GetPlaybackPosition(ctx, "user-2", "episode-1"); err == nil {
+		t.Error("Expected an error for a user without access")
+	}
+}
+
+func TestArticleService_GetArticles_PodcastsTab(t *testing.T) {
+	ctx := context.Background()
+	service, repos := newPodcastTestService(t)
+
+	resp, err := service.GetArticles(ctx, "user-1", &GetArticlesRequest{Tab: "podcasts", Limit: 10})
+	if err != nil {
+		t.Fatalf("GetArticles() unexpected error: %v", err)
+	}
+	if len(resp.Articles) != 1 || resp.Articles[0].ArticleID != "episode-1" {
+		t.Errorf("Expected only the podcast episode, got %+v", resp.Articles)
+	}
+
+	// Episodes older than many newer articles are still listed, one page after another
+	for i := 2; i <= 3; i++ {
+		episode := model.NewArticle(resp.Articles[0].FeedID, fmt.Sprintf("Episode %d", i), "", fmt.Sprintf("https://example.com/episodes/%d", i), time.Now().Add(-time.Duration(i)*24*time.Hour))
+		episode.ArticleID = fmt.Sprintf("episode-%d", i)
+		episode.Episode = &model.Episode{Duration: 600, Type: model.EpisodeTypeFull}
+		repos.ArticleRepo.Create(ctx, episode)
+	}
+	for i := 0; i < 250; i++ {
+		post := model.NewArticle(resp.Articles[0].FeedID, "Blog post", "Text", fmt.Sprintf("https://example.com/posts/%d", i+2), time.Now().Add(-time.Duration(i)*time.Minute))
+		post.ArticleID = fmt.Sprintf("post-%d", i)
+		repos.ArticleRepo.Create(ctx, post)
+	}
+
+	var episodes []string
+	req := &GetArticlesRequest{Tab: "podcasts", Limit: 2}
+	for {
+		resp, err := service.GetArticles(ctx, "user-1", req)
+		if err != nil {
+			t.Fatalf("GetArticles() unexpected error: %v", err)
+		}
+		episodes = append(episodes, articleIDs(resp.Articles)...)
+		if !resp.HasMore {
+			break
+		}
+		req.LastKey = resp.LastKey
+	}
+	if !reflect.DeepEqual(episodes, []string{"episode-1", "episode-2", "episode-3"}) {
+		t.Errorf("Expected every episode newest first, got %v", episodes)
+	}
+}
@@ -0,0 +1,269 @@
+package service
+
+import (
+	"fmt"
+	"strconv"
+	"strings"
+	"time"
+	"unicode"
+
+	"feed-bower-api/internal/model"
+	"feed-bower-api/pkg/search"
+)
+
+// ArticleQuery is a search query parsed into free text and structured filters.
+//
+// Supported syntax, combined with AND:
+//
+//	golang "error handling"    free text and phrases, ranked by relevance
+//	-keyword, -"some phrase"   exclude articles containing the text
+//	title:golang               title contains the word or phrase (-title: excludes)
+//	feed:<feed_id>             only articles of the feed (-feed: excludes)
+//	after:2026-01-01           published on or after the date (UTC)
+//	before:2026-02-01          published before the date (UTC)
+//	liked:true, read:false     like and read state of the current user
+//	has:image                  articles with an image (-has:image for articles without)
+type ArticleQuery struct {
+	Text            string // Free text passed to the search index, including exclusions
+	Title           [][]string
+	ExcludedTitle   [][]string
+	FeedIDs         []string
+	ExcludedFeedIDs []string
+	After           *time.Time
+	Before          *time.Time
+	Liked           *bool
+	Read            *bool
+	HasImage        *bool
+
+	text search.Query
+}
+
+// ParseArticleQuery parses a search query. Words with an unknown prefix such as "https:" are
+// treated as free text.
+func ParseArticleQuery(query string) (*ArticleQuery, error) {
+	q := &ArticleQuery{}
+	var text []string
+
+	for _, part := range splitArticleQuery(query) {
+		negated := strings.HasPrefix(part, "-")
+		key, value, found := strings.Cut(strings.TrimPrefix(part, "-"), ":")
+		key = strings.ToLower(key)
+		value = strings.Trim(value, `"`)
+
+		if !found || !isArticleQueryKey(key) {
+			text = append(text, part)
+			continue
+		}
+		if value == "" {
+			return nil, fmt.Errorf("invalid search query: %s: requires a value", key)
+		}
+		if negated && !isNegatableArticleQueryKey(key) {
+			return nil, fmt.Errorf("invalid search query: %s: cannot be negated", key)
+		}
+
+		switch key {
+		case "title":
+			terms := search.Terms(value)
+			if len(terms) == 0 {
+				continue
+			}
+			if negated {
+				q.ExcludedTitle = append(q.ExcludedTitle, terms)
+			} else {
+				q.Title = append(q.Title, terms)
+			}
+		case "feed":
+			if negated {
+				q.ExcludedFeedIDs = append(q.ExcludedFeedIDs, value)
+			} else {
+				q.FeedIDs = append(q.FeedIDs, value)
+			}
+		case "after", "before":
+			date, err := parseQueryDate(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid search query: %s: %w", key, err)
+			}
+			if key == "after" {
+				q.After = &date
+			} else {
+				q.Before = &date
+			}
+		case "liked", "read":
+			flag, err := strconv.ParseBool(value)
+			if err != nil {
+				return nil, fmt.Errorf("invalid search query: %s: expected true or false, got %q", key, value)
+			}
+			if key == "liked" {
+				q.Liked = &flag
+			} else {
+				q.Read = &flag
+			}
+		case "has":
+			if strings.ToLower(value) != "image" {
+				return nil, fmt.Errorf("invalid search query: has: unknown value %q", value)
+			}
+			hasImage := !negated
+			q.HasImage = &hasImage
+		}
+	}
+
+	q.Text = strings.Join(text, " ")
+	q.text = search.ParseQuery(q.Text)
+
+	return q, nil
+}
+
+// IsEmpty reports whether the query has neither text nor filters
+func (q *ArticleQuery) IsEmpty() bool {
+	return q.text.IsEmpty() && len(q.text.Excluded) == 0 &&
+		len(q.Title) == 0 && len(q.ExcludedTitle) == 0 &&
+		len(q.FeedIDs) == 0 && len(q.ExcludedFeedIDs) == 0 &&
+		q.After == nil && q.Before == nil &&
+		q.Liked == nil && q.Read == nil && q.HasImage == nil
+}
+
+// HasText reports whether the query has free text to rank articles by
+func (q *ArticleQuery) HasText() bool {
+	return !q.text.IsEmpty()
+}
+
+// MatchesFeed reports whether articles of a feed can match the query
+func (q *ArticleQuery) MatchesFeed(feedID string) bool {
+	for _, excluded := range q.ExcludedFeedIDs {
+		if excluded == feedID {
+			return false
+		}
+	}
+	if len(q.FeedIDs) == 0 {
+		return true
+	}
+	for _, included := range q.FeedIDs {
+		if included == feedID {
+			return true
+		}
+	}
+	return false
+}
+
+// MatchesContent checks the filters that depend only on the article itself.
+// Like and read state are checked with MatchesUserState once the article is enriched.
+func (q *ArticleQuery) MatchesContent(article *model.Article) bool {
+	if q.After != nil && article.PublishedAt < q.After.Unix() {
+		return false
+	}
+	if q.Before != nil && article.PublishedAt >= q.Before.Unix() {
+		return false
+	}
+	if q.HasImage != nil && article.HasImage() != *q.HasImage {
+		return false
+	}
+
+	if len(q.Title) > 0 || len(q.ExcludedTitle) > 0 {
+		title := search.Terms(article.Title)
+		for _, phrase := range q.Title {
+			if !containsPhrase(title, phrase) {
+				return false
+			}
+		}
+		for _, phrase := range q.ExcludedTitle {
+			if containsPhrase(title, phrase) {
+				return false
+			}
+		}
+	}
+
+	// The search index applies text exclusions itself; they are checked here for
+	// queries without free text, which list articles instead of searching
+	if !q.HasText() && len(q.text.Excluded) > 0 {
+		title := search.Terms(article.Title)
+		content := search.Terms(article.Content)
+		for _, phrase := range q.text.Excluded {
+			if containsPhrase(title, phrase) || containsPhrase(content, phrase) {
+				return false
+			}
+		}
+	}
+
+	return true
+}
+
+// MatchesUserState checks the like and read filters against an enriched article
+func (q *ArticleQuery) MatchesUserState(article *model.Article) bool {
+	if q.Liked != nil && article.Liked != *q.Liked {
+		return false
+	}
+	if q.Read != nil && article.Read != *q.Read {
+		return false
+	}
+	return true
+}
+
+// isArticleQueryKey reports whether key is a filter of the query syntax
+func isArticleQueryKey(key string) bool {
+	switch key {
+	case "title", "feed", "after", "before", "liked", "read", "has":
+		return true
+	}
+	return false
+}
+
+// isNegatableArticleQueryKey reports whether a filter can be negated with a leading "-"
+func isNegatableArticleQueryKey(key string) bool {
+	return key == "title" || key == "feed" || key == "has"
+}
+
+// parseQueryDate parses a date as YYYY-MM-DD or RFC 3339
+func parseQueryDate(value string) (time.Time, error) {
+	if date, err := time.Parse("2006-01-02", value); err == nil {
+		return date, nil
+	}
+	if date, err := time.Parse(time.RFC3339, value); err == nil {
+		return date, nil
+	}
+	return time.Time{}, fmt.Errorf("expected a date like 2026-01-01, got %q", value)
+}
+
+// splitArticleQuery splits a query on spaces outside double quotes. Quotes are kept so that
+// phrases in the free text reach the search index intact.
+func splitArticleQuery(query string) []string {
+	var parts []string
+	var current strings.Builder
+	quoted := false
+
+	for _, r := range query {
+		switch {
+		case r == '"':
+			quoted = !quoted
+			current.WriteRune(r)
+		case !quoted && unicode.IsSpace(r):
+			if current.Len() > 0 {
+				parts = append(parts, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		parts = append(parts, current.String())
+	}
+
+	return parts
+}
+
+// containsPhrase reports whether terms contain phrase as a consecutive sequence
+func containsPhrase(terms []string, phrase []string) bool {
+	for i := 0; i+len(phrase) <= len(terms); i++ {
+		matched := true
+		for j, term := range phrase {
+			if terms[i+j] != term {
+				matched = false
+				break
+			}
+		}
+		if matched {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,312 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"math"
+	"strings"
+	"time"
+
+	"feed-bower-api/internal/model"
+	"feed-bower-api/internal/repository"
+	"feed-bower-api/pkg/search"
+)
+
+// Score factor names
+const (
+	ScoreFactorKeywords     = "keywords"
+	ScoreFactorLikes        = "likes"
+	ScoreFactorFeedAffinity = "feed_affinity"
+	ScoreFactorRecency      = "recency"
+)
+
+// ArticleScorer estimates how important articles are for a user
+type ArticleScorer interface {
+	// ScoreArticles returns one score per article, in the order of req.Articles
+	ScoreArticles(ctx context.Context, req *ScoreArticlesRequest) ([]*model.ArticleScore, error)
+}
+
+// ScoreArticlesRequest represents the articles to score and what is known about the user's interests
+type ScoreArticlesRequest struct {
+	UserID   string
+	Articles []*model.Article
+	Keywords map[string][]string // Keywords of the bowers subscribed to each feed, by feed ID
+	Now      time.Time
+}
+
+// ArticleScorerConfig holds the weights of the score factors
+type ArticleScorerConfig struct {
+	KeywordWeight      float64
+	LikeWeight         float64
+	FeedAffinityWeight float64
+	RecencyWeight      float64
+	RecencyHalfLife    time.Duration // Age at which the recency signal halves
+	LikeHistory        int32         // Number of recent likes the like and feed signals are based on
+}
+
+// DefaultArticleScorerConfig returns the default score weights
+func DefaultArticleScorerConfig() *ArticleScorerConfig {
+	return &ArticleScorerConfig{
+		KeywordWeight:      0.35,
+		LikeWeight:         0.25,
+		FeedAffinityWeight: 0.2,
+		RecencyWeight:      0.2,
+		RecencyHalfLife:    24 * time.Hour,
+		LikeHistory:        50,
+	}
+}
+
+// articleScorer implements ArticleScorer using bower keywords, like history, feed affinity and recency
+type articleScorer struct {
+	chickRepo   repository.ChickRepository
+	articleRepo repository.ArticleRepository
+	config      *ArticleScorerConfig
+}
+
+// NewArticleScorer creates a new article scorer with the default weights
+func NewArticleScorer(chickRepo repository.ChickRepository, articleRepo repository.ArticleRepository) ArticleScorer {
+	return NewArticleScorerWithConfig(chickRepo, articleRepo, nil)
+}
+
+// NewArticleScorerWithConfig creates a new article scorer with custom weights
+func NewArticleScorerWithConfig(chickRepo repository.ChickRepository, articleRepo repository.ArticleRepository, config *ArticleScorerConfig) ArticleScorer {
+	if config == nil {
+		config = DefaultArticleScorerConfig()
+	}
+	if config.RecencyHalfLife <= 0 {
+		config.RecencyHalfLife = DefaultArticleScorerConfig().RecencyHalfLife
+	}
+
+	return &articleScorer{
+		chickRepo:   chickRepo,
+		articleRepo: articleRepo,
+		config:      config,
+	}
+}
+
+// likeProfile summarizes a user's recent likes
+type likeProfile struct {
+	total       int
+	feedLikes   map[string]int
+	likedTitles map[string]map[string]bool // Informative title terms of each liked article
+}
+
+// ScoreArticles scores articles for a user
+func (s *articleScorer) ScoreArticles(ctx context.Context, req *ScoreArticlesRequest) ([]*model.ArticleScore, error) {
+	profile, err := s.loadLikeProfile(ctx, req.UserID)
+	if err != nil {
+		return nil, err
+	}
+
+	now := req.Now
+	if now.IsZero() {
+		now = time.Now()
+	}
+
+	scores := make([]*model.ArticleScore, len(req.Articles))
+	for i, article := range req.Articles {
+		// Articles merged from several feeds match the keywords of all their bowers
+		var keywords []string
+		for _, feedID := range article.FeedIDs() {
+			keywords = appendUnique(keywords, req.Keywords[feedID]...)
+		}
+
+		factors := []model.ScoreFactor{
+			s.keywordFactor(article, keywords),
+			s.likeFactor(article, profile),
+			s.feedAffinityFactor(article, profile),
+			s.recencyFactor(article, now),
+		}
+
+		total := 0.0
+		for _, factor := range factors {
+			total += factor.Value * factor.Weight
+		}
+
+		scores[i] = &model.ArticleScore{
+			Total:   roundScore(total),
+			Factors: factors,
+		}
+	}
+
+	return scores, nil
+}
+
+// loadLikeProfile loads the user's recent likes
+func (s *articleScorer) loadLikeProfile(ctx context.Context, userID string) (*likeProfile, error) {
+	likedArticles, _, err := s.chickRepo.GetLikedArticles(ctx, userID, s.config.LikeHistory, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get liked articles: %w", err)
+	}
+
+	profile := &likeProfile{
+		feedLikes:   make(map[string]int),
+		likedTitles: make(map[string]map[string]bool),
+	}
+	for _, likedArticle := range likedArticles {
+		article, err := s.articleRepo.GetByID(ctx, likedArticle.ArticleID)
+		if err != nil {
+			continue // Skip likes of deleted articles
+		}
+
+		profile.total++
+		profile.feedLikes[article.FeedID]++
+		profile.likedTitles[article.ArticleID] = informativeTerms(article.Title)
+	}
+
+	return profile, nil
+}
+
+// keywordFactor scores matches of the bower's keywords, counting title matches double
+func (s *articleScorer) keywordFactor(article *model.Article, keywords []string) model.ScoreFactor {
+	factor := model.ScoreFactor{Name: ScoreFactorKeywords, Weight: s.config.KeywordWeight}
+	if len(keywords) == 0 {
+		factor.Reason = "no bower keywords"
+		return factor
+	}
+
+	title := search.Terms(article.Title)
+	content := search.Terms(article.Content)
+
+	hits := 0.0
+	var matched []string
+	for _, keyword := range keywords {
+		terms := search.Terms(keyword)
+		if len(terms) == 0 {
+			continue
+		}
+		switch {
+		case containsPhrase(title, terms):
+			hits++
+		case containsPhrase(content, terms):
+			hits += 0.5
+		default:
+			continue
+		}
+		matched = append(matched, keyword)
+	}
+
+	// Two keywords in the title are a full match
+	factor.Value = roundScore(math.Min(1, hits/2))
+	if len(matched) == 0 {
+		factor.Reason = "matches no bower keywords"
+	} else {
+		factor.Reason = "matches keywords: " + strings.Join(matched, ", ")
+	}
+	return factor
+}
+
+// likeFactor scores title words shared with articles the user liked
+func (s *articleScorer) likeFactor(article *model.Article, profile *likeProfile) model.ScoreFactor {
+	factor := model.ScoreFactor{Name: ScoreFactorLikes, Weight: s.config.LikeWeight}
+
+	terms := informativeTerms(article.Title)
+	sharedTerms := make(map[string]bool)
+	similarLikes := 0
+	for articleID, likedTerms := range profile.likedTitles {
+		if articleID == article.ArticleID {
+			continue // An article is not similar to its own like
+		}
+		similar := false
+		for term := range terms {
+			if likedTerms[term] {
+				sharedTerms[term] = true
+				similar = true
+			}
+		}
+		if similar {
+			similarLikes++
+		}
+	}
+
+	// Three shared words are a full match
+	factor.Value = roundScore(math.Min(1, float64(len(sharedTerms))/3))
+	if similarLikes == 0 {
+		factor.Reason = "not similar to liked articles"
+	} else {
+		factor.Reason = fmt.Sprintf("shares %d title words with %d liked articles", len(sharedTerms), similarLikes)
+	}
+	return factor
+}
+
+// feedAffinityFactor scores how often the user likes articles from the article's feed,
+// relative to their most liked feed
+func (s *articleScorer) feedAffinityFactor(article *model.Article, profile *likeProfile) model.ScoreFactor {
+	factor := model.ScoreFactor{Name: ScoreFactorFeedAffinity, Weight: s.config.FeedAffinityWeight}
+
+	feedLikes := profile.feedLikes[article.FeedID]
+	if _, liked := profile.likedTitles[article.ArticleID]; liked {
+		feedLikes-- // Do not count the article's own like
+	}
+
+	maxFeedLikes := 0
+	for _, count := range profile.feedLikes {
+		if count > maxFeedLikes {
+			maxFeedLikes = count
+		}
+	}
+
+	if feedLikes <= 0 || maxFeedLikes == 0 {
+		factor.Reason = "no likes from this feed"
+		return factor
+	}
+
+	factor.Value = roundScore(float64(feedLikes) / float64(maxFeedLikes))
+	factor.Reason = fmt.Sprintf("%d of %d recent likes are from this feed", feedLikes, profile.total)
+	return factor
+}
+
+// recencyFactor scores how recently the article was published, halving every half-life
+func (s *articleScorer) recencyFactor(article *model.Article, now time.Time) model.ScoreFactor {
+	factor := model.ScoreFactor{Name: ScoreFactorRecency, Weight: s.config.RecencyWeight}
+
+	age := now.Sub(article.GetPublishedAtTime())
+	if age < 0 {
+		age = 0
+	}
+
+	factor.Value = roundScore(math.Pow(0.5, age.Hours()/s.config.RecencyHalfLife.Hours()))
+	switch {
+	case age < time.Hour:
+		factor.Reason = "published less than an hour ago"
+	case age < 48*time.Hour:
+		factor.Reason = fmt.Sprintf("published %d hours ago", int(age.Hours()))
+	default:
+		factor.Reason = fmt.Sprintf("published %d days ago", int(age.Hours()/24))
+	}
+	return factor
+}
+
+// scoreStopWords are common English words that say nothing about an article's topic
+var scoreStopWords = map[string]bool{
+	"the": true, "and": true, "for": true, "with": true, "from": true, "this": true, "that": true,
+	"are": true, "was": true, "you": true, "your": true, "how": true, "what": true, "why": true,
+	"new": true, "not": true, "all": true, "can": true, "now": true, "its": true,
+}
+
+// informativeTerms returns the distinct terms of a title, leaving out short words and stop words
+func informativeTerms(title string) map[string]bool {
+	terms := make(map[string]bool)
+	for _, term := range search.Terms(title) {
+		if isASCIIWord(term) && (len(term) < 3 || scoreStopWords[term]) {
+			continue
+		}
+		terms[term] = true
+	}
+	return terms
+}
+
+// isASCIIWord reports whether a term consists of ASCII characters only
+func isASCIIWord(term string) bool {
+	for i := 0; i < len(term); i++ {
+		if term[i] >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
+
+// roundScore rounds a score to three decimals to keep responses readable
+func roundScore(score float64) float64 {
+	return math.Round(score*1000) / 1000
+}
@@ -0,0 +1,162 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"feed-bower-api/internal/model"
+)
+
+func TestArticleScorer_ScoreArticles(t *testing.T) {
+	ctx := context.Background()
+	repos := NewMockRepositories()
+	now := time.Now()
+
+	newArticle := func(id, feedID, title, content string, age time.Duration) *model.Article {
+		article := model.NewArticle(feedID, title, content, "https://example.com/"+id, now.Add(-age))
+		article.ArticleID = id
+		repos.ArticleRepo.Create(ctx, article)
+		return article
+	}
+
+	// The user liked two articles about Kubernetes from feed-1 and one from feed-2
+	newArticle("liked-1", "feed-1", "Kubernetes operators in practice", "", 72*time.Hour)
+	newArticle("liked-2", "feed-1", "Scaling Kubernetes clusters", "", 96*time.Hour)
+	newArticle("liked-3", "feed-2", "Cooking with cast iron", "", 96*time.Hour)
+	for _, id := range []string{"liked-1", "liked-2", "liked-3"} {
+		repos.ChickRepo.AddLikedArticle(ctx, model.NewLikedArticle("user-1", id))
+	}
+
+	candidates := []*model.Article{
+		newArticle("keyword", "feed-2", "Golang 1.26 release", "Generics and more.", 2*time.Hour),
+		newArticle("liked-topic", "feed-1", "Kubernetes operators explained", "", 2*time.Hour),
+		newArticle("old", "feed-3", "Unrelated news", "", 240*time.Hour),
+	}
+
+	scorer := NewArticleScorer(repos.ChickRepo, repos.ArticleRepo)
+	scores, err := scorer.ScoreArticles(ctx, &ScoreArticlesRequest{
+		UserID:   "user-1",
+		Articles: candidates,
+		Keywords: map[string][]string{"feed-2": {"golang", "release"}, "feed-1": {"golang"}},
+		Now:      now,
+	})
+	if err != nil {
+		t.Fatalf("ScoreArticles() unexpected error: %v", err)
+	}
+	if len(scores) != len(candidates) {
+		t.Fatalf("Expected %d scores, got %d", len(candidates), len(scores))
+	}
+
+	factor := func(score *model.ArticleScore, name string) model.ScoreFactor {
+		for _, f := range score.Factors {
+			if f.Name == name {
+				return f
+			}
+		}
+		t.Fatalf("Score has no %s factor", name)
+		return model.ScoreFactor{}
+	}
+
+	keyword := factor(scores[0], ScoreFactorKeywords)
+	if keyword.Value != 1 || keyword.Reason != "matches keywords: golang, release" {
+		t.Errorf("Expected full keyword match, got %+v", keyword)
+	}
+
+	likes := factor(scores[1], ScoreFactorLikes)
+	if likes.Value == 0 || factor(scores[0], ScoreFactorLikes).Value != 0 {
+		t.Errorf("Expected only the Kubernetes article to be similar to likes, got %+v", likes)
+	}
+
+	affinity := factor(scores[1], ScoreFactorFeedAffinity)
+	if affinity.Value != 1 || affinity.Reason != "2 of 3 recent likes are from this feed" {
+		t.Errorf("Expected full affinity for the most liked feed, got %+v", affinity)
+	}
+	if factor(scores[0], ScoreFactorFeedAffinity).Value != 0.5 {
+		t.Errorf("Expected half affinity for a feed with half the likes, got %+v", factor(scores[0], ScoreFactorFeedAffinity))
+	}
+
+	if recency := factor(scores[2], ScoreFactorRecency); recency.Value >= 0.01 {
+		t.Errorf("Expected a ten day old article to have almost no recency, got %+v", recency)
+	}
+
+	if scores[2].Total >= scores[0].Total || scores[2].Total >= scores[1].Total {
+		t.Errorf("Expected the unrelated old article to score lowest, got %v, %v, %v", scores[0].Total, scores[1].Total, scores[2].Total)
+	}
+}
+
+func TestArticleService_ImportantTab(t *testing.T) {
+	ctx := context.Background()
+	repos := NewMockRepositories()
+
+	bower := model.NewBower("user-1", "Tech", []string{"golang"}, nil, "#14b8a6", false)
+	repos.BowerRepo.Create(ctx, bower)
+	feed := model.NewFeed(bower.BowerID, "https://example.com/feed.xml", "Example", "", "tech")
+	repos.FeedRepo.Create(ctx, feed)
+
+	now := time.Now()
+	for _, a := range []struct {
+		id, title string
+		age       time.Duration
+	}{
+		{"newest", "Weekly roundup", time.Hour},
+		{"golang", "Golang generics deep dive", 30 * time.Hour},
+		{"older", "Office party photos", 50 * time.Hour},
+	} {
+		article := model.NewArticle(feed.FeedID, a.title, "", "https://example.com/"+a.id, now.Add(-a.age))
+		article.ArticleID = a.id
+		repos.ArticleRepo.Create(ctx, article)
+	}
+
+	articleService := NewArticleService(repos.ArticleRepo, repos.FeedRepo, repos.BowerRepo, repos.ChickRepo, repos.ReadStateRepo, nil)
+
+	resp, err := articleService.GetArticles(ctx, "user-1", &GetArticlesRequest{Tab: "important", Limit: 2})
+	if err != nil {
+		t.Fatalf("GetArticles() unexpected error: %v", err)
+	}
+	if len(resp.Articles) != 2 || resp.Articles[0].ArticleID != "golang" || resp.Articles[1].ArticleID != "newest" {
+		t.Errorf("Expected the keyword match first and the top 2 only, got %v", articleIDs(resp.Articles))
+	}
+	for _, article := range resp.Articles {
+		if article.Score == nil || len(article.Score.Factors) != 4 {
+			t.Errorf("Expected article %s to carry an explained score, got %+v", article.ArticleID, article.Score)
+		}
+	}
+	if !resp.HasMore {
+		t.Fatal("Expected a key to the next page")
+	}
+
+	resp, err = articleService.GetArticles(ctx, "user-1", &GetArticlesRequest{Tab: "important", Limit: 2, LastKey: resp.LastKey})
+	if err != nil {
+		t.Fatalf("GetArticles() unexpected error: %v", err)
+	}
+	if len(resp.Articles) != 1 || resp.Articles[0].ArticleID != "older" || resp.HasMore {
+		t.Errorf("Expected the last article on the second page, got %v", articleIDs(resp.Articles))
+	}
+
+	resp, err = articleService.GetArticles(ctx, "user-1", &GetArticlesRequest{Tab: "all"})
+	if err != nil {
+		t.Fatalf("GetArticles() unexpected error: %v", err)
+	}
+	if len(resp.Articles) != 3 {
+		t.Errorf("Expected the all tab to list every article, got %d", len(resp.Articles))
+	}
+
+	// A busy feed doesn't crowd the articles of other feeds out of the candidates
+	busyFeed := model.NewFeed(bower.BowerID, "https://example.com/busy.xml", "Busy", "", "tech")
+	repos.FeedRepo.Create(ctx, busyFeed)
+	for i := 0; i < 250; i++ {
+		article := model.NewArticle(busyFeed.FeedID, "Minor update", "", fmt.Sprintf("https://example.com/busy/%d", i), now.Add(-time.Duration(i)*time.Minute))
+		article.ArticleID = fmt.Sprintf("busy-%d", i)
+		repos.ArticleRepo.Create(ctx, article)
+	}
+
+	resp, err = articleService.GetArticles(ctx, "user-1", &GetArticlesRequest{Tab: "important", Limit: 1})
+	if err != nil {
+		t.Fatalf("GetArticles() unexpected error: %v", err)
+	}
+	if len(resp.Articles) != 1 || resp.Articles[0].ArticleID != "golang" {
+		t.Errorf("Expected the keyword match of the quiet feed first, got %v", articleIDs(resp.Articles))
+	}
+}
@@ -6,6 +6,8 @@ import (
 	"fmt"
 	"log"
 	"sort"
+	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 
@@ -18,6 +20,7 @@ type ArticleService interface {
 	// Article retrieval
 	GetArticles(ctx context.Context, userID string, req *GetArticlesRequest) (*ArticleListResponse, error)
 	GetArticleByID(ctx context.Context, articleID string, userID string) (*model.Article, error)
+	GetFullArticle(ctx context.Context, articleID string, userID string) (*model.Article, error)
 
 	// Like management
 	LikeArticle(ctx context.Context, userID string, articleID string) error
@@ -25,31 +28,49 @@ type ArticleService interface {
 
 	// Read management
 	MarkArticleAsRead(ctx context.Context, userID string, articleID string) error
+	MarkArticleAsUnread(ctx context.Context, userID string, articleID string) error
+
+	// Podcast playback
+	GetPlaybackPosition(ctx context.Context, userID string, articleID string) (*model.PlaybackPosition, error)
+	SavePlaybackPosition(ctx context.Context, userID string, articleID string, req *SavePlaybackPositionRequest) (*model.PlaybackPosition, error)
 
 	// Search
-	SearchArticles(ctx context.Context, userID string, req *SearchArticlesRequest) ([]*model.Article, error)
+	SearchArticles(ctx context.Context, userID string, req *SearchArticlesRequest) (*ArticleListResponse, error)
 
 	// Batch operations for RSS updates
 	CreateArticles(ctx context.Context, articles []*model.Article) error
+
+	// Output feed
+	GetBowerOutputFeed(ctx context.Context, bowerID string, token string) (*BowerOutputFeed, error)
 }
 
 // GetArticlesRequest represents the request to get articles
 type GetArticlesRequest struct {
 	BowerID   *string                         `json:"bower_id,omitempty"`
-	Tab       string                          `json:"tab" validate:"oneof=all important liked"`
+	Tab       string                          `json:"tab" validate:"oneof=all important liked podcasts"`
 	Limit     int32                           `json:"limit" validate:"min=1,max=100"`
 	LastKey   map[string]types.AttributeValue `json:"last_key,omitempty"`
 	SortBy    string                          `json:"sort_by" validate:"oneof=published_at created_at"`
 	SortOrder string                          `json:"sort_order" validate:"oneof=asc desc"`
 }
 
-// SearchArticlesRequest represents the request to search articles
+// SearchArticlesRequest represents the request to search articles.
+// Query uses the syntax described on ArticleQuery.
 type SearchArticlesRequest struct {
-	Query   string  `json:"query" validate:"required,min=1"`
-	BowerID *string `json:"bower_id,omitempty"`
-	Limit   int32   `json:"limit" validate:"min=1,max=100"`
+	Query   string                          `json:"query" validate:"required,min=1"`
+	BowerID *string                         `json:"bower_id,omitempty"`
+	Limit   int32                           `json:"limit" validate:"min=1,max=100"`
+	LastKey map[string]types.AttributeValue `json:"last_key,omitempty"`
 }
 
+// importantCandidatesPerFeed is how many of each feed's newest articles are scored for the
+// important tab, so that busy feeds don't crowd out the others
+const importantCandidatesPerFeed = 50
+
+// searchMaxReads caps how many pages of candidates a search page reads when filters reject most of
+// them. The page is then returned short, with a key to continue from.
+const searchMaxReads = 10
+
 // ArticleListResponse represents the response for article list
 type ArticleListResponse struct {
 	Articles []model.Article                 `json:"articles"`
@@ -60,11 +81,15 @@ type ArticleListResponse struct {
 
 // articleService implements ArticleService interface
 type articleService struct {
-	articleRepo  repository.ArticleRepository
-	feedRepo     repository.FeedRepository
-	bowerRepo    repository.BowerRepository
-	chickRepo    repository.ChickRepository
-	chickService ChickService
+	articleRepo   repository.ArticleRepository
+	feedRepo      repository.FeedRepository
+	bowerRepo     repository.BowerRepository
+	chickRepo     repository.ChickRepository
+	readStateRepo repository.ReadStateRepository
+	chickService  ChickService
+	scorer        ArticleScorer
+	rssService    RSSService                    // Optional, extracts full content on demand
+	playbackRepo  repository.PlaybackRepository // Optional, stores podcast playback positions
 }
 
 // NewArticleService creates a new article service
@@ -73,17 +98,30 @@ func NewArticleService(
 	feedRepo repository.FeedRepository,
 	bowerRepo repository.BowerRepository,
 	chickRepo repository.ChickRepository,
+	readStateRepo repository.ReadStateRepository,
 	chickService ChickService,
 ) ArticleService {
 	return &articleService{
-		articleRepo:  articleRepo,
-		feedRepo:     feedRepo,
-		bowerRepo:    bowerRepo,
-		chickRepo:    chickRepo,
-		chickService: chickService,
+		articleRepo:   articleRepo,
+		feedRepo:      feedRepo,
+		bowerRepo:     bowerRepo,
+		chickRepo:     chickRepo,
+		readStateRepo: readStateRepo,
+		chickService:  chickService,
+		scorer:        NewArticleScorer(chickRepo, articleRepo),
 	}
 }
 
+// SetArticleScorer replaces the scorer used to rank the important tab
+func (s *articleService) SetArticleScorer(scorer ArticleScorer) {
+	s.scorer = scorer
+}
+
+// SetRSSService enables extracting the full content of articles when it is first requested
+func (s *articleService) SetRSSService(rssService RSSService) {
+	s.rssService = rssService
+}
+
 // GetArticles retrieves articles based on the request parameters
 func (s *articleService) GetArticles(ctx context.Context, userID string, req *GetArticlesRequest) (*ArticleListResponse, error) {
 	if userID == "" {
@@ -115,9 +153,9 @@ func (s *articleService) GetArticles(ctx context.Context, userID string, req *Ge
 	case "liked":
 		articles, err = s.getLikedArticles(ctx, userID, req)
 	case "important":
-		// For now, important articles are the same as all articles
-		// This can be enhanced later with ML-based importance scoring
-		articles, nextKey, err = s.getAllArticles(ctx, userID, req)
+		articles, nextKey, err = s.getImportantArticles(ctx, userID, req)
+	case "podcasts":
+		articles, nextKey, err = s.getPodcastEpisodes(ctx, userID, req)
 	default: // "all"
 		articles, nextKey, err = s.getAllArticles(ctx, userID, req)
 	}
@@ -160,28 +198,45 @@ func (s *articleService) GetArticleByID(ctx context.Context, articleID string, u
 		return nil, fmt.Errorf("failed to get article: %w", err)
 	}
 
-	// Check if user has access through bower ownership
-	feed, err := s.feedRepo.GetByID(ctx, article.FeedID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get feed: %w", err)
+	// Check if user has access through a bower subscribed to one of the article's feeds
+	if _, _, err := findArticleSubscription(ctx, s.feedRepo, s.bowerRepo, article, userID); err != nil {
+		return nil, err
 	}
 
-	bower, err := s.bowerRepo.GetByID(ctx, feed.BowerID)
+	// Enrich article with like status and bower information
+	enrichedArticles, err := s.enrichArticles(ctx, userID, []*model.Article{article})
 	if err != nil {
-		return nil, fmt.Errorf("failed to get bower: %w", err)
+		return nil, fmt.Errorf("failed to enrich article: %w", err)
 	}
 
-	if bower.UserID != userID && !bower.IsPublic {
-		return nil, errors.New("access denied: bower is private")
+	return enrichedArticles[0], nil
+}
+
+// GetFullArticle retrieves a single article with the full content extracted from its page.
+// Content not extracted at ingest is extracted now if the user's subscription is in full text mode;
+// otherwise, or if extraction fails, FullContent is left empty.
+func (s *articleService) GetFullArticle(ctx context.Context, articleID string, userID string) (*model.Article, error) {
+	article, err := s.GetArticleByID(ctx, articleID, userID)
+	if err != nil {
+		return nil, err
+	}
+	if article.FullContent != "" || s.rssService == nil {
+		return article, nil
 	}
 
-	// Enrich article with like status and bower information
-	enrichedArticles, err := s.enrichArticles(ctx, userID, []*model.Article{article})
+	subscription, _, err := findArticleSubscription(ctx, s.feedRepo, s.bowerRepo, article, userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to enrich article: %w", err)
+		return nil, err
+	}
+	if !subscription.FullText {
+		return article, nil
 	}
 
-	return enrichedArticles[0], nil
+	if err := storeFullContent(ctx, s.rssService, s.articleRepo, article); err != nil {
+		log.Printf("⚠️ Full text extraction failed for %s: %v", article.URL, err)
+	}
+
+	return article, nil
 }
 
 // LikeArticle adds a like to an article
@@ -230,7 +285,7 @@ func (s *articleService) UnlikeArticle(ctx context.Context, userID string, artic
 	return nil
 }
 
-// MarkArticleAsRead marks an article as read (this is typically handled client-side)
+// MarkArticleAsRead marks an article as read for the user, so read state syncs across devices
 func (s *articleService) MarkArticleAsRead(ctx context.Context, userID string, articleID string) error {
 	if userID == "" {
 		return errors.New("user ID is required")
@@ -245,13 +300,41 @@ func (s *articleService) MarkArticleAsRead(ctx context.Context, userID string, a
 		return fmt.Errorf("article access check failed: %w", err)
 	}
 
-	// In the current design, read status is managed client-side in localStorage
-	// This method exists for future server-side read tracking if needed
+	err = s.readStateRepo.MarkRead(ctx, model.NewReadState(userID, articleID))
+	if err != nil {
+		return fmt.Errorf("failed to mark article as read: %w", err)
+	}
+
 	return nil
 }
 
-// SearchArticles searches for articles by title or content
-func (s *articleService) SearchArticles(ctx context.Context, userID string, req *SearchArticlesRequest) ([]*model.Article, error) {
+// MarkArticleAsUnread clears the user's read state for an article
+func (s *articleService) MarkArticleAsUnread(ctx context.Context, userID string, articleID string) error {
+	if userID == "" {
+		return errors.New("user ID is required")
+	}
+	if articleID == "" {
+		return errors.New("article ID is required")
+	}
+
+	// Check if article exists and user has access
+	_, err := s.GetArticleByID(ctx, articleID, userID)
+	if err != nil {
+		return fmt.Errorf("article access check failed: %w", err)
+	}
+
+	err = s.readStateRepo.MarkUnread(ctx, userID, articleID)
+	if err != nil {
+		return fmt.Errorf("failed to mark article as unread: %w", err)
+	}
+
+	return nil
+}
+
+// SearchArticles searches articles in a bower, or in all of the user's bowers, with the query
+// syntax of ArticleQuery. Queries with free text are ranked by relevance, others are listed newest
+// first. Pages continue from LastKey like GetArticles.
+func (s *articleService) SearchArticles(ctx context.Context, userID string, req *SearchArticlesRequest) (*ArticleListResponse, error) {
 	if userID == "" {
 		return nil, errors.New("user ID is required")
 	}
@@ -266,64 +349,93 @@ func (s *articleService) SearchArticles(ctx context.Context, userID string, req
 		req.Limit = 50
 	}
 
-	var feedIDs []string
-	var err error
+	query, err := ParseArticleQuery(req.Query)
+	if err != nil {
+		return nil, err
+	}
+	if query.IsEmpty() {
+		return nil, errors.New("search query is required")
+	}
 
-	if req.BowerID != nil {
-		// Search within a specific bower
-		bower, err := s.bowerRepo.GetByID(ctx, *req.BowerID)
-		if err != nil {
-			return nil, fmt.Errorf("bower not found: %w", err)
-		}
+	access, err := s.accessibleFeeds(ctx, userID, req.BowerID)
+	if err != nil {
+		return nil, err
+	}
 
-		if bower.UserID != userID && !bower.IsPublic {
-			return nil, errors.New("access denied: bower is private")
+	feedIDs := make([]string, 0, len(access.FeedIDs))
+	for _, feedID := range access.FeedIDs {
+		if query.MatchesFeed(feedID) {
+			feedIDs = append(feedIDs, feedID)
 		}
+	}
 
-		feeds, err := s.feedRepo.GetByBowerID(ctx, *req.BowerID)
-		if err != nil {
-			return nil, fmt.Errorf("failed to get bower feeds: %w", err)
-		}
+	if len(feedIDs) == 0 {
+		return &ArticleListResponse{Articles: []model.Article{}}, nil
+	}
 
-		feedIDs = make([]string, len(feeds))
-		for i, feed := range feeds {
-			feedIDs[i] = feed.FeedID
+	// Candidates are filtered after they are read, so candidates are read until the page is full
+	articleList := make([]model.Article, 0, req.Limit)
+	lastKey := req.LastKey
+	for reads := 0; reads < searchMaxReads && len(articleList) < int(req.Limit); reads++ {
+		need := req.Limit - int32(len(articleList))
+
+		var candidates []*model.Article
+		var nextKey map[string]types.AttributeValue
+		if query.HasText() {
+			candidates, nextKey, err = s.articleRepo.Search(ctx, query.Text, feedIDs, need, lastKey)
+		} else {
+			candidates, nextKey, err = s.articleRepo.GetByFeedIDs(ctx, feedIDs, need, lastKey)
 		}
-	} else {
-		// Search across all user's bowers
-		bowers, _, err := s.bowerRepo.GetByUserID(ctx, userID, 100, nil)
 		if err != nil {
-			return nil, fmt.Errorf("failed to get user bowers: %w", err)
+			if strings.Contains(err.Error(), "malformed cursor") {
+				return nil, errors.New("invalid search query: malformed cursor")
+			}
+			return nil, fmt.Errorf("failed to search articles: %w", err)
 		}
 
-		for _, bower := range bowers {
-			feeds, err := s.feedRepo.GetByBowerID(ctx, bower.BowerID)
-			if err != nil {
-				continue // Skip this bower if we can't get feeds
-			}
+		matched, err := s.matchSearch(ctx, userID, query, access, candidates)
+		if err != nil {
+			return nil, err
+		}
+		articleList = append(articleList, matched...)
 
-			for _, feed := range feeds {
-				feedIDs = append(feedIDs, feed.FeedID)
-			}
+		lastKey = nextKey
+		if lastKey == nil {
+			break
 		}
 	}
 
-	if len(feedIDs) == 0 {
-		return []*model.Article{}, nil
-	}
+	return &ArticleListResponse{
+		Articles: articleList,
+		Total:    len(articleList),
+		HasMore:  lastKey != nil,
+		LastKey:  lastKey,
+	}, nil
+}
 
-	articles, err := s.articleRepo.Search(ctx, req.Query, feedIDs, req.Limit)
-	if err != nil {
-		return nil, fmt.Errorf("failed to search articles: %w", err)
+// matchSearch returns the candidates of a search that match the query and the user can access,
+// enriched for the response
+func (s *articleService) matchSearch(ctx context.Context, userID string, query *ArticleQuery, access *feedAccess, candidates []*model.Article) ([]model.Article, error) {
+	matched := make([]*model.Article, 0, len(candidates))
+	for _, article := range candidates {
+		if query.MatchesContent(article) && access.allows(article) {
+			matched = append(matched, article)
+		}
 	}
 
 	// Enrich articles with like status and bower information
-	enrichedArticles, err := s.enrichArticles(ctx, userID, articles)
+	enrichedArticles, err := s.enrichArticles(ctx, userID, matched)
 	if err != nil {
 		return nil, fmt.Errorf("failed to enrich articles: %w", err)
 	}
 
-	return enrichedArticles, nil
+	articleList := make([]model.Article, 0, len(enrichedArticles))
+	for _, article := range enrichedArticles {
+		if query.MatchesUserState(article) {
+			articleList = append(articleList, *article)
+		}
+	}
+	return articleList, nil
 }
 
 // CreateArticles creates multiple articles (used by RSS service)
@@ -340,50 +452,75 @@ func (s *articleService) CreateArticles(ctx context.Context, articles []*model.A
 	return nil
 }
 
-// getAllArticles retrieves all articles for a user
-func (s *articleService) getAllArticles(ctx context.Context, userID string, req *GetArticlesRequest) ([]*model.Article, map[string]types.AttributeValue, error) {
-	var feedIDs []string
-	var err error
+// GetBowerOutputFeed retrieves a bower's newest articles for its public output feed.
+// Private bowers are only served when token matches the bower's feed token.
+func (s *articleService) GetBowerOutputFeed(ctx context.Context, bowerID string, token string) (*BowerOutputFeed, error) {
+	if bowerID == "" {
+		return nil, errors.New("bower ID is required")
+	}
 
-	if req.BowerID != nil {
-		// Get articles from specific bower
-		bower, err := s.bowerRepo.GetByID(ctx, *req.BowerID)
-		if err != nil {
-			return nil, nil, fmt.Errorf("bower not found: %w", err)
-		}
+	bower, err := s.bowerRepo.GetByID(ctx, bowerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bower: %w", err)
+	}
 
-		if bower.UserID != userID && !bower.IsPublic {
-			return nil, nil, errors.New("access denied: bower is private")
-		}
+	if !bower.CanReadOutputFeed(token) {
+		return nil, errors.New("access denied: invalid feed token")
+	}
 
-		feeds, err := s.feedRepo.GetByBowerID(ctx, *req.BowerID)
-		if err != nil {
-			return nil, nil, fmt.Errorf("failed to get bower feeds: %w", err)
-		}
+	feeds, err := s.feedRepo.GetByBowerID(ctx, bowerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bower feeds: %w", err)
+	}
 
-		feedIDs = make([]string, len(feeds))
-		for i, feed := range feeds {
-			feedIDs[i] = feed.FeedID
-		}
-	} else {
-		// Get articles from all user's bowers
-		bowers, _, err := s.bowerRepo.GetByUserID(ctx, userID, 100, nil)
-		if err != nil {
-			return nil, nil, fmt.Errorf("failed to get user bowers: %w", err)
-		}
+	output := &BowerOutputFeed{
+		Bower:    bower,
+		Feeds:    make(map[string]*model.Feed, len(feeds)),
+		Articles: make([]*model.Article, 0),
+	}
+	if len(feeds) == 0 {
+		return output, nil
+	}
 
-		for _, bower := range bowers {
-			feeds, err := s.feedRepo.GetByBowerID(ctx, bower.BowerID)
-			if err != nil {
-				continue // Skip this bower if we can't get feeds
-			}
+	feedIDs := make([]string, 0, len(feeds))
+	for _, feed := range feeds {
+		output.Feeds[feed.FeedID] = feed
+		feedIDs = append(feedIDs, feed.FeedID)
+	}
 
-			for _, feed := range feeds {
-				feedIDs = append(feedIDs, feed.FeedID)
+	articles, _, err := s.articleRepo.GetByFeedIDs(ctx, feedIDs, outputFeedMaxArticles, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get articles: %w", err)
+	}
+	if filter := bowerFilter(bower); filter != nil {
+		kept := articles[:0]
+		for _, article := range articles {
+			if filter.Allows(article) {
+				kept = append(kept, article)
 			}
 		}
+		articles = kept
+	}
+
+	sort.Slice(articles, func(i, j int) bool {
+		return articles[i].PublishedAt > articles[j].PublishedAt
+	})
+	if len(articles) > outputFeedMaxArticles {
+		articles = articles[:outputFeedMaxArticles]
 	}
+	output.Articles = articles
 
+	return output, nil
+}
+
+// getAllArticles retrieves all articles for a user
+func (s *articleService) getAllArticles(ctx context.Context, userID string, req *GetArticlesRequest) ([]*model.Article, map[string]types.AttributeValue, error) {
+	access, err := s.accessibleFeeds(ctx, userID, req.BowerID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	feedIDs := access.FeedIDs
 	if len(feedIDs) == 0 {
 		log.Printf("⚠️ No feed IDs found for user %s", userID)
 		return []*model.Article{}, nil, nil
@@ -397,6 +534,9 @@ func (s *articleService) getAllArticles(ctx context.Context, userID string, req
 
 	log.Printf("📥 Retrieved %d articles from repository", len(articles))
 
+	// Pages can come out shorter than the limit when bower filter rules exclude articles
+	articles = access.filter(articles)
+
 	// Sort articles by published date (DynamoDB scan doesn't guarantee order)
 	sort.Slice(articles, func(i, j int) bool {
 		if req.SortOrder == "asc" {
@@ -428,17 +568,7 @@ func (s *articleService) getLikedArticles(ctx context.Context, userID string, re
 		}
 
 		// Check if user still has access to this article
-		feed, err := s.feedRepo.GetByID(ctx, article.FeedID)
-		if err != nil {
-			continue
-		}
-
-		bower, err := s.bowerRepo.GetByID(ctx, feed.BowerID)
-		if err != nil {
-			continue
-		}
-
-		if bower.UserID != userID && !bower.IsPublic {
+		if _, _, err := findArticleSubscription(ctx, s.feedRepo, s.bowerRepo, article, userID); err != nil {
 			continue // Skip if no access
 		}
 
@@ -463,7 +593,188 @@ func (s *articleService) getLikedArticles(ctx context.Context, userID string, re
 	return articles, nil
 }
 
-// enrichArticles enriches articles with like status and bower information
+// getImportantArticles scores the recent articles of each feed and returns the top ones, each with
+// an explanation of its score. Pages continue after the rank of the last article of the page.
+func (s *articleService) getImportantArticles(ctx context.Context, userID string, req *GetArticlesRequest) ([]*model.Article, map[string]types.AttributeValue, error) {
+	after, err := repository.ParseSearchKey(req.LastKey)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	access, err := s.accessibleFeeds(ctx, userID, req.BowerID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	if len(access.FeedIDs) == 0 {
+		return []*model.Article{}, nil, nil
+	}
+
+	articles, err := s.newestArticlesPerFeed(ctx, access.FeedIDs, importantCandidatesPerFeed)
+	if err != nil {
+		return nil, nil, err
+	}
+	articles = access.filter(articles)
+
+	scores, err := s.scorer.ScoreArticles(ctx, &ScoreArticlesRequest{
+		UserID:   userID,
+		Articles: articles,
+		Keywords: access.Keywords,
+		Now:      time.Now(),
+	})
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to score articles: %w", err)
+	}
+
+	for i, article := range articles {
+		article.Score = scores[i]
+	}
+
+	sort.Slice(articles, func(i, j int) bool {
+		return importantRank(articles[i]).RanksBefore(importantRank(articles[j]))
+	})
+
+	if after != nil {
+		start := sort.Search(len(articles), func(i int) bool {
+			return after.RanksBefore(importantRank(articles[i]))
+		})
+		articles = articles[start:]
+	}
+
+	var nextKey map[string]types.AttributeValue
+	if len(articles) > int(req.Limit) {
+		articles = articles[:req.Limit]
+		nextKey = importantRank(articles[len(articles)-1]).Key()
+	}
+
+	log.Printf("⭐ Scored %d articles for the important tab, returning %d", len(scores), len(articles))
+
+	return articles, nextKey, nil
+}
+
+// importantRank returns where a scored article ranks on the important tab, which is ordered like
+// search hits
+func importantRank(article *model.Article) repository.SearchHit {
+	return repository.SearchHit{ArticleID: article.ArticleID, Score: article.Score.Total, PublishedAt: article.PublishedAt}
+}
+
+// newestArticlesPerFeed returns up to perFeed of the newest articles of each feed. Articles merged
+// from several feeds are returned once.
+func (s *articleService) newestArticlesPerFeed(ctx context.Context, feedIDs []string, perFeed int32) ([]*model.Article, error) {
+	articles := make([]*model.Article, 0)
+	seen := make(map[string]bool)
+	for _, feedID := range feedIDs {
+		feedArticles, _, err := s.articleRepo.GetByFeedIDs(ctx, []string{feedID}, perFeed, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get articles of feed %s: %w", feedID, err)
+		}
+		for _, article := range feedArticles {
+			if !seen[article.ArticleID] {
+				seen[article.ArticleID] = true
+				articles = append(articles, article)
+			}
+		}
+	}
+	return articles, nil
+}
+
+// feedAccess describes the feeds a user reads through a bower, or through all of their bowers
+type feedAccess struct {
+	FeedIDs  []string
+	Keywords map[string][]string // Keywords of the user's bowers subscribed to each feed
+
+	filters map[string]articleFilters // Filters of the user's bowers subscribed to each feed
+}
+
+// allows reports whether any bower the article is listed in through one of its feeds lets it through
+func (a *feedAccess) allows(article *model.Article) bool {
+	for _, feedID := range article.FeedIDs() {
+		if filters, ok := a.filters[feedID]; ok && filters.allows(article) {
+			return true
+		}
+	}
+	return false
+}
+
+// filter drops the articles that the filter rules of the user's bowers exclude
+func (a *feedAccess) filter(articles []*model.Article) []*model.Article {
+	kept := articles[:0]
+	for _, article := range articles {
+		if a.allows(article) {
+			kept = append(kept, article)
+		}
+	}
+	return kept
+}
+
+// accessibleFeeds returns the feeds of a bower the user can read, or of all of the user's bowers
+// if bowerID is nil, with the keywords and filter rules of the bowers subscribed to each feed
+func (s *articleService) accessibleFeeds(ctx context.Context, userID string, bowerID *string) (*feedAccess, error) {
+	var bowers []*model.Bower
+	if bowerID != nil {
+		bower, err := s.bowerRepo.GetByID(ctx, *bowerID)
+		if err != nil {
+			return nil, fmt.Errorf("bower not found: %w", err)
+		}
+
+		if bower.UserID != userID && !bower.IsPublic {
+			return nil, errors.New("access denied: bower is private")
+		}
+		bowers = []*model.Bower{bower}
+	} else {
+		userBowers, _, err := s.bowerRepo.GetByUserID(ctx, userID, 100, nil)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user bowers: %w", err)
+		}
+		bowers = userBowers
+	}
+
+	access := &feedAccess{
+		Keywords: make(map[string][]string),
+		filters:  make(map[string]articleFilters),
+	}
+	for _, bower := range bowers {
+		feeds, err := s.feedRepo.GetByBowerID(ctx, bower.BowerID)
+		if err != nil {
+			if bowerID != nil {
+				return nil, fmt.Errorf("failed to get bower feeds: %w", err)
+			}
+			continue // Skip this bower if we can't get feeds
+		}
+
+		// A feed can be subscribed to from several of the user's bowers
+		filter := bowerFilter(bower)
+		for _, feed := range feeds {
+			if _, seen := access.Keywords[feed.FeedID]; !seen {
+				access.FeedIDs = append(access.FeedIDs, feed.FeedID)
+				access.Keywords[feed.FeedID] = []string{}
+			}
+			access.Keywords[feed.FeedID] = appendUnique(access.Keywords[feed.FeedID], bower.Keywords...)
+			access.filters[feed.FeedID] = append(access.filters[feed.FeedID], filter)
+		}
+	}
+
+	return access, nil
+}
+
+// appendUnique appends the values not already in list
+func appendUnique(list []string, values ...string) []string {
+	for _, value := range values {
+		found := false
+		for _, existing := range list {
+			if existing == value {
+				found = true
+				break
+			}
+		}
+		if !found {
+			list = append(list, value)
+		}
+	}
+	return list
+}
+
+// enrichArticles enriches articles with like status, bower information and playback positions
 func (s *articleService) enrichArticles(ctx context.Context, userID string, articles []*model.Article) ([]*model.Article, error) {
 	if len(articles) == 0 {
 		return articles, nil
@@ -480,24 +791,41 @@ func (s *articleService) enrichArticles(ctx context.Context, userID string, arti
 		likedMap[liked.ArticleID] = true
 	}
 
+	// Get read state for these articles
+	articleIDs := make([]string, len(articles))
+	for i, article := range articles {
+		articleIDs[i] = article.ArticleID
+	}
+
+	readMap, err := s.readStateRepo.GetReadArticleIDs(ctx, userID, articleIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get read states: %w", err)
+	}
+
 	// Enrich each article
+	bowersByFeed := make(map[string]*model.Bower)
 	for _, article := range articles {
-		// Set like status
+		// Set like and read status
 		article.Liked = likedMap[article.ArticleID]
+		article.Read = readMap[article.ArticleID]
 
 		// Set bower name
-		feed, err := s.feedRepo.GetByID(ctx, article.FeedID)
-		if err != nil {
-			continue
+		feedsKey := strings.Join(article.FeedIDs(), ",")
+		bower, looked := bowersByFeed[feedsKey]
+		if !looked {
+			_, bower, _ = findArticleSubscription(ctx, s.feedRepo, s.bowerRepo, article, userID)
+			bowersByFeed[feedsKey] = bower
 		}
-
-		bower, err := s.bowerRepo.GetByID(ctx, feed.BowerID)
-		if err != nil {
+		if bower == nil {
 			continue
 		}
 
 		article.Bower = bower.Name
 	}
 
+	if err := s.attachPlaybackPositions(ctx, userID, articles); err != nil {
+		return nil, err
+	}
+
 	return articles, nil
 }
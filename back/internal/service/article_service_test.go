@@ -0,0 +1,273 @@
+package service
+
+import (
+	"context"
+	"reflect"
+	"sort"
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"feed-bower-api/internal/model"
+)
+
+func TestArticleService_ReadState(t *testing.T) {
+	ctx := context.Background()
+	repos := NewMockRepositories()
+
+	bower := model.NewBower("user-1", "Tech", []string{"go"}, nil, "#14b8a6", false)
+	repos.BowerRepo.Create(ctx, bower)
+	feed := model.NewFeed(bower.BowerID, "https://example.com/feed.xml", "Example", "", "tech")
+	repos.FeedRepo.Create(ctx, feed)
+
+	for _, id := range []string{"article-1", "article-2"} {
+		article := model.NewArticle(feed.FeedID, id, "content", "https://example.com/"+id, time.Now())
+		article.ArticleID = id
+		repos.ArticleRepo.Create(ctx, article)
+	}
+
+	articleService := NewArticleService(repos.ArticleRepo, repos.FeedRepo, repos.BowerRepo, repos.ChickRepo, repos.ReadStateRepo, nil)
+
+	if err := articleService.MarkArticleAsRead(ctx, "user-1", "article-1"); err != nil {
+		t.Fatalf("MarkArticleAsRead() unexpected error: %v", err)
+	}
+
+	resp, err := articleService.GetArticles(ctx, "user-1", &GetArticlesRequest{BowerID: &bower.BowerID})
+	if err != nil {
+		t.Fatalf("GetArticles() unexpected error: %v", err)
+	}
+	if len(resp.Articles) != 2 {
+		t.Fatalf("Expected 2 articles, got %d", len(resp.Articles))
+	}
+	for _, article := range resp.Articles {
+		if expected := article.ArticleID == "article-1"; article.Read != expected {
+			t.Errorf("Article %s: expected read=%v, got %v", article.ArticleID, expected, article.Read)
+		}
+	}
+
+	if err := articleService.MarkArticleAsUnread(ctx, "user-1", "article-1"); err != nil {
+		t.Fatalf("MarkArticleAsUnread() unexpected error: %v", err)
+	}
+	article, err := articleService.GetArticleByID(ctx, "article-1", "user-1")
+	if err != nil {
+		t.Fatalf("GetArticleByID() unexpected error: %v", err)
+	}
+	if article.Read {
+		t.Error("Expected article to be unread after MarkArticleAsUnread")
+	}
+
+	// Read state is per user
+	if err := articleService.MarkArticleAsRead(ctx, "user-2", "article-1"); err == nil {
+		t.Error("Expected access check to fail for a private bower of another user")
+	}
+}
+
+func TestArticleService_SearchArticles(t *testing.T) {
+	ctx := context.Background()
+	repos := NewMockRepositories()
+
+	bower := model.NewBower("user-1", "Tech", []string{"go"}, nil, "#14b8a6", false)
+	repos.BowerRepo.Create(ctx, bower)
+	feed := model.NewFeed(bower.BowerID, "https://example.com/feed.xml", "Example", "", "tech")
+	repos.FeedRepo.Create(ctx, feed)
+
+	otherBower := model.NewBower("user-2", "Private", []string{"go"}, nil, "#14b8a6", false)
+	repos.BowerRepo.Create(ctx, otherBower)
+	otherFeed := model.NewFeed(otherBower.BowerID, "https://example.org/feed.xml", "Other", "", "tech")
+	repos.FeedRepo.Create(ctx, otherFeed)
+
+	articles := []struct {
+		id, feedID, title, content string
+	}{
+		{"body-match", feed.FeedID, "Weekly notes", "A few words about Golang generics."},
+		{"title-match", feed.FeedID, "Golang generics explained", "Type parameters in depth."},
+		{"japanese", feed.FeedID, "機械学習の基礎", "ニューラルネットワークを解説します。"},
+		{"other-user", otherFeed.FeedID, "Golang generics", "Not visible to user-1."},
+	}
+	for _, a := range articles {
+		article := model.NewArticle(a.feedID, a.title, a.content, "https://example.com/"+a.id, time.Now())
+		article.ArticleID = a.id
+		repos.ArticleRepo.Create(ctx, article)
+	}
+
+	articleService := NewArticleService(repos.ArticleRepo, repos.FeedRepo, repos.BowerRepo, repos.ChickRepo, repos.ReadStateRepo, nil)
+
+	resp, err := articleService.SearchArticles(ctx, "user-1", &SearchArticlesRequest{Query: "GOLANG generics"})
+	if err != nil {
+		t.Fatalf("SearchArticles() unexpected error: %v", err)
+	}
+	results := resp.Articles
+	if len(results) != 2 || results[0].ArticleID != "title-match" || results[1].ArticleID != "body-match" {
+		t.Errorf("Expected title match ranked before body match and no other user's articles, got %v", articleIDs(results))
+	}
+	if results[0].Bower != "Tech" {
+		t.Errorf("Expected results to be enriched with the bower name, got %q", results[0].Bower)
+	}
+
+	resp, err = articleService.SearchArticles(ctx, "user-1", &SearchArticlesRequest{Query: "機械学習", BowerID: &bower.BowerID})
+	if err != nil {
+		t.Fatalf("SearchArticles() unexpected error: %v", err)
+	}
+	results = resp.Articles
+	if len(results) != 1 || results[0].ArticleID != "japanese" {
+		t.Errorf("Expected the Japanese article, got %v", articleIDs(results))
+	}
+
+	if _, err := articleService.SearchArticles(ctx, "user-1", &SearchArticlesRequest{Query: "golang", BowerID: &otherBower.BowerID}); err == nil {
+		t.Error("Expected access check to fail for a private bower of another user")
+	}
+}
+
+func TestArticleService_SearchArticles_Filters(t *testing.T) {
+	ctx := context.Background()
+	repos := NewMockRepositories()
+
+	bower := model.NewBower("user-1", "Tech", []string{"go"}, nil, "#14b8a6", false)
+	repos.BowerRepo.Create(ctx, bower)
+	goFeed := model.NewFeed(bower.BowerID, "https://example.com/go.xml", "Go", "", "tech")
+	repos.FeedRepo.Create(ctx, goFeed)
+	rustFeed := model.NewFeed(bower.BowerID, "https://example.com/rust.xml", "Rust", "", "tech")
+	repos.FeedRepo.Create(ctx, rustFeed)
+
+	day := func(date string) time.Time {
+		parsed, _ := time.Parse("2006-01-02", date)
+		return parsed
+	}
+	articles := []struct {
+		id, feedID, title, content string
+		published                  time.Time
+		image                      bool
+	}{
+		{"go-old", goFeed.FeedID, "Golang tips", "Error handling basics.", day("2025-12-20"), false},
+		{"go-new", goFeed.FeedID, "Golang release notes", "Generics improvements.", day("2026-01-10"), true},
+		{"go-beginner", goFeed.FeedID, "Golang for beginners", "Error handling for beginners.", day("2026-01-12"), false},
+		{"rust-new", rustFeed.FeedID, "Rust release notes", "Error handling with golang comparisons.", day("2026-01-15"), true},
+	}
+	for _, a := range articles {
+		article := model.NewArticle(a.feedID, a.title, a.content, "https://example.com/"+a.id, a.published)
+		article.ArticleID = a.id
+		if a.image {
+			article.SetImageURL("https://example.com/" + a.id + ".png")
+		}
+		repos.ArticleRepo.Create(ctx, article)
+	}
+	repos.ChickRepo.AddLikedArticle(ctx, model.NewLikedArticle("user-1", "go-new"))
+	repos.ReadStateRepo.MarkRead(ctx, model.NewReadState("user-1", "go-beginner"))
+
+	articleService := NewArticleService(repos.ArticleRepo, repos.FeedRepo, repos.BowerRepo, repos.ChickRepo, repos.ReadStateRepo, nil)
+
+	tests := []struct {
+		name  string
+		query string
+		want  []string
+	}{
+		{"title filter", "title:golang", []string{"go-beginner", "go-new", "go-old"}},
+		{"title filter ranks free text", "title:release error", []string{"rust-new"}},
+		{"feed filter", "feed:" + rustFeed.FeedID + " release", []string{"rust-new"}},
+		{"excluded feed", "-feed:" + goFeed.FeedID + " error", []string{"rust-new"}},
+		{"date range", "after:2026-01-01 before:2026-01-13", []string{"go-beginner", "go-new"}},
+		{"liked", "liked:true", []string{"go-new"}},
+		{"unread with image", "read:false has:image", []string{"rust-new", "go-new"}},
+		{"without image", "-has:image title:golang", []string{"go-beginner", "go-old"}},
+		{"excluded keyword without free text", "title:golang -beginners", []string{"go-new", "go-old"}},
+		{"excluded keyword with free text", "error -beginners", []string{"go-old", "rust-new"}},
+		{"excluded title", "handling -title:rust", []string{"go-beginner", "go-old"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp, err := articleService.SearchArticles(ctx, "user-1", &SearchArticlesRequest{Query: tt.query})
+			if err != nil {
+				t.Fatalf("SearchArticles(%q) unexpected error: %v", tt.query, err)
+			}
+
+			got := make([]string, len(resp.Articles))
+			for i, article := range resp.Articles {
+				got[i] = article.ArticleID
+			}
+			if !sameElements(got, tt.want) {
+				t.Errorf("SearchArticles(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+
+	// Queries without free text are listed newest first and paginate with LastKey
+	var pages [][]string
+	req := &SearchArticlesRequest{Query: "title:golang", Limit: 2}
+	for {
+		resp, err := articleService.SearchArticles(ctx, "user-1", req)
+		if err != nil {
+			t.Fatalf("SearchArticles() unexpected error: %v", err)
+		}
+		page := make([]string, len(resp.Articles))
+		for i, article := range resp.Articles {
+			page[i] = article.ArticleID
+		}
+		pages = append(pages, page)
+
+		if !resp.HasMore {
+			break
+		}
+		req.LastKey = resp.LastKey
+	}
+	if len(pages) != 2 || !reflect.DeepEqual(pages[0], []string{"go-beginner", "go-new"}) || !reflect.DeepEqual(pages[1], []string{"go-old"}) {
+		t.Errorf("Expected two pages newest first, got %v", pages)
+	}
+
+	// Ranked results continue after the last hit of the page, skipping articles filters reject
+	resp, err := articleService.SearchArticles(ctx, "user-1", &SearchArticlesRequest{Query: "error -title:rust"})
+	if err != nil {
+		t.Fatalf("SearchArticles() unexpected error: %v", err)
+	}
+	want := articleIDs(resp.Articles)
+	var ranked []string
+	req = &SearchArticlesRequest{Query: "error -title:rust", Limit: 1}
+	for {
+		resp, err := articleService.SearchArticles(ctx, "user-1", req)
+		if err != nil {
+			t.Fatalf("SearchArticles() unexpected error: %v", err)
+		}
+		ranked = append(ranked, articleIDs(resp.Articles)...)
+		if !resp.HasMore {
+			break
+		}
+		req.LastKey = resp.LastKey
+	}
+	if len(want) != 2 || !reflect.DeepEqual(ranked, want) {
+		t.Errorf("Expected pages of one to list %v, got %v", want, ranked)
+	}
+
+	req = &SearchArticlesRequest{Query: "error", LastKey: map[string]types.AttributeValue{"offset": &types.AttributeValueMemberN{Value: "1"}}}
+	if _, err := articleService.SearchArticles(ctx, "user-1", req); err == nil || !strings.HasPrefix(err.Error(), "invalid search query") {
+		t.Errorf("Expected a malformed cursor to be rejected, got %v", err)
+	}
+
+	for _, query := range []string{"after:yesterday", "liked:maybe", "has:video", "title:", "-liked:true"} {
+		if _, err := articleService.SearchArticles(ctx, "user-1", &SearchArticlesRequest{Query: query}); err == nil ||
+			!strings.HasPrefix(err.Error(), "invalid search query") {
+			t.Errorf("SearchArticles(%q) expected invalid query error, got %v", query, err)
+		}
+	}
+}
+
+func articleIDs(articles []model.Article) []string {
+	ids := make([]string, len(articles))
+	for i, article := range articles {
+		ids[i] = article.ArticleID
+	}
+	return ids
+}
+
+// sameElements reports whether two slices hold the same strings in any order
+func sameElements(a, b []string) bool {
+	if len(a) != len(b) {
+		return false
+	}
+	sortedA := append([]string(nil), a...)
+	sortedB := append([]string(nil), b...)
+	sort.Strings(sortedA)
+	sort.Strings(sortedB)
+	return reflect.DeepEqual(sortedA, sortedB)
+}
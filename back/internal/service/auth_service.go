@@ -51,6 +51,10 @@ func NewAuthService(userRepo repository.UserRepository, jwtSecret string) AuthSe
 	}
 }
 
+// lastActiveInterval is how often a user's activity is recorded. Requests within it of the last
+// recorded activity don't write the user again.
+const lastActiveInterval = time.Hour
+
 // JWTClaims represents the JWT token claims
 type JWTClaims struct {
 	UserID  string `json:"user_id"`
@@ -72,7 +76,7 @@ func (s *authService) CreateGuestUser(ctx context.Context, language string) (*mo
 		return nil, "", fmt.Errorf("failed to generate guest ID: %w", err)
 	}
 
-	guestEmail := fmt.Sprintf("guest_%s@feed-bower.local", guestID)
+	guestEmail := model.GuestEmailPrefix + guestID + model.GuestEmailSuffix
 	guestPassword := fmt.Sprintf("guest_%s", guestID)
 	guestName := fmt.Sprintf("Guest_%s", guestID[:8])
 
@@ -239,9 +243,26 @@ func (s *authService) ValidateToken(ctx context.Context, tokenString string) (*m
 		return nil, fmt.Errorf("user not found: %w", err)
 	}
 
+	s.recordActivity(ctx, user)
+
 	return user, nil
 }
 
+// recordActivity stores that a user is active, at most once per lastActiveInterval. Guests are
+// expired by their last activity. A failure is logged and does not fail the request.
+func (s *authService) recordActivity(ctx context.Context, user *model.User) {
+	now := time.Now().Unix()
+	if now-user.LastActivity() < int64(lastActiveInterval/time.Second) {
+		return
+	}
+
+	if err := s.userRepo.SetLastActive(ctx, user.UserID, now); err != nil {
+		fmt.Printf("⚠️  Failed to record activity of user %s: %v\n", user.UserID, err)
+		return
+	}
+	user.LastActiveAt = now
+}
+
 // RefreshToken generates a new token from an existing valid token
 func (s *authService) RefreshToken(ctx context.Context, tokenString string) (string, error) {
 	user, err := s.ValidateToken(ctx, tokenString)
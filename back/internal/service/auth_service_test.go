@@ -3,6 +3,7 @@ package service
 import (
 	"context"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 
@@ -11,7 +12,8 @@ import (
 
 // MockUserRepository is a mock implementation of UserRepository for testing
 type MockUserRepository struct {
-	users map[string]*model.User
+	users          map[string]*model.User
+	activityWrites int
 }
 
 func NewMockUserRepository() *MockUserRepository {
@@ -49,6 +51,14 @@ func (m *MockUserRepository) Update(ctx context.Context, user *model.User) error
 	return nil
 }
 
+func (m *MockUserRepository) SetLastActive(ctx context.Context, userID string, lastActiveAt int64) error {
+	if user, exists := m.users[userID]; exists {
+		user.LastActiveAt = lastActiveAt
+		m.activityWrites++
+	}
+	return nil
+}
+
 func (m *MockUserRepository) Delete(ctx context.Context, userID string) error {
 	delete(m.users, userID)
 	return nil
@@ -159,3 +169,32 @@ func TestAuthService_ValidateToken(t *testing.T) {
 		t.Errorf("Expected user ID to be %s, got %s", user.UserID, validatedUser.UserID)
 	}
 }
+
+func TestAuthService_ValidateToken_RecordsActivity(t *testing.T) {
+	mockRepo := NewMockUserRepository()
+	authService := NewAuthService(mockRepo, "test-secret")
+	ctx := context.Background()
+
+	user, token, err := authService.CreateGuestUser(ctx, "ja")
+	if err != nil {
+		t.Fatalf("Failed to create guest user: %v", err)
+	}
+
+	// A recently active user is not written again
+	if _, err := authService.ValidateToken(ctx, token); err != nil {
+		t.Fatalf("Expected no error validating token, got %v", err)
+	}
+	if mockRepo.activityWrites != 0 {
+		t.Errorf("Expected no activity write within the interval, got %d", mockRepo.activityWrites)
+	}
+
+	old := time.Now().Add(-2 * time.Hour).Unix()
+	user.CreatedAt, user.UpdatedAt, user.LastActiveAt = old, old, old
+
+	if _, err := authService.RefreshToken(ctx, token); err != nil {
+		t.Fatalf("Expected no error refreshing token, got %v", err)
+	}
+	if mockRepo.activityWrites != 1 || user.LastActiveAt <= old {
+		t.Errorf("Expected the refresh to record activity, got %d writes at %d", mockRepo.activityWrites, user.LastActiveAt)
+	}
+}
@@ -0,0 +1,297 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"feed-bower-api/internal/model"
+	"feed-bower-api/internal/repository"
+	"feed-bower-api/pkg/search"
+)
+
+// Filter rule limits
+const (
+	maxFilterRules         = 50  // Maximum number of entries in each list of a bower's filter rules
+	maxFilterPatternLength = 200 // Maximum length of a pattern, author or domain
+)
+
+// articleFilter is a bower's compiled filter rules. A nil filter allows every article.
+type articleFilter struct {
+	include      []filterPattern
+	exclude      []filterPattern
+	mutedAuthors map[string]bool
+	mutedDomains []string
+}
+
+// filterPattern matches an article's title and content, either as a phrase of search terms or
+// as a regular expression
+type filterPattern struct {
+	phrase []string
+	regex  *regexp.Regexp
+}
+
+// compileFilterRules validates and compiles a bower's filter rules. Empty rules compile to a nil filter.
+func compileFilterRules(rules *model.FilterRules) (*articleFilter, error) {
+	if rules.IsEmpty() {
+		return nil, nil
+	}
+
+	filter := &articleFilter{mutedAuthors: make(map[string]bool)}
+
+	var err error
+	if filter.include, err = compileFilterPatterns("include", rules.Include); err != nil {
+		return nil, err
+	}
+	if filter.exclude, err = compileFilterPatterns("exclude", rules.Exclude); err != nil {
+		return nil, err
+	}
+
+	if err := checkFilterList("muted_authors", rules.MutedAuthors); err != nil {
+		return nil, err
+	}
+	for _, author := range rules.MutedAuthors {
+		filter.mutedAuthors[strings.ToLower(strings.TrimSpace(author))] = true
+	}
+
+	if err := checkFilterList("muted_domains", rules.MutedDomains); err != nil {
+		return nil, err
+	}
+	for _, domain := range rules.MutedDomains {
+		host := normalizeFilterDomain(domain)
+		if host == "" || strings.ContainsAny(host, "/ ") {
+			return nil, fmt.Errorf("invalid filter rules: muted_domains: %q is not a domain", domain)
+		}
+		filter.mutedDomains = append(filter.mutedDomains, host)
+	}
+
+	return filter, nil
+}
+
+// compileFilterPatterns compiles include or exclude patterns. Patterns between slashes are
+// case-insensitive regular expressions, others are phrases.
+func compileFilterPatterns(list string, patterns []string) ([]filterPattern, error) {
+	if err := checkFilterList(list, patterns); err != nil {
+		return nil, err
+	}
+
+	compiled := make([]filterPattern, 0, len(patterns))
+	for _, pattern := range patterns {
+		pattern = strings.TrimSpace(pattern)
+		if len(pattern) > 2 && strings.HasPrefix(pattern, "/") && strings.HasSuffix(pattern, "/") {
+			regex, err := regexp.Compile("(?i)" + pattern[1:len(pattern)-1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid filter rules: %s: invalid regular expression %s: %v", list, pattern, err)
+			}
+			compiled = append(compiled, filterPattern{regex: regex})
+			continue
+		}
+
+		phrase := search.Terms(pattern)
+		if len(phrase) == 0 {
+			return nil, fmt.Errorf("invalid filter rules: %s: %q has no words", list, pattern)
+		}
+		compiled = append(compiled, filterPattern{phrase: phrase})
+	}
+
+	return compiled, nil
+}
+
+// checkFilterList checks the number and length of entries of a filter rule list
+func checkFilterList(list string, values []string) error {
+	if len(values) > maxFilterRules {
+		return fmt.Errorf("invalid filter rules: %s: at most %d entries allowed", list, maxFilterRules)
+	}
+	for _, value := range values {
+		if strings.TrimSpace(value) == "" {
+			return fmt.Errorf("invalid filter rules: %s: entries cannot be empty", list)
+		}
+		if len(value) > maxFilterPatternLength {
+			return fmt.Errorf("invalid filter rules: %s: entries must be at most %d characters", list, maxFilterPatternLength)
+		}
+	}
+	return nil
+}
+
+// normalizeFilterDomain returns the lowercase host of a muted domain, which may be written as a URL
+func normalizeFilterDomain(domain string) string {
+	domain = strings.ToLower(strings.TrimSpace(domain))
+	if strings.Contains(domain, "://") {
+		if u, err := url.Parse(domain); err == nil {
+			domain = u.Hostname()
+		}
+	}
+	domain = strings.TrimPrefix(domain, "*.")
+	return strings.TrimPrefix(domain, "www.")
+}
+
+// Allows reports whether an article passes the filter: it is not from a muted author or domain,
+// matches one of the include patterns if there are any, and matches none of the exclude patterns
+func (f *articleFilter) Allows(article *model.Article) bool {
+	if f == nil {
+		return true
+	}
+
+	if f.mutedAuthor(article.Author) || f.mutedDomain(article.URL) {
+		return false
+	}
+
+	text := newFilterText(article)
+	if len(f.include) > 0 && !text.matchesAny(f.include) {
+		return false
+	}
+	return !text.matchesAny(f.exclude)
+}
+
+// mutedAuthor reports whether any of an article's comma separated authors is muted
+func (f *articleFilter) mutedAuthor(authors string) bool {
+	if len(f.mutedAuthors) == 0 || authors == "" {
+		return false
+	}
+	if f.mutedAuthors[strings.ToLower(strings.TrimSpace(authors))] {
+		return true
+	}
+	for _, author := range strings.Split(authors, ",") {
+		if f.mutedAuthors[strings.ToLower(strings.TrimSpace(author))] {
+			return true
+		}
+	}
+	return false
+}
+
+// mutedDomain reports whether an article URL's host is a muted domain or one of its subdomains
+func (f *articleFilter) mutedDomain(articleURL string) bool {
+	if len(f.mutedDomains) == 0 {
+		return false
+	}
+	u, err := url.Parse(articleURL)
+	if err != nil {
+		return false
+	}
+	host := strings.ToLower(u.Hostname())
+	for _, domain := range f.mutedDomains {
+		if host == domain || strings.HasSuffix(host, "."+domain) {
+			return true
+		}
+	}
+	return false
+}
+
+// filterText is the text of an article that patterns are matched against, tokenized on first use
+type filterText struct {
+	article *model.Article
+	raw     string
+	title   []string
+	content []string
+}
+
+func newFilterText(article *model.Article) *filterText {
+	return &filterText{article: article}
+}
+
+// matchesAny reports whether any of the patterns matches the article's title or content
+func (t *filterText) matchesAny(patterns []filterPattern) bool {
+	for _, pattern := range patterns {
+		if pattern.regex != nil {
+			if t.raw == "" {
+				t.raw = t.article.Title + "\n" + t.article.Content
+			}
+			if pattern.regex.MatchString(t.raw) {
+				return true
+			}
+			continue
+		}
+
+		if t.title == nil {
+			t.title = search.Terms(t.article.Title)
+			t.content = search.Terms(t.article.Content)
+		}
+		if containsPhrase(t.title, pattern.phrase) || containsPhrase(t.content, pattern.phrase) {
+			return true
+		}
+	}
+	return false
+}
+
+// bowerFilter compiles a stored bower's filter rules. Rules are validated when saved, so rules
+// that no longer compile are logged and ignored rather than hiding every article.
+func bowerFilter(bower *model.Bower) *articleFilter {
+	filter, err := compileFilterRules(bower.FilterRules)
+	if err != nil {
+		log.Printf("⚠️ Ignoring filter rules of bower %s: %v", bower.BowerID, err)
+		return nil
+	}
+	return filter
+}
+
+// articleFilters are the filters of the bowers an article is listed in; the article is shown
+// if any of them allows it
+type articleFilters []*articleFilter
+
+// allows reports whether any of the filters allows the article. No filters allow every article.
+func (f articleFilters) allows(article *model.Article) bool {
+	if len(f) == 0 {
+		return true
+	}
+	for _, filter := range f {
+		if filter.Allows(article) {
+			return true
+		}
+	}
+	return false
+}
+
+// ingestFilters returns the filters of the bowers subscribed to a feed if every one of them
+// filters at ingest, or nil if fetched articles are all stored
+func ingestFilters(ctx context.Context, feedRepo repository.FeedRepository, bowerRepo repository.BowerRepository, feedID string) (articleFilters, error) {
+	subscriptions, err := feedRepo.GetSubscriptionsByFeedID(ctx, feedID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed subscriptions: %w", err)
+	}
+
+	var filters articleFilters
+	for _, subscription := range subscriptions {
+		bower, err := bowerRepo.GetByID(ctx, subscription.BowerID)
+		if err != nil || bower == nil {
+			continue // Orphaned subscription
+		}
+		if bower.FilterRules == nil || !bower.FilterRules.ApplyAtIngest {
+			return nil, nil
+		}
+		filter := bowerFilter(bower)
+		if filter == nil {
+			return nil, nil
+		}
+		filters = append(filters, filter)
+	}
+
+	return filters, nil
+}
+
+// filterIngestedArticles drops fetched articles that no bower subscribed to the feed would list,
+// if all of them filter at ingest. Articles are kept if the filters cannot be loaded.
+func filterIngestedArticles(ctx context.Context, feedRepo repository.FeedRepository, bowerRepo repository.BowerRepository, feedID string, articles []*model.Article) []*model.Article {
+	if bowerRepo == nil || len(articles) == 0 {
+		return articles
+	}
+
+	filters, err := ingestFilters(ctx, feedRepo, bowerRepo, feedID)
+	if err != nil {
+		log.Printf("⚠️ Storing all articles of feed %s, filter rules unavailable: %v", feedID, err)
+		return articles
+	}
+	if len(filters) == 0 {
+		return articles
+	}
+
+	kept := make([]*model.Article, 0, len(articles))
+	for _, article := range articles {
+		if filters.allows(article) {
+			kept = append(kept, article)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,172 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"feed-bower-api/internal/model"
+)
+
+func TestArticleFilter_Allows(t *testing.T) {
+	rules := &model.FilterRules{
+		Include:      []string{"machine learning", "/\\bGPT-\\d+\\b/"},
+		Exclude:      []string{"sponsored", "/^\\[PR\\]/"},
+		MutedAuthors: []string{"Spam Bot"},
+		MutedDomains: []string{"https://www.ads.example.com/", "tracker.net"},
+	}
+	filter, err := compileFilterRules(rules)
+	if err != nil {
+		t.Fatalf("compileFilterRules() unexpected error: %v", err)
+	}
+
+	tests := []struct {
+		name    string
+		title   string
+		content string
+		url     string
+		author  string
+		want    bool
+	}{
+		{"phrase in content", "Research news", "New Machine-Learning models for speech", "https://bbc.co.uk/1", "", true},
+		{"regex in title", "gpt-5 benchmarks", "", "https://bbc.co.uk/2", "", true},
+		{"no include match", "Football results", "The match ended 2-1", "https://bbc.co.uk/3", "", false},
+		{"words apart", "Machine shop learning day", "", "https://bbc.co.uk/4", "", false},
+		{"excluded phrase", "Machine learning courses", "Sponsored content", "https://bbc.co.uk/5", "", false},
+		{"excluded regex", "[PR] Machine learning platform", "", "https://bbc.co.uk/6", "", false},
+		{"muted author", "Machine learning digest", "", "https://bbc.co.uk/7", "Jane Doe, spam bot", false},
+		{"muted domain", "Machine learning offer", "", "https://ads.example.com/8", "", false},
+		{"muted subdomain", "Machine learning offer", "", "https://cdn.tracker.net/9", "", false},
+		{"similar domain", "Machine learning offer", "", "https://notracker.net/10", "", true},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			article := model.NewArticle("feed-1", tt.title, tt.content, tt.url, time.Now())
+			article.Author = tt.author
+			if got := filter.Allows(article); got != tt.want {
+				t.Errorf("Allows() = %v, want %v", got, tt.want)
+			}
+		})
+	}
+
+	var none *articleFilter
+	if !none.Allows(model.NewArticle("feed-1", "Anything", "", "https://example.com", time.Now())) {
+		t.Error("Expected a nil filter to allow every article")
+	}
+}
+
+func TestCompileFilterRules_Invalid(t *testing.T) {
+	tests := []struct {
+		name  string
+		rules *model.FilterRules
+	}{
+		{"bad regex", &model.FilterRules{Include: []string{"/(unclosed/"}}},
+		{"no words", &model.FilterRules{Exclude: []string{"!!!"}}},
+		{"empty author", &model.FilterRules{MutedAuthors: []string{" "}}},
+		{"bad domain", &model.FilterRules{MutedDomains: []string{"example.com/path"}}},
+		{"too long", &model.FilterRules{Include: []string{strings.Repeat("a", maxFilterPatternLength+1)}}},
+		{"too many", &model.FilterRules{Exclude: make([]string, maxFilterRules+1)}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, err := compileFilterRules(tt.rules)
+			if err == nil || !strings.HasPrefix(err.Error(), "invalid filter rules") {
+				t.Errorf("Expected an invalid filter rules error, got %v", err)
+			}
+		})
+	}
+
+	if filter, err := compileFilterRules(&model.FilterRules{ApplyAtIngest: true}); filter != nil || err != nil {
+		t.Errorf("Expected empty rules to compile to no filter, got %v, %v", filter, err)
+	}
+}
+
+func TestArticleService_BowerFilterRules(t *testing.T) {
+	ctx := context.Background()
+	repos := NewMockRepositories()
+	now := time.Now()
+
+	news := model.NewBower("user-1", "AI news", []string{"ai"}, nil, "#14b8a6", false)
+	news.FilterRules = &model.FilterRules{Include: []string{"artificial intelligence"}, MutedDomains: []string{"sport.example.com"}}
+	repos.BowerRepo.Create(ctx, news)
+	feed := model.NewFeed(news.BowerID, "https://news.example.com/feed.xml", "News", "", "news")
+	repos.FeedRepo.Create(ctx, feed)
+
+	for i, a := range []struct{ title, url string }{
+		{"Artificial intelligence rules proposed", "https://news.example.com/ai"},
+		{"Election results", "https://news.example.com/election"},
+		{"Artificial intelligence referees", "https://sport.example.com/var"},
+	} {
+		article := model.NewArticle(feed.FeedID, a.title, "", a.url, now.Add(-time.Duration(i)*time.Hour))
+		article.ArticleID = fmt.Sprintf("article-%d", i)
+		repos.ArticleRepo.Create(ctx, article)
+	}
+
+	articleService := NewArticleService(repos.ArticleRepo, repos.FeedRepo, repos.BowerRepo, repos.ChickRepo, repos.ReadStateRepo, nil)
+
+	resp, err := articleService.GetArticles(ctx, "user-1", &GetArticlesRequest{BowerID: &news.BowerID})
+	if err != nil {
+		t.Fatalf("GetArticles() unexpected error: %v", err)
+	}
+	if len(resp.Articles) != 1 || resp.Articles[0].Title != "Artificial intelligence rules proposed" {
+		t.Fatalf("Expected only the matching article, got %v", articleIDs(resp.Articles))
+	}
+
+	resp, err = articleService.SearchArticles(ctx, "user-1", &SearchArticlesRequest{Query: "intelligence"})
+	if err != nil {
+		t.Fatalf("SearchArticles() unexpected error: %v", err)
+	}
+	if len(resp.Articles) != 1 {
+		t.Errorf("Expected search to skip the muted domain, got %d articles", len(resp.Articles))
+	}
+
+	// Another bower of the user subscribed to the same feed without rules lists everything
+	all := model.NewBower("user-1", "Everything", []string{"news"}, nil, "#14b8a6", false)
+	repos.BowerRepo.Create(ctx, all)
+	if _, _, err := subscribeBower(ctx, repos.FeedRepo, all.BowerID, &model.Feed{URL: feed.URL}); err != nil {
+		t.Fatalf("subscribeBower() unexpected error: %v", err)
+	}
+	resp, err = articleService.GetArticles(ctx, "user-1", &GetArticlesRequest{})
+	if err != nil {
+		t.Fatalf("GetArticles() unexpected error: %v", err)
+	}
+	if len(resp.Articles) != 3 {
+		t.Errorf("Expected every article through the unfiltered bower, got %d", len(resp.Articles))
+	}
+}
+
+func TestFilterIngestedArticles(t *testing.T) {
+	ctx := context.Background()
+	repos := NewMockRepositories()
+
+	bower := model.NewBower("user-1", "AI news", []string{"ai"}, nil, "#14b8a6", false)
+	bower.FilterRules = &model.FilterRules{Exclude: []string{"football"}, ApplyAtIngest: true}
+	repos.BowerRepo.Create(ctx, bower)
+	feed := model.NewFeed(bower.BowerID, "https://news.example.com/feed.xml", "News", "", "news")
+	repos.FeedRepo.Create(ctx, feed)
+
+	fetched := func() []*model.Article {
+		return []*model.Article{
+			model.NewArticle(feed.FeedID, "AI chips", "", "https://news.example.com/1", time.Now()),
+			model.NewArticle(feed.FeedID, "Football scores", "", "https://news.example.com/2", time.Now()),
+		}
+	}
+
+	if kept := filterIngestedArticles(ctx, repos.FeedRepo, repos.BowerRepo, feed.FeedID, fetched()); len(kept) != 1 {
+		t.Errorf("Expected the excluded article to be dropped at ingest, got %d articles", len(kept))
+	}
+
+	// A subscribing bower that doesn't filter at ingest needs every article
+	other := model.NewBower("user-2", "Sport", []string{"sport"}, nil, "#14b8a6", false)
+	repos.BowerRepo.Create(ctx, other)
+	if _, _, err := subscribeBower(ctx, repos.FeedRepo, other.BowerID, &model.Feed{URL: feed.URL}); err != nil {
+		t.Fatalf("subscribeBower() unexpected error: %v", err)
+	}
+	if kept := filterIngestedArticles(ctx, repos.FeedRepo, repos.BowerRepo, feed.FeedID, fetched()); len(kept) != 2 {
+		t.Errorf("Expected every article to be stored for the unfiltered bower, got %d articles", len(kept))
+	}
+}
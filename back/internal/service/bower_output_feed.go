@@ -0,0 +1,233 @@
+package service
+
+import (
+	"encoding/json"
+	"encoding/xml"
+	"fmt"
+	"strings"
+	"time"
+
+	"feed-bower-api/internal/model"
+)
+
+// Output feed settings
+const (
+	outputFeedMaxArticles = 50
+	outputFeedSummaryLen  = 500
+)
+
+// BowerOutputFeed is a bower's aggregated articles, ready to be rendered as Atom or JSON Feed
+type BowerOutputFeed struct {
+	Bower    *model.Bower
+	Feeds    map[string]*model.Feed // Keyed by feed ID
+	Articles []*model.Article
+}
+
+// sourceFeed returns the bower's feed an article came from, preferring the feed it was first stored for
+func (f *BowerOutputFeed) sourceFeed(article *model.Article) *model.Feed {
+	for _, feedID := range article.FeedIDs() {
+		if source, ok := f.Feeds[feedID]; ok {
+			return source
+		}
+	}
+	return nil
+}
+
+// Atom 1.0 output structures
+type atomOutputFeed struct {
+	XMLName  xml.Name          `xml:"http://www.w3.org/2005/Atom feed"`
+	ID       string            `xml:"id"`
+	Title    string            `xml:"title"`
+	Subtitle string            `xml:"subtitle,omitempty"`
+	Updated  string            `xml:"updated"`
+	Links    []atomOutputLink  `xml:"link"`
+	Author   atomOutputAuthor  `xml:"author"`
+	Entries  []atomOutputEntry `xml:"entry"`
+}
+
+type atomOutputLink struct {
+	Href string `xml:"href,attr"`
+	Rel  string `xml:"rel,attr,omitempty"`
+	Type string `xml:"type,attr,omitempty"`
+}
+
+type atomOutputAuthor struct {
+	Name string `xml:"name"`
+}
+
+type atomOutputEntry struct {
+	ID        string            `xml:"id"`
+	Title     string            `xml:"title"`
+	Updated   string            `xml:"updated"`
+	Published string            `xml:"published"`
+	Links     []atomOutputLink  `xml:"link"`
+	Summary   *atomOutputText   `xml:"summary,omitempty"`
+	Content   *atomOutputText   `xml:"content,omitempty"`
+	Source    *atomOutputSource `xml:"source,omitempty"`
+}
+
+type atomOutputText struct {
+	Type string `xml:"type,attr"`
+	Body string `xml:",chardata"`
+}
+
+type atomOutputSource struct {
+	ID    string           `xml:"id"`
+	Title string           `xml:"title"`
+	Links []atomOutputLink `xml:"link"`
+}
+
+// JSON Feed 1.1 output structures
+type jsonOutputFeed struct {
+	Version     string             `json:"version"`
+	Title       string             `json:"title"`
+	FeedURL     string             `json:"feed_url"`
+	Description string             `json:"description,omitempty"`
+	Authors     []jsonOutputAuthor `json:"authors,omitempty"`
+	Items       []jsonOutputItem   `json:"items"`
+}
+
+type jsonOutputAuthor struct {
+	Name string `json:"name"`
+}
+
+type jsonOutputItem struct {
+	ID            string            `json:"id"`
+	URL           string            `json:"url"`
+	Title         string            `json:"title"`
+	ContentText   string            `json:"content_text"`
+	ContentHTML   string            `json:"content_html,omitempty"`
+	Summary       string            `json:"summary,omitempty"`
+	Image         string            `json:"image,omitempty"`
+	DatePublished string            `json:"date_published"`
+	Source        *jsonOutputSource `json:"_source,omitempty"` // Feed Bower extension
+}
+
+type jsonOutputSource struct {
+	Title   string `json:"title"`
+	FeedURL string `json:"feed_url"`
+}
+
+// MarshalAtomOutputFeed renders the output feed as an Atom 1.0 document.
+// selfURL is the public URL of the document itself.
+func MarshalAtomOutputFeed(feed *BowerOutputFeed, selfURL string) ([]byte, error) {
+	doc := atomOutputFeed{
+		ID:       outputFeedID(feed.Bower),
+		Title:    feed.Bower.Name,
+		Subtitle: outputFeedDescription(feed.Bower),
+		Updated:  formatAtomTime(feed.updated()),
+		Links:    []atomOutputLink{{Href: selfURL, Rel: "self", Type: "application/atom+xml"}},
+		Author:   atomOutputAuthor{Name: outputFeedAuthor(feed.Bower)},
+		Entries:  make([]atomOutputEntry, 0, len(feed.Articles)),
+	}
+
+	for _, article := range feed.Articles {
+		entry := atomOutputEntry{
+			ID:        outputArticleID(article),
+			Title:     article.Title,
+			Updated:   formatAtomTime(article.PublishedAt),
+			Published: formatAtomTime(article.PublishedAt),
+			Links:     []atomOutputLink{{Href: article.URL, Rel: "alternate", Type: "text/html"}},
+		}
+		if article.Content != "" {
+			entry.Summary = &atomOutputText{Type: "text", Body: truncateRunes(article.Content, outputFeedSummaryLen)}
+		}
+		if article.ContentHTML != "" {
+			entry.Content = &atomOutputText{Type: "html", Body: article.ContentHTML}
+		}
+		if source := feed.sourceFeed(article); source != nil {
+			entry.Source = &atomOutputSource{
+				ID:    source.URL,
+				Title: source.Title,
+				Links: []atomOutputLink{{Href: source.URL, Rel: "self"}},
+			}
+		}
+		doc.Entries = append(doc.Entries, entry)
+	}
+
+	data, err := xml.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal Atom feed: %w", err)
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+// MarshalJSONOutputFeed renders the output feed as a JSON Feed 1.1 document.
+// selfURL is the public URL of the document itself.
+func MarshalJSONOutputFeed(feed *BowerOutputFeed, selfURL string) ([]byte, error) {
+	doc := jsonOutputFeed{
+		Version:     "https://jsonfeed.org/version/1.1",
+		Title:       feed.Bower.Name,
+		FeedURL:     selfURL,
+		Description: outputFeedDescription(feed.Bower),
+		Authors:     []jsonOutputAuthor{{Name: outputFeedAuthor(feed.Bower)}},
+		Items:       make([]jsonOutputItem, 0, len(feed.Articles)),
+	}
+
+	for _, article := range feed.Articles {
+		item := jsonOutputItem{
+			ID:            outputArticleID(article),
+			URL:           article.URL,
+			Title:         article.Title,
+			ContentText:   article.Content,
+			ContentHTML:   article.ContentHTML,
+			Summary:       truncateRunes(article.Content, outputFeedSummaryLen),
+			DatePublished: formatAtomTime(article.PublishedAt),
+		}
+		if article.ImageURL != nil {
+			item.Image = *article.ImageURL
+		}
+		if source := feed.sourceFeed(article); source != nil {
+			item.Source = &jsonOutputSource{Title: source.Title, FeedURL: source.URL}
+		}
+		doc.Items = append(doc.Items, item)
+	}
+
+	data, err := json.MarshalIndent(doc, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal JSON feed: %w", err)
+	}
+	return data, nil
+}
+
+// updated returns the newest article publish time, falling back to the bower's update time
+func (f *BowerOutputFeed) updated() int64 {
+	updated := f.Bower.UpdatedAt
+	for _, article := range f.Articles {
+		if article.PublishedAt > updated {
+			updated = article.PublishedAt
+		}
+	}
+	return updated
+}
+
+// outputFeedID returns a stable identifier for the bower's output feed
+func outputFeedID(bower *model.Bower) string {
+	return "urn:feed-bower:bower:" + bower.BowerID
+}
+
+// outputArticleID returns a stable identifier for an article in the output feed
+func outputArticleID(article *model.Article) string {
+	return "urn:feed-bower:article:" + article.ArticleID
+}
+
+// outputFeedDescription describes the bower by its keywords
+func outputFeedDescription(bower *model.Bower) string {
+	if len(bower.Keywords) == 0 {
+		return ""
+	}
+	return "Keywords: " + strings.Join(bower.Keywords, ", ")
+}
+
+// outputFeedAuthor returns the display name of the bower's creator
+func outputFeedAuthor(bower *model.Bower) string {
+	if bower.CreatorName != nil && *bower.CreatorName != "" {
+		return *bower.CreatorName
+	}
+	return "Feed Bower"
+}
+
+// formatAtomTime formats a Unix timestamp as RFC 3339 in UTC
+func formatAtomTime(unix int64) string {
+	return time.Unix(unix, 0).UTC().Format(time.RFC3339)
+}
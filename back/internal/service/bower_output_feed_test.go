@@ -0,0 +1,101 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"feed-bower-api/internal/model"
+)
+
+func setupOutputFeedTest(t *testing.T, isPublic bool) (ArticleService, *model.Bower) {
+	t.Helper()
+	ctx := context.Background()
+
+	bowerRepo := NewMockBowerRepository()
+	feedRepo := NewMockFeedRepository()
+	articleRepo := NewMockArticleRepository()
+
+	bower := model.NewBower("owner-1", "Go News", []string{"go", "golang"}, nil, "#14b8a6", isPublic)
+	bowerRepo.Create(ctx, bower)
+
+	feed := model.NewFeed(bower.BowerID, "https://go.dev/blog/feed.atom", "The Go Blog", "", "tech")
+	feedRepo.Create(ctx, feed)
+
+	now := time.Now()
+	for i, title := range []string{"Older", "Newest"} {
+		article := model.NewArticle(feed.FeedID, title, title+" content", "https://go.dev/blog/"+title, now.Add(time.Duration(i)*time.Hour))
+		article.ArticleID = "article-" + title
+		article.ContentHTML = "<p>" + title + " <em>content</em></p>"
+		articleRepo.Create(ctx, article)
+	}
+
+	return NewArticleService(articleRepo, feedRepo, bowerRepo, nil, nil, nil), bower
+}
+
+func TestArticleService_GetBowerOutputFeed_Access(t *testing.T) {
+	ctx := context.Background()
+
+	publicService, publicBower := setupOutputFeedTest(t, true)
+	if _, err := publicService.GetBowerOutputFeed(ctx, publicBower.BowerID, ""); err != nil {
+		t.Errorf("Expected public bower to be readable without a token, got %v", err)
+	}
+
+	privateService, privateBower := setupOutputFeedTest(t, false)
+	if _, err := privateService.GetBowerOutputFeed(ctx, privateBower.BowerID, ""); err == nil || err.Error() != "access denied: invalid feed token" {
+		t.Errorf("Expected access denied without a token, got %v", err)
+	}
+
+	privateBower.FeedToken = "secret"
+	if _, err := privateService.GetBowerOutputFeed(ctx, privateBower.BowerID, "wrong"); err == nil {
+		t.Error("Expected access denied with a wrong token")
+	}
+
+	output, err := privateService.GetBowerOutputFeed(ctx, privateBower.BowerID, "secret")
+	if err != nil {
+		t.Fatalf("GetBowerOutputFeed() unexpected error: %v", err)
+	}
+	if len(output.Articles) != 2 || output.Articles[0].Title != "Newest" {
+		t.Errorf("Expected 2 articles newest first, got %+v", output.Articles)
+	}
+}
+
+func TestMarshalOutputFeed_RoundTrip(t *testing.T) {
+	articleService, bower := setupOutputFeedTest(t, true)
+	output, err := articleService.GetBowerOutputFeed(context.Background(), bower.BowerID, "")
+	if err != nil {
+		t.Fatalf("GetBowerOutputFeed() unexpected error: %v", err)
+	}
+
+	rss := NewRSSService()
+
+	atom, err := MarshalAtomOutputFeed(output, "https://api.example.com/api/bowers/"+bower.BowerID+"/feed.atom")
+	if err != nil {
+		t.Fatalf("MarshalAtomOutputFeed() unexpected error: %v", err)
+	}
+	atomData, err := rss.ParseAtomFeed(atom)
+	if err != nil {
+		t.Fatalf("Rendered Atom feed failed to parse: %v", err)
+	}
+	if atomData.Title != "Go News" || len(atomData.Articles) != 2 || atomData.Articles[0].URL != "https://go.dev/blog/Newest" {
+		t.Errorf("Unexpected Atom round-trip result: %+v", atomData)
+	}
+	if contentHTML := atomData.Articles[0].ContentHTML; contentHTML != "<p>Newest <em>content</em></p>" {
+		t.Errorf("Expected the HTML content in the Atom feed, got %q", contentHTML)
+	}
+
+	jsonFeed, err := MarshalJSONOutputFeed(output, "https://api.example.com/api/bowers/"+bower.BowerID+"/feed.json")
+	if err != nil {
+		t.Fatalf("MarshalJSONOutputFeed() unexpected error: %v", err)
+	}
+	jsonData, err := rss.ParseJSONFeed(jsonFeed)
+	if err != nil {
+		t.Fatalf("Rendered JSON feed failed to parse: %v", err)
+	}
+	if jsonData.Title != "Go News" || len(jsonData.Articles) != 2 || jsonData.Articles[1].Title != "Older" {
+		t.Errorf("Unexpected JSON Feed round-trip result: %+v", jsonData)
+	}
+	if contentHTML := jsonData.Articles[0].ContentHTML; contentHTML != "<p>Newest <em>content</em></p>" {
+		t.Errorf("Expected the HTML content in the JSON Feed, got %q", contentHTML)
+	}
+}
@@ -2,10 +2,14 @@ package service
 
 import (
 	"context"
+	"crypto/rand"
+	"encoding/hex"
 	"errors"
 	"fmt"
 	"log"
+	"strconv"
 	"strings"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
 
@@ -20,7 +24,7 @@ type BowerService interface {
 	GetBowerByID(ctx context.Context, bowerID string, userID string) (*model.Bower, error)
 	GetBowersByUserID(ctx context.Context, userID string, limit int32, lastKey map[string]types.AttributeValue) ([]*model.Bower, map[string]types.AttributeValue, error)
 	UpdateBower(ctx context.Context, userID string, bowerID string, req *UpdateBowerRequest) (*model.Bower, error)
-	DeleteBower(ctx context.Context, userID string, bowerID string) error
+	DeleteBower(ctx context.Context, userID string, bowerID string) (*DeleteBowerResult, error)
 
 	// Public bowers
 	GetPublicBowers(ctx context.Context, limit int32, lastKey map[string]types.AttributeValue) ([]*model.Bower, map[string]types.AttributeValue, error)
@@ -30,6 +34,14 @@ type BowerService interface {
 
 	// Bower name generation
 	GenerateBowerName(keywords []string) string
+
+	// OPML import/export
+	ImportOPML(ctx context.Context, userID string, data []byte) (*OPMLImportResult, error)
+	ExportOPML(ctx context.Context, userID string) ([]byte, error)
+
+	// Output feed access tokens
+	RotateFeedToken(ctx context.Context, userID string, bowerID string) (string, error)
+	RevokeFeedToken(ctx context.Context, userID string, bowerID string) error
 }
 
 // CreateBowerRequest represents the request to create a bower
@@ -41,6 +53,8 @@ type CreateBowerRequest struct {
 	IsPublic          bool     `json:"is_public"`
 	AutoRegisterFeeds bool     `json:"auto_register_feeds"`
 	MaxAutoFeeds      int      `json:"max_auto_feeds" validate:"omitempty,min=1,max=10"`
+
+	FilterRules *model.FilterRules `json:"filter_rules,omitempty"`
 }
 
 // CreateBowerResult represents the result of creating a bower
@@ -57,13 +71,32 @@ type UpdateBowerRequest struct {
 	EggColors *[]string `json:"egg_colors,omitempty"`
 	Color     *string   `json:"color,omitempty" validate:"omitempty,hexcolor"`
 	IsPublic  *bool     `json:"is_public,omitempty"`
+
+	// Replaces the filter rules; empty rules remove them
+	FilterRules *model.FilterRules `json:"filter_rules,omitempty"`
+}
+
+// DeleteBowerResult reports what a bower deletion removed
+type DeleteBowerResult struct {
+	BowerID              string `json:"bower_id"`
+	AlreadyDeleted       bool   `json:"already_deleted"`
+	SubscriptionsDeleted int    `json:"subscriptions_deleted"`
+	FeedsDeleted         int    `json:"feeds_deleted"` // Feeds deleted because no other bower subscribed to them
+	ArticlesDeleted      int    `json:"articles_deleted"`
+	LikesDeleted         int    `json:"likes_deleted"`
+	ReadStatesDeleted    int    `json:"read_states_deleted"`
+	PlaybackDeleted      int    `json:"playback_deleted"` // Podcast playback positions
 }
 
 // bowerService implements BowerService interface
 type bowerService struct {
-	bowerRepo   repository.BowerRepository
-	feedRepo    repository.FeedRepository
-	feedService FeedService
+	bowerRepo     repository.BowerRepository
+	feedRepo      repository.FeedRepository
+	articleRepo   repository.ArticleRepository
+	chickRepo     repository.ChickRepository
+	readStateRepo repository.ReadStateRepository
+	playbackRepo  repository.PlaybackRepository
+	feedService   FeedService
 }
 
 // NewBowerService creates a new bower service
@@ -80,6 +113,18 @@ func (s *bowerService) SetFeedService(feedService FeedService) {
 	s.feedService = feedService
 }
 
+// SetArticleRepositories sets the repositories used to cascade bower deletion to articles, likes and read state
+func (s *bowerService) SetArticleRepositories(articleRepo repository.ArticleRepository, chickRepo repository.ChickRepository, readStateRepo repository.ReadStateRepository) {
+	s.articleRepo = articleRepo
+	s.chickRepo = chickRepo
+	s.readStateRepo = readStateRepo
+}
+
+// SetPlaybackRepository sets the repository used to cascade bower deletion to podcast playback positions
+func (s *bowerService) SetPlaybackRepository(playbackRepo repository.PlaybackRepository) {
+	s.playbackRepo = playbackRepo
+}
+
 // Default colors for bowers
 var defaultColors = []string{
 	"#14b8a6", // Teal
@@ -122,6 +167,9 @@ func (s *bowerService) CreateBower(ctx context.Context, userID string, req *Crea
 
 	// Create bower
 	bower := model.NewBower(userID, name, req.Keywords, req.EggColors, color, req.IsPublic)
+	if !req.FilterRules.IsEmpty() {
+		bower.FilterRules = req.FilterRules
+	}
 
 	err := s.bowerRepo.Create(ctx, bower)
 	if err != nil {
@@ -294,6 +342,16 @@ func (s *bowerService) UpdateBower(ctx context.Context, userID string, bowerID s
 		bower.IsPublic = *req.IsPublic
 	}
 
+	if req.FilterRules != nil {
+		if _, err := compileFilterRules(req.FilterRules); err != nil {
+			return nil, err
+		}
+		bower.FilterRules = req.FilterRules
+		if req.FilterRules.IsEmpty() {
+			bower.FilterRules = nil
+		}
+	}
+
 	// Update bower
 	err = s.bowerRepo.Update(ctx, bower)
 	if err != nil {
@@ -315,56 +373,197 @@ func (s *bowerService) UpdateBower(ctx context.Context, userID string, bowerID s
 	return bower, nil
 }
 
-// DeleteBower deletes a bower and all its associated feeds
-func (s *bowerService) DeleteBower(ctx context.Context, userID string, bowerID string) error {
+// DeleteBower deletes a bower and its feed subscriptions. Feeds no other bower subscribes to are
+// deleted with their articles and every user's likes and read state for those articles.
+// Children are removed before their parents, so a deletion that fails partway can be retried and resumes where it stopped.
+// Deleting a bower that no longer exists succeeds with AlreadyDeleted set.
+func (s *bowerService) DeleteBower(ctx context.Context, userID string, bowerID string) (*DeleteBowerResult, error) {
 	if userID == "" {
-		return errors.New("user ID is required")
+		return nil, errors.New("user ID is required")
 	}
 	if bowerID == "" {
-		return errors.New("bower ID is required")
+		return nil, errors.New("bower ID is required")
 	}
 
+	result := &DeleteBowerResult{BowerID: bowerID}
+
 	// Get existing bower
 	bower, err := s.bowerRepo.GetByID(ctx, bowerID)
 	if err != nil {
-		return fmt.Errorf("failed to get bower: %w", err)
+		if isNotFoundError(err) {
+			result.AlreadyDeleted = true
+			return result, nil
+		}
+		return nil, fmt.Errorf("failed to get bower: %w", err)
 	}
 
 	// Check ownership
 	if bower.UserID != userID {
-		return errors.New("access denied: not bower owner")
+		return nil, errors.New("access denied: not bower owner")
 	}
 
-	// Delete associated feeds and articles
-	feeds, err := s.feedRepo.GetByBowerID(ctx, bowerID)
+	subscriptions, err := s.feedRepo.GetSubscriptionsByBowerID(ctx, bowerID)
 	if err != nil {
-		return fmt.Errorf("failed to get bower feeds: %w", err)
+		return result, fmt.Errorf("failed to get bower feeds: %w", err)
 	}
 
-	log.Printf("🗑️ Deleting bower %s with %d feeds", bowerID, len(feeds))
+	log.Printf("🗑️ Deleting bower %s with %d feeds", bowerID, len(subscriptions))
+
+	for _, subscription := range subscriptions {
+		if err := s.deleteSubscription(ctx, subscription, result); err != nil {
+			return result, err
+		}
+	}
 
-	for _, feed := range feeds {
-		// Delete articles for this feed (if article repository is available)
-		// Note: This requires adding articleRepo to bowerService
-		// For now, we'll log this and handle it separately
-		log.Printf("🗑️ Deleting feed %s (articles will be orphaned)", feed.FeedID)
+	// Delete bower
+	if err := s.bowerRepo.Delete(ctx, bowerID); err != nil && !isNotFoundError(err) {
+		return result, fmt.Errorf("failed to delete bower: %w", err)
+	}
 
-		err = s.feedRepo.Delete(ctx, feed.FeedID)
+	log.Printf("✅ Successfully deleted bower %s (subscriptions=%d, feeds=%d, articles=%d, likes=%d, read_states=%d, playback=%d)",
+		bowerID, result.SubscriptionsDeleted, result.FeedsDeleted, result.ArticlesDeleted, result.LikesDeleted, result.ReadStatesDeleted, result.PlaybackDeleted)
+	return result, nil
+}
+
+// deleteSubscription removes one of a bower's subscriptions. If the bower is the feed's last subscriber,
+// the feed's articles are deleted first and the feed itself afterwards.
+func (s *bowerService) deleteSubscription(ctx context.Context, subscription *model.Subscription, result *DeleteBowerResult) error {
+	feedID := subscription.FeedID
+
+	feed, err := s.feedRepo.GetByID(ctx, feedID)
+	if err != nil && !isNotFoundError(err) {
+		return fmt.Errorf("failed to get feed %s: %w", feedID, err)
+	}
+	lastSubscriber := feed != nil && feed.SubscriberCount <= 1
+
+	if lastSubscriber {
+		if err := s.deleteFeedArticles(ctx, feedID, result); err != nil {
+			return fmt.Errorf("failed to delete articles for feed %s: %w", feedID, err)
+		}
+	}
+
+	if err := s.feedRepo.Unsubscribe(ctx, subscription.BowerID, feedID); err != nil && !isNotFoundError(err) {
+		return fmt.Errorf("failed to delete subscription to feed %s: %w", feedID, err)
+	}
+	result.SubscriptionsDeleted++
+
+	if lastSubscriber {
+		deleted, err := s.feedRepo.DeleteIfUnsubscribed(ctx, feedID)
+		if err != nil {
+			return fmt.Errorf("failed to delete feed %s: %w", feedID, err)
+		}
+		if deleted {
+			result.FeedsDeleted++
+		}
+	}
+
+	return nil
+}
+
+// deleteFeedArticles deletes a feed's articles page by page, removing likes and read state before each article
+func (s *bowerService) deleteFeedArticles(ctx context.Context, feedID string, result *DeleteBowerResult) error {
+	if s.articleRepo == nil {
+		log.Printf("⚠️ Article repository not configured, articles of feed %s will be orphaned", feedID)
+		return nil
+	}
+
+	const pageSize = 100
+
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		// Always read the first page: the previous page has been deleted
+		articles, _, err := s.articleRepo.GetByFeedID(ctx, feedID, pageSize, nil)
 		if err != nil {
-			log.Printf("⚠️ Failed to delete feed %s: %v", feed.FeedID, err)
-			// Continue with deletion even if some feeds fail
+			return fmt.Errorf("failed to get articles: %w", err)
+		}
+		if len(articles) == 0 {
+			return nil
+		}
+
+		articleIDs := make([]string, 0, len(articles))
+		for _, article := range articles {
+			// Articles shared with other feeds are kept for those feeds
+			shared, err := s.detachSharedArticle(ctx, feedID, article)
+			if err != nil {
+				return err
+			}
+			if shared {
+				continue
+			}
+
+			if s.chickRepo != nil {
+				removed, err := s.chickRepo.RemoveLikedArticlesByArticleID(ctx, article.ArticleID)
+				if err != nil {
+					return fmt.Errorf("failed to remove likes: %w", err)
+				}
+				result.LikesDeleted += removed
+			}
+
+			if s.readStateRepo != nil {
+				removed, err := s.readStateRepo.DeleteByArticleID(ctx, article.ArticleID)
+				if err != nil {
+					return fmt.Errorf("failed to delete read states: %w", err)
+				}
+				result.ReadStatesDeleted += removed
+			}
+
+			if s.playbackRepo != nil && article.Episode != nil {
+				removed, err := s.playbackRepo.DeleteByArticleID(ctx, article.ArticleID)
+				if err != nil {
+					return fmt.Errorf("failed to delete playback positions: %w", err)
+				}
+				result.PlaybackDeleted += removed
+			}
+
+			articleIDs = append(articleIDs, article.ArticleID)
+		}
+
+		if len(articleIDs) == 0 {
 			continue
 		}
+		if err := s.articleRepo.BatchDelete(ctx, articleIDs); err != nil {
+			return fmt.Errorf("failed to delete articles: %w", err)
+		}
+		result.ArticlesDeleted += len(articleIDs)
 	}
+}
 
-	// Delete bower
-	err = s.bowerRepo.Delete(ctx, bowerID)
-	if err != nil {
-		return fmt.Errorf("failed to delete bower: %w", err)
+// detachSharedArticle removes a feed from an article merged from several feeds, and reports
+// whether the article was shared. An alias is deleted along with the feed's entry in the article's
+// source feeds; an article stored for the feed is handed over to the first of its source feeds.
+func (s *bowerService) detachSharedArticle(ctx context.Context, feedID string, article *model.Article) (bool, error) {
+	if article.IsAlias() {
+		err := s.articleRepo.RemoveSourceFeed(ctx, article.DuplicateOf, feedID)
+		if err != nil && !isNotFoundError(err) {
+			return false, fmt.Errorf("failed to remove feed from merged article: %w", err)
+		}
+		return true, nil
 	}
 
-	log.Printf("✅ Successfully deleted bower %s", bowerID)
-	return nil
+	if len(article.SourceFeedIDs) == 0 {
+		return false, nil
+	}
+
+	// The new owner lists the article directly, so its alias is no longer needed
+	newFeedID := article.SourceFeedIDs[0]
+	article.FeedID = newFeedID
+	article.SourceFeedIDs = article.SourceFeedIDs[1:]
+	if err := s.articleRepo.Update(ctx, article); err != nil {
+		return false, fmt.Errorf("failed to hand over merged article: %w", err)
+	}
+	if err := s.articleRepo.BatchDelete(ctx, []string{model.ArticleAliasID(article.ArticleID, newFeedID)}); err != nil {
+		return false, fmt.Errorf("failed to delete article alias: %w", err)
+	}
+
+	return true, nil
+}
+
+// isNotFoundError reports whether a repository error means the item does not exist
+func isNotFoundError(err error) bool {
+	return err != nil && strings.Contains(err.Error(), "not found")
 }
 
 // GetPublicBowers retrieves public bowers
@@ -462,6 +661,324 @@ func (s *bowerService) GenerateBowerName(keywords []string) string {
 	}
 }
 
+// ImportOPML imports feeds from an OPML document. Each top-level outline category becomes a bower
+// (reusing an existing bower with the same name) and the outlines nested beneath it become its feeds.
+func (s *bowerService) ImportOPML(ctx context.Context, userID string, data []byte) (*OPMLImportResult, error) {
+	if userID == "" {
+		return nil, errors.New("user ID is required")
+	}
+	if s.feedService == nil {
+		return nil, errors.New("feed service not configured")
+	}
+
+	opml, err := ParseOPML(data)
+	if err != nil {
+		return nil, fmt.Errorf("invalid OPML: %w", err)
+	}
+
+	categories := groupOPMLOutlines(opml)
+	totalFeeds := 0
+	for _, category := range categories {
+		totalFeeds += len(category.Feeds)
+	}
+	if len(categories) == 0 {
+		return nil, errors.New("invalid OPML: no feeds found")
+	}
+	if totalFeeds > opmlMaxFeeds {
+		return nil, fmt.Errorf("invalid OPML: too many feeds (max %d)", opmlMaxFeeds)
+	}
+
+	// Index the user's existing bowers by name so re-imports merge into them
+	existingBowers, _, err := s.bowerRepo.GetByUserID(ctx, userID, 100, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get user bowers: %w", err)
+	}
+	bowersByName := make(map[string]*model.Bower, len(existingBowers))
+	for _, bower := range existingBowers {
+		bowersByName[bower.Name] = bower
+	}
+
+	log.Printf("[ImportOPML] START | user_id=%s | categories=%d | feeds=%d", userID, len(categories), totalFeeds)
+
+	result := &OPMLImportResult{
+		Bowers: make([]OPMLImportBower, 0, len(categories)),
+		Feeds:  make([]OPMLImportFeedResult, 0, totalFeeds),
+	}
+
+	for i, category := range categories {
+		name := truncateRunes(category.Outline.name(), model.MaxBowerNameLength)
+		if name == "" {
+			name = opmlDefaultCategoryName
+		}
+
+		bower, created, err := s.findOrCreateImportBower(ctx, userID, name, category.Outline, i, bowersByName)
+		if err != nil {
+			log.Printf("[ImportOPML] BOWER_FAILED | user_id=%s | name=%s | error=%v", userID, name, err)
+			for _, outline := range category.Feeds {
+				result.Feeds = append(result.Feeds, OPMLImportFeedResult{
+					URL:    strings.TrimSpace(outline.XMLURL),
+					Title:  outline.name(),
+					Bower:  name,
+					Status: OPMLImportFailed,
+					Error:  fmt.Sprintf("failed to create bower: %v", err),
+				})
+				result.TotalFailed++
+			}
+			continue
+		}
+
+		result.Bowers = append(result.Bowers, OPMLImportBower{
+			BowerID: bower.BowerID,
+			Name:    bower.Name,
+			Created: created,
+		})
+
+		// Track URLs already in the bower to detect duplicates
+		seenURLs := make(map[string]bool)
+		if !created {
+			existingFeeds, err := s.feedRepo.GetByBowerID(ctx, bower.BowerID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to check existing feeds: %w", err)
+			}
+			for _, feed := range existingFeeds {
+				seenURLs[feed.URL] = true
+			}
+		}
+
+		for _, outline := range category.Feeds {
+			feedResult := s.importOPMLFeed(ctx, bower, outline, seenURLs)
+			switch feedResult.Status {
+			case OPMLImportCreated:
+				result.TotalCreated++
+			case OPMLImportDuplicate:
+				result.TotalDuplicates++
+			case OPMLImportFailed:
+				result.TotalFailed++
+			}
+			result.Feeds = append(result.Feeds, feedResult)
+		}
+	}
+
+	log.Printf("[ImportOPML] SUCCESS | user_id=%s | created=%d | duplicates=%d | failed=%d",
+		userID, result.TotalCreated, result.TotalDuplicates, result.TotalFailed)
+
+	return result, nil
+}
+
+// findOrCreateImportBower returns the user's bower with the given name, creating it from the
+// category outline's settings if needed
+func (s *bowerService) findOrCreateImportBower(ctx context.Context, userID string, name string, outline OPMLOutline, index int, bowersByName map[string]*model.Bower) (*model.Bower, bool, error) {
+	if bower, exists := bowersByName[name]; exists {
+		return bower, false, nil
+	}
+
+	keywords, color, eggColors, isPublic := outline.bowerSettings()
+
+	// Bowers need at least one keyword, so fall back to the category name
+	if len(keywords) == 0 {
+		keywords = []string{truncateRunes(name, model.MaxKeywordLength)}
+	}
+	if color == "" {
+		color = defaultColors[index%len(defaultColors)]
+	}
+	if eggColors == nil {
+		eggColors = []string{}
+	}
+
+	bower := model.NewBower(userID, name, keywords, eggColors, color, isPublic)
+	if err := s.bowerRepo.Create(ctx, bower); err != nil {
+		return nil, false, err
+	}
+
+	bowersByName[name] = bower
+	return bower, true, nil
+}
+
+// importOPMLFeed validates and creates a single feed from an OPML outline
+func (s *bowerService) importOPMLFeed(ctx context.Context, bower *model.Bower, outline OPMLOutline, seenURLs map[string]bool) OPMLImportFeedResult {
+	feedURL := strings.TrimSpace(outline.XMLURL)
+	result := OPMLImportFeedResult{
+		URL:     feedURL,
+		Title:   outline.name(),
+		BowerID: bower.BowerID,
+		Bower:   bower.Name,
+	}
+
+	if seenURLs[feedURL] {
+		result.Status = OPMLImportDuplicate
+		return result
+	}
+
+	if err := s.feedService.ValidateFeedURL(feedURL); err != nil {
+		result.Status = OPMLImportFailed
+		result.Error = err.Error()
+		return result
+	}
+
+	title := result.Title
+	if title == "" {
+		title = feedURL
+	}
+
+	feed := model.NewFeed(
+		bower.BowerID,
+		feedURL,
+		truncateRunes(title, model.MaxFeedTitleLength),
+		truncateRunes(strings.TrimSpace(outline.Description), model.MaxFeedDescLength),
+		truncateRunes(strings.TrimSpace(outline.Category), model.MaxCategoryLength),
+	)
+
+	feed, _, err := subscribeBower(ctx, s.feedRepo, bower.BowerID, feed)
+	if err != nil {
+		if err.Error() == "feed URL already exists in this bower" {
+			seenURLs[feedURL] = true
+			result.Status = OPMLImportDuplicate
+			return result
+		}
+		result.Status = OPMLImportFailed
+		result.Error = err.Error()
+		return result
+	}
+
+	seenURLs[feedURL] = true
+	result.FeedID = feed.FeedID
+	result.Status = OPMLImportCreated
+	return result
+}
+
+// ExportOPML exports all of the user's bowers as an OPML 2.0 document, with one outline group per bower
+func (s *bowerService) ExportOPML(ctx context.Context, userID string) ([]byte, error) {
+	if userID == "" {
+		return nil, errors.New("user ID is required")
+	}
+
+	opml := &OPML{
+		Version: "2.0",
+		Head: OPMLHead{
+			Title:       opmlExportTitle,
+			DateCreated: time.Now().UTC().Format(time.RFC1123Z),
+		},
+	}
+
+	var lastKey map[string]types.AttributeValue
+	for {
+		bowers, nextKey, err := s.bowerRepo.GetByUserID(ctx, userID, 100, lastKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user bowers: %w", err)
+		}
+
+		for _, bower := range bowers {
+			feeds, err := s.feedRepo.GetByBowerID(ctx, bower.BowerID)
+			if err != nil {
+				return nil, fmt.Errorf("failed to get bower feeds: %w", err)
+			}
+
+			group := OPMLOutline{
+				Text:      bower.Name,
+				Title:     bower.Name,
+				Keywords:  strings.Join(bower.Keywords, ","),
+				Color:     bower.Color,
+				EggColors: strings.Join(bower.EggColors, ","),
+				IsPublic:  strconv.FormatBool(bower.IsPublic),
+				Outlines:  make([]OPMLOutline, 0, len(feeds)),
+			}
+
+			for _, feed := range feeds {
+				group.Outlines = append(group.Outlines, OPMLOutline{
+					Text:        feed.Title,
+					Title:       feed.Title,
+					Type:        "rss",
+					XMLURL:      feed.URL,
+					Description: feed.Description,
+					Category:    feed.Category,
+				})
+			}
+
+			opml.Body.Outlines = append(opml.Body.Outlines, group)
+		}
+
+		if len(nextKey) == 0 {
+			break
+		}
+		lastKey = nextKey
+	}
+
+	log.Printf("[ExportOPML] SUCCESS | user_id=%s | bowers=%d", userID, len(opml.Body.Outlines))
+
+	return MarshalOPML(opml)
+}
+
+// RotateFeedToken issues a new output feed token for the bower, invalidating any previous token
+func (s *bowerService) RotateFeedToken(ctx context.Context, userID string, bowerID string) (string, error) {
+	bower, err := s.getOwnedBower(ctx, userID, bowerID)
+	if err != nil {
+		return "", err
+	}
+
+	token, err := generateFeedToken()
+	if err != nil {
+		return "", err
+	}
+
+	bower.FeedToken = token
+	if err := s.bowerRepo.Update(ctx, bower); err != nil {
+		return "", fmt.Errorf("failed to update bower: %w", err)
+	}
+
+	log.Printf("[RotateFeedToken] SUCCESS | user_id=%s | bower_id=%s", userID, bowerID)
+	return token, nil
+}
+
+// RevokeFeedToken removes the bower's output feed token so private output feeds are no longer readable
+func (s *bowerService) RevokeFeedToken(ctx context.Context, userID string, bowerID string) error {
+	bower, err := s.getOwnedBower(ctx, userID, bowerID)
+	if err != nil {
+		return err
+	}
+
+	if !bower.HasFeedToken() {
+		return nil
+	}
+
+	bower.FeedToken = ""
+	if err := s.bowerRepo.Update(ctx, bower); err != nil {
+		return fmt.Errorf("failed to update bower: %w", err)
+	}
+
+	log.Printf("[RevokeFeedToken] SUCCESS | user_id=%s | bower_id=%s", userID, bowerID)
+	return nil
+}
+
+// getOwnedBower loads a bower and checks that it belongs to the user
+func (s *bowerService) getOwnedBower(ctx context.Context, userID string, bowerID string) (*model.Bower, error) {
+	if userID == "" {
+		return nil, errors.New("user ID is required")
+	}
+	if bowerID == "" {
+		return nil, errors.New("bower ID is required")
+	}
+
+	bower, err := s.bowerRepo.GetByID(ctx, bowerID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to get bower: %w", err)
+	}
+
+	if bower.UserID != userID {
+		return nil, errors.New("access denied: not bower owner")
+	}
+
+	return bower, nil
+}
+
+// generateFeedToken returns a random 256-bit hex token
+func generateFeedToken() (string, error) {
+	buf := make([]byte, 32)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate feed token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
 // validateCreateBowerRequest validates the create bower request
 func (s *bowerService) validateCreateBowerRequest(req *CreateBowerRequest) error {
 	if len(req.Keywords) == 0 {
@@ -490,5 +1007,9 @@ func (s *bowerService) validateCreateBowerRequest(req *CreateBowerRequest) error
 		keywordMap[keyword] = true
 	}
 
+	if _, err := compileFilterRules(req.FilterRules); err != nil {
+		return err
+	}
+
 	return nil
 }
@@ -2,7 +2,11 @@ package service
 
 import (
 	"context"
+	"errors"
+	"fmt"
+	"strings"
 	"testing"
+	"time"
 
 	"feed-bower-api/internal/model"
 )
@@ -423,11 +427,18 @@ func TestBowerService_DeleteBower_Unit(t *testing.T) {
 	mockBowerRepo.bowers[bower.BowerID] = bower
 
 	tests := []struct {
-		name    string
-		bowerID string
-		userID  string
-		wantErr bool
+		name               string
+		bowerID            string
+		userID             string
+		wantErr            bool
+		wantAlreadyDeleted bool
 	}{
+		{
+			name:    "unauthorized user",
+			bowerID: "bower123",
+			userID:  "otheruser",
+			wantErr: true,
+		},
 		{
 			name:    "valid deletion",
 			bowerID: "bower123",
@@ -435,22 +446,22 @@ func TestBowerService_DeleteBower_Unit(t *testing.T) {
 			wantErr: false,
 		},
 		{
-			name:    "non-existing bower",
-			bowerID: "nonexistent",
-			userID:  "user123",
-			wantErr: true,
+			name:               "already deleted bower",
+			bowerID:            "bower123",
+			userID:             "user123",
+			wantAlreadyDeleted: true,
 		},
 		{
-			name:    "unauthorized user",
-			bowerID: "bower123",
-			userID:  "otheruser",
-			wantErr: true,
+			name:               "non-existing bower",
+			bowerID:            "nonexistent",
+			userID:             "user123",
+			wantAlreadyDeleted: true,
 		},
 	}
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
-			err := service.DeleteBower(context.Background(), tt.userID, tt.bowerID)
+			result, err := service.DeleteBower(context.Background(), tt.userID, tt.bowerID)
 
 			if tt.wantErr {
 				if err == nil {
@@ -464,6 +475,10 @@ func TestBowerService_DeleteBower_Unit(t *testing.T) {
 				return
 			}
 
+			if result.AlreadyDeleted != tt.wantAlreadyDeleted {
+				t.Errorf("DeleteBower() AlreadyDeleted = %v, expected %v", result.AlreadyDeleted, tt.wantAlreadyDeleted)
+			}
+
 			// Verify bower was deleted
 			_, exists := mockBowerRepo.bowers[tt.bowerID]
 			if exists {
@@ -473,6 +488,270 @@ func TestBowerService_DeleteBower_Unit(t *testing.T) {
 	}
 }
 
+func TestBowerService_DeleteBower_Cascade(t *testing.T) {
+	ctx := context.Background()
+	repos := NewMockRepositories()
+	service := NewBowerService(repos.BowerRepo, repos.FeedRepo)
+	service.(*bowerService).SetArticleRepositories(repos.ArticleRepo, repos.ChickRepo, repos.ReadStateRepo)
+
+	bower := model.NewBower("user-1", "Tech", []string{"go"}, nil, "#14b8a6", false)
+	repos.BowerRepo.Create(ctx, bower)
+	feed := model.NewFeed(bower.BowerID, "https://example.com/feed.xml", "Example", "", "tech")
+	repos.FeedRepo.Create(ctx, feed)
+
+	// An article in another bower must survive
+	otherFeed := model.NewFeed("other-bower", "https://other.example.com/feed.xml", "Other", "", "tech")
+	repos.FeedRepo.Create(ctx, otherFeed)
+	kept := model.NewArticle(otherFeed.FeedID, "Kept", "content", "https://other.example.com/kept", time.Now())
+	kept.ArticleID = "kept"
+	repos.ArticleRepo.Create(ctx, kept)
+	repos.ChickRepo.AddLikedArticle(ctx, model.NewLikedArticle("user-2", kept.ArticleID))
+
+	for i := 0; i < 150; i++ {
+		article := model.NewArticle(feed.FeedID, "Article", "content", "https://example.com/article", time.Now())
+		article.ArticleID = fmt.Sprintf("article-%d", i)
+		repos.ArticleRepo.Create(ctx, article)
+	}
+	repos.ChickRepo.AddLikedArticle(ctx, model.NewLikedArticle("user-1", "article-1"))
+	repos.ChickRepo.AddLikedArticle(ctx, model.NewLikedArticle("user-2", "article-1"))
+	repos.ReadStateRepo.MarkRead(ctx, model.NewReadState("user-1", "article-2"))
+
+	result, err := service.DeleteBower(ctx, "user-1", bower.BowerID)
+	if err != nil {
+		t.Fatalf("DeleteBower() unexpected error: %v", err)
+	}
+
+	expected := DeleteBowerResult{BowerID: bower.BowerID, SubscriptionsDeleted: 1, FeedsDeleted: 1, ArticlesDeleted: 150, LikesDeleted: 2, ReadStatesDeleted: 1}
+	if *result != expected {
+		t.Errorf("DeleteBower() result = %+v, expected %+v", *result, expected)
+	}
+	if len(repos.ArticleRepo.articles) != 1 || repos.ArticleRepo.articles["kept"] == nil {
+		t.Errorf("Expected only the other bower's article to remain, got %d articles", len(repos.ArticleRepo.articles))
+	}
+	if liked, _ := repos.ChickRepo.IsArticleLiked(ctx, "user-2", "kept"); !liked {
+		t.Error("Expected like on the other bower's article to remain")
+	}
+
+	// Retrying a completed deletion is a no-op
+	result, err = service.DeleteBower(ctx, "user-1", bower.BowerID)
+	if err != nil || !result.AlreadyDeleted {
+		t.Errorf("Expected rerun to report AlreadyDeleted, got %+v, %v", result, err)
+	}
+}
+
+func TestBowerService_DeleteBower_SharedFeed(t *testing.T) {
+	ctx := context.Background()
+	repos := NewMockRepositories()
+	service := NewBowerService(repos.BowerRepo, repos.FeedRepo)
+	service.(*bowerService).SetArticleRepositories(repos.ArticleRepo, repos.ChickRepo, repos.ReadStateRepo)
+
+	bower := model.NewBower("user-1", "Tech", []string{"go"}, nil, "#14b8a6", false)
+	repos.BowerRepo.Create(ctx, bower)
+	otherBower := model.NewBower("user-2", "Go", []string{"go"}, nil, "#14b8a6", false)
+	repos.BowerRepo.Create(ctx, otherBower)
+
+	feed := model.NewFeed(bower.BowerID, "https://example.com/feed.xml", "Example", "", "tech")
+	repos.FeedRepo.Create(ctx, feed)
+	if err := repos.FeedRepo.Subscribe(ctx, model.NewSubscription(otherBower.BowerID, feed.FeedID)); err != nil {
+		t.Fatalf("Subscribe() unexpected error: %v", err)
+	}
+
+	article := model.NewArticle(feed.FeedID, "Article", "content", "https://example.com/article", time.Now())
+	article.ArticleID = "article-1"
+	repos.ArticleRepo.Create(ctx, article)
+
+	result, err := service.DeleteBower(ctx, "user-1", bower.BowerID)
+	if err != nil {
+		t.Fatalf("DeleteBower() unexpected error: %v", err)
+	}
+
+	if result.SubscriptionsDeleted != 1 || result.FeedsDeleted != 0 || result.ArticlesDeleted != 0 {
+		t.Errorf("Expected only the subscription to be removed, got %+v", result)
+	}
+	feeds, _ := repos.FeedRepo.GetByBowerID(ctx, otherBower.BowerID)
+	if len(feeds) != 1 || feeds[0].SubscriberCount != 1 {
+		t.Errorf("Expected the other bower to keep the feed with one subscriber, got %+v", feeds)
+	}
+	if repos.ArticleRepo.articles["article-1"] == nil {
+		t.Error("Expected articles of a shared feed to remain")
+	}
+}
+
+// stubFeedValidator is a FeedService that only implements URL validation
+type stubFeedValidator struct {
+	FeedService
+	invalid map[string]bool
+}
+
+func (s *stubFeedValidator) ValidateFeedURL(feedURL string) error {
+	if s.invalid[feedURL] {
+		return errors.New("URL security validation failed")
+	}
+	return nil
+}
+
+const testOPML = `<?xml version="1.0" encoding="UTF-8"?>
+<opml version="2.0">
+  <head><title>My Subscriptions</title></head>
+  <body>
+    <outline text="Tech" title="Tech">
+      <outline type="rss" text="Go Blog" xmlUrl="https://go.dev/blog/feed.atom" htmlUrl="https://go.dev/blog" description="The Go Blog"/>
+      <outline text="Nested">
+        <outline type="rss" text="Hacker News" xmlUrl="https://news.ycombinator.com/rss"/>
+      </outline>
+      <outline type="rss" text="Go Blog again" xmlUrl="https://go.dev/blog/feed.atom"/>
+      <outline type="rss" text="Internal" xmlUrl="http://localhost/feed.xml"/>
+    </outline>
+    <outline text="Empty Category"/>
+    <outline type="rss" text="Loose Feed" xmlUrl="https://example.com/rss.xml"/>
+  </body>
+</opml>`
+
+func TestBowerService_ImportOPML_Unit(t *testing.T) {
+	mockBowerRepo := NewMockBowerRepository()
+	mockFeedRepo := NewMockFeedRepository()
+	service := NewBowerService(mockBowerRepo, mockFeedRepo)
+	service.(*bowerService).SetFeedService(&stubFeedValidator{
+		invalid: map[string]bool{"http://localhost/feed.xml": true},
+	})
+
+	result, err := service.ImportOPML(context.Background(), "user-1", []byte(testOPML))
+	if err != nil {
+		t.Fatalf("ImportOPML() unexpected error: %v", err)
+	}
+
+	if len(result.Bowers) != 2 {
+		t.Fatalf("ImportOPML() expected 2 bowers, got %d", len(result.Bowers))
+	}
+	if result.Bowers[0].Name != "Tech" || !result.Bowers[0].Created {
+		t.Errorf("ImportOPML() expected created bower 'Tech', got %+v", result.Bowers[0])
+	}
+	if result.Bowers[1].Name != "My Subscriptions" {
+		t.Errorf("ImportOPML() expected top-level feeds in 'My Subscriptions', got %s", result.Bowers[1].Name)
+	}
+
+	if result.TotalCreated != 3 || result.TotalDuplicates != 1 || result.TotalFailed != 1 {
+		t.Errorf("ImportOPML() expected 3 created, 1 duplicate, 1 failed, got %d/%d/%d",
+			result.TotalCreated, result.TotalDuplicates, result.TotalFailed)
+	}
+
+	expectedStatuses := []string{OPMLImportCreated, OPMLImportCreated, OPMLImportDuplicate, OPMLImportFailed, OPMLImportCreated}
+	for i, expected := range expectedStatuses {
+		if result.Feeds[i].Status != expected {
+			t.Errorf("ImportOPML() feed %d (%s) expected status %s, got %s", i, result.Feeds[i].URL, expected, result.Feeds[i].Status)
+		}
+	}
+
+	feeds, _ := mockFeedRepo.GetByBowerID(context.Background(), result.Bowers[0].BowerID)
+	if len(feeds) != 2 {
+		t.Errorf("ImportOPML() expected 2 feeds in 'Tech', got %d", len(feeds))
+	}
+
+	// Importing again should merge into the existing bowers and report duplicates
+	result, err = service.ImportOPML(context.Background(), "user-1", []byte(testOPML))
+	if err != nil {
+		t.Fatalf("ImportOPML() second import unexpected error: %v", err)
+	}
+	if result.TotalCreated != 0 || result.TotalDuplicates != 4 {
+		t.Errorf("ImportOPML() second import expected 0 created and 4 duplicates, got %d/%d",
+			result.TotalCreated, result.TotalDuplicates)
+	}
+	if len(mockBowerRepo.bowers) != 2 {
+		t.Errorf("ImportOPML() expected existing bowers to be reused, got %d bowers", len(mockBowerRepo.bowers))
+	}
+}
+
+func TestBowerService_ImportOPML_InvalidDocument(t *testing.T) {
+	service := NewBowerService(NewMockBowerRepository(), NewMockFeedRepository())
+	service.(*bowerService).SetFeedService(&stubFeedValidator{})
+
+	tests := []struct {
+		name string
+		data string
+	}{
+		{"empty", ""},
+		{"not xml", "not xml at all"},
+		{"no feeds", `<opml version="2.0"><body><outline text="Empty"/></body></opml>`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := service.ImportOPML(context.Background(), "user-1", []byte(tt.data)); err == nil {
+				t.Error("ImportOPML() expected error, got nil")
+			}
+		})
+	}
+}
+
+func TestBowerService_ExportOPML_RoundTrip(t *testing.T) {
+	ctx := context.Background()
+	mockBowerRepo := NewMockBowerRepository()
+	mockFeedRepo := NewMockFeedRepository()
+	service := NewBowerService(mockBowerRepo, mockFeedRepo)
+
+	bower := model.NewBower("user-1", "Tech & News", []string{"Go", "テクノロジー"}, []string{"#f59e0b"}, "#8b5cf6", true)
+	mockBowerRepo.Create(ctx, bower)
+	mockFeedRepo.Create(ctx, model.NewFeed(bower.BowerID, "https://go.dev/blog/feed.atom", "The Go Blog", "Go news", "Programming"))
+
+	emptyBower := model.NewBower("user-1", "Empty", []string{"empty"}, []string{}, "#14b8a6", false)
+	mockBowerRepo.Create(ctx, emptyBower)
+
+	data, err := service.ExportOPML(ctx, "user-1")
+	if err != nil {
+		t.Fatalf("ExportOPML() unexpected error: %v", err)
+	}
+
+	opml, err := ParseOPML(data)
+	if err != nil {
+		t.Fatalf("ExportOPML() produced invalid OPML: %v", err)
+	}
+	if opml.Version != "2.0" || len(opml.Body.Outlines) != 2 {
+		t.Fatalf("ExportOPML() expected OPML 2.0 with 2 groups, got version %s with %d groups", opml.Version, len(opml.Body.Outlines))
+	}
+
+	// Import into a fresh store and compare
+	importBowerRepo := NewMockBowerRepository()
+	importFeedRepo := NewMockFeedRepository()
+	importService := NewBowerService(importBowerRepo, importFeedRepo)
+	importService.(*bowerService).SetFeedService(&stubFeedValidator{})
+
+	result, err := importService.ImportOPML(ctx, "user-2", data)
+	if err != nil {
+		t.Fatalf("ImportOPML() unexpected error: %v", err)
+	}
+	if len(result.Bowers) != 2 || result.TotalCreated != 1 {
+		t.Fatalf("ImportOPML() expected 2 bowers and 1 feed, got %d bowers and %d feeds", len(result.Bowers), result.TotalCreated)
+	}
+
+	for _, imported := range result.Bowers {
+		got := importBowerRepo.bowers[imported.BowerID]
+		var want *model.Bower
+		if got.Name == bower.Name {
+			want = bower
+		} else {
+			want = emptyBower
+		}
+
+		if got.Color != want.Color || got.IsPublic != want.IsPublic {
+			t.Errorf("Round trip changed bower %s settings: got color=%s public=%v", want.Name, got.Color, got.IsPublic)
+		}
+		if strings.Join(got.Keywords, ",") != strings.Join(want.Keywords, ",") {
+			t.Errorf("Round trip changed bower %s keywords: got %v, want %v", want.Name, got.Keywords, want.Keywords)
+		}
+		if strings.Join(got.EggColors, ",") != strings.Join(want.EggColors, ",") {
+			t.Errorf("Round trip changed bower %s egg colors: got %v, want %v", want.Name, got.EggColors, want.EggColors)
+		}
+	}
+
+	feeds, _ := importFeedRepo.GetByBowerID(ctx, result.Feeds[0].BowerID)
+	if len(feeds) != 1 {
+		t.Fatalf("Round trip expected 1 feed, got %d", len(feeds))
+	}
+	if feeds[0].Title != "The Go Blog" || feeds[0].Description != "Go news" || feeds[0].Category != "Programming" {
+		t.Errorf("Round trip changed feed: %+v", feeds[0])
+	}
+}
+
 // Helper functions for pointer types
 func stringPtr(s string) *string {
 	return &s
@@ -481,3 +760,42 @@ func stringPtr(s string) *string {
 func boolPtr(b bool) *bool {
 	return &b
 }
+
+func TestBowerService_FeedToken_Unit(t *testing.T) {
+	mockBowerRepo := NewMockBowerRepository()
+	service := NewBowerService(mockBowerRepo, NewMockFeedRepository())
+	ctx := context.Background()
+
+	bower := model.NewBower("owner-1", "Tech", []string{"go"}, nil, "#14b8a6", false)
+	mockBowerRepo.Create(ctx, bower)
+
+	if _, err := service.RotateFeedToken(ctx, "other-user", bower.BowerID); err == nil || err.Error() != "access denied: not bower owner" {
+		t.Errorf("Expected access denied for non-owner, got %v", err)
+	}
+
+	first, err := service.RotateFeedToken(ctx, "owner-1", bower.BowerID)
+	if err != nil {
+		t.Fatalf("RotateFeedToken() unexpected error: %v", err)
+	}
+	if len(first) != 64 {
+		t.Errorf("Expected 64 character token, got %d", len(first))
+	}
+
+	second, err := service.RotateFeedToken(ctx, "owner-1", bower.BowerID)
+	if err != nil {
+		t.Fatalf("RotateFeedToken() unexpected error: %v", err)
+	}
+
+	stored, _ := mockBowerRepo.GetByID(ctx, bower.BowerID)
+	if first == second || stored.CheckFeedToken(first) || !stored.CheckFeedToken(second) {
+		t.Error("Expected rotation to replace the previous token")
+	}
+
+	if err := service.RevokeFeedToken(ctx, "owner-1", bower.BowerID); err != nil {
+		t.Fatalf("RevokeFeedToken() unexpected error: %v", err)
+	}
+	stored, _ = mockBowerRepo.GetByID(ctx, bower.BowerID)
+	if stored.HasFeedToken() || stored.CheckFeedToken(second) {
+		t.Error("Expected token to be revoked")
+	}
+}
@@ -299,22 +299,10 @@ func (s *chickService) GetLikedArticles(ctx context.Context, userID string, limi
 			continue
 		}
 
-		// Get bower name through feed
-		feed, err := s.feedRepo.GetByID(ctx, article.FeedID)
+		// Get bower name through the feed's subscriptions, checking the user still has access
+		_, bower, err := findArticleSubscription(ctx, s.feedRepo, s.bowerRepo, article, userID)
 		if err != nil {
-			// Skip if feed not found
-			continue
-		}
-
-		bower, err := s.bowerRepo.GetByID(ctx, feed.BowerID)
-		if err != nil {
-			// Skip if bower not found
-			continue
-		}
-
-		// Check if user still has access to this article
-		if bower.UserID != userID && !bower.IsPublic {
-			// Skip if no access
+			// Skip if feed not found or no access
 			continue
 		}
 
@@ -0,0 +1,226 @@
+package service
+
+import (
+	"bytes"
+	"context"
+	"errors"
+	"fmt"
+	"html"
+	"io"
+	"net/http"
+	"net/url"
+	"regexp"
+	"strings"
+
+	"feed-bower-api/pkg/httpclient"
+)
+
+// Feed candidate sources
+const (
+	FeedCandidateSourceDirect = "direct"      // The URL itself is a feed
+	FeedCandidateSourceLink   = "link"        // <link rel="alternate"> in the page
+	FeedCandidateSourcePath   = "common_path" // A well-known feed path on the site
+)
+
+// maxDiscoveryPageSize limits how much of an HTML page is read when looking for feed links
+const maxDiscoveryPageSize = 2 * 1024 * 1024 // 2MB
+
+// FeedCandidate is a feed found while discovering feeds for a web page
+type FeedCandidate struct {
+	URL    string `json:"url"`
+	Title  string `json:"title,omitempty"`
+	Type   string `json:"type,omitempty"`
+	Source string `json:"source"`
+}
+
+// feedLinkTypes are the MIME types of <link rel="alternate"> tags that point to feeds
+var feedLinkTypes = map[string]bool{
+	"application/rss+xml":   true,
+	"application/atom+xml":  true,
+	"application/feed+json": true,
+	"application/rdf+xml":   true,
+}
+
+// commonFeedPaths are probed, in order, when a page does not advertise its feeds
+var commonFeedPaths = []string{
+	"/feed",
+	"/rss.xml",
+	"/atom.xml",
+	"/feed.xml",
+	"/index.xml",
+	"/rss",
+	"/feed.json",
+}
+
+var (
+	linkTagRegex  = regexp.MustCompile(`(?is)<link\b[^>]*>`)
+	baseTagRegex  = regexp.MustCompile(`(?is)<base\b[^>]*>`)
+	htmlAttrRegex = regexp.MustCompile(`(?s)([a-zA-Z_:][-a-zA-Z0-9_:.]*)\s*=\s*("[^"]*"|'[^']*'|[^\s"'>]+)`)
+)
+
+// DiscoverFeeds finds feeds for a URL. If the URL is already a feed it is returned as the only candidate;
+// otherwise the page's <link rel="alternate"> tags are used, falling back to probing common feed paths.
+func (s *rssService) DiscoverFeeds(ctx context.Context, pageURL string) ([]FeedCandidate, error) {
+	if pageURL == "" {
+		return nil, errors.New("page URL is required")
+	}
+
+	config := httpclient.DefaultSecureHTTPConfig()
+	if err := httpclient.ValidateURL(pageURL, config); err != nil {
+		return nil, fmt.Errorf("invalid page URL: %w", err)
+	}
+
+	headers := map[string]string{
+		"Accept": "text/html, application/xhtml+xml, application/rss+xml, application/atom+xml, application/feed+json;q=0.9, */*;q=0.8",
+	}
+
+	resp, err := s.secureClient.Do(ctx, "GET", pageURL, headers)
+	if err != nil {
+		return nil, fmt.Errorf("failed to fetch page: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		return nil, &HTTPError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxDiscoveryPageSize))
+	if err != nil {
+		return nil, fmt.Errorf("failed to read page: %w", err)
+	}
+
+	// The URL may already be a feed
+	contentType := resp.Header.Get("Content-Type")
+	if !isHTMLDocument(body, contentType) {
+		if feedData, err := s.parseFeed(body, contentType, nil); err == nil {
+			return []FeedCandidate{{URL: pageURL, Title: feedData.Title, Source: FeedCandidateSourceDirect}}, nil
+		}
+	}
+
+	if candidates := ExtractFeedLinks(body, pageURL); len(candidates) > 0 {
+		return candidates, nil
+	}
+
+	// Probe well-known paths on the site, stopping at the first working feed
+	for _, candidateURL := range commonFeedPathURLs(pageURL) {
+		if ctx.Err() != nil {
+			return nil, ctx.Err()
+		}
+		feedInfo, err := s.FetchFeedInfo(ctx, candidateURL)
+		if err != nil {
+			continue
+		}
+		return []FeedCandidate{{URL: candidateURL, Title: feedInfo.Title, Source: FeedCandidateSourcePath}}, nil
+	}
+
+	return []FeedCandidate{}, nil
+}
+
+// ExtractFeedLinks returns the feeds advertised by <link rel="alternate"> tags in an HTML page,
+// resolving relative hrefs against the page URL (or its <base href>)
+func ExtractFeedLinks(page []byte, pageURL string) []FeedCandidate {
+	candidates := make([]FeedCandidate, 0)
+
+	base, err := url.Parse(pageURL)
+	if err != nil {
+		return candidates
+	}
+	if tag := baseTagRegex.Find(page); tag != nil {
+		if href := parseHTMLAttrs(tag)["href"]; href != "" {
+			if baseHref, err := base.Parse(href); err == nil {
+				base = baseHref
+			}
+		}
+	}
+
+	seen := make(map[string]bool)
+	for _, tag := range linkTagRegex.FindAll(page, -1) {
+		attrs := parseHTMLAttrs(tag)
+
+		if !hasRelToken(attrs["rel"], "alternate") {
+			continue
+		}
+		linkType := strings.ToLower(strings.TrimSpace(strings.SplitN(attrs["type"], ";", 2)[0]))
+		if !feedLinkTypes[linkType] || attrs["href"] == "" {
+			continue
+		}
+
+		resolved, err := base.Parse(attrs["href"])
+		if err != nil || (resolved.Scheme != "http" && resolved.Scheme != "https") {
+			continue
+		}
+		resolved.Fragment = ""
+
+		feedURL := resolved.String()
+		if seen[feedURL] {
+			continue
+		}
+		seen[feedURL] = true
+
+		candidates = append(candidates, FeedCandidate{
+			URL:    feedURL,
+			Title:  strings.TrimSpace(attrs["title"]),
+			Type:   linkType,
+			Source: FeedCandidateSourceLink,
+		})
+	}
+
+	return candidates
+}
+
+// commonFeedPathURLs returns the well-known feed URLs to probe for a site
+func commonFeedPathURLs(pageURL string) []string {
+	parsed, err := url.Parse(pageURL)
+	if err != nil || parsed.Host == "" {
+		return nil
+	}
+
+	urls := make([]string, 0, len(commonFeedPaths))
+	for _, path := range commonFeedPaths {
+		candidate := url.URL{Scheme: parsed.Scheme, Host: parsed.Host, Path: path}
+		urls = append(urls, candidate.String())
+	}
+	return urls
+}
+
+// parseHTMLAttrs parses the attributes of a single HTML tag, lower-casing names and unescaping values
+func parseHTMLAttrs(tag []byte) map[string]string {
+	attrs := make(map[string]string)
+	for _, match := range htmlAttrRegex.FindAllSubmatch(tag, -1) {
+		name := strings.ToLower(string(match[1]))
+		if _, exists := attrs[name]; exists {
+			continue
+		}
+		value := string(match[2])
+		if len(value) >= 2 && (value[0] == '"' || value[0] == '\'') {
+			value = value[1 : len(value)-1]
+		}
+		attrs[name] = html.UnescapeString(value)
+	}
+	return attrs
+}
+
+// hasRelToken reports whether a space-separated rel attribute contains token
+func hasRelToken(rel string, token string) bool {
+	for _, value := range strings.Fields(rel) {
+		if strings.EqualFold(value, token) {
+			return true
+		}
+	}
+	return false
+}
+
+// isHTMLDocument reports whether a response body is an HTML page rather than a feed
+func isHTMLDocument(body []byte, contentType string) bool {
+	contentType = strings.ToLower(contentType)
+	if strings.Contains(contentType, "text/html") || strings.Contains(contentType, "application/xhtml") {
+		return true
+	}
+
+	head := body
+	if len(head) > 1024 {
+		head = head[:1024]
+	}
+	head = bytes.ToLower(head)
+	return bytes.Contains(head, []byte("<!doctype html")) || bytes.Contains(head, []byte("<html"))
+}
@@ -0,0 +1,105 @@
+package service
+
+import (
+	"context"
+	"testing"
+)
+
+const testDiscoveryPage = `<!DOCTYPE html>
+<html>
+<head>
+  <title>Example Blog</title>
+  <link rel="stylesheet" href="/style.css">
+  <link rel="alternate" type="application/rss+xml" title="Example RSS" href="/rss.xml">
+  <LINK REL="Alternate" TYPE="application/atom+xml" TITLE="Example &amp; Friends" HREF="https://example.com/atom.xml#top">
+  <link href='feed.json' type='application/feed+json' rel='alternate'>
+  <link rel="alternate" type="text/html" hreflang="ja" href="/ja/">
+  <link rel="alternate" type="application/rss+xml" href="/rss.xml">
+  <link rel="alternate" type="application/rss+xml" href="javascript:alert(1)">
+</head>
+<body></body>
+</html>`
+
+func TestExtractFeedLinks(t *testing.T) {
+	candidates := ExtractFeedLinks([]byte(testDiscoveryPage), "https://example.com/blog/")
+
+	expected := []FeedCandidate{
+		{URL: "https://example.com/rss.xml", Title: "Example RSS", Type: "application/rss+xml", Source: FeedCandidateSourceLink},
+		{URL: "https://example.com/atom.xml", Title: "Example & Friends", Type: "application/atom+xml", Source: FeedCandidateSourceLink},
+		{URL: "https://example.com/blog/feed.json", Type: "application/feed+json", Source: FeedCandidateSourceLink},
+	}
+
+	if len(candidates) != len(expected) {
+		t.Fatalf("Expected %d candidates, got %d: %+v", len(expected), len(candidates), candidates)
+	}
+	for i, candidate := range candidates {
+		if candidate != expected[i] {
+			t.Errorf("Candidate %d = %+v, expected %+v", i, candidate, expected[i])
+		}
+	}
+}
+
+func TestExtractFeedLinks_BaseHref(t *testing.T) {
+	page := `<html><head><base href="https://cdn.example.com/site/"><link rel="alternate" type="application/atom+xml" href="atom.xml"></head></html>`
+
+	candidates := ExtractFeedLinks([]byte(page), "https://example.com/")
+	if len(candidates) != 1 || candidates[0].URL != "https://cdn.example.com/site/atom.xml" {
+		t.Errorf("Expected href to resolve against <base>, got %+v", candidates)
+	}
+}
+
+func TestCommonFeedPathURLs(t *testing.T) {
+	urls := commonFeedPathURLs("https://example.com/blog/post?id=1")
+
+	if len(urls) != len(commonFeedPaths) {
+		t.Fatalf("Expected %d URLs, got %d", len(commonFeedPaths), len(urls))
+	}
+	if urls[0] != "https://example.com/feed" || urls[1] != "https://example.com/rss.xml" {
+		t.Errorf("Expected probes on the site root, got %v", urls)
+	}
+}
+
+func TestIsHTMLDocument(t *testing.T) {
+	tests := []struct {
+		name        string
+		body        string
+		contentType string
+		expected    bool
+	}{
+		{"html content type", `<rss></rss>`, "text/html; charset=utf-8", true},
+		{"html doctype", `<!DOCTYPE html><html></html>`, "", true},
+		{"rss feed", `<?xml version="1.0"?><rss version="2.0"></rss>`, "application/xml", false},
+		{"json feed", `{"version":"https://jsonfeed.org/version/1.1"}`, "application/feed+json", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := isHTMLDocument([]byte(tt.body), tt.contentType); got != tt.expected {
+				t.Errorf("isHTMLDocument() = %v, expected %v", got, tt.expected)
+			}
+		})
+	}
+}
+
+// stubDiscoveryRSSService returns fixed discovery candidates
+type stubDiscoveryRSSService struct {
+	RSSService
+	candidates []FeedCandidate
+}
+
+func (s *stubDiscoveryRSSService) DiscoverFeeds(ctx context.Context, pageURL string) ([]FeedCandidate, error) {
+	return s.candidates, nil
+}
+
+func TestFeedService_DiscoverFeedCandidates_SkipsDirect(t *testing.T) {
+	rss := &stubDiscoveryRSSService{candidates: []FeedCandidate{
+		{URL: "https://example.com/", Source: FeedCandidateSourceDirect},
+		{URL: "https://example.com/feed", Source: FeedCandidateSourcePath},
+	}}
+	service := &feedService{rssService: rss}
+
+	candidates := service.discoverFeedCandidates(context.Background(), "https://example.com/")
+	if len(candidates) != 1 || candidates[0].URL != "https://example.com/feed" {
+		t.Errorf("Expected only the discovered feed, got %+v", candidates)
+	}
+}
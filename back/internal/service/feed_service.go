@@ -25,7 +25,7 @@ type FeedService interface {
 	GetFeedByID(ctx context.Context, feedID string, userID string) (*model.Feed, error)
 	GetFeedsByBowerID(ctx context.Context, bowerID string, userID string) ([]*model.Feed, error)
 	UpdateFeed(ctx context.Context, userID string, feedID string, req *UpdateFeedRequest) (*model.Feed, error)
-	DeleteFeed(ctx context.Context, userID string, feedID string) error
+	DeleteFeed(ctx context.Context, userID string, feedID string, bowerID string) error
 
 	// Feed preview and validation
 	PreviewFeed(ctx context.Context, userID string, feedURL string) (*FeedPreview, error)
@@ -50,20 +50,23 @@ type AddFeedRequest struct {
 	URL     string `json:"url" validate:"required,url"`
 }
 
-// UpdateFeedRequest represents the request to update a feed
+// UpdateFeedRequest represents the request to update a bower's subscription to a feed.
+// Title, category and full text mode are stored on the subscription; the shared feed is not changed.
 type UpdateFeedRequest struct {
-	URL         *string `json:"url,omitempty" validate:"omitempty,url"`
-	Title       *string `json:"title,omitempty" validate:"omitempty,min=1,max=200"`
-	Description *string `json:"description,omitempty" validate:"omitempty,max=1000"`
-	Category    *string `json:"category,omitempty" validate:"omitempty,max=50"`
+	BowerID  string  `json:"bower_id,omitempty"` // Required only if the feed is in several of the user's bowers
+	URL      *string `json:"url,omitempty" validate:"omitempty,url"`
+	Title    *string `json:"title,omitempty" validate:"omitempty,min=1,max=200"`
+	Category *string `json:"category,omitempty" validate:"omitempty,max=50"`
+	FullText *bool   `json:"full_text,omitempty"` // Extract the full content of new articles from their pages
 }
 
 // FeedPreview represents a preview of a feed with sample articles
 type FeedPreview struct {
-	Feed     *FeedPreviewInfo `json:"feed"`
-	Articles []ArticlePreview `json:"articles"`
-	IsValid  bool             `json:"is_valid"`
-	Error    string           `json:"error,omitempty"`
+	Feed       *FeedPreviewInfo `json:"feed"`
+	Articles   []ArticlePreview `json:"articles"`
+	IsValid    bool             `json:"is_valid"`
+	Error      string           `json:"error,omitempty"`
+	Candidates []FeedCandidate  `json:"candidates,omitempty"` // Feeds discovered when the URL was a web page
 }
 
 // FeedPreviewInfo represents basic feed information for preview
@@ -193,22 +196,55 @@ func (s *feedService) AddFeed(ctx context.Context, userID string, req *AddFeedRe
 		}
 	}
 
-	// Fetch feed information from RSS
-	feedInfo, err := s.rssService.FetchFeedInfo(ctx, req.URL)
-	if err != nil {
-		return nil, fmt.Errorf("failed to fetch feed information: %w", err)
+	// Reuse the canonical feed if another bower already subscribes to this URL
+	feed, err := s.feedRepo.GetByURL(ctx, req.URL)
+	if err != nil && !isNotFoundError(err) {
+		return nil, fmt.Errorf("failed to look up feed: %w", err)
 	}
 
-	// Create feed
-	feed := model.NewFeed(req.BowerID, req.URL, feedInfo.Title, feedInfo.Description, feedInfo.Category)
+	if feed == nil {
+		// Fetch feed information from RSS
+		feedURL := req.URL
+		feedInfo, err := s.rssService.FetchFeedInfo(ctx, feedURL)
+		if err != nil {
+			// The URL may be a web page that advertises its feeds
+			candidates := s.discoverFeedCandidates(ctx, req.URL)
+			if len(candidates) == 0 {
+				return nil, fmt.Errorf("failed to fetch feed information: %w", err)
+			}
+
+			feedURL = candidates[0].URL
+			for _, existingFeed := range existingFeeds {
+				if existingFeed.URL == feedURL {
+					return nil, errors.New("feed URL already exists in this bower")
+				}
+			}
 
-	err = s.feedRepo.Create(ctx, feed)
+			feedInfo, err = s.rssService.FetchFeedInfo(ctx, feedURL)
+			if err != nil {
+				return nil, fmt.Errorf("failed to fetch feed information: %w", err)
+			}
+
+			log.Printf("[AddFeed] DISCOVERED | user_id=%s | bower_id=%s | page_url=%s | feed_url=%s | candidates=%d",
+				userID, req.BowerID, req.URL, feedURL, len(candidates))
+		}
+
+		feed = model.NewFeed(req.BowerID, feedURL, feedInfo.Title, feedInfo.Description, feedInfo.Category)
+	}
+
+	// Subscribe the bower, creating the canonical feed if this is its first subscriber
+	feed, created, err := subscribeBower(ctx, s.feedRepo, req.BowerID, feed)
 	if err != nil {
-		return nil, fmt.Errorf("failed to create feed: %w", err)
+		return nil, err
 	}
 
-	log.Printf("[AddFeed] SUCCESS | user_id=%s | bower_id=%s | feed_id=%s | url=%s | title=%s",
-		userID, req.BowerID, feed.FeedID, feed.URL, feed.Title)
+	log.Printf("[AddFeed] SUCCESS | user_id=%s | bower_id=%s | feed_id=%s | url=%s | title=%s | new_feed=%t",
+		userID, req.BowerID, feed.FeedID, feed.URL, feed.Title, created)
+
+	// Articles of an existing feed are already stored and kept fresh by the scheduler
+	if !created {
+		return feed, nil
+	}
 
 	// Fetch articles for the newly added feed in background
 	go func() {
@@ -235,22 +271,18 @@ func (s *feedService) GetFeedByID(ctx context.Context, feedID string, userID str
 		return nil, errors.New("user ID is required")
 	}
 
-	feed, err := s.feedRepo.GetByID(ctx, feedID)
+	// Check if user has access through one of the subscribing bowers
+	subscription, _, err := findFeedSubscription(ctx, s.feedRepo, s.bowerRepo, feedID, userID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get feed: %w", err)
+		return nil, err
 	}
 
-	// Check if user has access through bower ownership
-	bower, err := s.bowerRepo.GetByID(ctx, feed.BowerID)
+	feed, err := s.feedRepo.GetByID(ctx, feedID)
 	if err != nil {
-		return nil, fmt.Errorf("failed to get bower: %w", err)
-	}
-
-	if bower.UserID != userID && !bower.IsPublic {
-		return nil, errors.New("access denied: bower is private")
+		return nil, fmt.Errorf("failed to get feed: %w", err)
 	}
 
-	return feed, nil
+	return subscription.ApplyTo(feed), nil
 }
 
 // GetFeedsByBowerID retrieves all feeds for a bower
@@ -280,7 +312,8 @@ func (s *feedService) GetFeedsByBowerID(ctx context.Context, bowerID string, use
 	return feeds, nil
 }
 
-// UpdateFeed updates an existing feed
+// UpdateFeed updates a bower's subscription to a feed. Changing the URL moves the subscription to
+// the canonical feed for the new URL.
 func (s *feedService) UpdateFeed(ctx context.Context, userID string, feedID string, req *UpdateFeedRequest) (*model.Feed, error) {
 	if userID == "" {
 		return nil, errors.New("user ID is required")
@@ -292,81 +325,117 @@ func (s *feedService) UpdateFeed(ctx context.Context, userID string, feedID stri
 		return nil, errors.New("update feed request is required")
 	}
 
-	// Get existing feed
+	// Check if user has access through bower ownership
+	subscription, bower, err := findOwnedSubscription(ctx, s.feedRepo, s.bowerRepo, userID, req.BowerID, feedID)
+	if err != nil {
+		return nil, err
+	}
+
 	feed, err := s.feedRepo.GetByID(ctx, feedID)
 	if err != nil {
 		return nil, fmt.Errorf("failed to get feed: %w", err)
 	}
 
-	// Store original URL to check if it changed
-	originalURL := feed.URL
+	// Apply updates
+	if req.Title != nil {
+		if *req.Title == "" {
+			return nil, errors.New("feed title cannot be empty")
+		}
+		subscription.Title = *req.Title
+	}
 
-	// Check if user has access through bower ownership
-	bower, err := s.bowerRepo.GetByID(ctx, feed.BowerID)
-	if err != nil {
-		return nil, fmt.Errorf("failed to get bower: %w", err)
+	if req.Category != nil {
+		subscription.Category = *req.Category
 	}
 
-	if bower.UserID != userID {
-		return nil, errors.New("access denied: not bower owner")
+	if req.FullText != nil {
+		subscription.FullText = *req.FullText
 	}
 
-	// Apply updates
-	if req.URL != nil {
+	if req.URL != nil && *req.URL != feed.URL {
 		if *req.URL == "" {
 			return nil, errors.New("feed URL cannot be empty")
 		}
-		// Validate new URL
-		if err := s.ValidateFeedURL(*req.URL); err != nil {
-			return nil, fmt.Errorf("invalid feed URL: %w", err)
-		}
-		feed.URL = *req.URL
+		return s.moveSubscription(ctx, userID, bower, subscription, feed, *req.URL)
 	}
 
-	if req.Title != nil {
-		if *req.Title == "" {
-			return nil, errors.New("feed title cannot be empty")
-		}
-		feed.Title = *req.Title
+	// Update subscription
+	err = s.feedRepo.UpdateSubscription(ctx, subscription)
+	if err != nil {
+		return nil, fmt.Errorf("failed to update feed: %w", err)
 	}
 
-	if req.Description != nil {
-		feed.Description = *req.Description
+	log.Printf("[UpdateFeed] SUCCESS | user_id=%s | bower_id=%s | feed_id=%s | title=%s",
+		userID, bower.BowerID, feedID, subscription.Title)
+
+	return subscription.ApplyTo(feed), nil
+}
+
+// moveSubscription replaces a bower's subscription with one to the feed at newURL, keeping its overrides
+func (s *feedService) moveSubscription(ctx context.Context, userID string, bower *model.Bower, subscription *model.Subscription, feed *model.Feed, newURL string) (*model.Feed, error) {
+	// Validate new URL
+	if err := s.ValidateFeedURL(newURL); err != nil {
+		return nil, fmt.Errorf("invalid feed URL: %w", err)
 	}
 
-	if req.Category != nil {
-		feed.Category = *req.Category
+	newFeed, err := s.feedRepo.GetByURL(ctx, newURL)
+	if err != nil && !isNotFoundError(err) {
+		return nil, fmt.Errorf("failed to look up feed: %w", err)
 	}
 
-	// Update feed
-	err = s.feedRepo.Update(ctx, feed)
+	if newFeed == nil {
+		feedInfo, err := s.rssService.FetchFeedInfo(ctx, newURL)
+		if err != nil {
+			return nil, fmt.Errorf("failed to fetch feed information: %w", err)
+		}
+		newFeed = model.NewFeed(bower.BowerID, newURL, feedInfo.Title, feedInfo.Description, feedInfo.Category)
+	}
+
+	newView, created, err := subscribeBower(ctx, s.feedRepo, bower.BowerID, newFeed)
 	if err != nil {
-		return nil, fmt.Errorf("failed to update feed: %w", err)
+		return nil, err
+	}
+
+	// Carry the bower's overrides over to the new subscription
+	if subscription.Title != "" || subscription.Category != "" || subscription.FullText {
+		moved := model.NewSubscription(bower.BowerID, newView.FeedID)
+		moved.Title = subscription.Title
+		moved.Category = subscription.Category
+		moved.FullText = subscription.FullText
+		if err := s.feedRepo.UpdateSubscription(ctx, moved); err != nil {
+			return nil, fmt.Errorf("failed to update feed: %w", err)
+		}
+		newView = moved.ApplyTo(newView)
 	}
 
-	log.Printf("[UpdateFeed] SUCCESS | user_id=%s | feed_id=%s | url=%s | title=%s",
-		userID, feedID, feed.URL, feed.Title)
+	if err := s.unsubscribe(ctx, bower.BowerID, feed.FeedID); err != nil {
+		return nil, err
+	}
 
-	// If URL was changed, fetch articles for the updated feed in background
-	if req.URL != nil && *req.URL != originalURL {
+	log.Printf("[UpdateFeed] MOVED | user_id=%s | bower_id=%s | old_feed_id=%s | new_feed_id=%s | new_url=%s",
+		userID, bower.BowerID, feed.FeedID, newView.FeedID, newView.URL)
+
+	// Fetch articles for a newly created feed in background
+	if created {
 		go func() {
 			bgCtx := context.Background()
-			log.Printf("[UpdateFeed] FETCH_ARTICLES_START | user_id=%s | feed_id=%s | new_url=%s", userID, feedID, feed.URL)
-			if err := s.fetchFeedArticles(bgCtx, feed); err != nil {
+			log.Printf("[UpdateFeed] FETCH_ARTICLES_START | user_id=%s | feed_id=%s | new_url=%s", userID, newView.FeedID, newView.URL)
+			if err := s.fetchFeedArticles(bgCtx, newView); err != nil {
 				log.Printf("[UpdateFeed] FETCH_ARTICLES_FAILED | user_id=%s | feed_id=%s | error=%v",
-					userID, feedID, err)
+					userID, newView.FeedID, err)
 			} else {
 				log.Printf("[UpdateFeed] FETCH_ARTICLES_SUCCESS | user_id=%s | feed_id=%s",
-					userID, feedID)
+					userID, newView.FeedID)
 			}
 		}()
 	}
 
-	return feed, nil
+	return newView, nil
 }
 
-// DeleteFeed deletes a feed from a bower
-func (s *feedService) DeleteFeed(ctx context.Context, userID string, feedID string) error {
+// DeleteFeed removes a feed from a bower. bowerID may be empty if the feed is in only one of the user's bowers.
+// The shared feed is deleted once no bower subscribes to it; its articles are left for orphan cleanup.
+func (s *feedService) DeleteFeed(ctx context.Context, userID string, feedID string, bowerID string) error {
 	if userID == "" {
 		return errors.New("user ID is required")
 	}
@@ -374,37 +443,38 @@ func (s *feedService) DeleteFeed(ctx context.Context, userID string, feedID stri
 		return errors.New("feed ID is required")
 	}
 
-	// Get existing feed
-	feed, err := s.feedRepo.GetByID(ctx, feedID)
-	if err != nil {
-		return fmt.Errorf("failed to get feed: %w", err)
-	}
-
 	// Check if user has access through bower ownership
-	bower, err := s.bowerRepo.GetByID(ctx, feed.BowerID)
+	_, bower, err := findOwnedSubscription(ctx, s.feedRepo, s.bowerRepo, userID, bowerID, feedID)
 	if err != nil {
-		return fmt.Errorf("failed to get bower: %w", err)
-	}
-
-	if bower.UserID != userID {
-		return errors.New("access denied: not bower owner")
+		return err
 	}
 
 	// Check if this is the last feed in the bower
-	feeds, err := s.feedRepo.GetByBowerID(ctx, feed.BowerID)
+	subscriptions, err := s.feedRepo.GetSubscriptionsByBowerID(ctx, bower.BowerID)
 	if err != nil {
 		return fmt.Errorf("failed to check bower feeds: %w", err)
 	}
 
-	if len(feeds) <= 1 {
+	if len(subscriptions) <= 1 {
 		return errors.New("cannot delete the last feed in a bower")
 	}
 
-	// Delete feed
-	err = s.feedRepo.Delete(ctx, feedID)
+	return s.unsubscribe(ctx, bower.BowerID, feedID)
+}
+
+// unsubscribe removes a bower's subscription and deletes the feed if it has no subscribers left
+func (s *feedService) unsubscribe(ctx context.Context, bowerID string, feedID string) error {
+	if err := s.feedRepo.Unsubscribe(ctx, bowerID, feedID); err != nil {
+		return fmt.Errorf("failed to delete feed: %w", err)
+	}
+
+	deleted, err := s.feedRepo.DeleteIfUnsubscribed(ctx, feedID)
 	if err != nil {
 		return fmt.Errorf("failed to delete feed: %w", err)
 	}
+	if deleted {
+		log.Printf("[DeleteFeed] FEED_DELETED | bower_id=%s | feed_id=%s | reason=no_subscribers", bowerID, feedID)
+	}
 
 	return nil
 }
@@ -431,13 +501,25 @@ func (s *feedService) PreviewFeed(ctx context.Context, userID string, feedURL st
 
 	// Fetch feed information and articles
 	feedData, err := s.rssService.FetchFeed(ctx, feedURL)
+
+	// The URL may be a web page that advertises its feeds; preview the first one found
+	var candidates []FeedCandidate
+	if err != nil {
+		candidates = s.discoverFeedCandidates(ctx, feedURL)
+		if len(candidates) > 0 {
+			feedURL = candidates[0].URL
+			feedData, err = s.rssService.FetchFeed(ctx, feedURL)
+		}
+	}
+
 	if err != nil {
 		return &FeedPreview{
 			Feed: &FeedPreviewInfo{
 				URL: feedURL,
 			},
-			IsValid: false,
-			Error:   fmt.Sprintf("Failed to fetch feed: %v", err),
+			IsValid:    false,
+			Error:      fmt.Sprintf("Failed to fetch feed: %v", err),
+			Candidates: candidates,
 		}, nil
 	}
 
@@ -470,11 +552,31 @@ func (s *feedService) PreviewFeed(ctx context.Context, userID string, feedURL st
 			URL:         feedURL,
 			Category:    feedData.Category,
 		},
-		Articles: articles,
-		IsValid:  true,
+		Articles:   articles,
+		IsValid:    true,
+		Candidates: candidates,
 	}, nil
 }
 
+// discoverFeedCandidates looks for feeds advertised by a web page.
+// Discovery failures are logged and reported as no candidates.
+func (s *feedService) discoverFeedCandidates(ctx context.Context, pageURL string) []FeedCandidate {
+	candidates, err := s.rssService.DiscoverFeeds(ctx, pageURL)
+	if err != nil {
+		log.Printf("[DiscoverFeeds] FAILED | url=%s | error=%v", pageURL, err)
+		return nil
+	}
+
+	// A direct candidate means the URL is itself a feed, which has already failed to fetch
+	filtered := make([]FeedCandidate, 0, len(candidates))
+	for _, candidate := range candidates {
+		if candidate.URL != pageURL {
+			filtered = append(filtered, candidate)
+		}
+	}
+	return filtered
+}
+
 // ValidateFeedURL validates a feed URL format and accessibility
 func (s *feedService) ValidateFeedURL(feedURL string) error {
 	if feedURL == "" {
@@ -1015,10 +1117,11 @@ func (s *feedService) AutoRegisterFeeds(ctx context.Context, userID string, bowe
 			// Update feed with fetched information
 			feed := model.NewFeed(bowerID, recommendation.URL, feedInfo.Title, feedInfo.Description, feedInfo.Category)
 
-			// Create feed in repository
-			if err := s.feedRepo.Create(feedCtx, feed); err != nil {
+			// Subscribe the bower, creating the canonical feed if needed
+			feed, _, err = subscribeBower(feedCtx, s.feedRepo, bowerID, feed)
+			if err != nil {
 				result.status = "failed"
-				result.reason = fmt.Sprintf("failed to create feed: %v", err)
+				result.reason = err.Error()
 				log.Printf("[AutoRegisterFeeds] CREATE_FAILED | user_id=%s | bower_id=%s | url=%s | error=%v",
 					userID, bowerID, recommendation.URL, err)
 				resultChan <- result
@@ -1188,43 +1291,26 @@ func (s *feedService) FetchBowerFeeds(ctx context.Context, userID string, bowerI
 		log.Printf("[FetchBowerFeeds] FETCHED | user_id=%s | bower_id=%s | feed_id=%s | articles=%d",
 			userID, bowerID, feed.FeedID, len(feedData.Articles))
 
-		// Save articles to DynamoDB
+		// Save new articles to DynamoDB, merging duplicates of other feeds' articles
 		if len(feedData.Articles) > 0 {
-			// Check for duplicates and save new articles
-			newArticles := make([]*model.Article, 0)
-			for _, articleData := range feedData.Articles {
-				// Check if article already exists by URL
-				existing, err := s.articleRepo.GetByURL(ctx, articleData.URL)
-				if err == nil && existing != nil {
-					// Article already exists, skip
-					continue
+			articles := ConvertToArticles(feed.FeedID, feedData.Articles)
+			articles = filterIngestedArticles(ctx, s.feedRepo, s.bowerRepo, feed.FeedID, articles)
+			saved, err := saveFetchedArticles(ctx, s.articleRepo, feed.FeedID, articles)
+			if err != nil {
+				log.Printf("[FetchBowerFeeds] SAVE_FAILED | user_id=%s | bower_id=%s | feed_id=%s | error=%v",
+					userID, bowerID, feed.FeedID, err)
+			} else {
+				if saved.Created > 0 || saved.Merged > 0 {
+					log.Printf("[FetchBowerFeeds] SAVED | user_id=%s | bower_id=%s | feed_id=%s | saved=%d | merged=%d",
+						userID, bowerID, feed.FeedID, saved.Created, saved.Merged)
 				}
-
-				// Convert ArticleData to model.Article
-				article := &model.Article{
-					FeedID:      feed.FeedID,
-					Title:       articleData.Title,
-					Content:     articleData.Content,
-					URL:         articleData.URL,
-					PublishedAt: articleData.PublishedAt.Unix(),
-					ImageURL:    articleData.ImageURL,
-					CreatedAt:   time.Now().Unix(),
+				if extracted := extractFullContent(ctx, s.rssService, s.feedRepo, s.articleRepo, feed.FeedID, saved.Articles); extracted > 0 {
+					log.Printf("[FetchBowerFeeds] FULL_TEXT | user_id=%s | bower_id=%s | feed_id=%s | extracted=%d",
+						userID, bowerID, feed.FeedID, extracted)
 				}
-				newArticles = append(newArticles, article)
-			}
-
-			if len(newArticles) > 0 {
-				log.Printf("[FetchBowerFeeds] SAVING | user_id=%s | bower_id=%s | feed_id=%s | new_articles=%d",
-					userID, bowerID, feed.FeedID, len(newArticles))
-
-				// Batch create articles
-				err = s.articleRepo.BatchCreate(ctx, newArticles)
-				if err != nil {
-					log.Printf("[FetchBowerFeeds] SAVE_FAILED | user_id=%s | bower_id=%s | feed_id=%s | error=%v",
-						userID, bowerID, feed.FeedID, err)
-				} else {
-					log.Printf("[FetchBowerFeeds] SAVED | user_id=%s | bower_id=%s | feed_id=%s | saved=%d",
-						userID, bowerID, feed.FeedID, len(newArticles))
+				if found := fillPageImages(ctx, s.rssService, s.articleRepo, saved.Articles); found > 0 {
+					log.Printf("[FetchBowerFeeds] PAGE_IMAGES | user_id=%s | bower_id=%s | feed_id=%s | found=%d",
+						userID, bowerID, feed.FeedID, found)
 				}
 			}
 		}
@@ -338,6 +338,10 @@ func (m *MockRSSServiceWithError) FetchFeed(ctx context.Context, feedURL string)
 	}, nil
 }
 
+func (m *MockRSSServiceWithError) FetchFeedWithOptions(ctx context.Context, feedURL string, opts *FetchOptions) (*FeedData, error) {
+	return m.FetchFeed(ctx, feedURL)
+}
+
 func (m *MockRSSServiceWithError) FetchFeedInfo(ctx context.Context, feedURL string) (*FeedInfo, error) {
 	if m.shouldFail {
 		return nil, errors.New("feed fetch error")
@@ -349,6 +353,22 @@ func (m *MockRSSServiceWithError) FetchFeedInfo(ctx context.Context, feedURL str
 	}, nil
 }
 
+func (m *MockRSSServiceWithError) DiscoverFeeds(ctx context.Context, pageURL string) ([]FeedCandidate, error) {
+	return []FeedCandidate{}, nil
+}
+
+func (m *MockRSSServiceWithError) FetchFullContent(ctx context.Context, articleURL string) (string, error) {
+	return "", errors.New("full text extraction not supported")
+}
+
+func (m *MockRSSServiceWithError) FetchPageImage(ctx context.Context, pageURL string) (string, error) {
+	return "", errNoPageImage
+}
+
+func (m *MockRSSServiceWithError) ParseFeed(data []byte, contentType string, feedURL string) (*FeedData, error) {
+	return &FeedData{}, nil
+}
+
 func (m *MockRSSServiceWithError) ParseRSSFeed(data []byte) (*FeedData, error) {
 	return &FeedData{}, nil
 }
@@ -361,6 +381,10 @@ func (m *MockRSSServiceWithError) ParseRDFFeed(data []byte) (*FeedData, error) {
 	return &FeedData{}, nil
 }
 
+func (m *MockRSSServiceWithError) ParseJSONFeed(data []byte) (*FeedData, error) {
+	return &FeedData{}, nil
+}
+
 func (m *MockRSSServiceWithError) ExtractImageURL(content string) string {
 	return ""
 }
@@ -0,0 +1,393 @@
+package service
+
+import (
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"io"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"feed-bower-api/pkg/charset"
+)
+
+// xmlFeedFormat describes an XML feed format
+type xmlFeedFormat struct {
+	name      string // Used in error messages
+	root      string // Local name of the root element
+	namespace string // Namespace of the channel and item elements, besides no namespace
+}
+
+// atomNamespace is the namespace of Atom elements, also used for WebSub links in RSS feeds
+const atomNamespace = "http://www.w3.org/2005/Atom"
+
+var (
+	rssFormat  = &xmlFeedFormat{name: "RSS", root: "rss"}
+	atomFormat = &xmlFeedFormat{name: "Atom", root: "feed", namespace: atomNamespace}
+	rdfFormat  = &xmlFeedFormat{name: "RDF", root: "RDF", namespace: "http://purl.org/rss/1.0/"}
+)
+
+// errStopFeed stops reading a feed once its newest items have been read
+var errStopFeed = errors.New("newest feed items read")
+
+// streamXMLFeed parses an XML feed one item at a time, so that only the items kept are held in
+// memory. The format is detected from the root element if it is nil. When maxItems is positive,
+// only the newest maxItems items are kept, and reading stops as soon as they have been read from
+// a feed listing its newest items first; channel elements after them are then not read.
+func (s *rssService) streamXMLFeed(r io.Reader, format *xmlFeedFormat, base *url.URL, maxItems int) (*FeedData, error) {
+	decoder := xml.NewDecoder(r)
+	decoder.CharsetReader = charset.NewReader
+
+	root, err := rootElement(decoder)
+	if err != nil {
+		if format == nil {
+			return nil, fmt.Errorf("failed to parse feed XML: %w", err)
+		}
+		return nil, fmt.Errorf("failed to parse %s XML: %w", format.name, err)
+	}
+
+	if format == nil {
+		for _, f := range []*xmlFeedFormat{rssFormat, atomFormat, rdfFormat} {
+			if strings.EqualFold(root.Name.Local, f.root) {
+				format = f
+				break
+			}
+		}
+		if format == nil {
+			return nil, fmt.Errorf("unsupported feed format: <%s>", root.Name.Local)
+		}
+	} else if !strings.EqualFold(root.Name.Local, format.root) {
+		return nil, fmt.Errorf("failed to parse %s XML: expected element type <%s> but have <%s>", format.name, format.root, root.Name.Local)
+	}
+
+	stream := &feedStream{
+		service: s,
+		decoder: decoder,
+		format:  format,
+		space:   root.Name.Space,
+		feed:    &FeedData{},
+		items:   &itemCollector{limit: maxItems, ordered: true},
+	}
+
+	switch format {
+	case rssFormat:
+		err = stream.readRSS()
+	case atomFormat:
+		err = stream.readAtom()
+	default:
+		err = stream.readRDF()
+	}
+	if err != nil && err != errStopFeed {
+		return nil, fmt.Errorf("failed to parse %s XML: %w", format.name, err)
+	}
+
+	return stream.finish(base), nil
+}
+
+// rootElement returns the root element of an XML document
+func rootElement(decoder *xml.Decoder) (xml.StartElement, error) {
+	for {
+		token, err := decoder.Token()
+		if err == io.EOF {
+			return xml.StartElement{}, errors.New("no root element")
+		}
+		if err != nil {
+			return xml.StartElement{}, err
+		}
+		if start, ok := token.(xml.StartElement); ok {
+			return start, nil
+		}
+	}
+}
+
+// feedStream reads the channel and items of an XML feed
+type feedStream struct {
+	service *rssService
+	decoder *xml.Decoder
+	format  *xmlFeedFormat
+	space   string // Namespace of the root element
+	feed    *FeedData
+	link    string // Link of the website, the base for relative item URLs
+	hub     string // WebSub hub link
+	self    string // Self link, the WebSub topic
+	items   *itemCollector
+}
+
+// readRSS reads the channel of an RSS 2.0 feed
+func (p *feedStream) readRSS() error {
+	return p.readChildren(func(start xml.StartElement) error {
+		if start.Name.Local != "channel" {
+			return p.decoder.Skip()
+		}
+
+		return p.readChildren(func(start xml.StartElement) error {
+			switch start.Name.Local {
+			case "item":
+				var item Item
+				if err := p.decoder.DecodeElement(&item, &start); err != nil {
+					return err
+				}
+				return p.add(item.PubDate, func(base *url.URL) (*ArticleData, error) {
+					return p.service.parseRSSItem(item, base)
+				})
+			case "title":
+				return p.decodeText(&p.feed.Title, start)
+			case "description":
+				return p.decodeText(&p.feed.Description, start)
+			case "link":
+				if start.Name.Space == atomNamespace {
+					var link AtomLink
+					if err := p.decoder.DecodeElement(&link, &start); err != nil {
+						return err
+					}
+					p.addWebSubLink(link)
+					return nil
+				}
+				return p.decodeText(&p.link, start)
+			case "category":
+				return p.decodeText(&p.feed.Category, start)
+			case "ttl":
+				var ttl string
+				if err := p.decodeText(&ttl, start); err != nil {
+					return err
+				}
+				if minutes, err := strconv.Atoi(strings.TrimSpace(ttl)); err == nil && minutes > 0 {
+					p.feed.TTL = minutes
+				}
+				return nil
+			case "skipHours":
+				return p.readChildren(func(start xml.StartElement) error {
+					var hour string
+					if err := p.decodeText(&hour, start); err != nil || start.Name.Local != "hour" {
+						return err
+					}
+					if h, err := strconv.Atoi(strings.TrimSpace(hour)); err == nil && h >= 0 && h < 24 {
+						p.feed.SkipHours = append(p.feed.SkipHours, h)
+					}
+					return nil
+				})
+			case "skipDays":
+				return p.readChildren(func(start xml.StartElement) error {
+					var day string
+					if err := p.decodeText(&day, start); err != nil || start.Name.Local != "day" {
+						return err
+					}
+					if day = strings.TrimSpace(day); day != "" {
+						p.feed.SkipDays = append(p.feed.SkipDays, day)
+					}
+					return nil
+				})
+			}
+			return p.decoder.Skip()
+		})
+	})
+}
+
+// readAtom reads an Atom feed
+func (p *feedStream) readAtom() error {
+	linkFound := false
+	return p.readChildren(func(start xml.StartElement) error {
+		switch start.Name.Local {
+		case "entry":
+			var entry AtomEntry
+			if err := p.decoder.DecodeElement(&entry, &start); err != nil {
+				return err
+			}
+			date := entry.Published
+			if date == "" {
+				date = entry.Updated
+			}
+			return p.add(date, func(base *url.URL) (*ArticleData, error) {
+				return p.service.parseAtomEntry(entry, base)
+			})
+		case "title":
+			return p.decodeText(&p.feed.Title, start)
+		case "subtitle":
+			return p.decodeText(&p.feed.Description, start)
+		case "link":
+			var link AtomLink
+			if err := p.decoder.DecodeElement(&link, &start); err != nil {
+				return err
+			}
+			p.addWebSubLink(link)
+			// The main link is the first alternate link
+			if !linkFound && (link.Rel == "alternate" || link.Rel == "") {
+				p.link = link.Href
+				linkFound = true
+			}
+			return nil
+		}
+		return p.decoder.Skip()
+	})
+}
+
+// readRDF reads an RSS 1.0 / RDF feed
+func (p *feedStream) readRDF() error {
+	return p.readChildren(func(start xml.StartElement) error {
+		switch start.Name.Local {
+		case "item":
+			var item RDFItem
+			if err := p.decoder.DecodeElement(&item, &start); err != nil {
+				return err
+			}
+			return p.add(item.Date, func(base *url.URL) (*ArticleData, error) {
+				return p.service.parseRDFItem(item, base)
+			})
+		case "channel":
+			var channel RDFChannel
+			if err := p.decoder.DecodeElement(&channel, &start); err != nil {
+				return err
+			}
+			p.feed.Title = channel.Title
+			p.feed.Description = channel.Description
+			p.link = channel.Link
+			for _, link := range channel.AtomLinks {
+				p.addWebSubLink(link)
+			}
+			return nil
+		}
+		return p.decoder.Skip()
+	})
+}
+
+// addWebSubLink records the first hub and self links of the feed (https://www.w3.org/TR/websub/#discovery)
+func (p *feedStream) addWebSubLink(link AtomLink) {
+	href := strings.TrimSpace(link.Href)
+	if href == "" {
+		return
+	}
+	for _, rel := range strings.Fields(strings.ToLower(link.Rel)) {
+		switch {
+		case rel == "hub" && p.hub == "":
+			p.hub = href
+		case rel == "self" && p.self == "":
+			p.self = href
+		}
+	}
+}
+
+// readChildren calls fn with each child element of the current element, until the element ends.
+// fn must consume the child element. Elements in namespaces other than the feed's own, such as
+// itunes:title in a channel, are skipped, except for Atom links.
+func (p *feedStream) readChildren(fn func(start xml.StartElement) error) error {
+	for {
+		token, err := p.decoder.Token()
+		if err == io.EOF {
+			return io.ErrUnexpectedEOF
+		}
+		if err != nil {
+			return err
+		}
+
+		switch t := token.(type) {
+		case xml.StartElement:
+			own := t.Name.Space == "" || t.Name.Space == p.space || t.Name.Space == p.format.namespace
+			if !own && !(t.Name.Space == atomNamespace && t.Name.Local == "link") {
+				err = p.decoder.Skip()
+			} else {
+				err = fn(t)
+			}
+			if err != nil {
+				return err
+			}
+		case xml.EndElement:
+			return nil
+		}
+	}
+}
+
+// decodeText decodes the text of an element into target
+func (p *feedStream) decodeText(target *string, start xml.StartElement) error {
+	return p.decoder.DecodeElement(target, &start)
+}
+
+// add collects an item, returning errStopFeed once the newest items have been read
+func (p *feedStream) add(date string, parse func(base *url.URL) (*ArticleData, error)) error {
+	item := streamedItem{parse: parse}
+	if date != "" {
+		if published, err := p.service.parseDate(strings.TrimSpace(date)); err == nil {
+			item.published = published
+		}
+	}
+
+	if p.items.add(item) {
+		return errStopFeed
+	}
+	return nil
+}
+
+// finish parses the collected items, resolving relative URLs against base and the feed's link
+func (p *feedStream) finish(base *url.URL) *FeedData {
+	feedData := p.feed
+	feedData.Title = p.service.CleanContent(feedData.Title)
+	feedData.Description = p.service.CleanContent(feedData.Description)
+	feedData.Category = p.service.CleanContent(feedData.Category)
+	feedData.URL = p.link
+	feedData.HubURL = resolveLink(base, p.hub)
+	feedData.SelfURL = resolveLink(base, p.self)
+	feedData.Articles = make([]ArticleData, 0, len(p.items.items))
+
+	base = contentBase(base, p.link)
+	for _, item := range p.items.items {
+		article, err := item.parse(base)
+		if err != nil {
+			// Skip invalid articles but continue processing
+			continue
+		}
+		feedData.Articles = append(feedData.Articles, *article)
+	}
+
+	return feedData
+}
+
+// streamedItem is a decoded feed item, parsed once the base for its relative URLs is known
+type streamedItem struct {
+	published time.Time // Zero if the item is undated
+	parse     func(base *url.URL) (*ArticleData, error)
+}
+
+// itemCollector keeps the newest items of a feed, in feed order
+type itemCollector struct {
+	limit   int // Items kept; no limit if 0
+	items   []streamedItem
+	ordered bool      // Whether dated items have been newest first so far
+	last    time.Time // Date of the last dated item
+}
+
+// add adds an item and reports whether the newest items have been read. Undated items do not
+// affect the ordering.
+func (c *itemCollector) add(item streamedItem) bool {
+	if !item.published.IsZero() {
+		if !c.last.IsZero() && item.published.After(c.last) {
+			c.ordered = false
+		}
+		c.last = item.published
+	}
+
+	c.items = append(c.items, item)
+	if c.limit <= 0 {
+		return false
+	}
+	if len(c.items) > c.limit {
+		c.removeOldest()
+	}
+
+	return c.ordered && len(c.items) >= c.limit
+}
+
+// removeOldest removes the oldest dated item, or the last item if none are dated.
+// Undated items are treated as new, as they are published now.
+func (c *itemCollector) removeOldest() {
+	oldest := len(c.items) - 1
+	for i, item := range c.items {
+		if item.published.IsZero() {
+			continue
+		}
+		if c.items[oldest].published.IsZero() || !item.published.After(c.items[oldest].published) {
+			oldest = i
+		}
+	}
+	c.items = append(c.items[:oldest], c.items[oldest+1:]...)
+}
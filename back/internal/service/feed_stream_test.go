@@ -0,0 +1,236 @@
+package service
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+)
+
+// rssWithItems builds an RSS feed with an item for each date, followed by the given trailer
+func rssWithItems(dates []time.Time, trailer string) []byte {
+	var b strings.Builder
+	b.WriteString(`<?xml version="1.0" encoding="UTF-8"?><rss version="2.0"><channel><title>Archive</title><link>https://example.com/</link>`)
+	for i, date := range dates {
+		fmt.Fprintf(&b, "<item><title>Item %d</title><link>/items/%d</link>", i, i)
+		if !date.IsZero() {
+			fmt.Fprintf(&b, "<pubDate>%s</pubDate>", date.Format(time.RFC1123Z))
+		}
+		b.WriteString("</item>")
+	}
+	b.WriteString(trailer)
+	return []byte(b.String())
+}
+
+func articleTitles(articles []ArticleData) []string {
+	titles := make([]string, len(articles))
+	for i, article := range articles {
+		titles[i] = article.Title
+	}
+	return titles
+}
+
+func TestRSSService_StreamXMLFeed_NewestFirst(t *testing.T) {
+	s := NewRSSService().(*rssService)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	dates := make([]time.Time, 1000)
+	for i := range dates {
+		dates[i] = start.Add(-time.Duration(i) * time.Hour)
+	}
+	// The rest of the feed is never read, so it doesn't matter that it is broken
+	data := rssWithItems(dates, "<item><title>broken")
+
+	feedData, err := s.streamXMLFeed(bytes.NewReader(data), nil, nil, 3)
+	if err != nil {
+		t.Fatalf("streamXMLFeed() unexpected error: %v", err)
+	}
+
+	if got := strings.Join(articleTitles(feedData.Articles), ","); got != "Item 0,Item 1,Item 2" {
+		t.Errorf("Expected the first three items, got %s", got)
+	}
+	if feedData.Title != "Archive" {
+		t.Errorf("Expected channel title 'Archive', got %q", feedData.Title)
+	}
+	if feedData.Articles[1].URL != "https://example.com/items/1" {
+		t.Errorf("Expected the link to be resolved against the channel link, got %q", feedData.Articles[1].URL)
+	}
+}
+
+func TestRSSService_StreamXMLFeed_OldestFirst(t *testing.T) {
+	s := NewRSSService().(*rssService)
+	start := time.Date(2024, 1, 1, 0, 0, 0, 0, time.UTC)
+
+	dates := make([]time.Time, 10)
+	for i := range dates {
+		dates[i] = start.Add(time.Duration(i) * time.Hour)
+	}
+	// Undated items are treated as new
+	dates[4] = time.Time{}
+	data := rssWithItems(dates, "</channel></rss>")
+
+	feedData, err := s.streamXMLFeed(bytes.NewReader(data), nil, nil, 3)
+	if err != nil {
+		t.Fatalf("streamXMLFeed() unexpected error: %v", err)
+	}
+
+	if got := strings.Join(articleTitles(feedData.Articles), ","); got != "Item 4,Item 8,Item 9" {
+		t.Errorf("Expected the newest items in feed order, got %s", got)
+	}
+
+	// Without a limit every item is kept, and a broken feed is an error
+	feedData, err = s.streamXMLFeed(bytes.NewReader(data), nil, nil, 0)
+	if err != nil {
+		t.Fatalf("streamXMLFeed() unexpected error: %v", err)
+	}
+	if len(feedData.Articles) != 10 {
+		t.Errorf("Expected 10 articles, got %d", len(feedData.Articles))
+	}
+	if _, err := s.streamXMLFeed(bytes.NewReader(rssWithItems(dates, "")), nil, nil, 0); err == nil {
+		t.Error("Expected an error for an unterminated feed")
+	}
+}
+
+func TestRSSService_StreamXMLFeed_Formats(t *testing.T) {
+	s := NewRSSService().(*rssService)
+
+	tests := []struct {
+		name  string
+		data  string
+		title string
+	}{
+		{
+			name:  "Atom",
+			data:  `<feed xmlns="http://www.w3.org/2005/Atom"><title>Atom Feed</title><link rel="self" href="https://example.com/feed"/><link href="https://example.com/"/><entry><title>Entry</title><link href="/entry"/><updated>2024-01-01T00:00:00Z</updated></entry></feed>`,
+			title: "Atom Feed",
+		},
+		{
+			name:  "RDF",
+			data:  `<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/"><channel><title>RDF Feed</title><link>https://example.com/</link></channel><item><title>Entry</title><link>/entry</link></item></rdf:RDF>`,
+			title: "RDF Feed",
+		},
+		{
+			name:  "RSS with namespaced channel elements",
+			data:  `<rss xmlns:itunes="http://www.itunes.com/dtds/podcast-1.0.dtd" xmlns:atom="http://www.w3.org/2005/Atom"><channel><title>RSS Feed</title><itunes:title>Podcast</itunes:title><link>https://example.com/</link><atom:link rel="self" href="https://example.com/feed"/><item><title>Entry</title><link>/entry</link></item></channel></rss>`,
+			title: "RSS Feed",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feedData, err := s.parseFeed([]byte(tt.data), "application/xml", nil)
+			if err != nil {
+				t.Fatalf("parseFeed() unexpected error: %v", err)
+			}
+			if feedData.Title != tt.title {
+				t.Errorf("Expected title %q, got %q", tt.title, feedData.Title)
+			}
+			if feedData.URL != "https://example.com/" {
+				t.Errorf("Expected feed URL 'https://example.com/', got %q", feedData.URL)
+			}
+			if len(feedData.Articles) != 1 || feedData.Articles[0].URL != "https://example.com/entry" {
+				t.Errorf("Expected one article linking to https://example.com/entry, got %+v", feedData.Articles)
+			}
+		})
+	}
+
+	if _, err := s.parseFeed([]byte(`<html><body>Not a feed</body></html>`), "text/html", nil); err == nil {
+		t.Error("Expected an error for an HTML page")
+	}
+	if _, err := s.ParseAtomFeed([]byte(tests[0].data)); err != nil {
+		t.Errorf("ParseAtomFeed() unexpected error: %v", err)
+	}
+	if _, err := s.ParseRSSFeed([]byte(tests[0].data)); err == nil {
+		t.Error("Expected ParseRSSFeed to reject an Atom feed")
+	}
+}
+
+func TestRSSService_ReadFeed(t *testing.T) {
+	s := NewRSSService().(*rssService)
+
+	// Shift_JIS feeds are decoded as they are streamed
+	data, err := os.ReadFile(filepath.Join("..", "..", "testdata", "charset", "shift_jis.xml"))
+	if err != nil {
+		t.Fatalf("Failed to read fixture: %v", err)
+	}
+	feedData, err := s.readFeed(bytes.NewReader(data), "application/rss+xml", nil, defaultMaxFeedItems)
+	if err != nil {
+		t.Fatalf("readFeed() unexpected error: %v", err)
+	}
+	if feedData.Title != "日本語のフィード" {
+		t.Errorf("Expected the Shift_JIS title to be decoded, got %q", feedData.Title)
+	}
+
+	// A JSON Feed of exactly the maximum size is read
+	jsonFeed := []byte(`{"version":"https://jsonfeed.org/version/1.1","title":"JSON Feed","items":[]}`)
+	exact := append(jsonFeed, bytes.Repeat([]byte(" "), maxFeedSize-len(jsonFeed))...)
+	feedData, err = s.readFeed(bytes.NewReader(exact), "application/feed+json", nil, defaultMaxFeedItems)
+	if err != nil {
+		t.Fatalf("readFeed() unexpected error for a feed of the maximum size: %v", err)
+	}
+	if feedData.Title != "JSON Feed" {
+		t.Errorf("Expected title 'JSON Feed', got %q", feedData.Title)
+	}
+
+	_, err = s.readFeed(bytes.NewReader(append(exact, ' ')), "application/feed+json", nil, defaultMaxFeedItems)
+	if !errors.Is(err, errFeedTooLarge) {
+		t.Errorf("Expected errFeedTooLarge for a feed over the maximum size, got %v", err)
+	}
+}
+
+func TestRSSService_ParseWebSubLinks(t *testing.T) {
+	s := NewRSSService().(*rssService)
+	base, _ := url.Parse("https://example.com/feeds/main.xml")
+
+	tests := []struct {
+		name string
+		data string
+		hub  string
+		self string
+	}{
+		{
+			name: "RSS",
+			data: `<rss xmlns:atom="http://www.w3.org/2005/Atom"><channel><title>RSS</title><link>https://example.com/</link><atom:link rel="hub" href="https://hub.example.com/"/><atom:link rel="self" href="https://example.com/feeds/main.xml"/></channel></rss>`,
+			hub:  "https://hub.example.com/",
+			self: "https://example.com/feeds/main.xml",
+		},
+		{
+			name: "Atom",
+			data: `<feed xmlns="http://www.w3.org/2005/Atom"><title>Atom</title><link rel="self" href="/feeds/atom.xml"/><link rel="hub" href="https://pubsubhubbub.appspot.com/"/><link rel="alternate" href="https://example.com/"/></feed>`,
+			hub:  "https://pubsubhubbub.appspot.com/",
+			self: "https://example.com/feeds/atom.xml",
+		},
+		{
+			name: "RDF",
+			data: `<rdf:RDF xmlns:rdf="http://www.w3.org/1999/02/22-rdf-syntax-ns#" xmlns="http://purl.org/rss/1.0/" xmlns:atom="http://www.w3.org/2005/Atom"><channel><title>RDF</title><atom:link rel="hub" href="https://hub.example.com/"/><link>https://example.com/</link></channel></rdf:RDF>`,
+			hub:  "https://hub.example.com/",
+		},
+		{
+			name: "No hub",
+			data: `<rss><channel><title>RSS</title><link>https://example.com/</link></channel></rss>`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feedData, err := s.parseFeed([]byte(tt.data), "application/xml", base)
+			if err != nil {
+				t.Fatalf("parseFeed() unexpected error: %v", err)
+			}
+			if feedData.HubURL != tt.hub {
+				t.Errorf("Expected hub %q, got %q", tt.hub, feedData.HubURL)
+			}
+			if feedData.SelfURL != tt.self {
+				t.Errorf("Expected self %q, got %q", tt.self, feedData.SelfURL)
+			}
+			if feedData.URL != "https://example.com/" {
+				t.Errorf("Expected the hub link not to be read as the feed URL, got %q", feedData.URL)
+			}
+		})
+	}
+}
@@ -0,0 +1,235 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"feed-bower-api/internal/model"
+	"feed-bower-api/internal/repository"
+)
+
+// errFeedNotFound is returned when a feed that should exist is missing
+var errFeedNotFound = errors.New("feed not found")
+
+// subscribeBower subscribes a bower to a feed. A feed without an ID is matched to the canonical feed
+// for its URL, and is created as that canonical feed if there is none yet. The canonical feed's ID is
+// derived from the URL, so a concurrent subscription creating the same feed makes the create fail
+// and this one subscribes to the other's feed.
+// It returns the feed as seen from the bower and whether a canonical feed was created.
+func subscribeBower(ctx context.Context, feedRepo repository.FeedRepository, bowerID string, feed *model.Feed) (*model.Feed, bool, error) {
+	canonical := feed
+	created := false
+
+	if canonical.FeedID == "" {
+		existing, err := feedRepo.GetByURL(ctx, feed.URL)
+		if err != nil && !isNotFoundError(err) {
+			return nil, false, fmt.Errorf("failed to look up feed: %w", err)
+		}
+
+		if existing != nil {
+			canonical = existing
+		} else {
+			canonical.BowerID = ""
+			canonical.FeedID = model.CanonicalFeedID(canonical.URL)
+			err := feedRepo.Create(ctx, canonical)
+			switch {
+			case err == nil:
+				created = true
+			case strings.Contains(err.Error(), "already exists"):
+				existing, err := feedRepo.GetByID(ctx, canonical.FeedID)
+				if err != nil {
+					return nil, false, fmt.Errorf("failed to get feed: %w", err)
+				}
+				if existing == nil {
+					return nil, false, fmt.Errorf("failed to get feed %s: %w", canonical.FeedID, errFeedNotFound)
+				}
+				canonical = existing
+			default:
+				return nil, false, fmt.Errorf("failed to create feed: %w", err)
+			}
+		}
+
+		// A new subscriber gets a dead feed fetched again instead of waiting for its next probe
+		if canonical.IsDead() {
+			canonical.ResetHealth()
+			if err := feedRepo.Update(ctx, canonical); err != nil {
+				return nil, false, fmt.Errorf("failed to reset feed health: %w", err)
+			}
+		}
+	}
+
+	subscription := model.NewSubscription(bowerID, canonical.FeedID)
+	if err := feedRepo.Subscribe(ctx, subscription); err != nil {
+		if strings.Contains(err.Error(), "already exists") {
+			return nil, false, errors.New("feed URL already exists in this bower")
+		}
+		return nil, false, fmt.Errorf("failed to subscribe to feed: %w", err)
+	}
+
+	return subscription.ApplyTo(canonical), created, nil
+}
+
+// findFeedSubscription returns the subscription and bower through which a user sees a feed:
+// one of the user's own bowers if possible, otherwise a public one
+func findFeedSubscription(ctx context.Context, feedRepo repository.FeedRepository, bowerRepo repository.BowerRepository, feedID string, userID string) (*model.Subscription, *model.Bower, error) {
+	bowers, err := listUserBowers(ctx, bowerRepo, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	owned, err := getOwnedSubscriptions(ctx, feedRepo, bowers, feedID)
+	if err != nil {
+		return nil, nil, err
+	}
+	if len(owned) > 0 {
+		return owned[0].subscription, owned[0].bower, nil
+	}
+
+	return findPublicSubscription(ctx, feedRepo, bowerRepo, feedID)
+}
+
+// findOwnedSubscription returns the subscription of one of the user's bowers to a feed.
+// bowerID may be empty if the feed is in only one of the user's bowers.
+func findOwnedSubscription(ctx context.Context, feedRepo repository.FeedRepository, bowerRepo repository.BowerRepository, userID string, bowerID string, feedID string) (*model.Subscription, *model.Bower, error) {
+	if bowerID != "" {
+		bower, err := bowerRepo.GetByID(ctx, bowerID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get bower: %w", err)
+		}
+		if bower.UserID != userID {
+			return nil, nil, errors.New("access denied: not bower owner")
+		}
+
+		subscription, err := feedRepo.GetSubscription(ctx, bowerID, feedID)
+		if err != nil {
+			return nil, nil, fmt.Errorf("failed to get feed: %w", err)
+		}
+		return subscription, bower, nil
+	}
+
+	bowers, err := listUserBowers(ctx, bowerRepo, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	owned, err := getOwnedSubscriptions(ctx, feedRepo, bowers, feedID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	switch len(owned) {
+	case 0:
+		feed, err := feedRepo.GetByID(ctx, feedID)
+		if err != nil || feed == nil {
+			return nil, nil, fmt.Errorf("feed with ID %s not found", feedID)
+		}
+		return nil, nil, errors.New("access denied: not bower owner")
+	case 1:
+		return owned[0].subscription, owned[0].bower, nil
+	default:
+		return nil, nil, errors.New("bower ID is required: feed is in several of the user's bowers")
+	}
+}
+
+// findArticleSubscription returns the subscription and bower through which a user sees an article,
+// trying each feed the article was merged from
+func findArticleSubscription(ctx context.Context, feedRepo repository.FeedRepository, bowerRepo repository.BowerRepository, article *model.Article, userID string) (*model.Subscription, *model.Bower, error) {
+	bowers, err := listUserBowers(ctx, bowerRepo, userID)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	for _, feedID := range article.FeedIDs() {
+		owned, err := getOwnedSubscriptions(ctx, feedRepo, bowers, feedID)
+		if err != nil {
+			return nil, nil, err
+		}
+		if len(owned) > 0 {
+			return owned[0].subscription, owned[0].bower, nil
+		}
+	}
+
+	var firstErr error
+	for _, feedID := range article.FeedIDs() {
+		subscription, bower, err := findPublicSubscription(ctx, feedRepo, bowerRepo, feedID)
+		if err == nil {
+			return subscription, bower, nil
+		}
+		if firstErr == nil {
+			firstErr = err
+		}
+	}
+	return nil, nil, firstErr
+}
+
+// ownedSubscription is a subscription of one of the user's bowers
+type ownedSubscription struct {
+	subscription *model.Subscription
+	bower        *model.Bower
+}
+
+// listUserBowers returns all bowers of a user. Users have few bowers, so looking up their
+// subscriptions is cheaper than loading every subscription of a popular feed.
+func listUserBowers(ctx context.Context, bowerRepo repository.BowerRepository, userID string) ([]*model.Bower, error) {
+	if userID == "" {
+		return nil, nil
+	}
+
+	var bowers []*model.Bower
+	var lastKey map[string]types.AttributeValue
+	for {
+		page, nextKey, err := bowerRepo.GetByUserID(ctx, userID, 100, lastKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get user bowers: %w", err)
+		}
+		bowers = append(bowers, page...)
+		if nextKey == nil {
+			return bowers, nil
+		}
+		lastKey = nextKey
+	}
+}
+
+// getOwnedSubscriptions returns the subscriptions of the given bowers to a feed
+func getOwnedSubscriptions(ctx context.Context, feedRepo repository.FeedRepository, bowers []*model.Bower, feedID string) ([]ownedSubscription, error) {
+	var owned []ownedSubscription
+	for _, bower := range bowers {
+		subscription, err := feedRepo.GetSubscription(ctx, bower.BowerID, feedID)
+		if err != nil {
+			if isNotFoundError(err) {
+				continue
+			}
+			return nil, fmt.Errorf("failed to get subscription: %w", err)
+		}
+		owned = append(owned, ownedSubscription{subscription: subscription, bower: bower})
+	}
+	return owned, nil
+}
+
+// findPublicSubscription returns a subscription of a public bower to a feed
+func findPublicSubscription(ctx context.Context, feedRepo repository.FeedRepository, bowerRepo repository.BowerRepository, feedID string) (*model.Subscription, *model.Bower, error) {
+	subscriptions, err := feedRepo.GetSubscriptionsByFeedID(ctx, feedID)
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to get feed subscriptions: %w", err)
+	}
+
+	if len(subscriptions) == 0 {
+		return nil, nil, fmt.Errorf("feed with ID %s not found", feedID)
+	}
+
+	for _, subscription := range subscriptions {
+		bower, err := bowerRepo.GetByID(ctx, subscription.BowerID)
+		if err != nil || bower == nil {
+			continue
+		}
+		if bower.IsPublic {
+			return subscription, bower, nil
+		}
+	}
+
+	return nil, nil, errors.New("access denied: bower is private")
+}
@@ -0,0 +1,183 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"testing"
+	"time"
+
+	"feed-bower-api/internal/model"
+)
+
+func TestSubscribeBower_SharesCanonicalFeed(t *testing.T) {
+	ctx := context.Background()
+	feedRepo := NewMockFeedRepository()
+
+	first, created, err := subscribeBower(ctx, feedRepo, "bower-1", model.NewFeed("bower-1", "https://example.com/feed.xml", "Example", "", "tech"))
+	if err != nil || !created {
+		t.Fatalf("Expected the first subscription to create the feed, got created=%v err=%v", created, err)
+	}
+
+	second, created, err := subscribeBower(ctx, feedRepo, "bower-2", model.NewFeed("bower-2", "https://example.com/feed.xml", "Example", "", "tech"))
+	if err != nil || created {
+		t.Fatalf("Expected the second subscription to reuse the feed, got created=%v err=%v", created, err)
+	}
+
+	if first.FeedID != second.FeedID {
+		t.Errorf("Expected one canonical feed, got %s and %s", first.FeedID, second.FeedID)
+	}
+	if second.BowerID != "bower-2" {
+		t.Errorf("Expected the returned feed to be seen from bower-2, got %s", second.BowerID)
+	}
+	if len(feedRepo.feeds) != 1 || feedRepo.feeds[first.FeedID].SubscriberCount != 2 {
+		t.Errorf("Expected one feed with two subscribers, got %d feeds", len(feedRepo.feeds))
+	}
+
+	if _, _, err := subscribeBower(ctx, feedRepo, "bower-1", model.NewFeed("bower-1", "https://example.com/feed.xml", "Example", "", "")); err == nil ||
+		err.Error() != "feed URL already exists in this bower" {
+		t.Errorf("Expected duplicate subscription error, got %v", err)
+	}
+
+	// A new subscriber revives a dead feed
+	feedRepo.feeds[first.FeedID].HealthStatus = model.FeedHealthDead
+	feedRepo.feeds[first.FeedID].ConsecutiveFailures = model.MaxConsecutiveFailures
+	third, _, err := subscribeBower(ctx, feedRepo, "bower-3", model.NewFeed("bower-3", "https://example.com/feed.xml", "Example", "", ""))
+	if err != nil {
+		t.Fatalf("Unexpected error: %v", err)
+	}
+	if third.IsDead() || third.ConsecutiveFailures != 0 || !third.IsDue(time.Now()) {
+		t.Errorf("Expected the dead feed to be reset and due, got %+v", third)
+	}
+}
+
+func TestSubscribeBower_ConcurrentCreate(t *testing.T) {
+	ctx := context.Background()
+	feedRepo := NewMockFeedRepository()
+
+	// Another subscription created the canonical feed after this one's URL lookup missed it
+	other := model.NewFeed("", "https://Example.com/feed.xml", "Example", "", "tech")
+	other.FeedID = model.CanonicalFeedID(other.URL)
+	other.HealthStatus = model.FeedHealthDead
+	other.ConsecutiveFailures = model.MaxConsecutiveFailures
+	feedRepo.Create(ctx, other)
+
+	feed, created, err := subscribeBower(ctx, feedRepo, "bower-1", model.NewFeed("bower-1", "https://example.com/feed.xml", "Example", "", "tech"))
+	if err != nil || created {
+		t.Fatalf("Expected the existing feed to be reused, got created=%v err=%v", created, err)
+	}
+	if feed.FeedID != other.FeedID || len(feedRepo.feeds) != 1 || feedRepo.feeds[other.FeedID].SubscriberCount != 1 {
+		t.Errorf("Expected one canonical feed with one subscriber, got %d feeds", len(feedRepo.feeds))
+	}
+	if stored := feedRepo.feeds[other.FeedID]; stored.IsDead() || stored.ConsecutiveFailures != 0 {
+		t.Errorf("Expected the dead feed to be revived, got %+v", stored)
+	}
+
+	// The feed that made the create fail is gone by the time it is read
+	conflictRepo := &createConflictFeedRepository{NewMockFeedRepository()}
+	if _, _, err := subscribeBower(ctx, conflictRepo, "bower-1", model.NewFeed("bower-1", "https://example.com/other.xml", "Other", "", "")); !errors.Is(err, errFeedNotFound) {
+		t.Errorf("Expected a feed not found error, got %v", err)
+	}
+}
+
+// createConflictFeedRepository reports that every feed it is asked to create already exists
+type createConflictFeedRepository struct {
+	*MockFeedRepository
+}
+
+func (r *createConflictFeedRepository) Create(ctx context.Context, feed *model.Feed) error {
+	return fmt.Errorf("feed with ID %s already exists", feed.FeedID)
+}
+
+func TestFindFeedSubscription_Access(t *testing.T) {
+	ctx := context.Background()
+	repos := NewMockRepositories()
+
+	privateBower := model.NewBower("owner-1", "Private", []string{"go"}, nil, "#14b8a6", false)
+	repos.BowerRepo.Create(ctx, privateBower)
+	publicBower := model.NewBower("owner-2", "Public", []string{"go"}, nil, "#14b8a6", true)
+	repos.BowerRepo.Create(ctx, publicBower)
+
+	feed := model.NewFeed(privateBower.BowerID, "https://example.com/feed.xml", "Example", "", "tech")
+	repos.FeedRepo.Create(ctx, feed)
+
+	if _, _, err := findFeedSubscription(ctx, repos.FeedRepo, repos.BowerRepo, feed.FeedID, "stranger"); err == nil ||
+		err.Error() != "access denied: bower is private" {
+		t.Errorf("Expected access denied for a feed only in private bowers, got %v", err)
+	}
+
+	repos.FeedRepo.Subscribe(ctx, model.NewSubscription(publicBower.BowerID, feed.FeedID))
+
+	_, bower, err := findFeedSubscription(ctx, repos.FeedRepo, repos.BowerRepo, feed.FeedID, "stranger")
+	if err != nil || bower.BowerID != publicBower.BowerID {
+		t.Errorf("Expected access through the public bower, got %v, %v", bower, err)
+	}
+
+	// The owner's own bower is found without listing every subscriber of the feed
+	ownerRepo := &subscriberListFailingFeedRepository{repos.FeedRepo}
+	_, bower, err = findFeedSubscription(ctx, ownerRepo, repos.BowerRepo, feed.FeedID, "owner-1")
+	if err != nil || bower.BowerID != privateBower.BowerID {
+		t.Errorf("Expected the owner's own bower to be preferred, got %v, %v", bower, err)
+	}
+}
+
+// subscriberListFailingFeedRepository fails to list the subscribers of a feed
+type subscriberListFailingFeedRepository struct {
+	*MockFeedRepository
+}
+
+func (r *subscriberListFailingFeedRepository) GetSubscriptionsByFeedID(ctx context.Context, feedID string) ([]*model.Subscription, error) {
+	return nil, errors.New("unexpected subscriber listing")
+}
+
+func TestFeedService_SubscriptionOverridesAndDelete(t *testing.T) {
+	ctx := context.Background()
+	repos := NewMockRepositories()
+	feedService := NewFeedService(repos.FeedRepo, repos.BowerRepo, repos.ArticleRepo, NewMockRSSService(), nil)
+
+	tech := model.NewBower("user-1", "Tech", []string{"go"}, nil, "#14b8a6", false)
+	repos.BowerRepo.Create(ctx, tech)
+	news := model.NewBower("user-1", "News", []string{"news"}, nil, "#14b8a6", false)
+	repos.BowerRepo.Create(ctx, news)
+
+	shared := model.NewFeed(tech.BowerID, "https://example.com/feed.xml", "Example", "", "tech")
+	repos.FeedRepo.Create(ctx, shared)
+	repos.FeedRepo.Subscribe(ctx, model.NewSubscription(news.BowerID, shared.FeedID))
+	repos.FeedRepo.Create(ctx, model.NewFeed(tech.BowerID, "https://example.com/other.xml", "Other", "", ""))
+	repos.FeedRepo.Create(ctx, model.NewFeed(news.BowerID, "https://example.com/more.xml", "More", "", ""))
+
+	title := "My Example"
+	updated, err := feedService.UpdateFeed(ctx, "user-1", shared.FeedID, &UpdateFeedRequest{BowerID: tech.BowerID, Title: &title})
+	if err != nil {
+		t.Fatalf("UpdateFeed() unexpected error: %v", err)
+	}
+	if updated.Title != "My Example" || repos.FeedRepo.feeds[shared.FeedID].Title != "Example" {
+		t.Errorf("Expected the title override to apply to the subscription only, got %s / %s",
+			updated.Title, repos.FeedRepo.feeds[shared.FeedID].Title)
+	}
+
+	newsFeeds, _ := repos.FeedRepo.GetByBowerID(ctx, news.BowerID)
+	for _, feed := range newsFeeds {
+		if feed.FeedID == shared.FeedID && feed.Title != "Example" {
+			t.Errorf("Expected the other bower to keep the feed's own title, got %s", feed.Title)
+		}
+	}
+
+	if err := feedService.DeleteFeed(ctx, "user-1", shared.FeedID, ""); err == nil {
+		t.Error("Expected DeleteFeed without a bower ID to fail for a feed in several of the user's bowers")
+	}
+
+	if err := feedService.DeleteFeed(ctx, "user-1", shared.FeedID, tech.BowerID); err != nil {
+		t.Fatalf("DeleteFeed() unexpected error: %v", err)
+	}
+	if feed := repos.FeedRepo.feeds[shared.FeedID]; feed == nil || feed.SubscriberCount != 1 {
+		t.Error("Expected the shared feed to remain for the other bower")
+	}
+
+	if err := feedService.DeleteFeed(ctx, "user-1", shared.FeedID, ""); err != nil {
+		t.Fatalf("DeleteFeed() unexpected error: %v", err)
+	}
+	if repos.FeedRepo.feeds[shared.FeedID] != nil {
+		t.Error("Expected the feed to be deleted after its last subscription was removed")
+	}
+}
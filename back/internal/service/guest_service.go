@@ -0,0 +1,201 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"feed-bower-api/internal/model"
+	"feed-bower-api/internal/repository"
+)
+
+// defaultGuestMaxAgeDays is how long guest users are kept by default after they were last active
+const defaultGuestMaxAgeDays = 30
+
+// GuestService defines the interface for managing temporary guest users
+type GuestService interface {
+	ExpireGuests(ctx context.Context, opts *ExpireGuestsOptions) (*ExpireGuestsSummary, error)
+}
+
+// ExpireGuestsOptions controls an ExpireGuests run
+type ExpireGuestsOptions struct {
+	MaxAgeDays int  `json:"max_age_days,omitempty"` // Guests inactive for this long are deleted; 30 if 0
+	DryRun     bool `json:"-"`                      // Count what would be deleted without deleting it
+}
+
+// ExpireGuestsSummary is the structured result of an ExpireGuests run. In a dry run, the guest
+// and bower counts are what would have been deleted, and nothing else is counted.
+type ExpireGuestsSummary struct {
+	DryRun          bool  `json:"dry_run"`
+	Cutoff          int64 `json:"cutoff"` // Guests last active before this time are expired
+	UsersChecked    int   `json:"users_checked"`
+	GuestsDeleted   int   `json:"guests_deleted"`
+	BowersDeleted   int   `json:"bowers_deleted"`
+	FeedsDeleted    int   `json:"feeds_deleted"` // Feeds no other bower subscribed to
+	ArticlesDeleted int   `json:"articles_deleted"`
+	LikesDeleted    int   `json:"likes_deleted"`
+	Errors          int   `json:"errors"`
+}
+
+// guestService implements GuestService interface
+type guestService struct {
+	userRepo      repository.UserRepository
+	bowerRepo     repository.BowerRepository
+	chickRepo     repository.ChickRepository
+	readStateRepo repository.ReadStateRepository
+	playbackRepo  repository.PlaybackRepository
+	bowerService  BowerService // Deletes bowers along with their feeds and articles
+}
+
+// NewGuestService creates a new guest service
+func NewGuestService(
+	userRepo repository.UserRepository,
+	bowerRepo repository.BowerRepository,
+	chickRepo repository.ChickRepository,
+	readStateRepo repository.ReadStateRepository,
+	playbackRepo repository.PlaybackRepository,
+	bowerService BowerService,
+) GuestService {
+	return &guestService{
+		userRepo:      userRepo,
+		bowerRepo:     bowerRepo,
+		chickRepo:     chickRepo,
+		readStateRepo: readStateRepo,
+		playbackRepo:  playbackRepo,
+		bowerService:  bowerService,
+	}
+}
+
+// ExpireGuests deletes the guest users that have not been active for opts.MaxAgeDays, with their
+// bowers, likes, read state and playback positions. Registered users are never deleted.
+func (s *guestService) ExpireGuests(ctx context.Context, opts *ExpireGuestsOptions) (*ExpireGuestsSummary, error) {
+	if opts == nil {
+		opts = &ExpireGuestsOptions{}
+	}
+	if opts.MaxAgeDays < 0 {
+		return nil, errors.New("max_age_days cannot be negative")
+	}
+	maxAgeDays := opts.MaxAgeDays
+	if maxAgeDays == 0 {
+		maxAgeDays = defaultGuestMaxAgeDays
+	}
+
+	summary := &ExpireGuestsSummary{
+		DryRun: opts.DryRun,
+		Cutoff: time.Now().AddDate(0, 0, -maxAgeDays).Unix(),
+	}
+
+	log.Printf("🧹 Expiring guest users inactive for %d days...", maxAgeDays)
+
+	var lastKey map[string]types.AttributeValue
+	for {
+		if err := ctx.Err(); err != nil {
+			return summary, err
+		}
+
+		users, nextKey, err := s.userRepo.List(ctx, cleanupPageSize, lastKey)
+		if err != nil {
+			return summary, fmt.Errorf("failed to list users: %w", err)
+		}
+
+		for _, user := range users {
+			summary.UsersChecked++
+			if !user.IsGuest() || user.LastActivity() >= summary.Cutoff {
+				continue
+			}
+
+			if err := s.expireGuest(ctx, user, opts.DryRun, summary); err != nil {
+				log.Printf("❌ Failed to delete guest user %s: %v", user.UserID, err)
+				summary.Errors++
+				continue
+			}
+			summary.GuestsDeleted++
+		}
+
+		if len(nextKey) == 0 {
+			break
+		}
+		lastKey = nextKey
+	}
+
+	log.Printf("✨ Guest expiration completed!")
+	log.Printf("📊 Summary:")
+	log.Printf("   - Total users checked: %d", summary.UsersChecked)
+	log.Printf("   - Guests deleted: %d", summary.GuestsDeleted)
+	log.Printf("   - Bowers deleted: %d", summary.BowersDeleted)
+	log.Printf("   - Errors: %d", summary.Errors)
+
+	return summary, nil
+}
+
+// expireGuest deletes a guest user and everything they own. The user is deleted last, so that a
+// failed deletion is retried by the next run.
+func (s *guestService) expireGuest(ctx context.Context, user *model.User, dryRun bool, summary *ExpireGuestsSummary) error {
+	bowers, err := s.guestBowers(ctx, user.UserID)
+	if err != nil {
+		return err
+	}
+
+	if dryRun {
+		summary.BowersDeleted += len(bowers)
+		return nil
+	}
+
+	log.Printf("🗑️  Deleting guest user %s with %d bowers", user.UserID, len(bowers))
+
+	for _, bower := range bowers {
+		result, err := s.bowerService.DeleteBower(ctx, user.UserID, bower.BowerID)
+		if err != nil {
+			return fmt.Errorf("failed to delete bower %s: %w", bower.BowerID, err)
+		}
+		summary.BowersDeleted++
+		summary.FeedsDeleted += result.FeedsDeleted
+		summary.ArticlesDeleted += result.ArticlesDeleted
+		summary.LikesDeleted += result.LikesDeleted
+	}
+
+	likes, err := s.chickRepo.RemoveLikedArticlesByUserID(ctx, user.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to remove likes: %w", err)
+	}
+	summary.LikesDeleted += likes
+
+	if err := s.chickRepo.DeleteStats(ctx, user.UserID); err != nil && !isNotFoundError(err) {
+		return fmt.Errorf("failed to delete chick stats: %w", err)
+	}
+	if _, err := s.readStateRepo.DeleteByUserID(ctx, user.UserID); err != nil {
+		return fmt.Errorf("failed to delete read states: %w", err)
+	}
+	if _, err := s.playbackRepo.DeleteByUserID(ctx, user.UserID); err != nil {
+		return fmt.Errorf("failed to delete playback positions: %w", err)
+	}
+
+	if err := s.userRepo.Delete(ctx, user.UserID); err != nil && !isNotFoundError(err) {
+		return fmt.Errorf("failed to delete user: %w", err)
+	}
+
+	return nil
+}
+
+// guestBowers returns all of a user's bowers
+func (s *guestService) guestBowers(ctx context.Context, userID string) ([]*model.Bower, error) {
+	var bowers []*model.Bower
+
+	var lastKey map[string]types.AttributeValue
+	for {
+		page, nextKey, err := s.bowerRepo.GetByUserID(ctx, userID, cleanupPageSize, lastKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get bowers: %w", err)
+		}
+		bowers = append(bowers, page...)
+
+		if len(nextKey) == 0 {
+			return bowers, nil
+		}
+		lastKey = nextKey
+	}
+}
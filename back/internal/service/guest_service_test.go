@@ -0,0 +1,84 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"feed-bower-api/internal/model"
+)
+
+func TestGuestService_ExpireGuests(t *testing.T) {
+	ctx := context.Background()
+	repos := NewMockRepositories()
+	bowers := NewBowerService(repos.BowerRepo, repos.FeedRepo)
+	bowers.(*bowerService).SetArticleRepositories(repos.ArticleRepo, repos.ChickRepo, repos.ReadStateRepo)
+	guests := NewGuestService(repos.UserRepo, repos.BowerRepo, repos.ChickRepo, repos.ReadStateRepo, repos.PlaybackRepo, bowers)
+	old := time.Now().AddDate(0, 0, -40).Unix()
+
+	addUser := func(userID, email string, updatedAt, lastActiveAt int64) {
+		user := model.NewUser(email, "hash", userID, "en")
+		user.UserID = userID
+		user.CreatedAt = old
+		user.UpdatedAt = updatedAt
+		user.LastActiveAt = lastActiveAt
+		repos.UserRepo.Create(ctx, user)
+	}
+	addUser("expired", "guest_expired@feed-bower.local", old, old)
+	addUser("active", "guest_active@feed-bower.local", old, time.Now().Unix())
+	addUser("updated", "guest_updated@feed-bower.local", time.Now().Unix(), 0)
+	addUser("registered", "guest_registered@example.com", old, old)
+
+	bower := model.NewBower("expired", "Guest Bower", []string{"go"}, nil, "#14b8a6", false)
+	repos.BowerRepo.Create(ctx, bower)
+	feed := model.NewFeed(bower.BowerID, "https://example.com/feed.xml", "Example", "", "")
+	repos.FeedRepo.Create(ctx, feed)
+	article := model.NewArticle(feed.FeedID, "Article", "content", "https://example.com/article", time.Now())
+	article.ArticleID = "article-1"
+	repos.ArticleRepo.Create(ctx, article)
+	repos.ChickRepo.AddLikedArticle(ctx, model.NewLikedArticle("expired", "liked-elsewhere"))
+	repos.ReadStateRepo.MarkRead(ctx, model.NewReadState("expired", "read-elsewhere"))
+	repos.ReadStateRepo.MarkRead(ctx, model.NewReadState("active", "read-elsewhere"))
+
+	// A dry run counts without deleting
+	summary, err := guests.ExpireGuests(ctx, &ExpireGuestsOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("ExpireGuests() unexpected error: %v", err)
+	}
+	if summary.UsersChecked != 4 || summary.GuestsDeleted != 1 || summary.BowersDeleted != 1 || summary.ArticlesDeleted != 0 {
+		t.Errorf("Unexpected dry run summary: %+v", *summary)
+	}
+	if len(repos.UserRepo.users) != 4 || len(repos.BowerRepo.bowers) != 1 {
+		t.Error("Expected a dry run to delete nothing")
+	}
+
+	summary, err = guests.ExpireGuests(ctx, nil)
+	if err != nil {
+		t.Fatalf("ExpireGuests() unexpected error: %v", err)
+	}
+	expected := ExpireGuestsSummary{Cutoff: summary.Cutoff, UsersChecked: 4, GuestsDeleted: 1, BowersDeleted: 1, FeedsDeleted: 1, ArticlesDeleted: 1, LikesDeleted: 1}
+	if *summary != expected {
+		t.Errorf("ExpireGuests() = %+v, expected %+v", *summary, expected)
+	}
+	if _, exists := repos.UserRepo.users["expired"]; exists {
+		t.Error("Expected the expired guest to be deleted")
+	}
+	for _, userID := range []string{"active", "updated", "registered"} {
+		if _, exists := repos.UserRepo.users[userID]; !exists {
+			t.Errorf("Expected user %s to be kept", userID)
+		}
+	}
+	if len(repos.BowerRepo.bowers) != 0 || len(repos.ArticleRepo.articles) != 0 {
+		t.Error("Expected the guest's bower and articles to be deleted")
+	}
+	if read, _ := repos.ReadStateRepo.IsRead(ctx, "expired", "read-elsewhere"); read {
+		t.Error("Expected the guest's read state to be deleted")
+	}
+	if read, _ := repos.ReadStateRepo.IsRead(ctx, "active", "read-elsewhere"); !read {
+		t.Error("Expected other users' read state to be kept")
+	}
+
+	if _, err := guests.ExpireGuests(ctx, &ExpireGuestsOptions{MaxAgeDays: -1}); err == nil {
+		t.Error("Expected an error for a negative maximum age")
+	}
+}
@@ -3,30 +3,39 @@ package service
 import (
 	"context"
 	"errors"
+	"fmt"
+	"sort"
 	"sync"
 	"testing"
+	"time"
 
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
 
 	"feed-bower-api/internal/model"
+	"feed-bower-api/internal/repository"
 )
 
 // MockRepositories for integration testing
 type MockRepositories struct {
-	UserRepo    *MockUserRepository
-	BowerRepo   *MockBowerRepository
-	FeedRepo    *MockFeedRepository
-	ArticleRepo *MockArticleRepository
-	ChickRepo   *MockChickRepository
+	UserRepo      *MockUserRepository
+	BowerRepo     *MockBowerRepository
+	FeedRepo      *MockFeedRepository
+	ArticleRepo   *MockArticleRepository
+	ChickRepo     *MockChickRepository
+	ReadStateRepo *MockReadStateRepository
+	PlaybackRepo  *MockPlaybackRepository
 }
 
 func NewMockRepositories() *MockRepositories {
 	return &MockRepositories{
-		UserRepo:    NewMockUserRepository(),
-		BowerRepo:   NewMockBowerRepository(),
-		FeedRepo:    NewMockFeedRepository(),
-		ArticleRepo: NewMockArticleRepository(),
-		ChickRepo:   NewMockChickRepository(),
+		UserRepo:      NewMockUserRepository(),
+		BowerRepo:     NewMockBowerRepository(),
+		FeedRepo:      NewMockFeedRepository(),
+		ArticleRepo:   NewMockArticleRepository(),
+		ChickRepo:     NewMockChickRepository(),
+		ReadStateRepo: NewMockReadStateRepository(),
+		PlaybackRepo:  NewMockPlaybackRepository(),
 	}
 }
 
@@ -47,7 +56,7 @@ func (m *MockBowerRepository) Create(ctx context.Context, bower *model.Bower) er
 	defer m.mu.Unlock()
 
 	if bower.BowerID == "" {
-		bower.BowerID = "test-bower-id"
+		bower.BowerID = uuid.New().String()
 	}
 	m.bowers[bower.BowerID] = bower
 	return nil
@@ -114,24 +123,33 @@ func (m *MockBowerRepository) Search(ctx context.Context, userID string, query s
 
 // MockFeedRepository
 type MockFeedRepository struct {
-	mu    sync.Mutex
-	feeds map[string]*model.Feed
+	mu            sync.Mutex
+	feeds         map[string]*model.Feed
+	subscriptions map[string]map[string]*model.Subscription // bowerID -> feedID -> Subscription
 }
 
 func NewMockFeedRepository() *MockFeedRepository {
 	return &MockFeedRepository{
-		feeds: make(map[string]*model.Feed),
+		feeds:         make(map[string]*model.Feed),
+		subscriptions: make(map[string]map[string]*model.Subscription),
 	}
 }
 
+// Create stores a canonical feed. A feed created with a BowerID is also subscribed to that bower.
 func (m *MockFeedRepository) Create(ctx context.Context, feed *model.Feed) error {
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
 	if feed.FeedID == "" {
-		feed.FeedID = "test-feed-id"
+		feed.FeedID = uuid.New().String()
+	}
+	if _, exists := m.feeds[feed.FeedID]; exists {
+		return fmt.Errorf("feed with ID %s already exists", feed.FeedID)
 	}
 	m.feeds[feed.FeedID] = feed
+	if feed.BowerID != "" {
+		m.subscribe(model.NewSubscription(feed.BowerID, feed.FeedID))
+	}
 	return nil
 }
 
@@ -150,9 +168,9 @@ func (m *MockFeedRepository) GetByBowerID(ctx context.Context, bowerID string) (
 	defer m.mu.Unlock()
 
 	feeds := make([]*model.Feed, 0)
-	for _, feed := range m.feeds {
-		if feed.BowerID == bowerID {
-			feeds = append(feeds, feed)
+	for feedID, subscription := range m.subscriptions[bowerID] {
+		if feed, exists := m.feeds[feedID]; exists {
+			feeds = append(feeds, subscription.ApplyTo(feed))
 		}
 	}
 	return feeds, nil
@@ -162,7 +180,12 @@ func (m *MockFeedRepository) GetByURL(ctx context.Context, url string) (*model.F
 	m.mu.Lock()
 	defer m.mu.Unlock()
 
-	return nil, nil
+	for _, feed := range m.feeds {
+		if feed.URL == url {
+			return feed, nil
+		}
+	}
+	return nil, fmt.Errorf("feed with URL %s not found", url)
 }
 
 func (m *MockFeedRepository) Update(ctx context.Context, feed *model.Feed) error {
@@ -181,6 +204,119 @@ func (m *MockFeedRepository) Delete(ctx context.Context, feedID string) error {
 	return nil
 }
 
+func (m *MockFeedRepository) DeleteIfUnsubscribed(ctx context.Context, feedID string) (bool, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	feed, exists := m.feeds[feedID]
+	if !exists || feed.SubscriberCount > 0 || feed.LegacyBowerID != "" {
+		return false, nil
+	}
+	delete(m.feeds, feedID)
+	return true, nil
+}
+
+func (m *MockFeedRepository) Subscribe(ctx context.Context, subscription *model.Subscription) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.subscriptions[subscription.BowerID][subscription.FeedID]; exists {
+		return fmt.Errorf("subscription to feed %s already exists in bower %s", subscription.FeedID, subscription.BowerID)
+	}
+	if _, exists := m.feeds[subscription.FeedID]; !exists {
+		return fmt.Errorf("feed with ID %s not found", subscription.FeedID)
+	}
+	m.subscribe(subscription)
+	return nil
+}
+
+func (m *MockFeedRepository) subscribe(subscription *model.Subscription) {
+	if m.subscriptions[subscription.BowerID] == nil {
+		m.subscriptions[subscription.BowerID] = make(map[string]*model.Subscription)
+	}
+	m.subscriptions[subscription.BowerID][subscription.FeedID] = subscription
+	if feed, exists := m.feeds[subscription.FeedID]; exists {
+		feed.SubscriberCount++
+	}
+}
+
+func (m *MockFeedRepository) Unsubscribe(ctx context.Context, bowerID, feedID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.subscriptions[bowerID][feedID]; !exists {
+		return fmt.Errorf("subscription to feed %s not found in bower %s", feedID, bowerID)
+	}
+	delete(m.subscriptions[bowerID], feedID)
+	if feed, exists := m.feeds[feedID]; exists {
+		feed.SubscriberCount--
+	}
+	return nil
+}
+
+func (m *MockFeedRepository) GetSubscription(ctx context.Context, bowerID, feedID string) (*model.Subscription, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	subscription, exists := m.subscriptions[bowerID][feedID]
+	if !exists {
+		return nil, fmt.Errorf("subscription to feed %s not found in bower %s", feedID, bowerID)
+	}
+	copied := *subscription
+	return &copied, nil
+}
+
+func (m *MockFeedRepository) UpdateSubscription(ctx context.Context, subscription *model.Subscription) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	if _, exists := m.subscriptions[subscription.BowerID][subscription.FeedID]; !exists {
+		return fmt.Errorf("subscription to feed %s not found in bower %s", subscription.FeedID, subscription.BowerID)
+	}
+	m.subscriptions[subscription.BowerID][subscription.FeedID] = subscription
+	return nil
+}
+
+func (m *MockFeedRepository) GetSubscriptionsByBowerID(ctx context.Context, bowerID string) ([]*model.Subscription, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	subscriptions := make([]*model.Subscription, 0)
+	for _, subscription := range m.subscriptions[bowerID] {
+		subscriptions = append(subscriptions, subscription)
+	}
+	return subscriptions, nil
+}
+
+func (m *MockFeedRepository) GetSubscriptionsByFeedID(ctx context.Context, feedID string) ([]*model.Subscription, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	subscriptions := make([]*model.Subscription, 0)
+	for _, bowerSubscriptions := range m.subscriptions {
+		if subscription, exists := bowerSubscriptions[feedID]; exists {
+			subscriptions = append(subscriptions, subscription)
+		}
+	}
+	return subscriptions, nil
+}
+
+func (m *MockFeedRepository) MigrateLegacySubscription(ctx context.Context, feed *model.Feed) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	stored, exists := m.feeds[feed.FeedID]
+	if !exists || stored.LegacyBowerID == "" {
+		return nil
+	}
+	if _, subscribed := m.subscriptions[stored.LegacyBowerID][stored.FeedID]; !subscribed {
+		m.subscribe(model.NewLegacySubscription(stored))
+	}
+	stored.LegacyBowerID = ""
+	feed.LegacyBowerID = ""
+	return nil
+}
+
 func (m *MockFeedRepository) List(ctx context.Context, limit int32, lastKey map[string]types.AttributeValue) ([]*model.Feed, map[string]types.AttributeValue, error) {
 	m.mu.Lock()
 	defer m.mu.Unlock()
@@ -199,6 +335,19 @@ func (m *MockFeedRepository) GetStaleFeeds(ctx context.Context, maxAgeSeconds in
 	return []*model.Feed{}, nil
 }
 
+func (m *MockFeedRepository) GetDueFeeds(ctx context.Context, now int64, limit int32) ([]*model.Feed, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+
+	var due []*model.Feed
+	for _, feed := range m.feeds {
+		if feed.IsDue(time.Unix(now, 0)) {
+			due = append(due, feed)
+		}
+	}
+	return due, nil
+}
+
 // MockArticleRepository
 type MockArticleRepository struct {
 	articles map[string]*model.Article
@@ -226,17 +375,180 @@ func (m *MockArticleRepository) GetByID(ctx context.Context, articleID string) (
 }
 
 func (m *MockArticleRepository) GetByFeedID(ctx context.Context, feedID string, limit int32, lastKey map[string]types.AttributeValue) ([]*model.Article, map[string]types.AttributeValue, error) {
-	return []*model.Article{}, nil, nil
+	articles := make([]*model.Article, 0)
+	for _, article := range m.articles {
+		if article.FeedID == feedID {
+			articles = append(articles, article)
+		}
+	}
+	sort.Slice(articles, func(i, j int) bool {
+		return repository.ArticlePositionOf(articles[i]).RanksBefore(repository.ArticlePositionOf(articles[j]))
+	})
+
+	after, err := repository.ParseArticleKey(lastKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	if after != nil {
+		start := sort.Search(len(articles), func(i int) bool {
+			return after.RanksBefore(repository.ArticlePositionOf(articles[i]))
+		})
+		articles = articles[start:]
+	}
+
+	var nextKey map[string]types.AttributeValue
+	if limit > 0 && len(articles) > int(limit) {
+		articles = articles[:limit]
+		nextKey = repository.ArticlePositionOf(articles[limit-1]).Key()
+	}
+	return articles, nextKey, nil
 }
 
 func (m *MockArticleRepository) GetByFeedIDs(ctx context.Context, feedIDs []string, limit int32, lastKey map[string]types.AttributeValue) ([]*model.Article, map[string]types.AttributeValue, error) {
-	return []*model.Article{}, nil, nil
+	wanted := make(map[string]bool, len(feedIDs))
+	for _, feedID := range feedIDs {
+		wanted[feedID] = true
+	}
+
+	articles := make([]*model.Article, 0)
+	seen := make(map[string]bool)
+	for _, article := range m.articles {
+		if !wanted[article.FeedID] {
+			continue
+		}
+		if article.IsAlias() {
+			article = m.articles[article.DuplicateOf]
+		}
+		if article != nil && !seen[article.ArticleID] {
+			seen[article.ArticleID] = true
+			articles = append(articles, article)
+		}
+	}
+	sort.Slice(articles, func(i, j int) bool {
+		return repository.ArticlePositionOf(articles[i]).RanksBefore(repository.ArticlePositionOf(articles[j]))
+	})
+
+	after, err := repository.ParseArticleKey(lastKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	if after != nil {
+		start := sort.Search(len(articles), func(i int) bool {
+			return after.RanksBefore(repository.ArticlePositionOf(articles[i]))
+		})
+		articles = articles[start:]
+	}
+
+	var nextKey map[string]types.AttributeValue
+	if limit > 0 && len(articles) > int(limit) {
+		articles = articles[:limit]
+		nextKey = repository.ArticlePositionOf(articles[limit-1]).Key()
+	}
+	return articles, nextKey, nil
+}
+
+func (m *MockArticleRepository) GetEpisodesByFeedIDs(ctx context.Context, feedIDs []string, limit int32, lastKey map[string]types.AttributeValue) ([]*model.Article, map[string]types.AttributeValue, error) {
+	articles, _, err := m.GetByFeedIDs(ctx, feedIDs, 0, nil)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	episodes := make([]*model.Article, 0, len(articles))
+	for _, article := range articles {
+		if article.Episode != nil {
+			episodes = append(episodes, article)
+		}
+	}
+
+	after, err := repository.ParseArticleKey(lastKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	if after != nil {
+		start := sort.Search(len(episodes), func(i int) bool {
+			return after.RanksBefore(repository.ArticlePositionOf(episodes[i]))
+		})
+		episodes = episodes[start:]
+	}
+
+	var nextKey map[string]types.AttributeValue
+	if limit > 0 && len(episodes) > int(limit) {
+		episodes = episodes[:limit]
+		nextKey = repository.ArticlePositionOf(episodes[limit-1]).Key()
+	}
+	return episodes, nextKey, nil
 }
 
 func (m *MockArticleRepository) GetByURL(ctx context.Context, url string) (*model.Article, error) {
 	return nil, nil
 }
 
+func (m *MockArticleRepository) GetByCanonicalURL(ctx context.Context, canonicalURL string) (*model.Article, error) {
+	for _, article := range m.articles {
+		if article.CanonicalURL == canonicalURL && !article.IsAlias() {
+			return article, nil
+		}
+	}
+	return nil, fmt.Errorf("article with canonical URL %s not found", canonicalURL)
+}
+
+func (m *MockArticleRepository) GetByTitleHash(ctx context.Context, titleHash string, limit int32) ([]*model.Article, error) {
+	articles := make([]*model.Article, 0)
+	for _, article := range m.articles {
+		if article.TitleHash == titleHash && !article.IsAlias() {
+			articles = append(articles, article)
+		}
+	}
+	return articles, nil
+}
+
+func (m *MockArticleRepository) AddSourceFeed(ctx context.Context, articleID, feedID string) error {
+	article, exists := m.articles[articleID]
+	if !exists {
+		return fmt.Errorf("article with ID %s not found", articleID)
+	}
+	if !article.HasFeed(feedID) {
+		article.SourceFeedIDs = append(article.SourceFeedIDs, feedID)
+	}
+	alias := model.NewArticleAlias(article, feedID)
+	m.articles[alias.ArticleID] = alias
+	return nil
+}
+
+func (m *MockArticleRepository) RemoveSourceFeed(ctx context.Context, articleID, feedID string) error {
+	delete(m.articles, model.ArticleAliasID(articleID, feedID))
+	article, exists := m.articles[articleID]
+	if !exists {
+		return fmt.Errorf("article with ID %s not found", articleID)
+	}
+	sourceFeedIDs := make([]string, 0, len(article.SourceFeedIDs))
+	for _, sourceFeedID := range article.SourceFeedIDs {
+		if sourceFeedID != feedID {
+			sourceFeedIDs = append(sourceFeedIDs, sourceFeedID)
+		}
+	}
+	article.SourceFeedIDs = sourceFeedIDs
+	return nil
+}
+
+func (m *MockArticleRepository) SetFullContent(ctx context.Context, articleID, fullContent string) error {
+	article, exists := m.articles[articleID]
+	if !exists {
+		return fmt.Errorf("article with ID %s not found", articleID)
+	}
+	article.FullContent = fullContent
+	return nil
+}
+
+func (m *MockArticleRepository) SetImageURL(ctx context.Context, articleID, imageURL string) error {
+	article, exists := m.articles[articleID]
+	if !exists {
+		return fmt.Errorf("article with ID %s not found", articleID)
+	}
+	article.SetImageURL(imageURL)
+	return nil
+}
+
 func (m *MockArticleRepository) Update(ctx context.Context, article *model.Article) error {
 	m.articles[article.ArticleID] = article
 	return nil
@@ -248,23 +560,66 @@ func (m *MockArticleRepository) Delete(ctx context.Context, articleID string) er
 }
 
 func (m *MockArticleRepository) List(ctx context.Context, limit int32, lastKey map[string]types.AttributeValue) ([]*model.Article, map[string]types.AttributeValue, error) {
-	return []*model.Article{}, nil, nil
+	articles := make([]*model.Article, 0, len(m.articles))
+	for _, article := range m.articles {
+		articles = append(articles, article)
+	}
+	return articles, nil, nil
+}
+
+func (m *MockArticleRepository) Search(ctx context.Context, query string, feedIDs []string, limit int32, lastKey map[string]types.AttributeValue) ([]*model.Article, map[string]types.AttributeValue, error) {
+	index := repository.NewEmbeddedSearchIndex()
+	for _, article := range m.articles {
+		index.IndexArticles(ctx, []*model.Article{article})
+	}
+
+	after, err := repository.ParseSearchKey(lastKey)
+	if err != nil {
+		return nil, nil, err
+	}
+	hits, err := index.Search(ctx, query, feedIDs, after, int(limit)+1)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	var nextKey map[string]types.AttributeValue
+	if len(hits) > int(limit) {
+		hits = hits[:limit]
+		nextKey = hits[limit-1].Key()
+	}
+
+	articles := make([]*model.Article, 0, len(hits))
+	for _, hit := range hits {
+		articles = append(articles, m.articles[hit.ArticleID])
+	}
+	return articles, nextKey, nil
 }
 
-func (m *MockArticleRepository) Search(ctx context.Context, query string, feedIDs []string, limit int32) ([]*model.Article, error) {
-	return []*model.Article{}, nil
+func (m *MockArticleRepository) IndexArticles(ctx context.Context, articles []*model.Article) error {
+	return nil
+}
+
+func (m *MockArticleRepository) SyncSearchIndex(ctx context.Context, articleIDs []string) error {
+	return nil
 }
 
 func (m *MockArticleRepository) BatchCreate(ctx context.Context, articles []*model.Article) error {
 	for _, article := range articles {
 		if article.ArticleID == "" {
-			article.ArticleID = "test-article-id"
+			article.ArticleID = uuid.New().String()
 		}
 		m.articles[article.ArticleID] = article
 	}
 	return nil
 }
 
+func (m *MockArticleRepository) BatchDelete(ctx context.Context, articleIDs []string) error {
+	for _, articleID := range articleIDs {
+		delete(m.articles, articleID)
+	}
+	return nil
+}
+
 // MockChickRepository
 type MockChickRepository struct {
 	stats         map[string]*model.ChickStats
@@ -315,6 +670,33 @@ func (m *MockChickRepository) RemoveLikedArticle(ctx context.Context, userID, ar
 	return nil
 }
 
+func (m *MockChickRepository) RemoveLikedArticlesByArticleID(ctx context.Context, articleID string) (int, error) {
+	removed := 0
+	for _, userLikes := range m.likedArticles {
+		if _, exists := userLikes[articleID]; exists {
+			delete(userLikes, articleID)
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+func (m *MockChickRepository) RemoveLikedArticlesByUserID(ctx context.Context, userID string) (int, error) {
+	removed := len(m.likedArticles[userID])
+	delete(m.likedArticles, userID)
+	return removed, nil
+}
+
+func (m *MockChickRepository) GetLikeCountByArticleID(ctx context.Context, articleID string) (int, error) {
+	count := 0
+	for _, userLikes := range m.likedArticles {
+		if _, exists := userLikes[articleID]; exists {
+			count++
+		}
+	}
+	return count, nil
+}
+
 func (m *MockChickRepository) GetLikedArticles(ctx context.Context, userID string, limit int32, lastKey map[string]types.AttributeValue) ([]*model.LikedArticle, map[string]types.AttributeValue, error) {
 	articles := make([]*model.LikedArticle, 0)
 	if userLikes, exists := m.likedArticles[userID]; exists {
@@ -340,6 +722,112 @@ func (m *MockChickRepository) GetLikedArticleCount(ctx context.Context, userID s
 	return 0, nil
 }
 
+// MockReadStateRepository
+type MockReadStateRepository struct {
+	read map[string]map[string]*model.ReadState // userID -> articleID -> ReadState
+}
+
+func NewMockReadStateRepository() *MockReadStateRepository {
+	return &MockReadStateRepository{
+		read: make(map[string]map[string]*model.ReadState),
+	}
+}
+
+func (m *MockReadStateRepository) MarkRead(ctx context.Context, readState *model.ReadState) error {
+	if m.read[readState.UserID] == nil {
+		m.read[readState.UserID] = make(map[string]*model.ReadState)
+	}
+	m.read[readState.UserID][readState.ArticleID] = readState
+	return nil
+}
+
+func (m *MockReadStateRepository) MarkUnread(ctx context.Context, userID, articleID string) error {
+	delete(m.read[userID], articleID)
+	return nil
+}
+
+func (m *MockReadStateRepository) IsRead(ctx context.Context, userID, articleID string) (bool, error) {
+	_, exists := m.read[userID][articleID]
+	return exists, nil
+}
+
+func (m *MockReadStateRepository) GetReadArticleIDs(ctx context.Context, userID string, articleIDs []string) (map[string]bool, error) {
+	readIDs := make(map[string]bool)
+	for _, articleID := range articleIDs {
+		if _, exists := m.read[userID][articleID]; exists {
+			readIDs[articleID] = true
+		}
+	}
+	return readIDs, nil
+}
+
+func (m *MockReadStateRepository) DeleteByArticleID(ctx context.Context, articleID string) (int, error) {
+	removed := 0
+	for _, userReads := range m.read {
+		if _, exists := userReads[articleID]; exists {
+			delete(userReads, articleID)
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+func (m *MockReadStateRepository) DeleteByUserID(ctx context.Context, userID string) (int, error) {
+	removed := len(m.read[userID])
+	delete(m.read, userID)
+	return removed, nil
+}
+
+// MockPlaybackRepository
+type MockPlaybackRepository struct {
+	positions map[string]map[string]*model.PlaybackPosition // userID -> articleID -> PlaybackPosition
+}
+
+func NewMockPlaybackRepository() *MockPlaybackRepository {
+	return &MockPlaybackRepository{
+		positions: make(map[string]map[string]*model.PlaybackPosition),
+	}
+}
+
+func (m *MockPlaybackRepository) Save(ctx context.Context, position *model.PlaybackPosition) error {
+	if m.positions[position.UserID] == nil {
+		m.positions[position.UserID] = make(map[string]*model.PlaybackPosition)
+	}
+	m.positions[position.UserID][position.ArticleID] = position
+	return nil
+}
+
+func (m *MockPlaybackRepository) Get(ctx context.Context, userID, articleID string) (*model.PlaybackPosition, error) {
+	return m.positions[userID][articleID], nil
+}
+
+func (m *MockPlaybackRepository) GetPositions(ctx context.Context, userID string, articleIDs []string) (map[string]*model.PlaybackPosition, error) {
+	positions := make(map[string]*model.PlaybackPosition)
+	for _, articleID := range articleIDs {
+		if position, exists := m.positions[userID][articleID]; exists {
+			positions[articleID] = position
+		}
+	}
+	return positions, nil
+}
+
+func (m *MockPlaybackRepository) DeleteByArticleID(ctx context.Context, articleID string) (int, error) {
+	removed := 0
+	for _, userPositions := range m.positions {
+		if _, exists := userPositions[articleID]; exists {
+			delete(userPositions, articleID)
+			removed++
+		}
+	}
+	return removed, nil
+}
+
+func (m *MockPlaybackRepository) DeleteByUserID(ctx context.Context, userID string) (int, error) {
+	removed := len(m.positions[userID])
+	delete(m.positions, userID)
+	return removed, nil
+}
+
 // MockRSSService
 type MockRSSService struct{}
 
@@ -357,6 +845,10 @@ func (m *MockRSSService) FetchFeed(ctx context.Context, feedURL string) (*FeedDa
 	}, nil
 }
 
+func (m *MockRSSService) FetchFeedWithOptions(ctx context.Context, feedURL string, opts *FetchOptions) (*FeedData, error) {
+	return m.FetchFeed(ctx, feedURL)
+}
+
 func (m *MockRSSService) FetchFeedInfo(ctx context.Context, feedURL string) (*FeedInfo, error) {
 	return &FeedInfo{
 		Title:       "Test Feed",
@@ -365,6 +857,22 @@ func (m *MockRSSService) FetchFeedInfo(ctx context.Context, feedURL string) (*Fe
 	}, nil
 }
 
+func (m *MockRSSService) DiscoverFeeds(ctx context.Context, pageURL string) ([]FeedCandidate, error) {
+	return []FeedCandidate{}, nil
+}
+
+func (m *MockRSSService) FetchFullContent(ctx context.Context, articleURL string) (string, error) {
+	return "", errors.New("full text extraction not supported")
+}
+
+func (m *MockRSSService) FetchPageImage(ctx context.Context, pageURL string) (string, error) {
+	return "", errNoPageImage
+}
+
+func (m *MockRSSService) ParseFeed(data []byte, contentType string, feedURL string) (*FeedData, error) {
+	return &FeedData{}, nil
+}
+
 func (m *MockRSSService) ParseRSSFeed(data []byte) (*FeedData, error) {
 	return &FeedData{}, nil
 }
@@ -377,6 +885,10 @@ func (m *MockRSSService) ParseRDFFeed(data []byte) (*FeedData, error) {
 	return &FeedData{}, nil
 }
 
+func (m *MockRSSService) ParseJSONFeed(data []byte) (*FeedData, error) {
+	return &FeedData{}, nil
+}
+
 func (m *MockRSSService) ExtractImageURL(content string) string {
 	return ""
 }
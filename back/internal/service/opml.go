@@ -0,0 +1,220 @@
+package service
+
+import (
+	"bytes"
+	"encoding/xml"
+	"errors"
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"feed-bower-api/internal/model"
+)
+
+// OPML 2.0 structures
+type OPML struct {
+	XMLName xml.Name `xml:"opml"`
+	Version string   `xml:"version,attr"`
+	Head    OPMLHead `xml:"head"`
+	Body    OPMLBody `xml:"body"`
+}
+
+type OPMLHead struct {
+	Title       string `xml:"title,omitempty"`
+	DateCreated string `xml:"dateCreated,omitempty"`
+}
+
+type OPMLBody struct {
+	Outlines []OPMLOutline `xml:"outline"`
+}
+
+type OPMLOutline struct {
+	Text        string `xml:"text,attr"`
+	Title       string `xml:"title,attr,omitempty"`
+	Type        string `xml:"type,attr,omitempty"`
+	XMLURL      string `xml:"xmlUrl,attr,omitempty"`
+	HTMLURL     string `xml:"htmlUrl,attr,omitempty"`
+	Description string `xml:"description,attr,omitempty"`
+	Category    string `xml:"category,attr,omitempty"`
+
+	// Feed Bower extensions on category outlines, so export/import round-trips bower settings
+	Keywords  string `xml:"keywords,attr,omitempty"`  // Comma-separated
+	Color     string `xml:"color,attr,omitempty"`     // Hex color
+	EggColors string `xml:"eggColors,attr,omitempty"` // Comma-separated
+	IsPublic  string `xml:"isPublic,attr,omitempty"`  // "true" or "false"
+
+	Outlines []OPMLOutline `xml:"outline"`
+}
+
+// OPML import statuses reported per feed
+const (
+	OPMLImportCreated   = "created"
+	OPMLImportDuplicate = "duplicate"
+	OPMLImportFailed    = "failed"
+)
+
+// Import/export settings
+const (
+	opmlMaxFeeds            = 500
+	opmlDefaultCategoryName = "Imported Feeds"
+	opmlExportTitle         = "Feed Bower Subscriptions"
+)
+
+var hexColorRegex = regexp.MustCompile(`^#[0-9a-fA-F]{6}$`)
+
+// OPMLImportResult is the per-feed report of an OPML import
+type OPMLImportResult struct {
+	Bowers          []OPMLImportBower      `json:"bowers"`
+	Feeds           []OPMLImportFeedResult `json:"feeds"`
+	TotalCreated    int                    `json:"total_created"`
+	TotalDuplicates int                    `json:"total_duplicates"`
+	TotalFailed     int                    `json:"total_failed"`
+}
+
+// OPMLImportBower describes a bower that received imported feeds
+type OPMLImportBower struct {
+	BowerID string `json:"bower_id"`
+	Name    string `json:"name"`
+	Created bool   `json:"created"`
+}
+
+// OPMLImportFeedResult describes the outcome of importing a single feed
+type OPMLImportFeedResult struct {
+	URL     string `json:"url"`
+	Title   string `json:"title"`
+	BowerID string `json:"bower_id,omitempty"`
+	Bower   string `json:"bower"`
+	FeedID  string `json:"feed_id,omitempty"`
+	Status  string `json:"status"`
+	Error   string `json:"error,omitempty"`
+}
+
+// MarshalOPML renders an OPML document with an XML declaration
+func MarshalOPML(opml *OPML) ([]byte, error) {
+	data, err := xml.MarshalIndent(opml, "", "  ")
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal OPML: %w", err)
+	}
+	return append([]byte(xml.Header), data...), nil
+}
+
+// opmlCategory is an outline category flattened to its feed outlines
+type opmlCategory struct {
+	Outline OPMLOutline
+	Feeds   []OPMLOutline
+}
+
+// ParseOPML parses an OPML document
+func ParseOPML(data []byte) (*OPML, error) {
+	if len(bytes.TrimSpace(data)) == 0 {
+		return nil, errors.New("OPML document is empty")
+	}
+
+	var opml OPML
+	if err := unmarshalXML(data, &opml); err != nil {
+		return nil, fmt.Errorf("failed to parse OPML: %w", err)
+	}
+
+	return &opml, nil
+}
+
+// groupOPMLOutlines maps each top-level category outline to the feeds nested beneath it.
+// Feeds at the top level are collected into a default category.
+func groupOPMLOutlines(opml *OPML) []opmlCategory {
+	categories := make([]opmlCategory, 0)
+	var uncategorized []OPMLOutline
+
+	for _, outline := range opml.Body.Outlines {
+		if outline.XMLURL != "" {
+			uncategorized = append(uncategorized, outline)
+			continue
+		}
+
+		// Skip empty groups unless they carry exported bower settings
+		feeds := collectOPMLFeeds(outline.Outlines)
+		if len(feeds) == 0 && outline.Keywords == "" {
+			continue
+		}
+		categories = append(categories, opmlCategory{Outline: outline, Feeds: feeds})
+	}
+
+	if len(uncategorized) > 0 {
+		name := strings.TrimSpace(opml.Head.Title)
+		if name == "" {
+			name = opmlDefaultCategoryName
+		}
+		categories = append(categories, opmlCategory{
+			Outline: OPMLOutline{Text: name},
+			Feeds:   uncategorized,
+		})
+	}
+
+	return categories
+}
+
+// collectOPMLFeeds returns all feed outlines (those with an xmlUrl), flattening nested groups
+func collectOPMLFeeds(outlines []OPMLOutline) []OPMLOutline {
+	feeds := make([]OPMLOutline, 0)
+	for _, outline := range outlines {
+		if outline.XMLURL != "" {
+			feeds = append(feeds, outline)
+		}
+		feeds = append(feeds, collectOPMLFeeds(outline.Outlines)...)
+	}
+	return feeds
+}
+
+// name returns the display name of an outline
+func (o OPMLOutline) name() string {
+	if title := strings.TrimSpace(o.Title); title != "" {
+		return title
+	}
+	return strings.TrimSpace(o.Text)
+}
+
+// bowerSettings returns the bower keywords, color, egg colors and visibility stored on a category outline.
+// Invalid or missing values are ignored so callers can fall back to defaults.
+func (o OPMLOutline) bowerSettings() (keywords []string, color string, eggColors []string, isPublic bool) {
+	seen := make(map[string]bool)
+	for _, keyword := range splitOPMLList(o.Keywords) {
+		if len([]rune(keyword)) > model.MaxKeywordLength || seen[keyword] || len(keywords) >= model.MaxKeywords {
+			continue
+		}
+		seen[keyword] = true
+		keywords = append(keywords, keyword)
+	}
+
+	if hexColorRegex.MatchString(o.Color) {
+		color = o.Color
+	}
+
+	for _, eggColor := range splitOPMLList(o.EggColors) {
+		if hexColorRegex.MatchString(eggColor) {
+			eggColors = append(eggColors, eggColor)
+		}
+	}
+
+	isPublic, _ = strconv.ParseBool(o.IsPublic)
+	return keywords, color, eggColors, isPublic
+}
+
+// splitOPMLList splits a comma-separated attribute value, dropping empty entries
+func splitOPMLList(value string) []string {
+	items := make([]string, 0)
+	for _, item := range strings.Split(value, ",") {
+		if item = strings.TrimSpace(item); item != "" {
+			items = append(items, item)
+		}
+	}
+	return items
+}
+
+// truncateRunes shortens s to at most maxRunes characters without splitting multi-byte characters
+func truncateRunes(s string, maxRunes int) string {
+	runes := []rune(s)
+	if len(runes) <= maxRunes {
+		return s
+	}
+	return string(runes[:maxRunes])
+}
@@ -1,30 +1,65 @@
 package service
 
 import (
+	"bufio"
+	"bytes"
 	"context"
+	"encoding/json"
 	"encoding/xml"
 	"errors"
 	"fmt"
 	"io"
 	"net/http"
-	"regexp"
+	"net/url"
+	"strconv"
 	"strings"
 	"time"
 
 	"feed-bower-api/internal/model"
+	"feed-bower-api/pkg/charset"
 	"feed-bower-api/pkg/httpclient"
+	"feed-bower-api/pkg/sanitize"
 )
 
+// maxContentHTMLSize is the largest sanitized feed content stored with an article.
+// DynamoDB items are limited to 400KB.
+const maxContentHTMLSize = 100 * 1024
+
+const (
+	// maxFeedSize is the largest JSON Feed read, as it is parsed in memory
+	maxFeedSize = 10 * 1024 * 1024
+
+	// maxStreamedFeedSize is the largest XML feed read. XML feeds are parsed one item at a time,
+	// so only the items kept are held in memory.
+	maxStreamedFeedSize = 100 * 1024 * 1024
+
+	// defaultMaxFeedItems is how many of the newest items of a feed are parsed by default
+	defaultMaxFeedItems = 500
+)
+
+// errFeedTooLarge is returned when a feed is larger than allowed
+var errFeedTooLarge = errors.New("feed size exceeds maximum allowed size")
+
 // RSSService defines the interface for RSS feed operations
 type RSSService interface {
 	// Feed fetching
 	FetchFeed(ctx context.Context, feedURL string) (*FeedData, error)
+	FetchFeedWithOptions(ctx context.Context, feedURL string, opts *FetchOptions) (*FeedData, error)
 	FetchFeedInfo(ctx context.Context, feedURL string) (*FeedInfo, error)
 
+	// Feed autodiscovery
+	DiscoverFeeds(ctx context.Context, pageURL string) ([]FeedCandidate, error)
+
+	// Article page lookups
+	FetchFullContent(ctx context.Context, articleURL string) (string, error)
+	FetchPageImage(ctx context.Context, pageURL string) (string, error)
+
 	// Article parsing
+	ParseFeed(data []byte, contentType string, feedURL string) (*FeedData, error)
 	ParseRSSFeed(data []byte) (*FeedData, error)
 	ParseAtomFeed(data []byte) (*FeedData, error)
 	ParseRDFFeed(data []byte) (*FeedData, error)
+	ParseJSONFeed(data []byte) (*FeedData, error)
 
 	// Utility functions
 	ExtractImageURL(content string) string
@@ -38,6 +73,51 @@ type FeedData struct {
 	URL         string        `json:"url"`
 	Category    string        `json:"category"`
 	Articles    []ArticleData `json:"articles"`
+
+	// HTTP cache validators returned by the server
+	ETag         string `json:"etag,omitempty"`
+	LastModified string `json:"last_modified,omitempty"`
+
+	// HTTP status code of the fetch response
+	StatusCode int `json:"status_code,omitempty"`
+
+	// NotModified is true when the server answered 304 Not Modified.
+	// In that case Articles is empty and the feed does not need to be parsed.
+	NotModified bool `json:"not_modified,omitempty"`
+
+	// Polling hints published by the feed (RSS 2.0 only)
+	TTL       int      `json:"ttl,omitempty"` // Minutes
+	SkipHours []int    `json:"skip_hours,omitempty"`
+	SkipDays  []string `json:"skip_days,omitempty"`
+
+	// WebSub hub and self URL advertised by the feed, for push subscriptions
+	HubURL  string `json:"hub_url,omitempty"`
+	SelfURL string `json:"self_url,omitempty"`
+
+	// Polling hints from the HTTP response
+	MaxAge     int64 `json:"max_age,omitempty"`     // Cache-Control max-age in seconds
+	RetryAfter int64 `json:"retry_after,omitempty"` // Unix time from Retry-After
+}
+
+// HTTPError is returned when the feed server responds with an unexpected status code
+type HTTPError struct {
+	StatusCode int
+	Status     string
+	RetryAfter int64 // Unix time from Retry-After, 0 if not present
+}
+
+func (e *HTTPError) Error() string {
+	return fmt.Sprintf("HTTP error: %d %s", e.StatusCode, e.Status)
+}
+
+// FetchOptions holds optional parameters for fetching a feed
+type FetchOptions struct {
+	// Cache validators from the previous fetch, sent as If-None-Match / If-Modified-Since
+	ETag         string
+	LastModified string
+
+	// MaxItems caps how many of the newest items are parsed; defaultMaxFeedItems if 0
+	MaxItems int
 }
 
 // FeedInfo represents basic feed information without articles
@@ -49,81 +129,152 @@ type FeedInfo struct {
 
 // ArticleData represents parsed article data
 type ArticleData struct {
-	Title       string    `json:"title"`
-	Content     string    `json:"content"`
-	URL         string    `json:"url"`
-	PublishedAt time.Time `json:"published_at"`
-	ImageURL    *string   `json:"image_url,omitempty"`
+	Title       string               `json:"title"`
+	Content     string               `json:"content"`                // Plain-text summary
+	ContentHTML string               `json:"content_html,omitempty"` // Sanitized HTML, if the content has markup
+	URL         string               `json:"url"`
+	PublishedAt time.Time            `json:"published_at"`
+	ImageURL    *string              `json:"image_url,omitempty"`
+	Media       []model.ArticleMedia `json:"media,omitempty"`
+	Episode     *model.Episode       `json:"episode,omitempty"` // Set for podcast episodes
+	Authors     []ArticleAuthor      `json:"authors,omitempty"`
+	Attachments []ArticleAttachment  `json:"attachments,omitempty"`
 }
 
-// RSS 2.0 structures
-type RSS struct {
-	XMLName xml.Name `xml:"rss"`
-	Channel Channel  `xml:"channel"`
+// ArticleAuthor represents an article author
+type ArticleAuthor struct {
+	Name   string `json:"name,omitempty"`
+	URL    string `json:"url,omitempty"`
+	Avatar string `json:"avatar,omitempty"`
 }
 
-type Channel struct {
-	Title       string `xml:"title"`
-	Description string `xml:"description"`
-	Link        string `xml:"link"`
-	Category    string `xml:"category"`
-	Items       []Item `xml:"item"`
+// ArticleAttachment represents a file attached to an article (e.g. a podcast episode)
+type ArticleAttachment struct {
+	URL               string `json:"url"`
+	MimeType          string `json:"mime_type"`
+	Title             string `json:"title,omitempty"`
+	SizeInBytes       int64  `json:"size_in_bytes,omitempty"`
+	DurationInSeconds int64  `json:"duration_in_seconds,omitempty"`
 }
 
+// RSS 2.0 structures
 type Item struct {
+	ITunesItem // Listed first so that itunes:title and itunes:author are not read as the item's own
+
 	Title       string `xml:"title"`
 	Description string `xml:"description"`
 	Link        string `xml:"link"`
 	PubDate     string `xml:"pubDate"`
 	GUID        string `xml:"guid"`
 	Category    string `xml:"category"`
-	Content     string `xml:"content"`
-	Encoded     string `xml:"encoded"` // For content:encoded
+
+	// Media, listed before Content so that media:content is not read as the item's content
+	Enclosures      []Enclosure      `xml:"enclosure"`
+	MediaContents   []MediaContent   `xml:"http://search.yahoo.com/mrss/ content"`
+	MediaThumbnails []MediaThumbnail `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+	MediaGroups     []MediaGroup     `xml:"http://search.yahoo.com/mrss/ group"`
+
+	Content string `xml:"content"`
+	Encoded string `xml:"encoded"` // For content:encoded
+	Author  string `xml:"author"`  // Usually "email (Name)"
+	Creator string `xml:"creator"` // dc:creator
 }
 
-// RSS 1.0 / RDF structures
-type RDF struct {
-	XMLName xml.Name   `xml:"RDF"`
-	Channel RDFChannel `xml:"channel"`
-	Items   []RDFItem  `xml:"item"`
+// Enclosure is an RSS 2.0 enclosure, such as a podcast episode
+type Enclosure struct {
+	URL    string `xml:"url,attr"`
+	Length string `xml:"length,attr"`
+	Type   string `xml:"type,attr"`
+}
+
+// Media RSS structures (https://www.rssboard.org/media-rss)
+type MediaContent struct {
+	URL        string           `xml:"url,attr"`
+	Type       string           `xml:"type,attr"`
+	Medium     string           `xml:"medium,attr"`
+	FileSize   string           `xml:"fileSize,attr"`
+	Duration   string           `xml:"duration,attr"`
+	Width      string           `xml:"width,attr"`
+	Height     string           `xml:"height,attr"`
+	Thumbnails []MediaThumbnail `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+}
+
+type MediaThumbnail struct {
+	URL    string `xml:"url,attr"`
+	Width  string `xml:"width,attr"`
+	Height string `xml:"height,attr"`
+}
+
+type MediaGroup struct {
+	Contents   []MediaContent   `xml:"http://search.yahoo.com/mrss/ content"`
+	Thumbnails []MediaThumbnail `xml:"http://search.yahoo.com/mrss/ thumbnail"`
 }
 
+// ITunesItem holds the iTunes podcast tags of an episode
+// (https://help.apple.com/itc/podcasts_connect/#/itcb54353390)
+type ITunesItem struct {
+	ITunesTitle       string      `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd title"`
+	ITunesAuthor      string      `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd author"`
+	ITunesSummary     string      `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd summary"`
+	ITunesImage       ITunesImage `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd image"`
+	ITunesDuration    string      `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd duration"`
+	ITunesSeason      string      `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd season"`
+	ITunesEpisode     string      `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd episode"`
+	ITunesEpisodeType string      `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd episodeType"`
+	ITunesExplicit    string      `xml:"http://www.itunes.com/dtds/podcast-1.0.dtd explicit"`
+}
+
+// ITunesImage is the artwork of a podcast or episode
+type ITunesImage struct {
+	Href string `xml:"href,attr"`
+}
+
+// RSS 1.0 / RDF structures
 type RDFChannel struct {
-	Title       string `xml:"title"`
-	Description string `xml:"description"`
-	Link        string `xml:"link"`
+	Title       string     `xml:"title"`
+	Description string     `xml:"description"`
+	AtomLinks   []AtomLink `xml:"http://www.w3.org/2005/Atom link"` // Listed before Link so that atom:link is not read as the channel's link
+	Link        string     `xml:"link"`
 }
 
 type RDFItem struct {
 	Title       string `xml:"title"`
 	Description string `xml:"description"`
 	Link        string `xml:"link"`
-	Date        string `xml:"date"` // dc:date
+	Date        string `xml:"date"`    // dc:date
+	Creator     string `xml:"creator"` // dc:creator
 }
 
 // Atom structures
-type AtomFeed struct {
-	XMLName  xml.Name    `xml:"feed"`
-	Title    string      `xml:"title"`
-	Subtitle string      `xml:"subtitle"`
-	Link     []AtomLink  `xml:"link"`
-	Entries  []AtomEntry `xml:"entry"`
-}
-
 type AtomLink struct {
-	Href string `xml:"href,attr"`
-	Rel  string `xml:"rel,attr"`
-	Type string `xml:"type,attr"`
+	Href   string `xml:"href,attr"`
+	Rel    string `xml:"rel,attr"`
+	Type   string `xml:"type,attr"`
+	Length string `xml:"length,attr"`
 }
 
 type AtomEntry struct {
-	Title     string      `xml:"title"`
-	Summary   string      `xml:"summary"`
-	Content   AtomContent `xml:"content"`
-	Link      []AtomLink  `xml:"link"`
-	Published string      `xml:"published"`
-	Updated   string      `xml:"updated"`
-	ID        string      `xml:"id"`
+	ITunesItem // Listed first so that itunes:title and itunes:summary are not read as the entry's own
+
+	Title   string `xml:"title"`
+	Summary string `xml:"summary"`
+
+	// Media RSS, listed before Content so that media:content is not read as the entry's content
+	MediaContents   []MediaContent   `xml:"http://search.yahoo.com/mrss/ content"`
+	MediaThumbnails []MediaThumbnail `xml:"http://search.yahoo.com/mrss/ thumbnail"`
+	MediaGroups     []MediaGroup     `xml:"http://search.yahoo.com/mrss/ group"`
+
+	Content   AtomContent  `xml:"content"`
+	Link      []AtomLink   `xml:"link"`
+	Published string       `xml:"published"`
+	Updated   string       `xml:"updated"`
+	ID        string       `xml:"id"`
+	Authors   []AtomPerson `xml:"author"`
+}
+
+type AtomPerson struct {
+	Name string `xml:"name"`
+	URI  string `xml:"uri"`
 }
 
 type AtomContent struct {
@@ -131,6 +282,52 @@ type AtomContent struct {
 	Text string `xml:",chardata"`
 }
 
+// JSON Feed 1.1 structures (https://www.jsonfeed.org/version/1.1/)
+type JSONFeed struct {
+	Version     string           `json:"version"`
+	Title       string           `json:"title"`
+	HomePageURL string           `json:"home_page_url"`
+	FeedURL     string           `json:"feed_url"`
+	Description string           `json:"description"`
+	Icon        string           `json:"icon"`
+	Favicon     string           `json:"favicon"`
+	Authors     []JSONFeedAuthor `json:"authors"`
+	Author      *JSONFeedAuthor  `json:"author"` // JSON Feed 1.0
+	Items       []JSONFeedItem   `json:"items"`
+}
+
+type JSONFeedAuthor struct {
+	Name   string `json:"name"`
+	URL    string `json:"url"`
+	Avatar string `json:"avatar"`
+}
+
+type JSONFeedItem struct {
+	ID            string               `json:"id"`
+	URL           string               `json:"url"`
+	ExternalURL   string               `json:"external_url"`
+	Title         string               `json:"title"`
+	ContentHTML   string               `json:"content_html"`
+	ContentText   string               `json:"content_text"`
+	Summary       string               `json:"summary"`
+	Image         string               `json:"image"`
+	BannerImage   string               `json:"banner_image"`
+	DatePublished string               `json:"date_published"`
+	DateModified  string               `json:"date_modified"`
+	Authors       []JSONFeedAuthor     `json:"authors"`
+	Author        *JSONFeedAuthor      `json:"author"` // JSON Feed 1.0
+	Tags          []string             `json:"tags"`
+	Attachments   []JSONFeedAttachment `json:"attachments"`
+}
+
+type JSONFeedAttachment struct {
+	URL               string  `json:"url"`
+	MimeType          string  `json:"mime_type"`
+	Title             string  `json:"title"`
+	SizeInBytes       float64 `json:"size_in_bytes"`
+	DurationInSeconds float64 `json:"duration_in_seconds"`
+}
+
 // rssService implements RSSService interface
 type rssService struct {
 	secureClient *httpclient.SecureHTTPClient
@@ -154,6 +351,12 @@ func NewRSSService() RSSService {
 
 // FetchFeed fetches and parses a complete RSS/Atom feed
 func (s *rssService) FetchFeed(ctx context.Context, feedURL string) (*FeedData, error) {
+	return s.FetchFeedWithOptions(ctx, feedURL, nil)
+}
+
+// FetchFeedWithOptions fetches and parses a feed, sending conditional request headers
+// when cache validators are provided. A 304 response returns FeedData with NotModified set.
+func (s *rssService) FetchFeedWithOptions(ctx context.Context, feedURL string, opts *FetchOptions) (*FeedData, error) {
 	if feedURL == "" {
 		return nil, errors.New("feed URL is required")
 	}
@@ -164,9 +367,20 @@ func (s *rssService) FetchFeed(ctx context.Context, feedURL string) (*FeedData,
 		return nil, fmt.Errorf("invalid feed URL: %w", err)
 	}
 
-	// Set headers for RSS/Atom feeds
+	// Set headers for RSS/Atom/JSON feeds
 	headers := map[string]string{
-		"Accept": "application/rss+xml, application/atom+xml, application/xml, text/xml",
+		"Accept":          "application/rss+xml, application/atom+xml, application/feed+json, application/xml, text/xml, application/json",
+		"Accept-Encoding": httpclient.AcceptEncoding,
+	}
+
+	// Add conditional request headers
+	if opts != nil {
+		if opts.ETag != "" {
+			headers["If-None-Match"] = opts.ETag
+		}
+		if opts.LastModified != "" {
+			headers["If-Modified-Since"] = opts.LastModified
+		}
 	}
 
 	// Make secure request
@@ -176,32 +390,143 @@ func (s *rssService) FetchFeed(ctx context.Context, feedURL string) (*FeedData,
 	}
 	defer resp.Body.Close()
 
+	// Feed has not changed since the last fetch
+	if resp.StatusCode == http.StatusNotModified {
+		notModified := &FeedData{
+			URL:          feedURL,
+			Articles:     []ArticleData{},
+			ETag:         resp.Header.Get("ETag"),
+			LastModified: resp.Header.Get("Last-Modified"),
+			StatusCode:   resp.StatusCode,
+			NotModified:  true,
+			MaxAge:       parseMaxAge(resp.Header.Get("Cache-Control")),
+			RetryAfter:   parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
+		// Servers may omit validators on 304, so keep the ones we sent
+		if opts != nil {
+			if notModified.ETag == "" {
+				notModified.ETag = opts.ETag
+			}
+			if notModified.LastModified == "" {
+				notModified.LastModified = opts.LastModified
+			}
+		}
+		return notModified, nil
+	}
+
 	if resp.StatusCode != http.StatusOK {
-		return nil, fmt.Errorf("HTTP error: %d %s", resp.StatusCode, resp.Status)
+		return nil, &HTTPError{
+			StatusCode: resp.StatusCode,
+			Status:     resp.Status,
+			RetryAfter: parseRetryAfter(resp.Header.Get("Retry-After"), time.Now()),
+		}
 	}
 
-	// Read response body with size limit
-	const maxFeedSize = 10 * 1024 * 1024 // 10MB limit
-	limitedReader := io.LimitReader(resp.Body, maxFeedSize)
-	body, err := io.ReadAll(limitedReader)
+	body, err := httpclient.DecodeBody(resp)
 	if err != nil {
 		return nil, fmt.Errorf("failed to read response body: %w", err)
 	}
+	defer body.Close()
 
-	// Check if we hit the size limit
-	if len(body) == maxFeedSize {
-		return nil, errors.New("feed size exceeds maximum allowed size (10MB)")
+	// Relative URLs in the feed are resolved against the URL it was served from
+	feedBase, _ := url.Parse(feedURL)
+	if resp.Request != nil && resp.Request.URL != nil {
+		feedBase = resp.Request.URL
+	}
+
+	maxItems := defaultMaxFeedItems
+	if opts != nil && opts.MaxItems > 0 {
+		maxItems = opts.MaxItems
 	}
 
 	// Determine feed type and parse
-	feedData, err := s.parseFeed(body, feedURL)
+	feedData, err := s.readFeed(body, resp.Header.Get("Content-Type"), feedBase, maxItems)
 	if err != nil {
+		if errors.Is(err, errFeedTooLarge) {
+			return nil, errFeedTooLarge
+		}
 		return nil, fmt.Errorf("failed to parse feed: %w", err)
 	}
 
+	feedData.StatusCode = resp.StatusCode
+	feedData.ETag = resp.Header.Get("ETag")
+	feedData.LastModified = resp.Header.Get("Last-Modified")
+	feedData.MaxAge = parseMaxAge(resp.Header.Get("Cache-Control"))
+	feedData.RetryAfter = parseRetryAfter(resp.Header.Get("Retry-After"), time.Now())
+
 	return feedData, nil
 }
 
+// readFeed reads and parses a feed body. XML feeds are parsed as they are read, keeping the newest
+// maxItems items; JSON Feeds are read into memory first.
+func (s *rssService) readFeed(body io.Reader, contentType string, base *url.URL, maxItems int) (*FeedData, error) {
+	buffered := bufio.NewReader(body)
+	head, _ := buffered.Peek(512)
+
+	if isJSONFeed(head, contentType) {
+		data, err := io.ReadAll(&feedSizeLimiter{r: buffered, remaining: maxFeedSize})
+		if err != nil {
+			return nil, err
+		}
+		return s.parseFeed(data, contentType, base)
+	}
+
+	document, err := charset.NewDocumentReader(&feedSizeLimiter{r: buffered, remaining: maxStreamedFeedSize}, contentType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode feed: %w", err)
+	}
+	return s.streamXMLFeed(document, nil, base, maxItems)
+}
+
+// feedSizeLimiter reads from r, failing with errFeedTooLarge after more than remaining bytes
+type feedSizeLimiter struct {
+	r         io.Reader
+	remaining int64
+}
+
+func (l *feedSizeLimiter) Read(p []byte) (int, error) {
+	if int64(len(p)) > l.remaining+1 {
+		p = p[:l.remaining+1]
+	}
+	n, err := l.r.Read(p)
+	l.remaining -= int64(n)
+	if l.remaining < 0 {
+		return 0, errFeedTooLarge
+	}
+	return n, err
+}
+
+// parseMaxAge extracts max-age in seconds from a Cache-Control header
+func parseMaxAge(cacheControl string) int64 {
+	for _, directive := range strings.Split(cacheControl, ",") {
+		directive = strings.TrimSpace(strings.ToLower(directive))
+		if value, found := strings.CutPrefix(directive, "max-age="); found {
+			if seconds, err := strconv.ParseInt(strings.Trim(value, `"`), 10, 64); err == nil && seconds > 0 {
+				return seconds
+			}
+		}
+	}
+	return 0
+}
+
+// parseRetryAfter converts a Retry-After header (delay in seconds or HTTP date) to a Unix time
+func parseRetryAfter(retryAfter string, now time.Time) int64 {
+	retryAfter = strings.TrimSpace(retryAfter)
+	if retryAfter == "" {
+		return 0
+	}
+	if seconds, err := strconv.ParseInt(retryAfter, 10, 64); err == nil {
+		if seconds <= 0 {
+			return 0
+		}
+		return now.Add(time.Duration(seconds) * time.Second).Unix()
+	}
+	if t, err := http.ParseTime(retryAfter); err == nil && t.After(now) {
+		return t.Unix()
+	}
+	return 0
+}
+
 // FetchFeedInfo fetches basic feed information without articles
 func (s *rssService) FetchFeedInfo(ctx context.Context, feedURL string) (*FeedInfo, error) {
 	feedData, err := s.FetchFeed(ctx, feedURL)
@@ -216,80 +541,89 @@ func (s *rssService) FetchFeedInfo(ctx context.Context, feedURL string) (*FeedIn
 	}, nil
 }
 
-// parseFeed determines feed type and parses accordingly
-func (s *rssService) parseFeed(data []byte, feedURL string) (*FeedData, error) {
-	// Try to determine feed type by looking at the XML
-	dataStr := string(data)
+// parseFeed determines feed type from the Content-Type header and body, and parses accordingly.
+// Feeds in other encodings, such as Shift_JIS, are converted to UTF-8 first.
+// Relative URLs are resolved against base, the URL of the feed, if it is not nil.
+func (s *rssService) parseFeed(data []byte, contentType string, base *url.URL) (*FeedData, error) {
+	data, err := charset.ToUTF8(data, contentType)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode feed: %w", err)
+	}
 
-	if strings.Contains(dataStr, "<rss") || strings.Contains(dataStr, "<RSS") {
-		return s.ParseRSSFeed(data)
-	} else if strings.Contains(dataStr, "<feed") && (strings.Contains(dataStr, "atom") || strings.Contains(dataStr, "Atom")) {
-		return s.ParseAtomFeed(data)
-	} else if strings.Contains(dataStr, "<rdf:RDF") || strings.Contains(dataStr, "<RDF") {
-		// RSS 1.0 / RDF
-		return s.ParseRDFFeed(data)
+	if isJSONFeed(data, contentType) {
+		return s.parseJSONFeed(data, base)
 	}
 
-	// Default to RSS if we can't determine
-	return s.ParseRSSFeed(data)
+	// XML feeds are identified by their root element
+	return s.streamXMLFeed(bytes.NewReader(data), nil, base, 0)
+}
+
+// isJSONFeed reports whether a feed is a JSON Feed, from its content type or a leading JSON object
+func isJSONFeed(head []byte, contentType string) bool {
+	trimmed := bytes.TrimLeft(head, " \t\r\n\ufeff")
+	return strings.Contains(strings.ToLower(contentType), "json") || bytes.HasPrefix(trimmed, []byte("{"))
+}
+
+// ParseFeed parses a feed of any supported format, such as content pushed by a WebSub hub.
+// Relative URLs are resolved against feedURL.
+func (s *rssService) ParseFeed(data []byte, contentType string, feedURL string) (*FeedData, error) {
+	base, _ := url.Parse(feedURL)
+	return s.parseFeed(data, contentType, base)
 }
 
 // ParseRSSFeed parses RSS 2.0 feed data
 func (s *rssService) ParseRSSFeed(data []byte) (*FeedData, error) {
-	var rss RSS
-	err := xml.Unmarshal(data, &rss)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse RSS XML: %w", err)
-	}
+	return s.streamXMLFeed(bytes.NewReader(data), rssFormat, nil, 0)
+}
 
-	feedData := &FeedData{
-		Title:       s.CleanContent(rss.Channel.Title),
-		Description: s.CleanContent(rss.Channel.Description),
-		URL:         rss.Channel.Link,
-		Category:    s.CleanContent(rss.Channel.Category),
-		Articles:    make([]ArticleData, 0, len(rss.Channel.Items)),
-	}
+// ParseAtomFeed parses Atom feed data
+func (s *rssService) ParseAtomFeed(data []byte) (*FeedData, error) {
+	return s.streamXMLFeed(bytes.NewReader(data), atomFormat, nil, 0)
+}
 
-	// Parse articles
-	for _, item := range rss.Channel.Items {
-		article, err := s.parseRSSItem(item)
-		if err != nil {
-			// Skip invalid articles but continue processing
-			continue
-		}
-		feedData.Articles = append(feedData.Articles, *article)
-	}
+// ParseRDFFeed parses RSS 1.0 / RDF feed data
+func (s *rssService) ParseRDFFeed(data []byte) (*FeedData, error) {
+	return s.streamXMLFeed(bytes.NewReader(data), rdfFormat, nil, 0)
+}
 
-	return feedData, nil
+// ParseJSONFeed parses JSON Feed 1.0/1.1 data
+func (s *rssService) ParseJSONFeed(data []byte) (*FeedData, error) {
+	return s.parseJSONFeed(data, nil)
 }
 
-// ParseAtomFeed parses Atom feed data
-func (s *rssService) ParseAtomFeed(data []byte) (*FeedData, error) {
-	var atom AtomFeed
-	err := xml.Unmarshal(data, &atom)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse Atom XML: %w", err)
+// unmarshalXML decodes XML like xml.Unmarshal, converting documents whose XML declaration names
+// another encoding to UTF-8
+func unmarshalXML(data []byte, v interface{}) error {
+	decoder := xml.NewDecoder(bytes.NewReader(data))
+	decoder.CharsetReader = charset.NewReader
+	return decoder.Decode(v)
+}
+
+// parseJSONFeed parses JSON Feed 1.0/1.1 data, resolving relative URLs against base
+func (s *rssService) parseJSONFeed(data []byte, base *url.URL) (*FeedData, error) {
+	var feed JSONFeed
+	if err := json.Unmarshal(bytes.TrimPrefix(data, []byte("\ufeff")), &feed); err != nil {
+		return nil, fmt.Errorf("failed to parse JSON Feed: %w", err)
 	}
 
-	// Find the main link
-	var feedURL string
-	for _, link := range atom.Link {
-		if link.Rel == "alternate" || link.Rel == "" {
-			feedURL = link.Href
-			break
-		}
+	if !strings.HasPrefix(feed.Version, "https://jsonfeed.org/version/") {
+		return nil, fmt.Errorf("unsupported JSON Feed version: %q", feed.Version)
 	}
 
 	feedData := &FeedData{
-		Title:       s.CleanContent(atom.Title),
-		Description: s.CleanContent(atom.Subtitle),
-		URL:         feedURL,
-		Articles:    make([]ArticleData, 0, len(atom.Entries)),
+		Title:       s.CleanContent(feed.Title),
+		Description: s.CleanContent(feed.Description),
+		URL:         feed.HomePageURL,
+		Articles:    make([]ArticleData, 0, len(feed.Items)),
 	}
 
+	// Feed-level authors apply to items that don't list their own
+	feedAuthors := jsonFeedAuthors(feed.Authors, feed.Author)
+
 	// Parse articles
-	for _, entry := range atom.Entries {
-		article, err := s.parseAtomEntry(entry)
+	base = contentBase(base, feed.HomePageURL)
+	for _, item := range feed.Items {
+		article, err := s.parseJSONFeedItem(item, feedAuthors, base)
 		if err != nil {
 			// Skip invalid articles but continue processing
 			continue
@@ -300,36 +634,19 @@ func (s *rssService) ParseAtomFeed(data []byte) (*FeedData, error) {
 	return feedData, nil
 }
 
-// ParseRDFFeed parses RSS 1.0 / RDF feed data
-func (s *rssService) ParseRDFFeed(data []byte) (*FeedData, error) {
-	var rdf RDF
-	err := xml.Unmarshal(data, &rdf)
-	if err != nil {
-		return nil, fmt.Errorf("failed to parse RDF XML: %w", err)
+// parseRSSItem parses a single RSS item
+func (s *rssService) parseRSSItem(item Item, base *url.URL) (*ArticleData, error) {
+	// Podcast feeds may only carry the iTunes title and summary
+	if item.Title == "" {
+		item.Title = item.ITunesTitle
 	}
-
-	feedData := &FeedData{
-		Title:       s.CleanContent(rdf.Channel.Title),
-		Description: s.CleanContent(rdf.Channel.Description),
-		URL:         rdf.Channel.Link,
-		Articles:    make([]ArticleData, 0, len(rdf.Items)),
+	if item.Description == "" {
+		item.Description = item.ITunesSummary
 	}
-
-	// Parse articles
-	for _, item := range rdf.Items {
-		article, err := s.parseRDFItem(item)
-		if err != nil {
-			// Skip invalid articles but continue processing
-			continue
-		}
-		feedData.Articles = append(feedData.Articles, *article)
+	if item.Author == "" {
+		item.Author = item.ITunesAuthor
 	}
 
-	return feedData, nil
-}
-
-// parseRSSItem parses a single RSS item
-func (s *rssService) parseRSSItem(item Item) (*ArticleData, error) {
 	if item.Title == "" && item.Description == "" {
 		return nil, errors.New("item has no title or description")
 	}
@@ -350,23 +667,32 @@ func (s *rssService) parseRSSItem(item Item) (*ArticleData, error) {
 		content = item.Content
 	}
 
+	articleURL := resolveLink(base, item.Link)
 	article := &ArticleData{
 		Title:       s.CleanContent(item.Title),
-		Content:     s.CleanContent(content),
-		URL:         item.Link,
+		URL:         articleURL,
 		PublishedAt: publishedAt,
+		Authors:     rssAuthors(item.Creator, item.Author),
 	}
+	base = contentBase(base, articleURL)
+	article.setContent(content, base)
 
-	// Extract image URL from content
-	if imageURL := s.ExtractImageURL(content); imageURL != "" {
-		article.ImageURL = &imageURL
-	}
+	article.Media = itemMedia(base, item.MediaThumbnails, item.MediaContents, item.MediaGroups, item.Enclosures, item.ITunesImage.Href)
+	article.ImageURL = articleImage(article.Media, content, base)
+	article.Episode = itemEpisode(item.ITunesItem, article.Media)
 
 	return article, nil
 }
 
 // parseAtomEntry parses a single Atom entry
-func (s *rssService) parseAtomEntry(entry AtomEntry) (*ArticleData, error) {
+func (s *rssService) parseAtomEntry(entry AtomEntry, base *url.URL) (*ArticleData, error) {
+	if entry.Title == "" {
+		entry.Title = entry.ITunesTitle
+	}
+	if entry.Summary == "" {
+		entry.Summary = entry.ITunesSummary
+	}
+
 	if entry.Title == "" && entry.Summary == "" {
 		return nil, errors.New("entry has no title or summary")
 	}
@@ -398,23 +724,39 @@ func (s *rssService) parseAtomEntry(entry AtomEntry) (*ArticleData, error) {
 		content = entry.Content.Text
 	}
 
+	articleURL = resolveLink(base, articleURL)
 	article := &ArticleData{
 		Title:       s.CleanContent(entry.Title),
-		Content:     s.CleanContent(content),
 		URL:         articleURL,
 		PublishedAt: publishedAt,
+		Authors:     atomAuthors(entry.Authors),
+	}
+	if len(article.Authors) == 0 && entry.ITunesAuthor != "" {
+		article.Authors = []ArticleAuthor{{Name: s.CleanContent(entry.ITunesAuthor)}}
+	}
+	base = contentBase(base, articleURL)
+	if entry.Content.Text != "" && entry.Content.Type == "text" {
+		article.Content = truncateRunes(strings.Join(strings.Fields(content), " "), model.MaxArticleContent)
+	} else {
+		article.setContent(content, base)
 	}
 
-	// Extract image URL from content
-	if imageURL := s.ExtractImageURL(content); imageURL != "" {
-		article.ImageURL = &imageURL
+	// Enclosures are links with rel="enclosure"
+	var enclosures []Enclosure
+	for _, link := range entry.Link {
+		if link.Rel == "enclosure" {
+			enclosures = append(enclosures, Enclosure{URL: link.Href, Length: link.Length, Type: link.Type})
+		}
 	}
+	article.Media = itemMedia(base, entry.MediaThumbnails, entry.MediaContents, entry.MediaGroups, enclosures, entry.ITunesImage.Href)
+	article.ImageURL = articleImage(article.Media, content, base)
+	article.Episode = itemEpisode(entry.ITunesItem, article.Media)
 
 	return article, nil
 }
 
 // parseRDFItem parses a single RDF item
-func (s *rssService) parseRDFItem(item RDFItem) (*ArticleData, error) {
+func (s *rssService) parseRDFItem(item RDFItem, base *url.URL) (*ArticleData, error) {
 	if item.Title == "" && item.Description == "" {
 		return nil, errors.New("item has no title or description")
 	}
@@ -427,21 +769,164 @@ func (s *rssService) parseRDFItem(item RDFItem) (*ArticleData, error) {
 		}
 	}
 
+	articleURL := resolveLink(base, item.Link)
 	article := &ArticleData{
 		Title:       s.CleanContent(item.Title),
-		Content:     s.CleanContent(item.Description),
-		URL:         item.Link,
+		URL:         articleURL,
 		PublishedAt: publishedAt,
+		Authors:     rssAuthors(item.Creator, ""),
 	}
+	base = contentBase(base, articleURL)
+	article.setContent(item.Description, base)
+	article.ImageURL = articleImage(nil, item.Description, base)
 
-	// Extract image URL from content
-	if imageURL := s.ExtractImageURL(item.Description); imageURL != "" {
+	return article, nil
+}
+
+// parseJSONFeedItem parses a single JSON Feed item
+func (s *rssService) parseJSONFeedItem(item JSONFeedItem, feedAuthors []ArticleAuthor, base *url.URL) (*ArticleData, error) {
+	// Get content (prefer HTML over plain text, then summary)
+	content := item.ContentHTML
+	if content == "" {
+		content = item.ContentText
+	}
+	if content == "" {
+		content = item.Summary
+	}
+
+	// Titles are optional in JSON Feed (e.g. microblog posts), so fall back to the text
+	title := s.CleanContent(item.Title)
+	if title == "" {
+		title = truncateRunes(s.CleanContent(content), 100)
+	}
+	if title == "" {
+		return nil, errors.New("item has no title or content")
+	}
+
+	articleURL := item.URL
+	if articleURL == "" {
+		articleURL = item.ExternalURL
+	}
+	if articleURL == "" && strings.HasPrefix(item.ID, "http") {
+		articleURL = item.ID
+	}
+	articleURL = resolveLink(base, articleURL)
+
+	// Parse published date
+	publishedAt := time.Now()
+	dateStr := item.DatePublished
+	if dateStr == "" {
+		dateStr = item.DateModified
+	}
+	if dateStr != "" {
+		if parsed, err := s.parseDate(dateStr); err == nil {
+			publishedAt = parsed
+		}
+	}
+
+	article := &ArticleData{
+		Title:       title,
+		URL:         articleURL,
+		PublishedAt: publishedAt,
+		Authors:     jsonFeedAuthors(item.Authors, item.Author),
+	}
+	base = contentBase(base, articleURL)
+	if item.ContentHTML != "" {
+		article.setContent(item.ContentHTML, base)
+	} else {
+		article.Content = truncateRunes(strings.Join(strings.Fields(content), " "), model.MaxArticleContent)
+	}
+	if len(article.Authors) == 0 {
+		article.Authors = feedAuthors
+	}
+
+	for _, attachment := range item.Attachments {
+		if attachment.URL == "" || attachment.MimeType == "" {
+			continue
+		}
+		article.Attachments = append(article.Attachments, ArticleAttachment{
+			URL:               attachment.URL,
+			MimeType:          attachment.MimeType,
+			Title:             attachment.Title,
+			SizeInBytes:       int64(attachment.SizeInBytes),
+			DurationInSeconds: int64(attachment.DurationInSeconds),
+		})
+		article.Media = appendMedia(article.Media, model.ArticleMedia{
+			URL:      attachment.URL,
+			Type:     attachment.MimeType,
+			Size:     int64(attachment.SizeInBytes),
+			Duration: int64(attachment.DurationInSeconds),
+		}, base)
+	}
+	article.Episode = itemEpisode(ITunesItem{}, article.Media)
+
+	// Prefer the item's main image, then the banner, then its media and content
+	if imageURL := sanitize.URL(item.Image, base); imageURL != "" {
+		article.ImageURL = &imageURL
+	} else if imageURL := sanitize.URL(item.BannerImage, base); imageURL != "" {
 		article.ImageURL = &imageURL
+	} else {
+		article.ImageURL = articleImage(article.Media, item.ContentHTML, base)
 	}
 
 	return article, nil
 }
 
+// jsonFeedAuthors converts JSON Feed authors, falling back to the JSON Feed 1.0 single author
+func jsonFeedAuthors(authors []JSONFeedAuthor, legacy *JSONFeedAuthor) []ArticleAuthor {
+	if len(authors) == 0 && legacy != nil {
+		authors = []JSONFeedAuthor{*legacy}
+	}
+
+	result := make([]ArticleAuthor, 0, len(authors))
+	for _, author := range authors {
+		if author.Name == "" && author.URL == "" {
+			continue
+		}
+		result = append(result, ArticleAuthor{
+			Name:   author.Name,
+			URL:    author.URL,
+			Avatar: author.Avatar,
+		})
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
+// rssAuthors converts an RSS or RDF item's author, preferring dc:creator over the author element,
+// which usually holds an e-mail address followed by the name in parentheses
+func rssAuthors(creator, author string) []ArticleAuthor {
+	name := strings.TrimSpace(creator)
+	if name == "" {
+		name = strings.TrimSpace(author)
+		if open := strings.Index(name, "("); open > 0 && strings.HasSuffix(name, ")") {
+			name = strings.TrimSpace(name[open+1 : len(name)-1])
+		}
+	}
+	if name == "" {
+		return nil
+	}
+	return []ArticleAuthor{{Name: name}}
+}
+
+// atomAuthors converts an Atom entry's authors
+func atomAuthors(people []AtomPerson) []ArticleAuthor {
+	result := make([]ArticleAuthor, 0, len(people))
+	for _, person := range people {
+		name := strings.TrimSpace(person.Name)
+		if name == "" && person.URI == "" {
+			continue
+		}
+		result = append(result, ArticleAuthor{Name: name, URL: person.URI})
+	}
+	if len(result) == 0 {
+		return nil
+	}
+	return result
+}
+
 // parseDate parses various date formats commonly used in RSS/Atom feeds
 func (s *rssService) parseDate(dateStr string) (time.Time, error) {
 	// Common date formats in RSS/Atom feeds
@@ -468,56 +953,63 @@ func (s *rssService) parseDate(dateStr string) (time.Time, error) {
 	return time.Time{}, fmt.Errorf("unable to parse date: %s", dateStr)
 }
 
-// ExtractImageURL extracts the first image URL from HTML content
+// ExtractImageURL extracts the first image URL from HTML content, skipping tracking pixels.
+// Relative URLs are not returned.
 func (s *rssService) ExtractImageURL(content string) string {
 	if content == "" {
 		return ""
 	}
-
-	// Regular expression to find img tags with src attribute
-	imgRegex := regexp.MustCompile(`<img[^>]+src=["']([^"']+)["'][^>]*>`)
-	matches := imgRegex.FindStringSubmatch(content)
-
-	if len(matches) > 1 {
-		imageURL := matches[1]
-		// Basic validation - check if it looks like a valid image URL
-		if strings.HasPrefix(imageURL, "http") &&
-			(strings.Contains(imageURL, ".jpg") ||
-				strings.Contains(imageURL, ".jpeg") ||
-				strings.Contains(imageURL, ".png") ||
-				strings.Contains(imageURL, ".gif") ||
-				strings.Contains(imageURL, ".webp")) {
-			return imageURL
-		}
-	}
-
-	return ""
+	return sanitize.ImageURL(content, nil)
 }
 
-// CleanContent removes HTML tags and cleans up text content
+// CleanContent converts HTML content to plain text: tags are removed, entities are decoded and
+// whitespace is collapsed. Scripts and styles are removed with their content.
 func (s *rssService) CleanContent(content string) string {
 	if content == "" {
 		return ""
 	}
+	return sanitize.Text(content)
+}
 
-	// Remove HTML tags
-	htmlRegex := regexp.MustCompile(`<[^>]*>`)
-	cleaned := htmlRegex.ReplaceAllString(content, "")
+// setContent sets an article's sanitized HTML and plain-text summary from its feed content.
+// Relative URLs are resolved against base. Content without markup is kept only as text, and
+// HTML too large to store alongside the article is dropped in favor of the summary.
+func (a *ArticleData) setContent(content string, base *url.URL) {
+	a.Content = truncateRunes(sanitize.Text(content), model.MaxArticleContent)
 
-	// Decode common HTML entities
-	cleaned = strings.ReplaceAll(cleaned, "&amp;", "&")
-	cleaned = strings.ReplaceAll(cleaned, "&lt;", "<")
-	cleaned = strings.ReplaceAll(cleaned, "&gt;", ">")
-	cleaned = strings.ReplaceAll(cleaned, "&quot;", "\"")
-	cleaned = strings.ReplaceAll(cleaned, "&#39;", "'")
-	cleaned = strings.ReplaceAll(cleaned, "&apos;", "'")
-	cleaned = strings.ReplaceAll(cleaned, "&nbsp;", " ")
+	contentHTML := sanitize.HTML(content, base)
+	if strings.Contains(contentHTML, "<") && len(contentHTML) <= maxContentHTMLSize {
+		a.ContentHTML = contentHTML
+	}
+}
 
-	// Clean up whitespace
-	cleaned = regexp.MustCompile(`\s+`).ReplaceAllString(cleaned, " ")
-	cleaned = strings.TrimSpace(cleaned)
+// contentBase resolves a possibly relative link against base and returns it as the new base
+// for relative URLs. base is returned if the link does not resolve to an http(s) URL.
+func contentBase(base *url.URL, link string) *url.URL {
+	link = strings.TrimSpace(link)
+	if link == "" {
+		return base
+	}
+	u, err := url.Parse(link)
+	if err != nil {
+		return base
+	}
+	if base != nil {
+		u = base.ResolveReference(u)
+	}
+	if u.Scheme != "http" && u.Scheme != "https" {
+		return base
+	}
+	return u
+}
 
-	return cleaned
+// resolveLink resolves a relative link against base. Absolute links are returned unchanged.
+func resolveLink(base *url.URL, link string) string {
+	u, err := url.Parse(strings.TrimSpace(link))
+	if err != nil || u.IsAbs() || link == "" || base == nil {
+		return link
+	}
+	return base.ResolveReference(u).String()
 }
 
 // ConvertToArticles converts ArticleData slice to model.Article slice
@@ -533,10 +1025,22 @@ func ConvertToArticles(feedID string, articles []ArticleData) []*model.Article {
 			articleData.PublishedAt,
 		)
 
+		article.ContentHTML = articleData.ContentHTML
+		article.Media = articleData.Media
+		article.Episode = articleData.Episode
+
 		if articleData.ImageURL != nil {
 			article.SetImageURL(*articleData.ImageURL)
 		}
 
+		names := make([]string, 0, len(articleData.Authors))
+		for _, author := range articleData.Authors {
+			if author.Name != "" {
+				names = append(names, author.Name)
+			}
+		}
+		article.Author = strings.Join(names, ", ")
+
 		result = append(result, article)
 	}
 
@@ -4,7 +4,11 @@ import (
 	"context"
 	"net/http"
 	"net/http/httptest"
+	"net/url"
+	"os"
+	"path/filepath"
 	"testing"
+	"time"
 )
 
 func TestRSSService_FetchFeed_ValidFeed(t *testing.T) {
@@ -162,7 +166,7 @@ func TestRSSService_CleanContent(t *testing.T) {
 		},
 		{
 			"<script>alert('xss')</script>Safe content",
-			"alert('xss')Safe content", // HTML tags are removed, but content remains
+			"Safe content", // Scripts are removed with their content
 		},
 	}
 
@@ -382,6 +386,110 @@ func TestRSSService_ParseAtomWithMultipleLinks(t *testing.T) {
 	}
 }
 
+func TestRSSService_ParseAuthors(t *testing.T) {
+	service := NewRSSService()
+
+	rssData := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0" xmlns:dc="http://purl.org/dc/elements/1.1/">
+  <channel>
+    <title>Test Feed</title>
+    <item><title>By e-mail</title><link>http://example.com/1</link><author>jane@example.com (Jane Doe)</author></item>
+    <item><title>By creator</title><link>http://example.com/2</link><author>x@example.com</author><dc:creator>John Smith</dc:creator></item>
+    <item><title>Anonymous</title><link>http://example.com/3</link></item>
+  </channel>
+</rss>`)
+
+	feedData, err := service.ParseRSSFeed(rssData)
+	if err != nil {
+		t.Fatalf("Failed to parse RSS feed: %v", err)
+	}
+	articles := ConvertToArticles("feed-1", feedData.Articles)
+	for i, want := range []string{"Jane Doe", "John Smith", ""} {
+		if articles[i].Author != want {
+			t.Errorf("Expected article %d author %q, got %q", i, want, articles[i].Author)
+		}
+	}
+
+	atomData := []byte(`<?xml version="1.0" encoding="UTF-8"?>
+<feed xmlns="http://www.w3.org/2005/Atom">
+  <title>Test Feed</title>
+  <entry>
+    <title>Co-written</title>
+    <link href="http://example.com/4"/>
+    <author><name>Alice</name><uri>http://example.com/alice</uri></author>
+    <author><name>Bob</name></author>
+  </entry>
+</feed>`)
+
+	feedData, err = service.ParseAtomFeed(atomData)
+	if err != nil {
+		t.Fatalf("Failed to parse Atom feed: %v", err)
+	}
+	if authors := feedData.Articles[0].Authors; len(authors) != 2 || authors[0].URL != "http://example.com/alice" {
+		t.Errorf("Expected both Atom authors, got %+v", authors)
+	}
+	if author := ConvertToArticles("feed-1", feedData.Articles)[0].Author; author != "Alice, Bob" {
+		t.Errorf("Expected joined author names, got %q", author)
+	}
+}
+
+func TestRSSService_SanitizeContent(t *testing.T) {
+	service := NewRSSService().(*rssService)
+	base, _ := url.Parse("https://example.com/feeds/rss.xml")
+
+	rssData := `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0">
+  <channel>
+    <title>Example</title>
+    <link>/blog/</link>
+    <item>
+      <title>Sanitized article</title>
+      <link>posts/1</link>
+      <description><![CDATA[<p onclick="x()">See <a href="../about">about</a> &#8212; &#x65E5;&#26412;</p>
+<ul><li>One</li><li>Two</li></ul><pre><code>x := 1</code></pre>
+<img src="/images/a.png" alt="A"><img src="https://feeds.feedburner.com/~r/example/~4/1" height="1" width="1">
+<script>track()</script>]]></description>
+    </item>
+    <item>
+      <title>Plain article</title>
+      <link>https://example.com/blog/posts/2</link>
+      <description>Just text &amp; more</description>
+    </item>
+  </channel>
+</rss>`
+
+	feedData, err := service.parseFeed([]byte(rssData), "application/rss+xml", base)
+	if err != nil {
+		t.Fatalf("parseFeed() unexpected error: %v", err)
+	}
+	if len(feedData.Articles) != 2 {
+		t.Fatalf("Expected 2 articles, got %d", len(feedData.Articles))
+	}
+
+	article := feedData.Articles[0]
+	if article.URL != "https://example.com/blog/posts/1" {
+		t.Errorf("Expected the link resolved against the channel link, got %q", article.URL)
+	}
+	wantHTML := `<p>See <a href="https://example.com/blog/about" rel="nofollow noopener noreferrer">about</a> — 日本</p>
+<ul><li>One</li><li>Two</li></ul><pre><code>x := 1</code></pre>
+<img src="https://example.com/images/a.png" alt="A">`
+	if article.ContentHTML != wantHTML {
+		t.Errorf("Unexpected sanitized HTML:\n got: %s\nwant: %s", article.ContentHTML, wantHTML)
+	}
+	if article.Content != "See about — 日本 One Two x := 1" {
+		t.Errorf("Unexpected plain-text summary: %q", article.Content)
+	}
+
+	plain := feedData.Articles[1]
+	if plain.Content != "Just text & more" || plain.ContentHTML != "" {
+		t.Errorf("Expected text content without HTML, got %q / %q", plain.Content, plain.ContentHTML)
+	}
+
+	if contentHTML := ConvertToArticles("feed-1", feedData.Articles)[0].ContentHTML; contentHTML != wantHTML {
+		t.Errorf("Expected the sanitized HTML on the article, got %q", contentHTML)
+	}
+}
+
 func TestRSSService_CleanContentWithHTMLEntities(t *testing.T) {
 	service := NewRSSService()
 
@@ -409,6 +517,10 @@ func TestRSSService_CleanContentWithHTMLEntities(t *testing.T) {
 			"Multiple&nbsp;&nbsp;&nbsp;spaces",
 			"Multiple spaces",
 		},
+		{
+			"&#x65E5;&#26412; &mdash; caf&eacute;",
+			"日本 — café",
+		},
 	}
 
 	for _, tc := range testCases {
@@ -418,3 +530,260 @@ func TestRSSService_CleanContentWithHTMLEntities(t *testing.T) {
 		}
 	}
 }
+
+func TestRSSService_ParseRSSPollingHints(t *testing.T) {
+	service := NewRSSService()
+
+	rssData := `<?xml version="1.0" encoding="UTF-8"?>
+<rss version="2.0">
+  <channel>
+    <title>Test Feed</title>
+    <link>http://example.com</link>
+    <ttl>120</ttl>
+    <skipHours>
+      <hour>0</hour>
+      <hour>23</hour>
+      <hour>99</hour>
+    </skipHours>
+    <skipDays>
+      <day>Saturday</day>
+      <day>Sunday</day>
+    </skipDays>
+    <item>
+      <title>Test Article</title>
+      <link>http://example.com/article1</link>
+    </item>
+  </channel>
+</rss>`
+
+	feedData, err := service.ParseRSSFeed([]byte(rssData))
+	if err != nil {
+		t.Fatalf("Failed to parse RSS feed: %v", err)
+	}
+
+	if feedData.TTL != 120 {
+		t.Errorf("Expected TTL 120, got %d", feedData.TTL)
+	}
+	if len(feedData.SkipHours) != 2 || feedData.SkipHours[0] != 0 || feedData.SkipHours[1] != 23 {
+		t.Errorf("Expected skip hours [0 23], got %v", feedData.SkipHours)
+	}
+	if len(feedData.SkipDays) != 2 || feedData.SkipDays[0] != "Saturday" {
+		t.Errorf("Expected skip days [Saturday Sunday], got %v", feedData.SkipDays)
+	}
+}
+
+func TestParseMaxAge(t *testing.T) {
+	tests := []struct {
+		header   string
+		expected int64
+	}{
+		{"", 0},
+		{"max-age=3600", 3600},
+		{"public, max-age=600, must-revalidate", 600},
+		{"Max-Age=60", 60},
+		{"no-cache", 0},
+		{"max-age=abc", 0},
+	}
+
+	for _, tt := range tests {
+		if got := parseMaxAge(tt.header); got != tt.expected {
+			t.Errorf("parseMaxAge(%q) = %d, expected %d", tt.header, got, tt.expected)
+		}
+	}
+}
+
+func TestParseRetryAfter(t *testing.T) {
+	now := time.Date(2026, 1, 1, 12, 0, 0, 0, time.UTC)
+
+	tests := []struct {
+		header   string
+		expected int64
+	}{
+		{"", 0},
+		{"120", now.Add(2 * time.Minute).Unix()},
+		{"0", 0},
+		{"Thu, 01 Jan 2026 13:00:00 GMT", now.Add(time.Hour).Unix()},
+		{"Thu, 01 Jan 2026 11:00:00 GMT", 0}, // In the past
+		{"invalid", 0},
+	}
+
+	for _, tt := range tests {
+		if got := parseRetryAfter(tt.header, now); got != tt.expected {
+			t.Errorf("parseRetryAfter(%q) = %d, expected %d", tt.header, got, tt.expected)
+		}
+	}
+}
+
+func TestRSSService_ParseJSONFeed(t *testing.T) {
+	service := NewRSSService()
+
+	jsonData := `{
+  "version": "https://jsonfeed.org/version/1.1",
+  "title": "Test JSON Feed",
+  "home_page_url": "https://example.com/",
+  "description": "A test JSON feed",
+  "authors": [{"name": "Feed Author", "url": "https://example.com/about"}],
+  "items": [
+    {
+      "id": "1",
+      "url": "https://example.com/posts/1",
+      "title": "First Post",
+      "content_html": "<p>Hello <b>world</b></p>",
+      "image": "https://example.com/images/1.jpg",
+      "date_published": "2024-01-02T03:04:05Z",
+      "authors": [{"name": "Jane", "avatar": "https://example.com/jane.png"}],
+      "attachments": [
+        {"url": "https://example.com/ep1.mp3", "mime_type": "audio/mpeg", "size_in_bytes": 12345678, "duration_in_seconds": 1800}
+      ]
+    },
+    {
+      "id": "https://example.com/posts/2",
+      "content_text": "A short microblog post without a title",
+      "banner_image": "https://example.com/images/banner.png"
+    },
+    {
+      "id": "3"
+    }
+  ]
+}`
+
+	feedData, err := service.ParseJSONFeed([]byte(jsonData))
+	if err != nil {
+		t.Fatalf("Failed to parse JSON Feed: %v", err)
+	}
+
+	if feedData.Title != "Test JSON Feed" || feedData.URL != "https://example.com/" {
+		t.Errorf("Unexpected feed metadata: %+v", feedData)
+	}
+	if len(feedData.Articles) != 2 {
+		t.Fatalf("Expected 2 articles, got %d", len(feedData.Articles))
+	}
+
+	first := feedData.Articles[0]
+	if first.Title != "First Post" || first.Content != "Hello world" || first.URL != "https://example.com/posts/1" {
+		t.Errorf("Unexpected first article: %+v", first)
+	}
+	if first.PublishedAt.Year() != 2024 {
+		t.Errorf("Expected published year 2024, got %d", first.PublishedAt.Year())
+	}
+	if first.ImageURL == nil || *first.ImageURL != "https://example.com/images/1.jpg" {
+		t.Errorf("Expected item image, got %v", first.ImageURL)
+	}
+	if len(first.Authors) != 1 || first.Authors[0].Name != "Jane" || first.Authors[0].Avatar == "" {
+		t.Errorf("Expected item author Jane, got %+v", first.Authors)
+	}
+	if len(first.Attachments) != 1 || first.Attachments[0].MimeType != "audio/mpeg" ||
+		first.Attachments[0].SizeInBytes != 12345678 || first.Attachments[0].DurationInSeconds != 1800 {
+		t.Errorf("Unexpected attachments: %+v", first.Attachments)
+	}
+
+	second := feedData.Articles[1]
+	if second.Title != "A short microblog post without a title" {
+		t.Errorf("Expected title to fall back to content text, got %q", second.Title)
+	}
+	if second.URL != "https://example.com/posts/2" {
+		t.Errorf("Expected URL to fall back to id, got %q", second.URL)
+	}
+	if second.ImageURL == nil || *second.ImageURL != "https://example.com/images/banner.png" {
+		t.Errorf("Expected banner image, got %v", second.ImageURL)
+	}
+	if len(second.Authors) != 1 || second.Authors[0].Name != "Feed Author" {
+		t.Errorf("Expected feed-level author fallback, got %+v", second.Authors)
+	}
+}
+
+func TestRSSService_ParseJSONFeed_Invalid(t *testing.T) {
+	service := NewRSSService()
+
+	invalid := []string{
+		`not json`,
+		`{"title": "No version"}`,
+		`{"version": "1.0", "title": "Wrong version"}`,
+	}
+
+	for _, data := range invalid {
+		if _, err := service.ParseJSONFeed([]byte(data)); err == nil {
+			t.Errorf("Expected error for invalid JSON Feed: %s", data)
+		}
+	}
+}
+
+func TestRSSService_ParseFeed_Sniffing(t *testing.T) {
+	service := NewRSSService().(*rssService)
+
+	jsonFeed := `{"version": "https://jsonfeed.org/version/1", "title": "JSON", "items": [{"id": "1", "title": "Item"}]}`
+	rssFeed := `<?xml version="1.0"?><rss version="2.0"><channel><title>RSS</title><item><title>Item</title></item></channel></rss>`
+
+	tests := []struct {
+		name        string
+		data        string
+		contentType string
+		expected    string
+	}{
+		{"json by content type", jsonFeed, "application/feed+json; charset=utf-8", "JSON"},
+		{"json by body", "\n  " + jsonFeed, "text/plain", "JSON"},
+		{"rss", rssFeed, "application/rss+xml", "RSS"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feedData, err := service.parseFeed([]byte(tt.data), tt.contentType, nil)
+			if err != nil {
+				t.Fatalf("Unexpected error: %v", err)
+			}
+			if feedData.Title != tt.expected {
+				t.Errorf("Expected %s feed, got %q", tt.expected, feedData.Title)
+			}
+		})
+	}
+}
+
+func TestRSSService_ParseFeed_Encodings(t *testing.T) {
+	service := NewRSSService().(*rssService)
+
+	tests := []struct {
+		fixture     string
+		contentType string
+		title       string
+		article     string
+	}{
+		{"shift_jis.xml", "application/rss+xml", "日本語のフィード", "文字コードのテスト：ｶﾀｶﾅ～①"},
+		{"euc-jp.xml", "text/xml", "日本語のフィード", "文字コードのテスト：ｶﾀｶﾅ～"},
+		{"iso-8859-1.xml", "application/rss+xml", "Café crème", "Déjà vu à Zürich"},
+		{"shift_jis-undeclared.xml", "application/rss+xml; charset=Shift_JIS", "日本語のフィード", "文字コードのテスト：ｶﾀｶﾅ～①"},
+		{"utf-16le-bom.xml", "application/xml", "日本語のフィード", "文字コードのテスト：ｶﾀｶﾅ～①"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			data, err := os.ReadFile(filepath.Join("..", "..", "testdata", "charset", tt.fixture))
+			if err != nil {
+				t.Fatalf("failed to read fixture: %v", err)
+			}
+
+			feedData, err := service.parseFeed(data, tt.contentType, nil)
+			if err != nil {
+				t.Fatalf("parseFeed() unexpected error: %v", err)
+			}
+			if feedData.Title != tt.title {
+				t.Errorf("Expected title %q, got %q", tt.title, feedData.Title)
+			}
+			if len(feedData.Articles) != 1 || feedData.Articles[0].Title != tt.article {
+				t.Errorf("Expected article %q, got %+v", tt.article, feedData.Articles)
+			}
+		})
+	}
+
+	// Parsing without a Content-Type still honors the XML declaration
+	data, err := os.ReadFile(filepath.Join("..", "..", "testdata", "charset", "euc-jp.xml"))
+	if err != nil {
+		t.Fatalf("failed to read fixture: %v", err)
+	}
+	feedData, err := service.ParseRSSFeed(data)
+	if err != nil {
+		t.Fatalf("ParseRSSFeed() unexpected error: %v", err)
+	}
+	if feedData.Title != "日本語のフィード" {
+		t.Errorf("Expected the EUC-JP title to be decoded, got %q", feedData.Title)
+	}
+}
@@ -0,0 +1,341 @@
+package service
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"log"
+	"time"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"feed-bower-api/internal/model"
+)
+
+const (
+	// cleanupPageSize is how many feeds or articles a cleanup reads at a time
+	cleanupPageSize = 100
+
+	// unsubscribedFeedGracePeriod keeps new feeds, which are created before their first subscription
+	unsubscribedFeedGracePeriod = time.Hour
+
+	// defaultArticleMaxAgeDays is how long articles are kept by default
+	defaultArticleMaxAgeDays = 90
+)
+
+// CleanupOptions controls a CleanupOrphanedArticles run
+type CleanupOptions struct {
+	KeepUnsubscribedFeeds bool `json:"keep_unsubscribed_feeds,omitempty"` // Don't delete feeds without subscribers
+	DryRun                bool `json:"-"`                                 // Count what would be deleted without deleting it
+}
+
+// CleanupSummary is the structured result of a CleanupOrphanedArticles run. In a dry run, the
+// deleted counts are what would have been deleted.
+type CleanupSummary struct {
+	DryRun          bool `json:"dry_run"`
+	FeedsChecked    int  `json:"feeds_checked"`
+	FeedsDeleted    int  `json:"feeds_deleted"` // Feeds without subscribers
+	ArticlesChecked int  `json:"articles_checked"`
+	ArticlesDeleted int  `json:"articles_deleted"`
+	Errors          int  `json:"errors"`
+}
+
+// PurgeOptions controls a PurgeOldArticles run
+type PurgeOptions struct {
+	MaxAgeDays int  `json:"max_age_days,omitempty"` // Articles older than this are deleted; 90 if 0
+	DryRun     bool `json:"-"`                      // Count what would be deleted without deleting it
+}
+
+// PurgeSummary is the structured result of a PurgeOldArticles run. In a dry run, the deleted
+// counts are what would have been deleted.
+type PurgeSummary struct {
+	DryRun          bool  `json:"dry_run"`
+	Cutoff          int64 `json:"cutoff"` // Articles published and stored before this time are old
+	ArticlesChecked int   `json:"articles_checked"`
+	ArticlesDeleted int   `json:"articles_deleted"`
+	LikedKept       int   `json:"liked_kept"` // Old articles kept because users like them
+	Errors          int   `json:"errors"`
+}
+
+// CleanupOrphanedArticles removes articles whose feeds no longer exist. Unless opts says to keep
+// them, feeds that no bower subscribes to are removed first, along with their articles.
+func (s *schedulerService) CleanupOrphanedArticles(ctx context.Context, opts *CleanupOptions) (*CleanupSummary, error) {
+	if opts == nil {
+		opts = &CleanupOptions{}
+	}
+
+	log.Println("🧹 Starting orphaned articles cleanup...")
+
+	summary := &CleanupSummary{DryRun: opts.DryRun}
+
+	// Feeds that exist, or have been deleted by this run, by ID
+	feedExists := make(map[string]bool)
+
+	if !opts.KeepUnsubscribedFeeds {
+		if err := s.deleteUnsubscribedFeeds(ctx, opts.DryRun, feedExists, summary); err != nil {
+			return summary, err
+		}
+	}
+
+	var lastKey map[string]types.AttributeValue
+	for {
+		if err := ctx.Err(); err != nil {
+			return summary, err
+		}
+
+		articles, nextKey, err := s.articleRepo.List(ctx, cleanupPageSize, lastKey)
+		if err != nil {
+			return summary, fmt.Errorf("failed to list articles: %w", err)
+		}
+
+		for _, article := range articles {
+			summary.ArticlesChecked++
+
+			// Articles merged from several feeds are kept while any of the feeds exists
+			orphaned := true
+			for _, feedID := range article.FeedIDs() {
+				exists, err := s.feedExists(ctx, feedID, feedExists)
+				if err != nil || exists {
+					if err != nil {
+						log.Printf("❌ Failed to check feed %s of article %s: %v", feedID, article.ArticleID, err)
+						summary.Errors++
+					}
+					orphaned = false
+					break
+				}
+			}
+			if !orphaned {
+				continue
+			}
+
+			if !opts.DryRun {
+				log.Printf("🗑️  Deleting orphaned article: %s (feed_id: %s)", article.ArticleID, article.FeedID)
+				if err := s.deleteOrphanedArticle(ctx, article); err != nil {
+					log.Printf("❌ Failed to delete article %s: %v", article.ArticleID, err)
+					summary.Errors++
+					continue
+				}
+			}
+			summary.ArticlesDeleted++
+		}
+
+		if len(nextKey) == 0 {
+			break
+		}
+		lastKey = nextKey
+	}
+
+	log.Printf("✨ Cleanup completed!")
+	log.Printf("📊 Summary:")
+	log.Printf("   - Total feeds checked: %d", summary.FeedsChecked)
+	log.Printf("   - Unsubscribed feeds deleted: %d", summary.FeedsDeleted)
+	log.Printf("   - Total articles checked: %d", summary.ArticlesChecked)
+	log.Printf("   - Orphaned articles deleted: %d", summary.ArticlesDeleted)
+	log.Printf("   - Errors: %d", summary.Errors)
+
+	return summary, nil
+}
+
+// deleteUnsubscribedFeeds deletes the feeds that no bower subscribes to, recording them as missing
+// in feedExists so that their articles are cleaned up as orphans
+func (s *schedulerService) deleteUnsubscribedFeeds(ctx context.Context, dryRun bool, feedExists map[string]bool, summary *CleanupSummary) error {
+	createdBefore := time.Now().Add(-unsubscribedFeedGracePeriod).Unix()
+
+	var lastKey map[string]types.AttributeValue
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		feeds, nextKey, err := s.feedRepo.List(ctx, cleanupPageSize, lastKey)
+		if err != nil {
+			return fmt.Errorf("failed to list feeds: %w", err)
+		}
+
+		for _, feed := range feeds {
+			summary.FeedsChecked++
+			feedExists[feed.FeedID] = true
+			if feed.SubscriberCount > 0 || feed.LegacyBowerID != "" || feed.CreatedAt > createdBefore {
+				continue
+			}
+
+			// The count is only a cache of the subscriptions
+			subscriptions, err := s.feedRepo.GetSubscriptionsByFeedID(ctx, feed.FeedID)
+			if err != nil {
+				log.Printf("❌ Failed to get subscriptions of feed %s: %v", feed.FeedID, err)
+				summary.Errors++
+				continue
+			}
+			if len(subscriptions) > 0 {
+				continue
+			}
+
+			if !dryRun {
+				log.Printf("🗑️  Deleting unsubscribed feed: %s (%s)", feed.FeedID, feed.URL)
+				deleted, err := s.feedRepo.DeleteIfUnsubscribed(ctx, feed.FeedID)
+				if err != nil {
+					log.Printf("❌ Failed to delete feed %s: %v", feed.FeedID, err)
+					summary.Errors++
+					continue
+				}
+				if !deleted {
+					// Subscribed in the meantime
+					continue
+				}
+			}
+			feedExists[feed.FeedID] = false
+			summary.FeedsDeleted++
+		}
+
+		if len(nextKey) == 0 {
+			return nil
+		}
+		lastKey = nextKey
+	}
+}
+
+// feedExists checks if a feed exists, caching the answer in known
+func (s *schedulerService) feedExists(ctx context.Context, feedID string, known map[string]bool) (bool, error) {
+	if exists, ok := known[feedID]; ok {
+		return exists, nil
+	}
+
+	feed, err := s.feedRepo.GetByID(ctx, feedID)
+	if err != nil && !isNotFoundError(err) {
+		return false, err
+	}
+	known[feedID] = err == nil && feed != nil
+	return known[feedID], nil
+}
+
+// deleteOrphanedArticle deletes an article, or removes an alias from the article it points at
+func (s *schedulerService) deleteOrphanedArticle(ctx context.Context, article *model.Article) error {
+	if article.IsAlias() {
+		err := s.articleRepo.RemoveSourceFeed(ctx, article.DuplicateOf, article.FeedID)
+		if isNotFoundError(err) {
+			err = nil
+		}
+		return err
+	}
+
+	if err := s.deleteArticleUserData(ctx, article); err != nil {
+		return err
+	}
+	return s.articleRepo.Delete(ctx, article.ArticleID)
+}
+
+// PurgeOldArticles deletes the articles that were both published and stored more than
+// opts.MaxAgeDays ago. Articles users like are kept, as they are listed by the chick.
+func (s *schedulerService) PurgeOldArticles(ctx context.Context, opts *PurgeOptions) (*PurgeSummary, error) {
+	if opts == nil {
+		opts = &PurgeOptions{}
+	}
+	if opts.MaxAgeDays < 0 {
+		return nil, errors.New("max_age_days cannot be negative")
+	}
+	maxAgeDays := opts.MaxAgeDays
+	if maxAgeDays == 0 {
+		maxAgeDays = defaultArticleMaxAgeDays
+	}
+	if s.chickRepo == nil {
+		return nil, errors.New("chick repository is required to keep liked articles")
+	}
+
+	summary := &PurgeSummary{
+		DryRun: opts.DryRun,
+		Cutoff: time.Now().AddDate(0, 0, -maxAgeDays).Unix(),
+	}
+
+	log.Printf("🧹 Purging articles older than %d days...", maxAgeDays)
+
+	var lastKey map[string]types.AttributeValue
+	for {
+		if err := ctx.Err(); err != nil {
+			return summary, err
+		}
+
+		articles, nextKey, err := s.articleRepo.List(ctx, cleanupPageSize, lastKey)
+		if err != nil {
+			return summary, fmt.Errorf("failed to list articles: %w", err)
+		}
+
+		for _, article := range articles {
+			// Aliases are deleted along with the article they point at
+			if article.IsAlias() {
+				continue
+			}
+			summary.ArticlesChecked++
+			if article.PublishedAt >= summary.Cutoff || article.CreatedAt >= summary.Cutoff {
+				continue
+			}
+
+			likes, err := s.chickRepo.GetLikeCountByArticleID(ctx, article.ArticleID)
+			if err != nil {
+				log.Printf("❌ Failed to count likes of article %s: %v", article.ArticleID, err)
+				summary.Errors++
+				continue
+			}
+			if likes > 0 {
+				summary.LikedKept++
+				continue
+			}
+
+			if !opts.DryRun {
+				if err := s.deleteOldArticle(ctx, article); err != nil {
+					log.Printf("❌ Failed to delete article %s: %v", article.ArticleID, err)
+					summary.Errors++
+					continue
+				}
+			}
+			summary.ArticlesDeleted++
+		}
+
+		if len(nextKey) == 0 {
+			break
+		}
+		lastKey = nextKey
+	}
+
+	log.Printf("✨ Purge completed!")
+	log.Printf("📊 Summary:")
+	log.Printf("   - Total articles checked: %d", summary.ArticlesChecked)
+	log.Printf("   - Old articles deleted: %d", summary.ArticlesDeleted)
+	log.Printf("   - Liked articles kept: %d", summary.LikedKept)
+	log.Printf("   - Errors: %d", summary.Errors)
+
+	return summary, nil
+}
+
+// deleteOldArticle deletes an article with its aliases in the feeds it was merged from
+func (s *schedulerService) deleteOldArticle(ctx context.Context, article *model.Article) error {
+	if err := s.deleteArticleUserData(ctx, article); err != nil {
+		return err
+	}
+
+	articleIDs := []string{article.ArticleID}
+	for _, feedID := range article.SourceFeedIDs {
+		articleIDs = append(articleIDs, model.ArticleAliasID(article.ArticleID, feedID))
+	}
+	return s.articleRepo.BatchDelete(ctx, articleIDs)
+}
+
+// deleteArticleUserData removes users' likes, read state and playback positions of an article
+// about to be deleted
+func (s *schedulerService) deleteArticleUserData(ctx context.Context, article *model.Article) error {
+	if s.chickRepo != nil {
+		if _, err := s.chickRepo.RemoveLikedArticlesByArticleID(ctx, article.ArticleID); err != nil {
+			return fmt.Errorf("failed to remove likes: %w", err)
+		}
+	}
+	if s.readStateRepo != nil {
+		if _, err := s.readStateRepo.DeleteByArticleID(ctx, article.ArticleID); err != nil {
+			return fmt.Errorf("failed to delete read states: %w", err)
+		}
+	}
+	if s.playbackRepo != nil && article.Episode != nil {
+		if _, err := s.playbackRepo.DeleteByArticleID(ctx, article.ArticleID); err != nil {
+			return fmt.Errorf("failed to delete playback positions: %w", err)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,150 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"feed-bower-api/internal/model"
+)
+
+func newCleanupTestScheduler(repos *MockRepositories) SchedulerService {
+	scheduler := NewSchedulerService(repos.FeedRepo, repos.ArticleRepo, NewMockRSSService())
+	scheduler.(*schedulerService).SetUserDataRepositories(repos.ChickRepo, repos.ReadStateRepo, repos.PlaybackRepo)
+	return scheduler
+}
+
+func TestSchedulerService_CleanupOrphanedArticles(t *testing.T) {
+	ctx := context.Background()
+	repos := NewMockRepositories()
+	scheduler := newCleanupTestScheduler(repos)
+	old := time.Now().Add(-2 * time.Hour).Unix()
+
+	subscribed := model.NewFeed("bower-1", "https://example.com/feed.xml", "Subscribed", "", "")
+	repos.FeedRepo.Create(ctx, subscribed)
+	unsubscribed := model.NewFeed("", "https://unsubscribed.example.com/feed.xml", "Unsubscribed", "", "")
+	unsubscribed.CreatedAt = old
+	repos.FeedRepo.Create(ctx, unsubscribed)
+	// Feeds are created before their first subscription
+	newFeed := model.NewFeed("", "https://new.example.com/feed.xml", "New", "", "")
+	repos.FeedRepo.Create(ctx, newFeed)
+
+	articles := map[string]string{
+		"kept":         subscribed.FeedID,
+		"unsubscribed": unsubscribed.FeedID,
+		"deleted-feed": "deleted-feed",
+	}
+	for articleID, feedID := range articles {
+		article := model.NewArticle(feedID, articleID, "content", "https://example.com/"+articleID, time.Now())
+		article.ArticleID = articleID
+		repos.ArticleRepo.Create(ctx, article)
+	}
+	// Merged articles are kept while one of their feeds exists
+	repos.ArticleRepo.AddSourceFeed(ctx, "deleted-feed", subscribed.FeedID)
+	repos.ReadStateRepo.MarkRead(ctx, model.NewReadState("user-1", "unsubscribed"))
+
+	// A dry run counts without deleting
+	summary, err := scheduler.CleanupOrphanedArticles(ctx, &CleanupOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("CleanupOrphanedArticles() unexpected error: %v", err)
+	}
+	expected := CleanupSummary{DryRun: true, FeedsChecked: 3, FeedsDeleted: 1, ArticlesChecked: 4, ArticlesDeleted: 1}
+	if *summary != expected {
+		t.Errorf("CleanupOrphanedArticles() dry run = %+v, expected %+v", *summary, expected)
+	}
+	if len(repos.ArticleRepo.articles) != 4 || len(repos.FeedRepo.feeds) != 3 {
+		t.Errorf("Expected a dry run to delete nothing, got %d articles and %d feeds", len(repos.ArticleRepo.articles), len(repos.FeedRepo.feeds))
+	}
+
+	summary, err = scheduler.CleanupOrphanedArticles(ctx, nil)
+	if err != nil {
+		t.Fatalf("CleanupOrphanedArticles() unexpected error: %v", err)
+	}
+	expected.DryRun = false
+	if *summary != expected {
+		t.Errorf("CleanupOrphanedArticles() = %+v, expected %+v", *summary, expected)
+	}
+	if _, exists := repos.ArticleRepo.articles["unsubscribed"]; exists {
+		t.Error("Expected the article of the unsubscribed feed to be deleted")
+	}
+	if _, exists := repos.FeedRepo.feeds[newFeed.FeedID]; !exists {
+		t.Error("Expected the new feed to be kept")
+	}
+	if read, _ := repos.ReadStateRepo.IsRead(ctx, "user-1", "unsubscribed"); read {
+		t.Error("Expected the read state of the deleted article to be removed")
+	}
+
+	// Without its other feed, the merged article and its alias are orphaned
+	repos.FeedRepo.Delete(ctx, subscribed.FeedID)
+	summary, err = scheduler.CleanupOrphanedArticles(ctx, &CleanupOptions{KeepUnsubscribedFeeds: true})
+	if err != nil {
+		t.Fatalf("CleanupOrphanedArticles() unexpected error: %v", err)
+	}
+	if summary.FeedsChecked != 0 || summary.ArticlesDeleted != 3 || len(repos.ArticleRepo.articles) != 0 {
+		t.Errorf("Expected every article to be deleted, got %+v with %d articles left", *summary, len(repos.ArticleRepo.articles))
+	}
+}
+
+func TestSchedulerService_PurgeOldArticles(t *testing.T) {
+	ctx := context.Background()
+	repos := NewMockRepositories()
+	scheduler := newCleanupTestScheduler(repos)
+	old := time.Now().AddDate(0, 0, -100)
+
+	feed := model.NewFeed("bower-1", "https://example.com/feed.xml", "Example", "", "")
+	repos.FeedRepo.Create(ctx, feed)
+
+	add := func(articleID string, published, created time.Time) {
+		article := model.NewArticle(feed.FeedID, articleID, "content", "https://example.com/"+articleID, published)
+		article.ArticleID = articleID
+		article.CreatedAt = created.Unix()
+		repos.ArticleRepo.Create(ctx, article)
+	}
+	add("old", old, old)
+	add("old-liked", old, old)
+	add("recent", time.Now(), time.Now())
+	// Old articles of a newly added feed are kept until they have been stored for as long
+	add("old-new", old, time.Now())
+	repos.ArticleRepo.AddSourceFeed(ctx, "old", "other-feed")
+	repos.ChickRepo.AddLikedArticle(ctx, model.NewLikedArticle("user-1", "old-liked"))
+	repos.ReadStateRepo.MarkRead(ctx, model.NewReadState("user-1", "old"))
+
+	summary, err := scheduler.PurgeOldArticles(ctx, &PurgeOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("PurgeOldArticles() unexpected error: %v", err)
+	}
+	if summary.ArticlesChecked != 4 || summary.ArticlesDeleted != 1 || summary.LikedKept != 1 {
+		t.Errorf("Unexpected dry run summary: %+v", *summary)
+	}
+	if len(repos.ArticleRepo.articles) != 5 {
+		t.Errorf("Expected a dry run to delete nothing, got %d articles", len(repos.ArticleRepo.articles))
+	}
+
+	summary, err = scheduler.PurgeOldArticles(ctx, nil)
+	if err != nil {
+		t.Fatalf("PurgeOldArticles() unexpected error: %v", err)
+	}
+	if summary.DryRun || summary.ArticlesDeleted != 1 {
+		t.Errorf("Expected one article to be deleted, got %+v", *summary)
+	}
+	for _, articleID := range []string{"old", model.ArticleAliasID("old", "other-feed")} {
+		if _, exists := repos.ArticleRepo.articles[articleID]; exists {
+			t.Errorf("Expected %s to be deleted", articleID)
+		}
+	}
+	if len(repos.ArticleRepo.articles) != 3 {
+		t.Errorf("Expected 3 articles to be kept, got %d", len(repos.ArticleRepo.articles))
+	}
+	if read, _ := repos.ReadStateRepo.IsRead(ctx, "user-1", "old"); read {
+		t.Error("Expected the read state of the deleted article to be removed")
+	}
+
+	// Nothing left is older than a longer maximum age
+	summary, err = scheduler.PurgeOldArticles(ctx, &PurgeOptions{MaxAgeDays: 200})
+	if err != nil || summary.ArticlesDeleted != 0 {
+		t.Errorf("Expected nothing older than 200 days, got %+v, %v", summary, err)
+	}
+	if _, err := scheduler.PurgeOldArticles(ctx, &PurgeOptions{MaxAgeDays: -1}); err == nil {
+		t.Error("Expected an error for a negative maximum age")
+	}
+}
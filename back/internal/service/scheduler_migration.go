@@ -0,0 +1,323 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"feed-bower-api/internal/model"
+)
+
+// MigrateFeedsOptions controls a MigrateFeeds run
+type MigrateFeedsOptions struct {
+	DryRun bool `json:"-"` // Count what would be migrated without migrating it
+}
+
+// MigrateFeedsSummary is the structured result of a MigrateFeeds run. In a dry run, the counts are
+// what would have been migrated.
+type MigrateFeedsSummary struct {
+	DryRun              bool `json:"dry_run"`
+	FeedsChecked        int  `json:"feeds_checked"`
+	LegacyLinksMigrated int  `json:"legacy_links_migrated"` // bower_id links turned into subscriptions
+	FeedsMerged         int  `json:"feeds_merged"`          // Feeds merged into the canonical feed for their URL
+	SubscriptionsMoved  int  `json:"subscriptions_moved"`
+	ArticlesMoved       int  `json:"articles_moved"`
+	DuplicateArticles   int  `json:"duplicate_articles"` // Articles the canonical feed already had, deleted
+	Errors              int  `json:"errors"`
+}
+
+// MigrateFeeds migrates feeds stored before feeds were shared between bowers. The bower that owned
+// each feed becomes a subscription to it, and feeds with the same URL are merged into the canonical
+// feed for the URL, along with their subscriptions and articles. Runs can be repeated: migrated
+// feeds are left as they are.
+func (s *schedulerService) MigrateFeeds(ctx context.Context, opts *MigrateFeedsOptions) (*MigrateFeedsSummary, error) {
+	if opts == nil {
+		opts = &MigrateFeedsOptions{}
+	}
+
+	log.Println("🚚 Starting feed migration...")
+
+	summary := &MigrateFeedsSummary{DryRun: opts.DryRun}
+
+	var lastKey map[string]types.AttributeValue
+	for {
+		if err := ctx.Err(); err != nil {
+			return summary, err
+		}
+
+		feeds, nextKey, err := s.feedRepo.List(ctx, cleanupPageSize, lastKey)
+		if err != nil {
+			return summary, fmt.Errorf("failed to list feeds: %w", err)
+		}
+
+		for _, feed := range feeds {
+			summary.FeedsChecked++
+			if err := s.migrateFeed(ctx, feed, opts.DryRun, summary); err != nil {
+				log.Printf("❌ Failed to migrate feed %s: %v", feed.FeedID, err)
+				summary.Errors++
+			}
+		}
+
+		if len(nextKey) == 0 {
+			break
+		}
+		lastKey = nextKey
+	}
+
+	log.Printf("✨ Feed migration completed!")
+	log.Printf("📊 Summary:")
+	log.Printf("   - Total feeds checked: %d", summary.FeedsChecked)
+	log.Printf("   - Legacy links migrated: %d", summary.LegacyLinksMigrated)
+	log.Printf("   - Feeds merged: %d", summary.FeedsMerged)
+	log.Printf("   - Subscriptions moved: %d", summary.SubscriptionsMoved)
+	log.Printf("   - Articles moved: %d", summary.ArticlesMoved)
+	log.Printf("   - Duplicate articles deleted: %d", summary.DuplicateArticles)
+	log.Printf("   - Errors: %d", summary.Errors)
+
+	return summary, nil
+}
+
+// migrateFeed migrates a feed's legacy bower link, then merges the feed into the canonical feed for
+// its URL if it is not that feed
+func (s *schedulerService) migrateFeed(ctx context.Context, feed *model.Feed, dryRun bool, summary *MigrateFeedsSummary) error {
+	if feed.LegacyBowerID != "" {
+		if !dryRun {
+			if err := s.feedRepo.MigrateLegacySubscription(ctx, feed); err != nil {
+				return err
+			}
+		}
+		summary.LegacyLinksMigrated++
+	}
+
+	canonicalID := model.CanonicalFeedID(feed.URL)
+	if feed.FeedID == canonicalID {
+		return nil
+	}
+	if dryRun {
+		summary.FeedsMerged++
+		return nil
+	}
+
+	canonical, err := s.getOrCreateCanonicalFeed(ctx, feed, canonicalID)
+	if err != nil {
+		return err
+	}
+
+	log.Printf("🔀 Merging feed %s into %s (%s)", feed.FeedID, canonical.FeedID, feed.URL)
+
+	if err := s.moveSubscriptions(ctx, feed, canonical, summary); err != nil {
+		return err
+	}
+	if err := s.moveArticles(ctx, feed, canonical, summary); err != nil {
+		return err
+	}
+
+	deleted, err := s.feedRepo.DeleteIfUnsubscribed(ctx, feed.FeedID)
+	if err != nil {
+		return fmt.Errorf("failed to delete merged feed: %w", err)
+	}
+	if !deleted {
+		return fmt.Errorf("feed %s was subscribed to while being merged", feed.FeedID)
+	}
+	summary.FeedsMerged++
+	return nil
+}
+
+// getOrCreateCanonicalFeed returns the canonical feed with the given ID, creating it as a copy of
+// feed if it does not exist yet
+func (s *schedulerService) getOrCreateCanonicalFeed(ctx context.Context, feed *model.Feed, canonicalID string) (*model.Feed, error) {
+	canonical, err := s.feedRepo.GetByID(ctx, canonicalID)
+	if err != nil && !isNotFoundError(err) {
+		return nil, fmt.Errorf("failed to get canonical feed: %w", err)
+	}
+	if err == nil && canonical != nil {
+		return canonical, nil
+	}
+
+	copied := *feed
+	copied.FeedID = canonicalID
+	copied.BowerID = ""
+	copied.LegacyBowerID = ""
+	copied.SubscriberCount = 0
+	// The hub knows the old feed's callback; the canonical feed subscribes on its next fetch
+	copied.WebSubSecret = ""
+	copied.WebSubState = ""
+	copied.WebSubRequestedAt = 0
+	copied.WebSubLeaseExpiresAt = 0
+
+	err = s.feedRepo.Create(ctx, &copied)
+	if err != nil && !strings.Contains(err.Error(), "already exists") {
+		return nil, fmt.Errorf("failed to create canonical feed: %w", err)
+	}
+	if err != nil {
+		// Created by a subscription in the meantime
+		return s.feedRepo.GetByID(ctx, canonicalID)
+	}
+	return &copied, nil
+}
+
+// moveSubscriptions moves the subscriptions of a feed to the canonical feed. The feed's title and
+// category, which its bower may have customized, are kept as overrides where they differ.
+func (s *schedulerService) moveSubscriptions(ctx context.Context, feed *model.Feed, canonical *model.Feed, summary *MigrateFeedsSummary) error {
+	subscriptions, err := s.feedRepo.GetSubscriptionsByFeedID(ctx, feed.FeedID)
+	if err != nil {
+		return fmt.Errorf("failed to get subscriptions: %w", err)
+	}
+
+	for _, subscription := range subscriptions {
+		moved := *subscription
+		moved.FeedID = canonical.FeedID
+		if moved.Title == "" && feed.Title != canonical.Title {
+			moved.Title = feed.Title
+		}
+		if moved.Category == "" && feed.Category != canonical.Category {
+			moved.Category = feed.Category
+		}
+
+		// A bower already subscribed to the canonical feed keeps that subscription
+		if err := s.feedRepo.Subscribe(ctx, &moved); err != nil && !strings.Contains(err.Error(), "already exists") {
+			return fmt.Errorf("failed to subscribe bower %s: %w", subscription.BowerID, err)
+		}
+		if err := s.feedRepo.Unsubscribe(ctx, subscription.BowerID, feed.FeedID); err != nil && !isNotFoundError(err) {
+			return fmt.Errorf("failed to unsubscribe bower %s: %w", subscription.BowerID, err)
+		}
+		summary.SubscriptionsMoved++
+	}
+
+	return nil
+}
+
+// moveArticles moves the articles of a feed to the canonical feed. Articles whose URL the canonical
+// feed already has are deleted unless users like them, and merged duplicates from other feeds are
+// listed under the canonical feed instead. It fails if any article could not be moved, so that the
+// feed is kept for the next run.
+func (s *schedulerService) moveArticles(ctx context.Context, feed *model.Feed, canonical *model.Feed, summary *MigrateFeedsSummary) error {
+	urls, err := s.getArticleURLs(ctx, canonical.FeedID)
+	if err != nil {
+		return err
+	}
+
+	failed := 0
+	var lastKey map[string]types.AttributeValue
+	for {
+		if err := ctx.Err(); err != nil {
+			return err
+		}
+
+		articles, nextKey, err := s.articleRepo.GetByFeedID(ctx, feed.FeedID, cleanupPageSize, lastKey)
+		if err != nil {
+			return fmt.Errorf("failed to get articles: %w", err)
+		}
+
+		for _, article := range articles {
+			if err := s.moveArticle(ctx, article, feed.FeedID, canonical.FeedID, urls, summary); err != nil {
+				log.Printf("❌ Failed to move article %s: %v", article.ArticleID, err)
+				failed++
+			}
+		}
+
+		if len(nextKey) == 0 {
+			if failed > 0 {
+				return fmt.Errorf("failed to move %d articles", failed)
+			}
+			return nil
+		}
+		lastKey = nextKey
+	}
+}
+
+// getArticleURLs returns the URLs of a feed's articles
+func (s *schedulerService) getArticleURLs(ctx context.Context, feedID string) (map[string]bool, error) {
+	urls := make(map[string]bool)
+	var lastKey map[string]types.AttributeValue
+	for {
+		articles, nextKey, err := s.articleRepo.GetByFeedID(ctx, feedID, cleanupPageSize, lastKey)
+		if err != nil {
+			return nil, fmt.Errorf("failed to get articles: %w", err)
+		}
+		for _, article := range articles {
+			urls[article.URL] = true
+		}
+		if len(nextKey) == 0 {
+			return urls, nil
+		}
+		lastKey = nextKey
+	}
+}
+
+// moveArticle moves an article or alias from one feed to another, whose article URLs are urls
+func (s *schedulerService) moveArticle(ctx context.Context, article *model.Article, fromFeedID, toFeedID string, urls map[string]bool, summary *MigrateFeedsSummary) error {
+	if article.IsAlias() {
+		if err := s.articleRepo.RemoveSourceFeed(ctx, article.DuplicateOf, fromFeedID); err != nil {
+			if isNotFoundError(err) {
+				return nil
+			}
+			return err
+		}
+		original, err := s.articleRepo.GetByID(ctx, article.DuplicateOf)
+		if err != nil || original == nil {
+			return err
+		}
+		if !original.HasFeed(toFeedID) {
+			if err := s.articleRepo.AddSourceFeed(ctx, original.ArticleID, toFeedID); err != nil {
+				return err
+			}
+		}
+		urls[original.URL] = true
+		summary.ArticlesMoved++
+		return nil
+	}
+
+	if urls[article.URL] {
+		duplicate, err := s.isRemovableDuplicate(ctx, article)
+		if err != nil {
+			return err
+		}
+		if duplicate {
+			summary.DuplicateArticles++
+			return s.deleteOldArticle(ctx, article)
+		}
+	}
+
+	if article.HasFeed(toFeedID) {
+		if err := s.articleRepo.RemoveSourceFeed(ctx, article.ArticleID, toFeedID); err != nil {
+			return err
+		}
+		article.SourceFeedIDs = removeString(article.SourceFeedIDs, toFeedID)
+	}
+	article.FeedID = toFeedID
+	if err := s.articleRepo.Update(ctx, article); err != nil {
+		return err
+	}
+	urls[article.URL] = true
+	summary.ArticlesMoved++
+	return nil
+}
+
+// isRemovableDuplicate reports whether an article that another article of its feed duplicates can
+// be deleted. Articles users like, or that were merged from other feeds, are kept, as are all
+// articles when likes cannot be checked.
+func (s *schedulerService) isRemovableDuplicate(ctx context.Context, article *model.Article) (bool, error) {
+	if len(article.SourceFeedIDs) > 0 || s.chickRepo == nil {
+		return false, nil
+	}
+	likes, err := s.chickRepo.GetLikeCountByArticleID(ctx, article.ArticleID)
+	if err != nil {
+		return false, err
+	}
+	return likes == 0, nil
+}
+
+// removeString returns values without value
+func removeString(values []string, value string) []string {
+	kept := make([]string, 0, len(values))
+	for _, v := range values {
+		if v != value {
+			kept = append(kept, v)
+		}
+	}
+	return kept
+}
@@ -0,0 +1,95 @@
+package service
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"feed-bower-api/internal/model"
+)
+
+func TestSchedulerService_MigrateFeeds(t *testing.T) {
+	ctx := context.Background()
+	repos := NewMockRepositories()
+	scheduler := newCleanupTestScheduler(repos)
+
+	// Two bowers added the same URL before feeds were shared
+	const feedURL = "https://example.com/feed.xml"
+	legacyFeeds := map[string]string{"bower-1": "Example", "bower-2": "My Example"}
+	for bowerID, title := range legacyFeeds {
+		feed := model.NewFeed("", feedURL, title, "", "tech")
+		feed.FeedID = "legacy-" + bowerID
+		feed.LegacyBowerID = bowerID
+		repos.FeedRepo.Create(ctx, feed)
+	}
+
+	articles := []struct{ articleID, feedID, url string }{
+		{"first", "legacy-bower-1", "https://example.com/1"},
+		{"first-copy", "legacy-bower-2", "https://example.com/1"},
+		{"second", "legacy-bower-2", "https://example.com/2"},
+	}
+	for _, a := range articles {
+		article := model.NewArticle(a.feedID, a.articleID, "content", a.url, time.Now())
+		article.ArticleID = a.articleID
+		repos.ArticleRepo.Create(ctx, article)
+	}
+
+	// A dry run counts without migrating
+	summary, err := scheduler.MigrateFeeds(ctx, &MigrateFeedsOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("MigrateFeeds() unexpected error: %v", err)
+	}
+	if summary.FeedsChecked != 2 || summary.LegacyLinksMigrated != 2 || summary.FeedsMerged != 2 {
+		t.Errorf("MigrateFeeds() dry run = %+v", *summary)
+	}
+	if len(repos.FeedRepo.feeds) != 2 || repos.FeedRepo.feeds["legacy-bower-1"].LegacyBowerID == "" {
+		t.Error("Expected a dry run to migrate nothing")
+	}
+
+	summary, err = scheduler.MigrateFeeds(ctx, nil)
+	if err != nil {
+		t.Fatalf("MigrateFeeds() unexpected error: %v", err)
+	}
+	if summary.LegacyLinksMigrated != 2 || summary.FeedsMerged != 2 || summary.SubscriptionsMoved != 2 ||
+		summary.ArticlesMoved != 2 || summary.DuplicateArticles != 1 || summary.Errors != 0 {
+		t.Errorf("MigrateFeeds() = %+v", *summary)
+	}
+
+	canonicalID := model.CanonicalFeedID(feedURL)
+	canonical, exists := repos.FeedRepo.feeds[canonicalID]
+	if len(repos.FeedRepo.feeds) != 1 || !exists {
+		t.Fatalf("Expected only the canonical feed to remain, got %d feeds", len(repos.FeedRepo.feeds))
+	}
+	if canonical.SubscriberCount != 2 || canonical.LegacyBowerID != "" {
+		t.Errorf("Expected two subscribers and no legacy bower, got %d and %q", canonical.SubscriberCount, canonical.LegacyBowerID)
+	}
+	for bowerID, title := range legacyFeeds {
+		subscription, err := repos.FeedRepo.GetSubscription(ctx, bowerID, canonicalID)
+		if err != nil {
+			t.Fatalf("Expected %s to subscribe to the canonical feed: %v", bowerID, err)
+		}
+		if view := subscription.ApplyTo(canonical); view.Title != title {
+			t.Errorf("Expected %s to keep its title %q, got %q", bowerID, title, view.Title)
+		}
+	}
+
+	remaining := 0
+	for _, article := range repos.ArticleRepo.articles {
+		if article.FeedID != canonicalID {
+			t.Errorf("Expected article %s to be moved to the canonical feed, got %s", article.ArticleID, article.FeedID)
+		}
+		remaining++
+	}
+	if remaining != 2 {
+		t.Errorf("Expected the duplicate article to be deleted, got %d articles", remaining)
+	}
+
+	// Migrated feeds are left as they are
+	summary, err = scheduler.MigrateFeeds(ctx, nil)
+	if err != nil {
+		t.Fatalf("MigrateFeeds() unexpected error: %v", err)
+	}
+	if summary.FeedsChecked != 1 || summary.LegacyLinksMigrated != 0 || summary.FeedsMerged != 0 {
+		t.Errorf("Expected a second run to do nothing, got %+v", *summary)
+	}
+}
@@ -0,0 +1,93 @@
+package service
+
+import (
+	"context"
+	"fmt"
+	"log"
+
+	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+
+	"feed-bower-api/internal/model"
+)
+
+// ReindexSearchOptions controls a ReindexSearch run
+type ReindexSearchOptions struct {
+	DryRun bool `json:"-"` // Count what would be indexed without indexing it
+}
+
+// ReindexSearchSummary is the structured result of a ReindexSearch run. In a dry run, the indexed
+// count is what would have been indexed.
+type ReindexSearchSummary struct {
+	DryRun          bool `json:"dry_run"`
+	ArticlesChecked int  `json:"articles_checked"`
+	ArticlesIndexed int  `json:"articles_indexed"` // Aliases are indexed through their originals
+	Errors          int  `json:"errors"`
+}
+
+// ReindexSearch adds every stored article to the search index. Articles are indexed from the
+// Articles table stream, so this backfills articles stored before the index existed, articles
+// written where there is no stream (such as DynamoDB Local), and records the stream gave up on.
+// Unchanged articles are skipped by the index, so runs can be repeated.
+func (s *schedulerService) ReindexSearch(ctx context.Context, opts *ReindexSearchOptions) (*ReindexSearchSummary, error) {
+	if opts == nil {
+		opts = &ReindexSearchOptions{}
+	}
+
+	log.Println("🔎 Starting search reindex...")
+
+	summary := &ReindexSearchSummary{DryRun: opts.DryRun}
+
+	var lastKey map[string]types.AttributeValue
+	for {
+		if err := ctx.Err(); err != nil {
+			return summary, err
+		}
+
+		articles, nextKey, err := s.articleRepo.List(ctx, cleanupPageSize, lastKey)
+		if err != nil {
+			return summary, fmt.Errorf("failed to list articles: %w", err)
+		}
+
+		originals := make([]*model.Article, 0, len(articles))
+		for _, article := range articles {
+			summary.ArticlesChecked++
+			if !article.IsAlias() {
+				originals = append(originals, article)
+			}
+		}
+
+		if opts.DryRun {
+			summary.ArticlesIndexed += len(originals)
+		} else if err := s.articleRepo.IndexArticles(ctx, originals); err != nil {
+			log.Printf("❌ Failed to index %d articles: %v", len(originals), err)
+			summary.Errors++
+		} else {
+			summary.ArticlesIndexed += len(originals)
+		}
+
+		if len(nextKey) == 0 {
+			break
+		}
+		lastKey = nextKey
+	}
+
+	log.Printf("✨ Search reindex completed!")
+	log.Printf("📊 Summary:")
+	log.Printf("   - Total articles checked: %d", summary.ArticlesChecked)
+	log.Printf("   - Articles indexed: %d", summary.ArticlesIndexed)
+	log.Printf("   - Errors: %d", summary.Errors)
+
+	return summary, nil
+}
+
+// SyncSearchIndex updates the search index for articles that were written or deleted, as
+// reported by the Articles table stream
+func (s *schedulerService) SyncSearchIndex(ctx context.Context, articleIDs []string) error {
+	if len(articleIDs) == 0 {
+		return nil
+	}
+	if err := s.articleRepo.SyncSearchIndex(ctx, articleIDs); err != nil {
+		return fmt.Errorf("failed to sync search index: %w", err)
+	}
+	return nil
+}
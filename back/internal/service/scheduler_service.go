@@ -2,8 +2,13 @@ package service
 
 import (
 	"context"
+	"errors"
 	"fmt"
 	"log"
+	"net/url"
+	"sort"
+	"strings"
+	"sync"
 	"time"
 
 	"feed-bower-api/internal/model"
@@ -12,15 +17,93 @@ import (
 
 // SchedulerService defines the interface for scheduled operations
 type SchedulerService interface {
-	FetchAllFeeds(ctx context.Context) error
-	CleanupOrphanedArticles(ctx context.Context) error
+	FetchAllFeeds(ctx context.Context) (*FetchRunSummary, error)
+	FetchFeeds(ctx context.Context, opts *FetchRunOptions) (*FetchRunSummary, error)
+	CleanupOrphanedArticles(ctx context.Context, opts *CleanupOptions) (*CleanupSummary, error)
+	PurgeOldArticles(ctx context.Context, opts *PurgeOptions) (*PurgeSummary, error)
+	MigrateFeeds(ctx context.Context, opts *MigrateFeedsOptions) (*MigrateFeedsSummary, error)
+	ReindexSearch(ctx context.Context, opts *ReindexSearchOptions) (*ReindexSearchSummary, error)
+	SyncSearchIndex(ctx context.Context, articleIDs []string) error
+}
+
+// Feed fetch statuses reported in FeedFetchResult
+const (
+	FeedFetchStatusSuccess     = "success"
+	FeedFetchStatusNotModified = "not_modified"
+	FeedFetchStatusError       = "error"
+	FeedFetchStatusCancelled   = "cancelled"
+	FeedFetchStatusDue         = "due" // Listed but not fetched by a dry run
+)
+
+// defaultMaxFetchFeeds is how many due feeds a run fetches by default
+const defaultMaxFetchFeeds = 1000
+
+// SchedulerConfig holds configuration for the scheduler worker pool
+type SchedulerConfig struct {
+	Workers      int // Maximum number of feeds fetched concurrently
+	PerHostLimit int // Maximum number of concurrent fetches against a single host
+}
+
+// DefaultSchedulerConfig returns the default scheduler configuration
+func DefaultSchedulerConfig() *SchedulerConfig {
+	return &SchedulerConfig{
+		Workers:      10,
+		PerHostLimit: 2,
+	}
+}
+
+// FeedFetchResult describes the outcome of fetching a single feed
+type FeedFetchResult struct {
+	FeedID          string `json:"feed_id"`
+	URL             string `json:"url"`
+	Title           string `json:"title"`
+	Status          string `json:"status"`
+	HTTPStatus      int    `json:"http_status,omitempty"`
+	Dead            bool   `json:"dead,omitempty"`
+	ArticlesFetched int    `json:"articles_fetched"`
+	NewArticles     int    `json:"new_articles"`
+	MergedArticles  int    `json:"merged_articles,omitempty"` // Duplicates of other feeds' articles
+	Error           string `json:"error,omitempty"`
+	DurationMs      int64  `json:"duration_ms"`
+}
+
+// FetchRunOptions controls a FetchFeeds run
+type FetchRunOptions struct {
+	MaxFeeds int32 `json:"max_feeds,omitempty"` // Maximum number of due feeds fetched; 1000 if 0
+	DryRun   bool  `json:"-"`                   // List the due feeds without fetching them
+}
+
+// FetchRunSummary is the structured result of a FetchAllFeeds run
+type FetchRunSummary struct {
+	DryRun        bool              `json:"dry_run,omitempty"`
+	StartedAt     int64             `json:"started_at"`
+	FinishedAt    int64             `json:"finished_at"`
+	TotalFeeds    int               `json:"total_feeds"`
+	Succeeded     int               `json:"succeeded"`
+	NotModified   int               `json:"not_modified"`
+	Failed        int               `json:"failed"`
+	Cancelled     int               `json:"cancelled"`
+	TotalArticles int               `json:"total_articles"`
+	NewArticles   int               `json:"new_articles"`
+	Feeds         []FeedFetchResult `json:"feeds"`
 }
 
 // schedulerService implements SchedulerService interface
 type schedulerService struct {
 	feedRepo    repository.FeedRepository
 	articleRepo repository.ArticleRepository
+	bowerRepo   repository.BowerRepository // Optional, for bower filter rules applied at ingest
 	rssService  RSSService
+	webSub      WebSubService // Optional, for push subscriptions to feeds with a WebSub hub
+	config      *SchedulerConfig
+
+	// Optional, for removing users' likes, read state and playback positions of deleted articles
+	chickRepo     repository.ChickRepository
+	readStateRepo repository.ReadStateRepository
+	playbackRepo  repository.PlaybackRepository
+
+	hostMu   sync.Mutex
+	hostSems map[string]chan struct{}
 }
 
 // NewSchedulerService creates a new scheduler service
@@ -29,142 +112,445 @@ func NewSchedulerService(
 	articleRepo repository.ArticleRepository,
 	rssService RSSService,
 ) SchedulerService {
+	return NewSchedulerServiceWithConfig(feedRepo, articleRepo, rssService, nil)
+}
+
+// NewSchedulerServiceWithConfig creates a new scheduler service with a custom worker pool configuration
+func NewSchedulerServiceWithConfig(
+	feedRepo repository.FeedRepository,
+	articleRepo repository.ArticleRepository,
+	rssService RSSService,
+	config *SchedulerConfig,
+) SchedulerService {
+	defaults := DefaultSchedulerConfig()
+	if config == nil {
+		config = defaults
+	}
+	if config.Workers <= 0 {
+		config.Workers = defaults.Workers
+	}
+	if config.PerHostLimit <= 0 {
+		config.PerHostLimit = defaults.PerHostLimit
+	}
+
 	return &schedulerService{
 		feedRepo:    feedRepo,
 		articleRepo: articleRepo,
 		rssService:  rssService,
+		config:      config,
+		hostSems:    make(map[string]chan struct{}),
 	}
 }
 
+// SetBowerRepository enables bower filter rules applied at ingest
+func (s *schedulerService) SetBowerRepository(bowerRepo repository.BowerRepository) {
+	s.bowerRepo = bowerRepo
+}
+
+// SetWebSubService enables WebSub push subscriptions to feeds that advertise a hub
+func (s *schedulerService) SetWebSubService(webSub WebSubService) {
+	s.webSub = webSub
+}
+
+// SetUserDataRepositories enables removing users' likes, read state and playback positions along with
+// the articles deleted by cleanups
+func (s *schedulerService) SetUserDataRepositories(chickRepo repository.ChickRepository, readStateRepo repository.ReadStateRepository, playbackRepo repository.PlaybackRepository) {
+	s.chickRepo = chickRepo
+	s.readStateRepo = readStateRepo
+	s.playbackRepo = playbackRepo
+}
+
 // FetchAllFeeds fetches articles from all feeds and saves them to DynamoDB
-func (s *schedulerService) FetchAllFeeds(ctx context.Context) error {
+func (s *schedulerService) FetchAllFeeds(ctx context.Context) (*FetchRunSummary, error) {
+	return s.FetchFeeds(ctx, nil)
+}
+
+// FetchFeeds fetches articles from the feeds that are due and saves them to DynamoDB
+func (s *schedulerService) FetchFeeds(ctx context.Context, opts *FetchRunOptions) (*FetchRunSummary, error) {
+	if opts == nil {
+		opts = &FetchRunOptions{}
+	}
+	maxFeeds := opts.MaxFeeds
+	if maxFeeds <= 0 {
+		maxFeeds = defaultMaxFetchFeeds
+	}
+
 	log.Println("🔄 Starting scheduled feed fetch...")
 
-	// Get all feeds
-	feeds, _, err := s.feedRepo.List(ctx, 1000, nil)
+	summary := &FetchRunSummary{
+		DryRun:    opts.DryRun,
+		StartedAt: time.Now().Unix(),
+		Feeds:     []FeedFetchResult{},
+	}
+
+	// Get feeds whose next fetch time has passed
+	feeds, err := s.feedRepo.GetDueFeeds(ctx, time.Now().Unix(), maxFeeds)
 	if err != nil {
-		return fmt.Errorf("failed to list feeds: %w", err)
+		return nil, fmt.Errorf("failed to list due feeds: %w", err)
 	}
 
 	if len(feeds) == 0 {
-		log.Println("⚠️  No feeds found to fetch")
-		return nil
+		log.Println("⚠️  No feeds due for fetching")
+		summary.FinishedAt = time.Now().Unix()
+		return summary, nil
+	}
+
+	if opts.DryRun {
+		log.Printf("🔍 Dry run: %d feeds due for fetching", len(feeds))
+		for _, feed := range feeds {
+			summary.Feeds = append(summary.Feeds, FeedFetchResult{
+				FeedID: feed.FeedID,
+				URL:    feed.URL,
+				Title:  feed.Title,
+				Status: FeedFetchStatusDue,
+			})
+		}
+		summary.TotalFeeds = len(feeds)
+		summary.FinishedAt = time.Now().Unix()
+		return summary, nil
 	}
 
-	log.Printf("📡 Found %d feeds to fetch", len(feeds))
+	log.Printf("📡 Found %d feeds due for fetching (workers: %d, per-host limit: %d)", len(feeds), s.config.Workers, s.config.PerHostLimit)
 
-	totalArticles := 0
-	totalNew := 0
-	totalErrors := 0
+	// Each worker writes only to its own slot, so results keep the feed order
+	results := make([]FeedFetchResult, len(feeds))
 
-	// Process each feed
-	for i, feed := range feeds {
-		log.Printf("📥 [%d/%d] Fetching feed: %s (%s)", i+1, len(feeds), feed.Title, feed.URL)
+	var wg sync.WaitGroup
+	semaphore := make(chan struct{}, s.config.Workers)
 
-		// Fetch feed data
-		feedData, err := s.rssService.FetchFeed(ctx, feed.URL)
-		if err != nil {
-			log.Printf("❌ Error fetching feed %s: %v", feed.URL, err)
-			totalErrors++
+	for i, feed := range feeds {
+		// Stop dispatching once the context is cancelled. The select alone picks randomly when a
+		// worker slot is also free, so check the context first.
+		if ctx.Err() != nil {
+			results[i] = cancelledResult(feed)
 			continue
 		}
-
-		log.Printf("✅ Fetched %d articles from %s", len(feedData.Articles), feed.Title)
-		totalArticles += len(feedData.Articles)
-
-		// Convert to model articles
-		articles := ConvertToArticles(feed.FeedID, feedData.Articles)
-
-		// Filter out duplicates by checking existing articles
-		newArticles := make([]*model.Article, 0)
-		for _, article := range articles {
-			// Check if article already exists by URL
-			existing, err := s.articleRepo.GetByURL(ctx, article.URL)
-			if err != nil || existing == nil {
-				// Article doesn't exist, add it
-				newArticles = append(newArticles, article)
-			}
+		select {
+		case <-ctx.Done():
+			results[i] = cancelledResult(feed)
+			continue
+		case semaphore <- struct{}{}: // Acquire worker slot
 		}
 
-		if len(newArticles) == 0 {
-			log.Printf("ℹ️  No new articles for feed: %s", feed.Title)
-			continue
+		wg.Add(1)
+		go func(i int, feed *model.Feed) {
+			defer wg.Done()
+			defer func() { <-semaphore }() // Release worker slot
+
+			results[i] = s.fetchFeed(ctx, i, len(feeds), feed)
+		}(i, feed)
+	}
+
+	wg.Wait()
+
+	for _, result := range results {
+		switch result.Status {
+		case FeedFetchStatusSuccess:
+			summary.Succeeded++
+		case FeedFetchStatusNotModified:
+			summary.NotModified++
+		case FeedFetchStatusError:
+			summary.Failed++
+		case FeedFetchStatusCancelled:
+			summary.Cancelled++
 		}
+		summary.TotalArticles += result.ArticlesFetched
+		summary.NewArticles += result.NewArticles
+	}
+	summary.TotalFeeds = len(feeds)
+	summary.Feeds = results
+	summary.FinishedAt = time.Now().Unix()
 
-		log.Printf("💾 Saving %d new articles for feed: %s", len(newArticles), feed.Title)
+	log.Printf("✨ Feed fetch completed!")
+	log.Printf("📊 Summary:")
+	log.Printf("   - Total feeds processed: %d", summary.TotalFeeds)
+	log.Printf("   - Total articles fetched: %d", summary.TotalArticles)
+	log.Printf("   - New articles saved: %d", summary.NewArticles)
+	log.Printf("   - Not modified: %d", summary.NotModified)
+	log.Printf("   - Errors: %d", summary.Failed)
+	if summary.Cancelled > 0 {
+		log.Printf("   - Cancelled: %d", summary.Cancelled)
+	}
 
-		// Batch create new articles
-		if err := s.articleRepo.BatchCreate(ctx, newArticles); err != nil {
-			log.Printf("❌ Error saving articles for feed %s: %v", feed.URL, err)
-			totalErrors++
-			continue
+	return summary, nil
+}
+
+// fetchFeed fetches a single feed and saves its new articles
+func (s *schedulerService) fetchFeed(ctx context.Context, index, total int, feed *model.Feed) (result FeedFetchResult) {
+	start := time.Now()
+	result = FeedFetchResult{
+		FeedID: feed.FeedID,
+		URL:    feed.URL,
+		Title:  feed.Title,
+	}
+	defer func() {
+		result.DurationMs = time.Since(start).Milliseconds()
+	}()
+
+	// Limit concurrent requests against the same host
+	release, err := s.acquireHost(ctx, feed.URL)
+	if err != nil {
+		return cancelledResult(feed)
+	}
+	defer release()
+
+	log.Printf("📥 [%d/%d] Fetching feed: %s (%s)", index+1, total, feed.Title, feed.URL)
+
+	// Fetch feed data, using cache validators from the previous fetch
+	opts := &FetchOptions{
+		ETag:         feed.ETag,
+		LastModified: feed.LastModified,
+	}
+	feedData, err := s.rssService.FetchFeedWithOptions(ctx, feed.URL, opts)
+	if err != nil {
+		if ctx.Err() != nil {
+			return cancelledResult(feed)
 		}
+		log.Printf("❌ Error fetching feed %s: %v", feed.URL, err)
+		result.Status = FeedFetchStatusError
+		result.Error = err.Error()
+		s.recordFailure(ctx, feed, err)
+		result.HTTPStatus = feed.LastHTTPStatus
+		result.Dead = feed.IsDead()
+		return result
+	}
 
-		totalNew += len(newArticles)
+	result.HTTPStatus = feedData.StatusCode
 
-		// Update feed's last_updated timestamp
-		feed.UpdateLastUpdated()
+	// Skip parsing and article writes if the feed hasn't changed
+	if feedData.NotModified {
+		log.Printf("⏭️  Feed not modified since last fetch: %s", feed.Title)
+		result.Status = FeedFetchStatusNotModified
+		feed.RecordFetchSuccess(feedData.StatusCode)
+		s.scheduleNextFetch(feed, feedData, 0)
 		if err := s.feedRepo.Update(ctx, feed); err != nil {
-			log.Printf("⚠️  Warning: Failed to update feed timestamp for %s: %v", feed.URL, err)
+			log.Printf("⚠️  Warning: Failed to update fetch schedule for %s: %v", feed.URL, err)
 		}
+		s.subscribeWebSub(ctx, feed)
+		return result
+	}
+
+	feed.SetPollingHints(feedData.TTL, feedData.SkipHours, feedData.SkipDays)
+	feed.SetWebSubHub(feedData.HubURL, feedData.SelfURL)
 
-		// Add a small delay to avoid overwhelming external servers
-		time.Sleep(500 * time.Millisecond)
+	log.Printf("✅ Fetched %d articles from %s", len(feedData.Articles), feed.Title)
+	result.ArticlesFetched = len(feedData.Articles)
+
+	// Convert to model articles and save those not stored yet, merging duplicates of other feeds.
+	// Articles that no subscribed bower would list are dropped if all of them filter at ingest.
+	articles := ConvertToArticles(feed.FeedID, feedData.Articles)
+	articles = filterIngestedArticles(ctx, s.feedRepo, s.bowerRepo, feed.FeedID, articles)
+	saved, err := saveFetchedArticles(ctx, s.articleRepo, feed.FeedID, articles)
+	if err != nil {
+		log.Printf("❌ Error saving articles for feed %s: %v", feed.URL, err)
+		result.Status = FeedFetchStatusError
+		result.Error = fmt.Sprintf("failed to save articles: %v", err)
+		// Retry with backoff, keeping the old cache validators so the articles are fetched again
+		s.recordFailure(ctx, feed, errors.New(result.Error))
+		result.Dead = feed.IsDead()
+		return result
 	}
 
-	log.Printf("✨ Feed fetch completed!")
-	log.Printf("📊 Summary:")
-	log.Printf("   - Total feeds processed: %d", len(feeds))
-	log.Printf("   - Total articles fetched: %d", totalArticles)
-	log.Printf("   - New articles saved: %d", totalNew)
-	log.Printf("   - Errors: %d", totalErrors)
+	feed.RecordFetchSuccess(feedData.StatusCode)
+	feed.SetCacheValidators(feedData.ETag, feedData.LastModified)
+
+	result.Status = FeedFetchStatusSuccess
+	result.NewArticles = saved.Created
+	result.MergedArticles = saved.Merged
+
+	if saved.Created == 0 && saved.Merged == 0 {
+		log.Printf("ℹ️  No new articles for feed: %s", feed.Title)
+	} else {
+		log.Printf("💾 Saved %d new articles for feed: %s (%d merged with other feeds)", saved.Created, feed.Title, saved.Merged)
+	}
+
+	// Extract the full content of new articles for bowers in full text mode
+	if extracted := extractFullContent(ctx, s.rssService, s.feedRepo, s.articleRepo, feed.FeedID, saved.Articles); extracted > 0 {
+		log.Printf("📄 Extracted full text of %d articles for feed: %s", extracted, feed.Title)
+	}
+
+	// Fall back to the page's preview image for new articles without one
+	if found := fillPageImages(ctx, s.rssService, s.articleRepo, saved.Articles); found > 0 {
+		log.Printf("🖼️ Found page images for %d articles of feed: %s", found, feed.Title)
+	}
+
+	// Update feed's last_updated timestamp, cache validators and fetch schedule
+	s.scheduleNextFetch(feed, feedData, saved.Created+saved.Merged)
+	feed.UpdateLastUpdated()
+	if err := s.feedRepo.Update(ctx, feed); err != nil {
+		log.Printf("⚠️  Warning: Failed to update feed timestamp for %s: %v", feed.URL, err)
+	}
+
+	s.subscribeWebSub(ctx, feed)
 
-	return nil
+	return result
 }
 
-// CleanupOrphanedArticles removes articles whose feeds no longer exist
-func (s *schedulerService) CleanupOrphanedArticles(ctx context.Context) error {
-	log.Println("🧹 Starting orphaned articles cleanup...")
+// subscribeWebSub asks the feed's WebSub hub to push new content, renewing the lease before it
+// expires. It runs after the fetch has updated the feed, since the hub's verification updates it too.
+func (s *schedulerService) subscribeWebSub(ctx context.Context, feed *model.Feed) {
+	if s.webSub == nil || !feed.NeedsWebSubSubscription(time.Now()) {
+		return
+	}
 
-	// Get all articles
-	articles, _, err := s.articleRepo.List(ctx, 1000, nil)
-	if err != nil {
-		return fmt.Errorf("failed to list articles: %w", err)
+	if err := s.webSub.Subscribe(ctx, feed); err != nil {
+		log.Printf("⚠️  Warning: WebSub subscription request failed for %s: %v", feed.URL, err)
+		return
+	}
+	log.Printf("📡 Requested WebSub subscription for %s from %s", feed.URL, feed.HubURL)
+}
+
+// recordFailure updates the feed's health after a failed fetch and schedules a retry with exponential backoff
+func (s *schedulerService) recordFailure(ctx context.Context, feed *model.Feed, fetchErr error) {
+	now := time.Now()
+
+	// Respect Retry-After from rate-limited or unavailable servers
+	httpStatus := 0
+	var notBefore time.Time
+	var httpErr *HTTPError
+	if errors.As(fetchErr, &httpErr) {
+		httpStatus = httpErr.StatusCode
+		if httpErr.RetryAfter > 0 {
+			notBefore = time.Unix(httpErr.RetryAfter, 0)
+		}
+	}
+
+	feed.RecordFetchFailure(fetchErr.Error(), httpStatus, now)
+	feed.ScheduleRetry(now, feed.FailureBackoff(), notBefore)
+
+	if feed.IsDead() {
+		log.Printf("💀 Feed dead after %d consecutive failures, probing again in %s: %s", feed.ConsecutiveFailures, feed.FailureBackoff(), feed.URL)
+	} else {
+		log.Printf("⏳ Retrying %s in %s (%d consecutive failures)", feed.URL, feed.FailureBackoff(), feed.ConsecutiveFailures)
+	}
+
+	if err := s.feedRepo.Update(ctx, feed); err != nil {
+		log.Printf("⚠️  Warning: Failed to update feed health for %s: %v", feed.URL, err)
+	}
+}
+
+// scheduleNextFetch learns a new polling interval for the feed and sets its next fetch time
+func (s *schedulerService) scheduleNextFetch(feed *model.Feed, feedData *FeedData, newArticles int) {
+	now := time.Now()
+	interval := nextFetchInterval(feed, feedData, newArticles)
+
+	var notBefore time.Time
+	if feedData.RetryAfter > 0 {
+		notBefore = time.Unix(feedData.RetryAfter, 0)
+	}
+
+	feed.ScheduleNextFetch(now, interval, notBefore)
+	log.Printf("🗓️  Next fetch for %s in %s", feed.Title, interval)
+}
+
+// nextFetchInterval adapts the feed's polling interval to how often it actually publishes.
+// Feeds with new articles move toward their observed publish interval; quiet feeds back off.
+func nextFetchInterval(feed *model.Feed, feedData *FeedData, newArticles int) time.Duration {
+	interval := feed.GetFetchInterval()
+
+	if newArticles > 0 {
+		if observed := estimatePublishInterval(feedData.Articles); observed > 0 {
+			interval = (interval + observed) / 2
+		} else {
+			interval = interval / 2
+		}
+	} else {
+		interval = interval * 3 / 2
+	}
+
+	minInterval := time.Duration(model.MinFetchInterval) * time.Second
+	maxInterval := time.Duration(model.MaxFetchInterval) * time.Second
+	if interval < minInterval {
+		interval = minInterval
+	}
+	if interval > maxInterval {
+		interval = maxInterval
 	}
 
-	if len(articles) == 0 {
-		log.Println("ℹ️  No articles found")
-		return nil
+	// Feeds pushed by their WebSub hub are only polled in case pushes stop
+	if feed.HasActiveWebSub(time.Now()) {
+		interval = maxInterval
 	}
 
-	log.Printf("📊 Checking %d articles for orphaned entries", len(articles))
+	// Never poll more often than the feed or the server asks us to
+	if ttl := time.Duration(feed.TTL) * time.Minute; interval < ttl {
+		interval = ttl
+	}
+	if maxAge := time.Duration(feedData.MaxAge) * time.Second; interval < maxAge {
+		interval = maxAge
+	}
 
-	deletedCount := 0
-	errorCount := 0
+	return interval
+}
 
-	// Check each article's feed
+// estimatePublishInterval returns the median gap between article publish times, or 0 if unknown
+func estimatePublishInterval(articles []ArticleData) time.Duration {
+	times := make([]time.Time, 0, len(articles))
 	for _, article := range articles {
-		// Check if feed exists
-		_, err := s.feedRepo.GetByID(ctx, article.FeedID)
-		if err != nil {
-			// Feed doesn't exist, delete the article
-			log.Printf("🗑️  Deleting orphaned article: %s (feed_id: %s)", article.ArticleID, article.FeedID)
-
-			if err := s.articleRepo.Delete(ctx, article.ArticleID); err != nil {
-				log.Printf("❌ Failed to delete article %s: %v", article.ArticleID, err)
-				errorCount++
-				continue
-			}
-
-			deletedCount++
+		if !article.PublishedAt.IsZero() {
+			times = append(times, article.PublishedAt)
 		}
 	}
+	if len(times) < 2 {
+		return 0
+	}
 
-	log.Printf("✨ Cleanup completed!")
-	log.Printf("📊 Summary:")
-	log.Printf("   - Total articles checked: %d", len(articles))
-	log.Printf("   - Orphaned articles deleted: %d", deletedCount)
-	log.Printf("   - Errors: %d", errorCount)
+	sort.Slice(times, func(i, j int) bool { return times[i].After(times[j]) })
+
+	gaps := make([]time.Duration, 0, len(times)-1)
+	for i := 1; i < len(times); i++ {
+		if gap := times[i-1].Sub(times[i]); gap > 0 {
+			gaps = append(gaps, gap)
+		}
+	}
+	if len(gaps) == 0 {
+		return 0
+	}
+
+	sort.Slice(gaps, func(i, j int) bool { return gaps[i] < gaps[j] })
+	return gaps[len(gaps)/2]
+}
+
+// acquireHost blocks until a fetch slot for the feed's host is available.
+// The returned function releases the slot.
+func (s *schedulerService) acquireHost(ctx context.Context, feedURL string) (func(), error) {
+	host := feedHost(feedURL)
+
+	s.hostMu.Lock()
+	sem, exists := s.hostSems[host]
+	if !exists {
+		sem = make(chan struct{}, s.config.PerHostLimit)
+		s.hostSems[host] = sem
+	}
+	s.hostMu.Unlock()
 
-	return nil
+	if err := ctx.Err(); err != nil {
+		return nil, err
+	}
+	select {
+	case sem <- struct{}{}:
+		return func() { <-sem }, nil
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	}
+}
+
+// feedHost returns the lowercased host of a feed URL, falling back to the raw URL
+func feedHost(feedURL string) string {
+	parsed, err := url.Parse(feedURL)
+	if err != nil || parsed.Hostname() == "" {
+		return feedURL
+	}
+	return strings.ToLower(parsed.Hostname())
+}
+
+// cancelledResult builds the result for a feed skipped due to cancellation
+func cancelledResult(feed *model.Feed) FeedFetchResult {
+	return FeedFetchResult{
+		FeedID: feed.FeedID,
+		URL:    feed.URL,
+		Title:  feed.Title,
+		Status: FeedFetchStatusCancelled,
+		Error:  "fetch cancelled",
+	}
 }
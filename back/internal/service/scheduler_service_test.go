@@ -3,17 +3,22 @@ package service
 import (
 	"context"
 	"errors"
+	"sync"
+	"sync/atomic"
 	"testing"
 	"time"
 
 	"feed-bower-api/internal/model"
 	"github.com/aws/aws-sdk-go-v2/service/dynamodb/types"
+	"github.com/google/uuid"
 )
 
 // Mock repositories for testing
 type mockFeedRepoForScheduler struct {
-	feeds []*model.Feed
-	err   error
+	mu      sync.Mutex
+	feeds   []*model.Feed
+	err     error
+	updated []*model.Feed
 }
 
 func (m *mockFeedRepoForScheduler) Create(ctx context.Context, feed *model.Feed) error {
@@ -33,6 +38,9 @@ func (m *mockFeedRepoForScheduler) GetByURL(ctx context.Context, url string) (*m
 }
 
 func (m *mockFeedRepoForScheduler) Update(ctx context.Context, feed *model.Feed) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	m.updated = append(m.updated, feed)
 	return nil
 }
 
@@ -40,6 +48,38 @@ func (m *mockFeedRepoForScheduler) Delete(ctx context.Context, feedID string) er
 	return nil
 }
 
+func (m *mockFeedRepoForScheduler) DeleteIfUnsubscribed(ctx context.Context, feedID string) (bool, error) {
+	return false, nil
+}
+
+func (m *mockFeedRepoForScheduler) Subscribe(ctx context.Context, subscription *model.Subscription) error {
+	return nil
+}
+
+func (m *mockFeedRepoForScheduler) Unsubscribe(ctx context.Context, bowerID, feedID string) error {
+	return nil
+}
+
+func (m *mockFeedRepoForScheduler) GetSubscription(ctx context.Context, bowerID, feedID string) (*model.Subscription, error) {
+	return nil, nil
+}
+
+func (m *mockFeedRepoForScheduler) UpdateSubscription(ctx context.Context, subscription *model.Subscription) error {
+	return nil
+}
+
+func (m *mockFeedRepoForScheduler) GetSubscriptionsByBowerID(ctx context.Context, bowerID string) ([]*model.Subscription, error) {
+	return nil, nil
+}
+
+func (m *mockFeedRepoForScheduler) GetSubscriptionsByFeedID(ctx context.Context, feedID string) ([]*model.Subscription, error) {
+	return nil, nil
+}
+
+func (m *mockFeedRepoForScheduler) MigrateLegacySubscription(ctx context.Context, feed *model.Feed) error {
+	return nil
+}
+
 func (m *mockFeedRepoForScheduler) List(ctx context.Context, limit int32, lastKey map[string]types.AttributeValue) ([]*model.Feed, map[string]types.AttributeValue, error) {
 	if m.err != nil {
 		return nil, nil, m.err
@@ -51,8 +91,23 @@ func (m *mockFeedRepoForScheduler) GetStaleFeeds(ctx context.Context, maxAgeSeco
 	return nil, nil
 }
 
+func (m *mockFeedRepoForScheduler) GetDueFeeds(ctx context.Context, now int64, limit int32) ([]*model.Feed, error) {
+	if m.err != nil {
+		return nil, m.err
+	}
+	due := make([]*model.Feed, 0, len(m.feeds))
+	for _, feed := range m.feeds {
+		if feed.IsDue(time.Unix(now, 0)) {
+			due = append(due, feed)
+		}
+	}
+	return due, nil
+}
+
 type mockArticleRepoForScheduler struct {
-	articles map[string]*model.Article
+	mu       sync.Mutex
+	articles map[string]*model.Article // Keyed by URL
+	aliases  map[string]*model.Article // Keyed by ID
 	err      error
 }
 
@@ -65,23 +120,97 @@ func (m *mockArticleRepoForScheduler) GetByID(ctx context.Context, articleID str
 }
 
 func (m *mockArticleRepoForScheduler) GetByFeedID(ctx context.Context, feedID string, limit int32, lastKey map[string]types.AttributeValue) ([]*model.Article, map[string]types.AttributeValue, error) {
-	return nil, nil, nil
+	if m.err != nil {
+		return nil, nil, m.err
+	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	articles := make([]*model.Article, 0)
+	for _, article := range m.articles {
+		if article.FeedID == feedID {
+			articles = append(articles, article)
+		}
+	}
+	for _, alias := range m.aliases {
+		if alias.FeedID == feedID {
+			articles = append(articles, alias)
+		}
+	}
+	return articles, nil, nil
 }
 
 func (m *mockArticleRepoForScheduler) GetByFeedIDs(ctx context.Context, feedIDs []string, limit int32, lastKey map[string]types.AttributeValue) ([]*model.Article, map[string]types.AttributeValue, error) {
 	return nil, nil, nil
 }
 
+func (m *mockArticleRepoForScheduler) GetEpisodesByFeedIDs(ctx context.Context, feedIDs []string, limit int32, lastKey map[string]types.AttributeValue) ([]*model.Article, map[string]types.AttributeValue, error) {
+	return nil, nil, nil
+}
+
 func (m *mockArticleRepoForScheduler) GetByURL(ctx context.Context, url string) (*model.Article, error) {
 	if m.err != nil {
 		return nil, m.err
 	}
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	if article, exists := m.articles[url]; exists {
 		return article, nil
 	}
 	return nil, errors.New("article not found")
 }
 
+func (m *mockArticleRepoForScheduler) GetByCanonicalURL(ctx context.Context, canonicalURL string) (*model.Article, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, article := range m.articles {
+		if article.CanonicalURL == canonicalURL {
+			return article, nil
+		}
+	}
+	return nil, errors.New("article not found")
+}
+
+func (m *mockArticleRepoForScheduler) GetByTitleHash(ctx context.Context, titleHash string, limit int32) ([]*model.Article, error) {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	articles := make([]*model.Article, 0)
+	for _, article := range m.articles {
+		if article.TitleHash == titleHash {
+			articles = append(articles, article)
+		}
+	}
+	return articles, nil
+}
+
+func (m *mockArticleRepoForScheduler) AddSourceFeed(ctx context.Context, articleID, feedID string) error {
+	m.mu.Lock()
+	defer m.mu.Unlock()
+	for _, article := range m.articles {
+		if article.ArticleID == articleID {
+			article.SourceFeedIDs = append(article.SourceFeedIDs, feedID)
+			if m.aliases == nil {
+				m.aliases = make(map[string]*model.Article)
+			}
+			alias := model.NewArticleAlias(article, feedID)
+			m.aliases[alias.ArticleID] = alias
+			return nil
+		}
+	}
+	return errors.New("article not found")
+}
+
+func (m *mockArticleRepoForScheduler) RemoveSourceFeed(ctx context.Context, articleID, feedID string) error {
+	return nil
+}
+
+func (m *mockArticleRepoForScheduler) SetFullContent(ctx context.Context, articleID, fullContent string) error {
+	return nil
+}
+
+func (m *mockArticleRepoForScheduler) SetImageURL(ctx context.Context, articleID, imageURL string) error {
+	return nil
+}
+
 func (m *mockArticleRepoForScheduler) Update(ctx context.Context, article *model.Article) error {
 	return nil
 }
@@ -90,12 +219,24 @@ func (m *mockArticleRepoForScheduler) Delete(ctx context.Context, articleID stri
 	return nil
 }
 
+func (m *mockArticleRepoForScheduler) IndexArticles(ctx context.Context, articles []*model.Article) error {
+	return nil
+}
+
+func (m *mockArticleRepoForScheduler) SyncSearchIndex(ctx context.Context, articleIDs []string) error {
+	return nil
+}
+
+func (m *mockArticleRepoForScheduler) BatchDelete(ctx context.Context, articleIDs []string) error {
+	return nil
+}
+
 func (m *mockArticleRepoForScheduler) List(ctx context.Context, limit int32, lastKey map[string]types.AttributeValue) ([]*model.Article, map[string]types.AttributeValue, error) {
 	return nil, nil, nil
 }
 
-func (m *mockArticleRepoForScheduler) Search(ctx context.Context, query string, feedIDs []string, limit int32) ([]*model.Article, error) {
-	return nil, nil
+func (m *mockArticleRepoForScheduler) Search(ctx context.Context, query string, feedIDs []string, limit int32, lastKey map[string]types.AttributeValue) ([]*model.Article, map[string]types.AttributeValue, error) {
+	return nil, nil, nil
 }
 
 func (m *mockArticleRepoForScheduler) BatchCreate(ctx context.Context, articles []*model.Article) error {
@@ -103,15 +244,27 @@ func (m *mockArticleRepoForScheduler) BatchCreate(ctx context.Context, articles
 		return m.err
 	}
 	// Store articles in map for duplicate checking
+	m.mu.Lock()
+	defer m.mu.Unlock()
 	for _, article := range articles {
+		if article.ArticleID == "" {
+			article.ArticleID = uuid.New().String()
+		}
 		m.articles[article.URL] = article
 	}
 	return nil
 }
 
 type mockRSSServiceForScheduler struct {
+	mu       sync.Mutex
 	feedData *FeedData
 	err      error
+	lastOpts *FetchOptions
+	delay    time.Duration
+
+	// Concurrency tracking
+	active    int32
+	maxActive int32
 }
 
 func (m *mockRSSServiceForScheduler) FetchFeed(ctx context.Context, feedURL string) (*FeedData, error) {
@@ -121,10 +274,50 @@ func (m *mockRSSServiceForScheduler) FetchFeed(ctx context.Context, feedURL stri
 	return m.feedData, nil
 }
 
+func (m *mockRSSServiceForScheduler) FetchFeedWithOptions(ctx context.Context, feedURL string, opts *FetchOptions) (*FeedData, error) {
+	m.mu.Lock()
+	m.lastOpts = opts
+	m.mu.Unlock()
+
+	active := atomic.AddInt32(&m.active, 1)
+	defer atomic.AddInt32(&m.active, -1)
+	for {
+		peak := atomic.LoadInt32(&m.maxActive)
+		if active <= peak || atomic.CompareAndSwapInt32(&m.maxActive, peak, active) {
+			break
+		}
+	}
+
+	if m.delay > 0 {
+		select {
+		case <-time.After(m.delay):
+		case <-ctx.Done():
+			return nil, ctx.Err()
+		}
+	}
+	return m.FetchFeed(ctx, feedURL)
+}
+
 func (m *mockRSSServiceForScheduler) FetchFeedInfo(ctx context.Context, feedURL string) (*FeedInfo, error) {
 	return nil, nil
 }
 
+func (m *mockRSSServiceForScheduler) DiscoverFeeds(ctx context.Context, pageURL string) ([]FeedCandidate, error) {
+	return []FeedCandidate{}, nil
+}
+
+func (m *mockRSSServiceForScheduler) FetchFullContent(ctx context.Context, articleURL string) (string, error) {
+	return "", errors.New("full text extraction not supported")
+}
+
+func (m *mockRSSServiceForScheduler) FetchPageImage(ctx context.Context, pageURL string) (string, error) {
+	return "", errNoPageImage
+}
+
+func (m *mockRSSServiceForScheduler) ParseFeed(data []byte, contentType string, feedURL string) (*FeedData, error) {
+	return nil, nil
+}
+
 func (m *mockRSSServiceForScheduler) ParseRSSFeed(data []byte) (*FeedData, error) {
 	return nil, nil
 }
@@ -137,6 +330,10 @@ func (m *mockRSSServiceForScheduler) ParseRDFFeed(data []byte) (*FeedData, error
 	return nil, nil
 }
 
+func (m *mockRSSServiceForScheduler) ParseJSONFeed(data []byte) (*FeedData, error) {
+	return nil, nil
+}
+
 func (m *mockRSSServiceForScheduler) ExtractImageURL(content string) string {
 	return ""
 }
@@ -156,7 +353,7 @@ func TestSchedulerService_FetchAllFeeds_NoFeeds(t *testing.T) {
 
 	service := NewSchedulerService(feedRepo, articleRepo, rssService)
 
-	err := service.FetchAllFeeds(context.Background())
+	_, err := service.FetchAllFeeds(context.Background())
 	if err != nil {
 		t.Errorf("Expected no error for empty feed list, got: %v", err)
 	}
@@ -199,15 +396,23 @@ func TestSchedulerService_FetchAllFeeds_Success(t *testing.T) {
 
 	service := NewSchedulerService(feedRepo, articleRepo, rssService)
 
-	err := service.FetchAllFeeds(context.Background())
+	summary, err := service.FetchAllFeeds(context.Background())
 	if err != nil {
-		t.Errorf("Expected no error, got: %v", err)
+		t.Fatalf("Expected no error, got: %v", err)
 	}
 
 	// Verify articles were saved
 	if len(articleRepo.articles) != 2 {
 		t.Errorf("Expected 2 articles to be saved, got: %d", len(articleRepo.articles))
 	}
+
+	// Verify summary
+	if summary.Succeeded != 1 || summary.NewArticles != 2 {
+		t.Errorf("Expected 1 succeeded feed with 2 new articles, got: %+v", summary)
+	}
+	if len(summary.Feeds) != 1 || summary.Feeds[0].Status != FeedFetchStatusSuccess {
+		t.Errorf("Expected per-feed success status, got: %+v", summary.Feeds)
+	}
 }
 
 func TestSchedulerService_FetchAllFeeds_DuplicateDetection(t *testing.T) {
@@ -258,7 +463,7 @@ func TestSchedulerService_FetchAllFeeds_DuplicateDetection(t *testing.T) {
 
 	service := NewSchedulerService(feedRepo, articleRepo, rssService)
 
-	err := service.FetchAllFeeds(context.Background())
+	_, err := service.FetchAllFeeds(context.Background())
 	if err != nil {
 		t.Errorf("Expected no error, got: %v", err)
 	}
@@ -288,10 +493,28 @@ func TestSchedulerService_FetchAllFeeds_FeedError(t *testing.T) {
 
 	service := NewSchedulerService(feedRepo, articleRepo, rssService)
 
-	// Should not return error, just log it and continue
-	err := service.FetchAllFeeds(context.Background())
+	// Should not return error, just report it in the summary
+	summary, err := service.FetchAllFeeds(context.Background())
 	if err != nil {
-		t.Errorf("Expected no error (errors should be logged), got: %v", err)
+		t.Fatalf("Expected no error (errors should be reported), got: %v", err)
+	}
+
+	if summary.Failed != 1 {
+		t.Errorf("Expected 1 failed feed, got: %d", summary.Failed)
+	}
+	if summary.Feeds[0].Status != FeedFetchStatusError || summary.Feeds[0].Error != "feed fetch error" {
+		t.Errorf("Expected error status with message, got: %+v", summary.Feeds[0])
+	}
+
+	// Failure should be recorded on the feed and a retry scheduled
+	if len(feedRepo.updated) != 1 {
+		t.Fatalf("Expected feed health to be saved once, got %d updates", len(feedRepo.updated))
+	}
+	if feed.ConsecutiveFailures != 1 || feed.LastError != "feed fetch error" || feed.LastErrorAt == 0 {
+		t.Errorf("Expected failure to be recorded, got: %+v", feed)
+	}
+	if feed.NextFetchAt <= time.Now().Unix() {
+		t.Error("Expected retry to be scheduled in the future")
 	}
 
 	// No articles should be saved
@@ -313,8 +536,443 @@ func TestSchedulerService_FetchAllFeeds_ListError(t *testing.T) {
 
 	service := NewSchedulerService(feedRepo, articleRepo, rssService)
 
-	err := service.FetchAllFeeds(context.Background())
+	_, err := service.FetchAllFeeds(context.Background())
 	if err == nil {
 		t.Error("Expected error when feed list fails, got nil")
 	}
 }
+
+func TestSchedulerService_FetchAllFeeds_NotModified(t *testing.T) {
+	feed := model.NewFeed("bower-1", "https://example.com/feed.xml", "Test Feed", "Test Description", "Technology")
+	feed.ETag = `"abc123"`
+	feed.LastModified = "Mon, 01 Jan 2024 00:00:00 GMT"
+
+	feedRepo := &mockFeedRepoForScheduler{
+		feeds: []*model.Feed{feed},
+	}
+
+	articleRepo := &mockArticleRepoForScheduler{
+		articles: make(map[string]*model.Article),
+	}
+
+	rssService := &mockRSSServiceForScheduler{
+		feedData: &FeedData{
+			URL:          feed.URL,
+			ETag:         feed.ETag,
+			LastModified: feed.LastModified,
+			NotModified:  true,
+		},
+	}
+
+	service := NewSchedulerService(feedRepo, articleRepo, rssService)
+
+	if _, err := service.FetchAllFeeds(context.Background()); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+
+	// Cache validators should be sent with the request
+	if rssService.lastOpts == nil {
+		t.Fatal("Expected fetch options to be passed")
+	}
+	if rssService.lastOpts.ETag != feed.ETag {
+		t.Errorf("Expected ETag %s, got %s", feed.ETag, rssService.lastOpts.ETag)
+	}
+	if rssService.lastOpts.LastModified != feed.LastModified {
+		t.Errorf("Expected LastModified %s, got %s", feed.LastModified, rssService.lastOpts.LastModified)
+	}
+
+	// No articles should be written for an unchanged feed, only the fetch schedule
+	if len(articleRepo.articles) != 0 {
+		t.Errorf("Expected 0 articles to be saved, got: %d", len(articleRepo.articles))
+	}
+	if len(feedRepo.updated) != 1 {
+		t.Fatalf("Expected feed schedule to be updated once, got %d updates", len(feedRepo.updated))
+	}
+	if feedRepo.updated[0].NextFetchAt <= time.Now().Unix() {
+		t.Error("Expected next fetch to be scheduled in the future")
+	}
+}
+
+func TestSchedulerService_FetchAllFeeds_StoresCacheValidators(t *testing.T) {
+	feed := model.NewFeed("bower-1", "https://example.com/feed.xml", "Test Feed", "Test Description", "Technology")
+
+	feedRepo := &mockFeedRepoForScheduler{
+		feeds: []*model.Feed{feed},
+	}
+
+	articleRepo := &mockArticleRepoForScheduler{
+		articles: make(map[string]*model.Article),
+	}
+
+	rssService := &mockRSSServiceForScheduler{
+		feedData: &FeedData{
+			Title:        "Test Feed",
+			URL:          "https://example.com",
+			ETag:         `"v2"`,
+			LastModified: "Tue, 02 Jan 2024 00:00:00 GMT",
+			Articles:     []ArticleData{},
+		},
+	}
+
+	service := NewSchedulerService(feedRepo, articleRepo, rssService)
+
+	if _, err := service.FetchAllFeeds(context.Background()); err != nil {
+		t.Errorf("Expected no error, got: %v", err)
+	}
+
+	if len(feedRepo.updated) != 1 {
+		t.Fatalf("Expected feed to be updated once, got %d updates", len(feedRepo.updated))
+	}
+	if feedRepo.updated[0].ETag != `"v2"` {
+		t.Errorf("Expected stored ETag \"v2\", got %s", feedRepo.updated[0].ETag)
+	}
+	if feedRepo.updated[0].LastModified != "Tue, 02 Jan 2024 00:00:00 GMT" {
+		t.Errorf("Expected stored LastModified to be updated, got %s", feedRepo.updated[0].LastModified)
+	}
+}
+
+func TestSchedulerService_FetchAllFeeds_PerHostLimit(t *testing.T) {
+	feeds := make([]*model.Feed, 0, 6)
+	for i := 0; i < 6; i++ {
+		feeds = append(feeds, model.NewFeed("bower-1", "https://example.com/feed.xml", "Test Feed", "Test Description", "Technology"))
+	}
+
+	feedRepo := &mockFeedRepoForScheduler{
+		feeds: feeds,
+	}
+
+	articleRepo := &mockArticleRepoForScheduler{
+		articles: make(map[string]*model.Article),
+	}
+
+	rssService := &mockRSSServiceForScheduler{
+		feedData: &FeedData{Title: "Test Feed", Articles: []ArticleData{}},
+		delay:    20 * time.Millisecond,
+	}
+
+	service := NewSchedulerServiceWithConfig(feedRepo, articleRepo, rssService, &SchedulerConfig{
+		Workers:      6,
+		PerHostLimit: 2,
+	})
+
+	summary, err := service.FetchAllFeeds(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if summary.Succeeded != 6 {
+		t.Errorf("Expected 6 succeeded feeds, got: %d", summary.Succeeded)
+	}
+	if peak := atomic.LoadInt32(&rssService.maxActive); peak > 2 {
+		t.Errorf("Expected at most 2 concurrent fetches per host, got: %d", peak)
+	}
+}
+
+func TestSchedulerService_FetchAllFeeds_Cancelled(t *testing.T) {
+	feed := model.NewFeed("bower-1", "https://example.com/feed.xml", "Test Feed", "Test Description", "Technology")
+
+	feedRepo := &mockFeedRepoForScheduler{
+		feeds: []*model.Feed{feed},
+	}
+
+	articleRepo := &mockArticleRepoForScheduler{
+		articles: make(map[string]*model.Article),
+	}
+
+	rssService := &mockRSSServiceForScheduler{
+		feedData: &FeedData{Title: "Test Feed", Articles: []ArticleData{}},
+	}
+
+	service := NewSchedulerService(feedRepo, articleRepo, rssService)
+
+	ctx, cancel := context.WithCancel(context.Background())
+	cancel()
+
+	summary, err := service.FetchAllFeeds(ctx)
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if summary.Cancelled != 1 {
+		t.Errorf("Expected 1 cancelled feed, got: %d", summary.Cancelled)
+	}
+	if summary.Feeds[0].Status != FeedFetchStatusCancelled {
+		t.Errorf("Expected cancelled status, got: %s", summary.Feeds[0].Status)
+	}
+}
+
+func TestSchedulerService_FetchAllFeeds_OnlyDueFeeds(t *testing.T) {
+	dueFeed := model.NewFeed("bower-1", "https://example.com/due.xml", "Due Feed", "", "Technology")
+	notDueFeed := model.NewFeed("bower-1", "https://example.com/later.xml", "Later Feed", "", "Technology")
+	notDueFeed.NextFetchAt = time.Now().Add(time.Hour).Unix()
+
+	feedRepo := &mockFeedRepoForScheduler{
+		feeds: []*model.Feed{dueFeed, notDueFeed},
+	}
+
+	articleRepo := &mockArticleRepoForScheduler{
+		articles: make(map[string]*model.Article),
+	}
+
+	rssService := &mockRSSServiceForScheduler{
+		feedData: &FeedData{Title: "Test Feed", Articles: []ArticleData{}},
+	}
+
+	service := NewSchedulerService(feedRepo, articleRepo, rssService)
+
+	summary, err := service.FetchAllFeeds(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if summary.TotalFeeds != 1 || summary.Feeds[0].FeedID != dueFeed.FeedID {
+		t.Errorf("Expected only the due feed to be fetched, got: %+v", summary.Feeds)
+	}
+}
+
+func TestSchedulerService_FetchFeeds_DryRun(t *testing.T) {
+	feeds := []*model.Feed{
+		model.NewFeed("bower-1", "https://example.com/a.xml", "Feed A", "", "Technology"),
+		model.NewFeed("bower-1", "https://example.com/b.xml", "Feed B", "", "Technology"),
+	}
+	feedRepo := &mockFeedRepoForScheduler{feeds: feeds}
+	articleRepo := &mockArticleRepoForScheduler{
+		articles: make(map[string]*model.Article),
+	}
+	rssService := &mockRSSServiceForScheduler{
+		feedData: &FeedData{Title: "Test Feed", Articles: []ArticleData{{Title: "Article", URL: "https://example.com/article", PublishedAt: time.Now()}}},
+	}
+
+	service := NewSchedulerService(feedRepo, articleRepo, rssService)
+
+	summary, err := service.FetchFeeds(context.Background(), &FetchRunOptions{DryRun: true})
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if !summary.DryRun || summary.TotalFeeds != 2 || summary.Feeds[0].Status != FeedFetchStatusDue {
+		t.Errorf("Expected both feeds to be listed as due, got: %+v", summary)
+	}
+	if len(articleRepo.articles) != 0 || feeds[0].NextFetchAt != 0 {
+		t.Error("Expected a dry run not to fetch feeds")
+	}
+}
+
+func TestSchedulerService_FetchAllFeeds_RetryAfter(t *testing.T) {
+	feed := model.NewFeed("bower-1", "https://example.com/feed.xml", "Test Feed", "", "Technology")
+
+	feedRepo := &mockFeedRepoForScheduler{
+		feeds: []*model.Feed{feed},
+	}
+
+	articleRepo := &mockArticleRepoForScheduler{
+		articles: make(map[string]*model.Article),
+	}
+
+	retryAfter := time.Now().Add(6 * time.Hour).Unix()
+	rssService := &mockRSSServiceForScheduler{
+		err: &HTTPError{StatusCode: 429, Status: "429 Too Many Requests", RetryAfter: retryAfter},
+	}
+
+	service := NewSchedulerService(feedRepo, articleRepo, rssService)
+
+	if _, err := service.FetchAllFeeds(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if feed.NextFetchAt != retryAfter {
+		t.Errorf("Expected next fetch at Retry-After %d, got %d", retryAfter, feed.NextFetchAt)
+	}
+}
+
+func TestNextFetchInterval(t *testing.T) {
+	now := time.Now()
+	hourly := []ArticleData{
+		{PublishedAt: now},
+		{PublishedAt: now.Add(-30 * time.Minute)},
+		{PublishedAt: now.Add(-60 * time.Minute)},
+		{PublishedAt: now.Add(-90 * time.Minute)},
+	}
+
+	tests := []struct {
+		name        string
+		interval    int64
+		ttl         int
+		feedData    *FeedData
+		newArticles int
+		expected    time.Duration
+	}{
+		{
+			name:        "moves toward observed publish interval",
+			interval:    90 * 60,
+			feedData:    &FeedData{Articles: hourly},
+			newArticles: 1,
+			expected:    time.Hour,
+		},
+		{
+			name:     "backs off when nothing is new",
+			interval: 60 * 60,
+			feedData: &FeedData{},
+			expected: 90 * time.Minute,
+		},
+		{
+			name:     "clamped to maximum",
+			interval: model.MaxFetchInterval,
+			feedData: &FeedData{},
+			expected: time.Duration(model.MaxFetchInterval) * time.Second,
+		},
+		{
+			name:        "clamped to minimum",
+			interval:    model.MinFetchInterval,
+			feedData:    &FeedData{},
+			newArticles: 1,
+			expected:    time.Duration(model.MinFetchInterval) * time.Second,
+		},
+		{
+			name:        "honors RSS ttl",
+			interval:    60 * 60,
+			ttl:         180,
+			feedData:    &FeedData{},
+			newArticles: 1,
+			expected:    3 * time.Hour,
+		},
+		{
+			name:        "honors Cache-Control max-age",
+			interval:    60 * 60,
+			feedData:    &FeedData{MaxAge: 2 * 60 * 60},
+			newArticles: 1,
+			expected:    2 * time.Hour,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			feed := model.NewFeed("bower-1", "https://example.com/feed.xml", "Test Feed", "", "")
+			feed.FetchInterval = tt.interval
+			feed.TTL = tt.ttl
+
+			if got := nextFetchInterval(feed, tt.feedData, tt.newArticles); got != tt.expected {
+				t.Errorf("Expected interval %v, got %v", tt.expected, got)
+			}
+		})
+	}
+}
+
+func TestSchedulerService_FetchAllFeeds_MarksDeadFeed(t *testing.T) {
+	feed := model.NewFeed("bower-1", "https://example.com/feed.xml", "Test Feed", "", "Technology")
+	feed.ConsecutiveFailures = model.MaxConsecutiveFailures - 1
+	feed.HealthStatus = model.FeedHealthFailing
+
+	feedRepo := &mockFeedRepoForScheduler{
+		feeds: []*model.Feed{feed},
+	}
+
+	articleRepo := &mockArticleRepoForScheduler{
+		articles: make(map[string]*model.Article),
+	}
+
+	rssService := &mockRSSServiceForScheduler{
+		err: &HTTPError{StatusCode: 404, Status: "404 Not Found"},
+	}
+
+	service := NewSchedulerService(feedRepo, articleRepo, rssService)
+
+	summary, err := service.FetchAllFeeds(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if !feed.IsDead() {
+		t.Errorf("Expected feed to be marked dead, got status: %s", feed.HealthStatus)
+	}
+	if feed.LastHTTPStatus != 404 {
+		t.Errorf("Expected last HTTP status 404, got %d", feed.LastHTTPStatus)
+	}
+	if !summary.Feeds[0].Dead || summary.Feeds[0].HTTPStatus != 404 {
+		t.Errorf("Expected summary to report dead feed with status 404, got: %+v", summary.Feeds[0])
+	}
+
+	// Dead feeds are not fetched again until their next probe
+	summary, err = service.FetchAllFeeds(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+	if summary.TotalFeeds != 0 {
+		t.Errorf("Expected dead feed to be skipped, got %d feeds", summary.TotalFeeds)
+	}
+}
+
+func TestSchedulerService_FetchAllFeeds_SaveFailure(t *testing.T) {
+	feed := model.NewFeed("bower-1", "https://example.com/feed.xml", "Test Feed", "", "Technology")
+	feed.SubscriberCount = 1
+	feed.ETag = `"old"`
+
+	feedRepo := &mockFeedRepoForScheduler{
+		feeds: []*model.Feed{feed},
+	}
+
+	articleRepo := &mockArticleRepoForScheduler{
+		articles: make(map[string]*model.Article),
+		err:      errors.New("throughput exceeded"),
+	}
+
+	rssService := &mockRSSServiceForScheduler{
+		feedData: &FeedData{
+			Title:      "Test Feed",
+			StatusCode: 200,
+			ETag:       `"new"`,
+			Articles:   []ArticleData{{Title: "Article", URL: "https://example.com/article", PublishedAt: time.Now()}},
+		},
+	}
+
+	service := NewSchedulerService(feedRepo, articleRepo, rssService)
+
+	summary, err := service.FetchAllFeeds(context.Background())
+	if err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if summary.Failed != 1 || summary.Feeds[0].Status != FeedFetchStatusError {
+		t.Errorf("Expected the feed to fail, got: %+v", summary.Feeds[0])
+	}
+	if feed.ConsecutiveFailures != 1 || feed.LastError == "" {
+		t.Errorf("Expected the save failure to be recorded in the feed's health, got: %+v", feed)
+	}
+	if feed.IsDue(time.Now()) {
+		t.Error("Expected the retry to be scheduled with backoff")
+	}
+	if feed.ETag != `"old"` {
+		t.Errorf("Expected the old cache validators to be kept, got ETag %s", feed.ETag)
+	}
+	if len(feedRepo.updated) != 1 {
+		t.Errorf("Expected the feed to be updated once, got %d", len(feedRepo.updated))
+	}
+}
+
+func TestSchedulerService_FetchAllFeeds_SuccessResetsHealth(t *testing.T) {
+	feed := model.NewFeed("bower-1", "https://example.com/feed.xml", "Test Feed", "", "Technology")
+	feed.RecordFetchFailure("HTTP error: 500", 500, time.Now().Add(-time.Hour))
+	feed.NextFetchAt = 0
+
+	feedRepo := &mockFeedRepoForScheduler{
+		feeds: []*model.Feed{feed},
+	}
+
+	articleRepo := &mockArticleRepoForScheduler{
+		articles: make(map[string]*model.Article),
+	}
+
+	rssService := &mockRSSServiceForScheduler{
+		feedData: &FeedData{Title: "Test Feed", StatusCode: 200, Articles: []ArticleData{}},
+	}
+
+	service := NewSchedulerService(feedRepo, articleRepo, rssService)
+
+	if _, err := service.FetchAllFeeds(context.Background()); err != nil {
+		t.Fatalf("Expected no error, got: %v", err)
+	}
+
+	if feed.ConsecutiveFailures != 0 || feed.GetHealthStatus() != model.FeedHealthActive || feed.LastHTTPStatus != 200 {
+		t.Errorf("Expected feed health to be reset, got: %+v", feed)
+	}
+}
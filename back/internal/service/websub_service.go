@@ -0,0 +1,317 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/rand"
+	"crypto/sha1"
+	"crypto/sha256"
+	"crypto/sha512"
+	"encoding/hex"
+	"errors"
+	"fmt"
+	"hash"
+	"io"
+	"log"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"feed-bower-api/internal/model"
+	"feed-bower-api/internal/repository"
+	"feed-bower-api/pkg/httpclient"
+)
+
+// WebSubService defines the interface for WebSub push subscriptions (https://www.w3.org/TR/websub/).
+// Hubs push new content of subscribed feeds as soon as it is published, instead of waiting for the
+// next poll.
+type WebSubService interface {
+	Subscribe(ctx context.Context, feed *model.Feed) error
+	VerifyIntent(ctx context.Context, feedID string, req *WebSubVerification) (string, error)
+	ReceiveContent(ctx context.Context, feedID string, delivery *WebSubDelivery) (*FeedFetchResult, error)
+}
+
+// WebSub hub request modes
+const (
+	WebSubModeSubscribe   = "subscribe"
+	WebSubModeUnsubscribe = "unsubscribe"
+	WebSubModeDenied      = "denied"
+)
+
+// WebSubCallbackPath is the path of the subscriber callback, followed by the feed ID
+const WebSubCallbackPath = "/api/websub/callback/"
+
+var (
+	errWebSubUnknownFeed      = errors.New("unknown WebSub feed")
+	errWebSubNotRequested     = errors.New("WebSub subscription not requested")
+	errWebSubInvalidSignature = errors.New("invalid WebSub signature")
+)
+
+// WebSubVerification is a hub's request to verify that a subscription change was requested
+type WebSubVerification struct {
+	Mode         string
+	Topic        string
+	Challenge    string
+	LeaseSeconds int64
+	Reason       string // Why the hub denied the subscription
+}
+
+// WebSubDelivery is feed content pushed by a hub
+type WebSubDelivery struct {
+	ContentType string
+	Signature   string // X-Hub-Signature header, "method=signature" with an HMAC of the body
+	Body        []byte
+}
+
+// webSubService implements WebSubService interface
+type webSubService struct {
+	feedRepo        repository.FeedRepository
+	articleRepo     repository.ArticleRepository
+	bowerRepo       repository.BowerRepository // Optional, for bower filter rules applied at ingest
+	rssService      RSSService
+	secureClient    *httpclient.SecureHTTPClient
+	callbackBaseURL string
+}
+
+// NewWebSubService creates a new WebSub service. callbackBaseURL is the public URL of the API,
+// which hubs call back; feeds can only be subscribed if it is set.
+func NewWebSubService(
+	feedRepo repository.FeedRepository,
+	articleRepo repository.ArticleRepository,
+	rssService RSSService,
+	callbackBaseURL string,
+) WebSubService {
+	config := httpclient.DefaultSecureHTTPConfig()
+	config.UserAgent = "Feed-Bower/1.0 (RSS Reader)"
+
+	secureClient, err := httpclient.NewSecureHTTPClient(config)
+	if err != nil {
+		// Fallback to default config if there's an error
+		secureClient, _ = httpclient.NewSecureHTTPClient(nil)
+	}
+
+	return &webSubService{
+		feedRepo:        feedRepo,
+		articleRepo:     articleRepo,
+		rssService:      rssService,
+		secureClient:    secureClient,
+		callbackBaseURL: strings.TrimRight(callbackBaseURL, "/"),
+	}
+}
+
+// SetBowerRepository enables bower filter rules applied to pushed articles
+func (s *webSubService) SetBowerRepository(bowerRepo repository.BowerRepository) {
+	s.bowerRepo = bowerRepo
+}
+
+// Subscribe asks the feed's hub to push its new content, or renews the subscription's lease.
+// The hub verifies the request by calling VerifyIntent.
+func (s *webSubService) Subscribe(ctx context.Context, feed *model.Feed) error {
+	if s.callbackBaseURL == "" {
+		return errors.New("WebSub callback URL is not configured")
+	}
+	if feed.HubURL == "" || feed.TopicURL == "" {
+		return errors.New("feed has no WebSub hub")
+	}
+
+	if feed.WebSubSecret == "" {
+		secret, err := newWebSubSecret()
+		if err != nil {
+			return err
+		}
+		feed.WebSubSecret = secret
+	}
+	feed.RequestWebSub(time.Now())
+
+	// Save the request first, as the hub may verify it before responding
+	if err := s.feedRepo.Update(ctx, feed); err != nil {
+		return fmt.Errorf("failed to save WebSub request: %w", err)
+	}
+
+	form := url.Values{
+		"hub.mode":          {WebSubModeSubscribe},
+		"hub.topic":         {feed.TopicURL},
+		"hub.callback":      {s.callbackBaseURL + WebSubCallbackPath + url.PathEscape(feed.FeedID)},
+		"hub.secret":        {feed.WebSubSecret},
+		"hub.lease_seconds": {strconv.Itoa(model.WebSubLeaseSeconds)},
+	}
+	headers := map[string]string{
+		"Content-Type": "application/x-www-form-urlencoded",
+	}
+
+	resp, err := s.secureClient.DoWithBody(ctx, "POST", feed.HubURL, headers, strings.NewReader(form.Encode()))
+	if err != nil {
+		return fmt.Errorf("failed to send WebSub request: %w", err)
+	}
+	defer resp.Body.Close()
+	io.Copy(io.Discard, io.LimitReader(resp.Body, 64*1024))
+
+	// Hubs accept requests with 202 Accepted, but some answer 204 No Content
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		return &HTTPError{StatusCode: resp.StatusCode, Status: resp.Status}
+	}
+
+	return nil
+}
+
+// VerifyIntent answers a hub's verification of a subscription change, returning the challenge to
+// echo back. Only subscriptions that were requested for feeds with subscribers are confirmed;
+// unsubscriptions are confirmed otherwise.
+func (s *webSubService) VerifyIntent(ctx context.Context, feedID string, req *WebSubVerification) (string, error) {
+	if req == nil {
+		return "", errors.New("WebSub verification is required")
+	}
+	if req.Mode != WebSubModeDenied && req.Challenge == "" {
+		return "", errors.New("hub.challenge is required")
+	}
+
+	feed, err := s.getFeed(ctx, feedID)
+	if err != nil && err != errWebSubUnknownFeed {
+		return "", err
+	}
+
+	// A hub can only change the subscription it was asked for
+	requested := feed != nil && feed.SubscriberCount > 0 && feed.TopicURL == req.Topic &&
+		(feed.WebSubState == model.WebSubStatePending || feed.WebSubState == model.WebSubStateSubscribed)
+
+	switch req.Mode {
+	case WebSubModeSubscribe:
+		if !requested {
+			return "", errWebSubNotRequested
+		}
+		feed.ConfirmWebSub(req.LeaseSeconds, time.Now())
+		if err := s.feedRepo.Update(ctx, feed); err != nil {
+			return "", fmt.Errorf("failed to save WebSub subscription: %w", err)
+		}
+		log.Printf("📡 WebSub subscription verified for %s until %s", feed.URL, time.Unix(feed.WebSubLeaseExpiresAt, 0).UTC().Format(time.RFC3339))
+		return req.Challenge, nil
+
+	case WebSubModeUnsubscribe:
+		if requested {
+			return "", errWebSubNotRequested
+		}
+		return req.Challenge, nil
+
+	case WebSubModeDenied:
+		if feed != nil && feed.TopicURL == req.Topic {
+			feed.WebSubState = model.WebSubStateDenied
+			if err := s.feedRepo.Update(ctx, feed); err != nil {
+				return "", fmt.Errorf("failed to save WebSub subscription: %w", err)
+			}
+			log.Printf("🚫 WebSub subscription denied for %s: %s", feed.URL, req.Reason)
+		}
+		return "", nil
+	}
+
+	return "", fmt.Errorf("invalid hub.mode: %q", req.Mode)
+}
+
+// ReceiveContent verifies the signature of content pushed by a hub and saves its new articles,
+// like a scheduled fetch
+func (s *webSubService) ReceiveContent(ctx context.Context, feedID string, delivery *WebSubDelivery) (*FeedFetchResult, error) {
+	if delivery == nil {
+		return nil, errors.New("WebSub delivery is required")
+	}
+
+	feed, err := s.getFeed(ctx, feedID)
+	if err != nil {
+		return nil, err
+	}
+	if feed.WebSubSecret == "" || !validWebSubSignature(feed.WebSubSecret, delivery.Signature, delivery.Body) {
+		return nil, errWebSubInvalidSignature
+	}
+
+	start := time.Now()
+	result := &FeedFetchResult{
+		FeedID: feed.FeedID,
+		URL:    feed.URL,
+		Title:  feed.Title,
+	}
+
+	feedData, err := s.rssService.ParseFeed(delivery.Body, delivery.ContentType, feed.TopicURL)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse pushed content: %w", err)
+	}
+	result.ArticlesFetched = len(feedData.Articles)
+
+	// Save the articles the same way as fetched ones
+	articles := ConvertToArticles(feed.FeedID, feedData.Articles)
+	articles = filterIngestedArticles(ctx, s.feedRepo, s.bowerRepo, feed.FeedID, articles)
+	saved, err := saveFetchedArticles(ctx, s.articleRepo, feed.FeedID, articles)
+	if err != nil {
+		return nil, fmt.Errorf("failed to save articles: %w", err)
+	}
+
+	result.Status = FeedFetchStatusSuccess
+	result.NewArticles = saved.Created
+	result.MergedArticles = saved.Merged
+	log.Printf("📨 WebSub pushed %d articles for feed: %s (%d new, %d merged with other feeds)", len(articles), feed.Title, saved.Created, saved.Merged)
+
+	if extracted := extractFullContent(ctx, s.rssService, s.feedRepo, s.articleRepo, feed.FeedID, saved.Articles); extracted > 0 {
+		log.Printf("📄 Extracted full text of %d articles for feed: %s", extracted, feed.Title)
+	}
+	if found := fillPageImages(ctx, s.rssService, s.articleRepo, saved.Articles); found > 0 {
+		log.Printf("🖼️ Found page images for %d articles of feed: %s", found, feed.Title)
+	}
+
+	result.DurationMs = time.Since(start).Milliseconds()
+	return result, nil
+}
+
+// getFeed returns a feed by ID, or errWebSubUnknownFeed if it does not exist
+func (s *webSubService) getFeed(ctx context.Context, feedID string) (*model.Feed, error) {
+	if feedID == "" {
+		return nil, errWebSubUnknownFeed
+	}
+
+	feed, err := s.feedRepo.GetByID(ctx, feedID)
+	if isNotFoundError(err) || (err == nil && feed == nil) {
+		return nil, errWebSubUnknownFeed
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get feed: %w", err)
+	}
+
+	return feed, nil
+}
+
+// newWebSubSecret generates the key hubs sign pushed content with
+func newWebSubSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", fmt.Errorf("failed to generate WebSub secret: %w", err)
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+// validWebSubSignature checks an X-Hub-Signature header, "method=signature", against the HMAC of body
+func validWebSubSignature(secret, signature string, body []byte) bool {
+	method, expected, found := strings.Cut(strings.TrimSpace(signature), "=")
+	if !found {
+		return false
+	}
+
+	var newHash func() hash.Hash
+	switch strings.ToLower(method) {
+	case "sha1":
+		newHash = sha1.New
+	case "sha256":
+		newHash = sha256.New
+	case "sha384":
+		newHash = sha512.New384
+	case "sha512":
+		newHash = sha512.New
+	default:
+		return false
+	}
+
+	got, err := hex.DecodeString(expected)
+	if err != nil {
+		return false
+	}
+
+	mac := hmac.New(newHash, []byte(secret))
+	mac.Write(body)
+	return hmac.Equal(mac.Sum(nil), got)
+}
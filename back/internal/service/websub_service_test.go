@@ -0,0 +1,199 @@
+package service
+
+import (
+	"context"
+	"crypto/hmac"
+	"crypto/sha1"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"feed-bower-api/internal/model"
+)
+
+// offlineRSSService parses feeds without looking up page images over the network
+type offlineRSSService struct {
+	RSSService
+}
+
+func (s *offlineRSSService) FetchPageImage(ctx context.Context, pageURL string) (string, error) {
+	return "", errNoPageImage
+}
+
+func newWebSubTestFeed(repos *MockRepositories) *model.Feed {
+	feed := model.NewFeed("bower-1", "https://example.com/feed.xml", "Example", "", "")
+	feed.SetWebSubHub("https://hub.example.com/", "")
+	feed.WebSubSecret = "secret"
+	feed.RequestWebSub(time.Now())
+	repos.FeedRepo.Create(context.Background(), feed)
+	feed.SubscriberCount = 1
+	return feed
+}
+
+func signWebSubContent(secret string, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write(body)
+	return "sha256=" + hex.EncodeToString(mac.Sum(nil))
+}
+
+func TestWebSubService_VerifyIntent(t *testing.T) {
+	ctx := context.Background()
+	repos := NewMockRepositories()
+	s := NewWebSubService(repos.FeedRepo, repos.ArticleRepo, &offlineRSSService{NewRSSService()}, "https://api.example.com")
+	feed := newWebSubTestFeed(repos)
+
+	verify := func(mode, topic string) (string, error) {
+		return s.VerifyIntent(ctx, feed.FeedID, &WebSubVerification{
+			Mode:         mode,
+			Topic:        topic,
+			Challenge:    "challenge-1",
+			LeaseSeconds: 3600,
+		})
+	}
+
+	// Another topic was not requested
+	if _, err := verify(WebSubModeSubscribe, "https://example.com/other.xml"); err != errWebSubNotRequested {
+		t.Errorf("Expected errWebSubNotRequested for another topic, got %v", err)
+	}
+
+	// The requested subscription is confirmed with the lease the hub granted
+	challenge, err := verify(WebSubModeSubscribe, feed.TopicURL)
+	if err != nil {
+		t.Fatalf("VerifyIntent() unexpected error: %v", err)
+	}
+	if challenge != "challenge-1" {
+		t.Errorf("Expected the challenge to be echoed, got %q", challenge)
+	}
+	if !feed.HasActiveWebSub(time.Now()) {
+		t.Error("Expected the subscription to be active")
+	}
+	if feed.WebSubLeaseExpiresAt > time.Now().Unix()+3600 {
+		t.Errorf("Expected a lease of an hour, expires at %d", feed.WebSubLeaseExpiresAt)
+	}
+
+	// Subscribed feeds are not unsubscribed
+	if _, err := verify(WebSubModeUnsubscribe, feed.TopicURL); err != errWebSubNotRequested {
+		t.Errorf("Expected errWebSubNotRequested for an unsubscription, got %v", err)
+	}
+
+	// Feeds without subscribers are not confirmed, but can be unsubscribed
+	feed.SubscriberCount = 0
+	if _, err := verify(WebSubModeSubscribe, feed.TopicURL); err != errWebSubNotRequested {
+		t.Errorf("Expected errWebSubNotRequested for a feed without subscribers, got %v", err)
+	}
+	if _, err := verify(WebSubModeUnsubscribe, feed.TopicURL); err != nil {
+		t.Errorf("Expected the unsubscription to be confirmed, got %v", err)
+	}
+	feed.SubscriberCount = 1
+
+	// Denials are recorded
+	if _, err := s.VerifyIntent(ctx, feed.FeedID, &WebSubVerification{Mode: WebSubModeDenied, Topic: feed.TopicURL, Reason: "not allowed"}); err != nil {
+		t.Fatalf("VerifyIntent() unexpected error for a denial: %v", err)
+	}
+	if feed.WebSubState != model.WebSubStateDenied {
+		t.Errorf("Expected state %q, got %q", model.WebSubStateDenied, feed.WebSubState)
+	}
+
+	// Invalid requests
+	if _, err := s.VerifyIntent(ctx, feed.FeedID, &WebSubVerification{Mode: WebSubModeSubscribe, Topic: feed.TopicURL}); err == nil {
+		t.Error("Expected an error without a challenge")
+	}
+	if _, err := verify("publish", feed.TopicURL); err == nil {
+		t.Error("Expected an error for an invalid mode")
+	}
+	if _, err := s.VerifyIntent(ctx, "unknown", &WebSubVerification{Mode: WebSubModeSubscribe, Topic: feed.TopicURL, Challenge: "c"}); err != errWebSubNotRequested {
+		t.Errorf("Expected errWebSubNotRequested for an unknown feed, got %v", err)
+	}
+}
+
+func TestWebSubService_ReceiveContent(t *testing.T) {
+	ctx := context.Background()
+	repos := NewMockRepositories()
+	s := NewWebSubService(repos.FeedRepo, repos.ArticleRepo, &offlineRSSService{NewRSSService()}, "https://api.example.com")
+	feed := newWebSubTestFeed(repos)
+
+	body := []byte(`<?xml version="1.0"?><feed xmlns="http://www.w3.org/2005/Atom"><title>Example</title>` +
+		`<entry><title>Pushed</title><link href="/posts/pushed"/><id>pushed</id><updated>2024-01-01T00:00:00Z</updated></entry></feed>`)
+
+	// Content with an invalid signature is ignored
+	_, err := s.ReceiveContent(ctx, feed.FeedID, &WebSubDelivery{
+		ContentType: "application/atom+xml",
+		Signature:   signWebSubContent("other", body),
+		Body:        body,
+	})
+	if err != errWebSubInvalidSignature {
+		t.Errorf("Expected errWebSubInvalidSignature, got %v", err)
+	}
+	if len(repos.ArticleRepo.articles) != 0 {
+		t.Errorf("Expected no articles to be saved, got %d", len(repos.ArticleRepo.articles))
+	}
+
+	result, err := s.ReceiveContent(ctx, feed.FeedID, &WebSubDelivery{
+		ContentType: "application/atom+xml",
+		Signature:   signWebSubContent(feed.WebSubSecret, body),
+		Body:        body,
+	})
+	if err != nil {
+		t.Fatalf("ReceiveContent() unexpected error: %v", err)
+	}
+	if result.Status != FeedFetchStatusSuccess || result.NewArticles != 1 {
+		t.Errorf("Expected one new article, got %+v", result)
+	}
+
+	var saved *model.Article
+	for _, article := range repos.ArticleRepo.articles {
+		saved = article
+	}
+	if saved == nil || saved.URL != "https://example.com/posts/pushed" {
+		t.Errorf("Expected the pushed article to be saved with a resolved URL, got %+v", saved)
+	}
+
+	if _, err := s.ReceiveContent(ctx, "unknown", &WebSubDelivery{Body: body}); err != errWebSubUnknownFeed {
+		t.Errorf("Expected errWebSubUnknownFeed, got %v", err)
+	}
+}
+
+func TestWebSubService_Subscribe_NotConfigured(t *testing.T) {
+	repos := NewMockRepositories()
+	s := NewWebSubService(repos.FeedRepo, repos.ArticleRepo, NewRSSService(), "")
+	feed := newWebSubTestFeed(repos)
+
+	if err := s.Subscribe(context.Background(), feed); err == nil {
+		t.Error("Expected an error without a callback URL")
+	}
+}
+
+func TestValidWebSubSignature(t *testing.T) {
+	body := []byte("content")
+
+	tests := []struct {
+		name      string
+		signature string
+		want      bool
+	}{
+		{"sha256", signWebSubContent("secret", body), true},
+		{"sha1", "sha1=" + hmacHex(t, "secret", body), true},
+		{"wrong secret", signWebSubContent("other", body), false},
+		{"no method", hex.EncodeToString([]byte("content")), false},
+		{"unknown method", "md5=00", false},
+		{"not hex", "sha256=zz", false},
+		{"empty", "", false},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := validWebSubSignature("secret", tt.signature, body); got != tt.want {
+				t.Errorf("validWebSubSignature(%q) = %v, want %v", tt.signature, got, tt.want)
+			}
+		})
+	}
+}
+
+// hmacHex returns the hex HMAC-SHA1 of body
+func hmacHex(t *testing.T, secret string, body []byte) string {
+	t.Helper()
+	mac := hmac.New(sha1.New, []byte(secret))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
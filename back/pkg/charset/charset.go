@@ -0,0 +1,283 @@
+package charset
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"mime"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/text/encoding/htmlindex"
+	"golang.org/x/text/transform"
+)
+
+// UTF8 is the name Detect returns for UTF-8 documents
+const UTF8 = "utf-8"
+
+const (
+	// declarationSize is how much of a document is searched for its XML declaration
+	declarationSize = 1024
+
+	// sniffSize is how much of a streamed document is read to detect its encoding
+	sniffSize = 64 * 1024
+)
+
+// xmlDeclaration matches the encoding of an XML declaration; the first submatch is the encoding name
+var xmlDeclaration = regexp.MustCompile(`^\s*<\?xml\s[^>]*?\bencoding\s*=\s*["']([A-Za-z0-9._:-]+)["']`)
+
+// metaCharset matches the charset of an HTML <meta charset> tag or of a <meta http-equiv> tag's
+// Content-Type; the first submatch is the encoding name
+var metaCharset = regexp.MustCompile(`(?i)<meta\s[^>]*?\bcharset\s*=\s*["']?([A-Za-z0-9._:-]+)`)
+
+// byteOrderMarks maps the byte order marks to the encodings they identify
+var byteOrderMarks = []struct {
+	mark     []byte
+	encoding string
+}{
+	{[]byte{0xEF, 0xBB, 0xBF}, UTF8},
+	{[]byte{0xFE, 0xFF}, "utf-16be"},
+	{[]byte{0xFF, 0xFE}, "utf-16le"},
+}
+
+// Detect returns the name of a document's character encoding from its byte order mark, the charset
+// of its Content-Type, or its XML declaration, in that order. The Content-Type is ignored for valid
+// UTF-8 documents that do not declare another encoding, since servers often send a default charset.
+// Names are canonical WHATWG names, such as "shift_jis", "euc-jp" or "windows-1252" for ISO-8859-1.
+// It returns "utf-8" if no encoding is declared, and an error for unsupported encodings.
+func Detect(data []byte, contentType string) (string, error) {
+	return detect(data, contentType, declaredEncoding(data))
+}
+
+// DetectHTML returns the name of an HTML page's character encoding like Detect, reading the
+// page's <meta charset> instead of an XML declaration.
+func DetectHTML(data []byte, contentType string) (string, error) {
+	return detect(data, contentType, metaEncoding(data))
+}
+
+// detect returns the encoding named by a document's byte order mark, its Content-Type, or the
+// encoding the document declares, in that order
+func detect(data []byte, contentType string, declared string) (string, error) {
+	for _, bom := range byteOrderMarks {
+		if bytes.HasPrefix(data, bom.mark) {
+			return bom.encoding, nil
+		}
+	}
+
+	if label := contentTypeCharset(contentType); label != "" {
+		if !((declared == "" || isUTF8(declared)) && utf8.Valid(data)) {
+			return canonicalName(label)
+		}
+	}
+	if declared != "" {
+		return canonicalName(declared)
+	}
+	return UTF8, nil
+}
+
+// ToUTF8 converts a document to UTF-8, detecting its encoding with Detect. The byte order mark is
+// removed and the XML declaration, if any, is rewritten to declare UTF-8.
+func ToUTF8(data []byte, contentType string) ([]byte, error) {
+	name, err := Detect(data, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	data, err = decode(data, name)
+	if err != nil {
+		return nil, err
+	}
+	return declareUTF8(data), nil
+}
+
+// HTMLToUTF8 converts an HTML page to UTF-8, detecting its encoding with DetectHTML. The byte order
+// mark is removed; the <meta charset> is left as it is, since HTML parsers read UTF-8 regardless.
+func HTMLToUTF8(data []byte, contentType string) ([]byte, error) {
+	name, err := DetectHTML(data, contentType)
+	if err != nil {
+		return nil, err
+	}
+	return decode(data, name)
+}
+
+// decode removes a document's byte order mark and converts it from the named encoding to UTF-8
+func decode(data []byte, name string) ([]byte, error) {
+	for _, bom := range byteOrderMarks {
+		if bom.encoding == name && bytes.HasPrefix(data, bom.mark) {
+			data = data[len(bom.mark):]
+			break
+		}
+	}
+
+	if name == UTF8 {
+		return data, nil
+	}
+
+	enc, err := htmlindex.Get(name)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported charset %q", name)
+	}
+	decoded, _, err := transform.Bytes(enc.NewDecoder(), data)
+	if err != nil {
+		return nil, fmt.Errorf("failed to decode %s: %w", name, err)
+	}
+	return decoded, nil
+}
+
+// NewDocumentReader returns a reader that converts a document to UTF-8 as it is read, like ToUTF8.
+// The encoding is detected from the first 64KB of the document.
+func NewDocumentReader(r io.Reader, contentType string) (io.Reader, error) {
+	buffered := bufio.NewReaderSize(r, sniffSize)
+	sample, err := buffered.Peek(sniffSize)
+	if err != nil && err != io.EOF {
+		return nil, err
+	}
+	if len(sample) == sniffSize {
+		sample = trimPartialRune(sample)
+	}
+
+	name, err := Detect(sample, contentType)
+	if err != nil {
+		return nil, err
+	}
+
+	for _, bom := range byteOrderMarks {
+		if bom.encoding == name && bytes.HasPrefix(sample, bom.mark) {
+			buffered.Discard(len(bom.mark))
+			break
+		}
+	}
+
+	var decoded io.Reader = buffered
+	if name != UTF8 {
+		enc, err := htmlindex.Get(name)
+		if err != nil {
+			return nil, fmt.Errorf("unsupported charset %q", name)
+		}
+		decoded = transform.NewReader(buffered, enc.NewDecoder())
+	}
+
+	// Rewrite the declaration so the XML decoder does not convert the document again
+	head := make([]byte, declarationSize)
+	n, err := io.ReadFull(decoded, head)
+	if err != nil && err != io.EOF && err != io.ErrUnexpectedEOF {
+		return nil, fmt.Errorf("failed to decode %s: %w", name, err)
+	}
+
+	return io.MultiReader(bytes.NewReader(declareUTF8(head[:n])), decoded), nil
+}
+
+// NewReader returns a reader that converts input in the named encoding to UTF-8.
+// It can be used as the CharsetReader of an xml.Decoder.
+func NewReader(label string, input io.Reader) (io.Reader, error) {
+	name, err := canonicalName(label)
+	if err != nil {
+		return nil, err
+	}
+	if name == UTF8 {
+		return input, nil
+	}
+
+	enc, err := htmlindex.Get(name)
+	if err != nil {
+		return nil, fmt.Errorf("unsupported charset %q", label)
+	}
+	return transform.NewReader(input, enc.NewDecoder()), nil
+}
+
+// canonicalName returns the WHATWG name of an encoding label
+func canonicalName(label string) (string, error) {
+	enc, err := htmlindex.Get(strings.TrimSpace(label))
+	if err != nil {
+		return "", fmt.Errorf("unsupported charset %q", label)
+	}
+	name, err := htmlindex.Name(enc)
+	if err != nil {
+		return "", fmt.Errorf("unsupported charset %q", label)
+	}
+	return name, nil
+}
+
+// isUTF8 reports whether an encoding label names UTF-8
+func isUTF8(label string) bool {
+	name, err := canonicalName(label)
+	return err == nil && name == UTF8
+}
+
+// contentTypeCharset returns the charset parameter of a Content-Type header
+func contentTypeCharset(contentType string) string {
+	if contentType == "" {
+		return ""
+	}
+	_, params, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return ""
+	}
+	return params["charset"]
+}
+
+// trimPartialRune removes a UTF-8 sequence cut off at the end of data
+func trimPartialRune(data []byte) []byte {
+	for i := 1; i <= utf8.UTFMax && i <= len(data); i++ {
+		if utf8.RuneStart(data[len(data)-i]) {
+			if !utf8.FullRune(data[len(data)-i:]) {
+				return data[:len(data)-i]
+			}
+			break
+		}
+	}
+	return data
+}
+
+// declaredEncoding returns the encoding named in a document's XML declaration
+func declaredEncoding(data []byte) string {
+	if len(data) > declarationSize {
+		data = data[:declarationSize]
+	}
+	match := xmlDeclaration.FindSubmatch(data)
+	if match == nil {
+		return ""
+	}
+	return string(match[1])
+}
+
+// metaEncoding returns the encoding named in an HTML page's <meta> tags. Like browsers, it only
+// searches the start of the page, and reads a UTF-16 declaration as UTF-8 since a page that could
+// be read to find it is not UTF-16.
+func metaEncoding(data []byte) string {
+	if len(data) > declarationSize {
+		data = data[:declarationSize]
+	}
+	match := metaCharset.FindSubmatch(data)
+	if match == nil {
+		return ""
+	}
+	if label := strings.ToLower(string(match[1])); strings.HasPrefix(label, "utf-16") {
+		return UTF8
+	}
+	return string(match[1])
+}
+
+// declareUTF8 rewrites the encoding of a document's XML declaration to UTF-8
+func declareUTF8(data []byte) []byte {
+	head := data
+	if len(head) > declarationSize {
+		head = head[:declarationSize]
+	}
+	loc := xmlDeclaration.FindSubmatchIndex(head)
+	if loc == nil {
+		return data
+	}
+
+	start, end := loc[2], loc[3]
+	if string(data[start:end]) == "UTF-8" {
+		return data
+	}
+
+	result := make([]byte, 0, len(data)-(end-start)+5)
+	result = append(result, data[:start]...)
+	result = append(result, "UTF-8"...)
+	return append(result, data[end:]...)
+}
@@ -0,0 +1,224 @@
+package charset
+
+import (
+	"bytes"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+)
+
+// readFixture reads a feed from the shared test data directory
+func readFixture(t *testing.T, name string) []byte {
+	t.Helper()
+	data, err := os.ReadFile(filepath.Join("..", "..", "testdata", "charset", name))
+	if err != nil {
+		t.Fatalf("failed to read fixture %s: %v", name, err)
+	}
+	return data
+}
+
+func TestDetect(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        []byte
+		contentType string
+		want        string
+	}{
+		{"no declaration", []byte(`<rss></rss>`), "", UTF8},
+		{"XML declaration", []byte(`<?xml version="1.0" encoding="Shift_JIS"?><rss/>`), "", "shift_jis"},
+		{"single quoted declaration", []byte("<?xml version='1.0' encoding='euc-jp'?><rss/>"), "", "euc-jp"},
+		{"aliases are canonical", []byte(`<?xml version="1.0" encoding="x-sjis"?><rss/>`), "", "shift_jis"},
+		{"ISO-8859-1 is read as windows-1252", []byte(`<?xml version="1.0" encoding="ISO-8859-1"?><rss/>`), "", "windows-1252"},
+		{"Content-Type overrides the declaration", []byte(`<?xml version="1.0" encoding="Shift_JIS"?><rss/>`), "text/xml; charset=EUC-JP", "euc-jp"},
+		{"Content-Type without a declaration", []byte("<rss>\x93\xfa\x96\x7b</rss>"), "application/rss+xml; charset=Shift_JIS", "shift_jis"},
+		{"default charset on valid UTF-8", []byte("<?xml version=\"1.0\" encoding=\"UTF-8\"?><rss>café</rss>"), "text/xml; charset=ISO-8859-1", UTF8},
+		{"default charset on undeclared UTF-8", []byte("<rss>日本語</rss>"), "text/xml; charset=Shift_JIS", UTF8},
+		{"Content-Type without charset", []byte(`<?xml version="1.0" encoding="EUC-JP"?><rss/>`), "text/xml", "euc-jp"},
+		{"byte order mark overrides both", append([]byte{0xEF, 0xBB, 0xBF}, `<?xml version="1.0" encoding="Shift_JIS"?>`...), "text/xml; charset=EUC-JP", UTF8},
+		{"UTF-16 byte order mark", []byte{0xFF, 0xFE, '<', 0}, "", "utf-16le"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := Detect(tt.data, tt.contentType)
+			if err != nil {
+				t.Fatalf("Detect() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("Detect() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+
+	if _, err := Detect([]byte(`<?xml version="1.0" encoding="x-unknown"?>`), ""); err == nil {
+		t.Error("Expected an error for an unsupported encoding")
+	}
+}
+
+func TestToUTF8(t *testing.T) {
+	tests := []struct {
+		fixture     string
+		contentType string
+		want        []string
+	}{
+		{"shift_jis.xml", "application/rss+xml", []string{"日本語のフィード", "文字コードのテスト：ｶﾀｶﾅ～①", "本文です。"}},
+		{"euc-jp.xml", "", []string{"日本語のフィード", "文字コードのテスト：ｶﾀｶﾅ～", "本文です。"}},
+		{"iso-8859-1.xml", "text/xml", []string{"Café crème", "Déjà vu à Zürich", "Ça marche très bien."}},
+		{"shift_jis-undeclared.xml", "text/xml; charset=Shift_JIS", []string{"日本語のフィード", "本文です。"}},
+		{"utf-16le-bom.xml", "", []string{"日本語のフィード", "本文です。"}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			converted, err := ToUTF8(readFixture(t, tt.fixture), tt.contentType)
+			if err != nil {
+				t.Fatalf("ToUTF8() unexpected error: %v", err)
+			}
+			if !bytes.HasPrefix(converted, []byte(`<?xml version="1.0"`)) {
+				t.Errorf("Expected the byte order mark to be removed, got %q", converted[:10])
+			}
+			if declared := declaredEncoding(converted); declared != "" && declared != "UTF-8" {
+				t.Errorf("Expected the declaration to be rewritten to UTF-8, got %q", declared)
+			}
+			for _, text := range tt.want {
+				if !bytes.Contains(converted, []byte(text)) {
+					t.Errorf("Expected %q in the converted feed:\n%s", text, converted)
+				}
+			}
+		})
+	}
+}
+
+func TestToUTF8_KeepsUTF8(t *testing.T) {
+	data := []byte("<?xml version=\"1.0\" encoding=\"utf-8\"?><rss>日本語</rss>")
+	converted, err := ToUTF8(data, "")
+	if err != nil {
+		t.Fatalf("ToUTF8() unexpected error: %v", err)
+	}
+	if string(converted) != "<?xml version=\"1.0\" encoding=\"UTF-8\"?><rss>日本語</rss>" {
+		t.Errorf("Unexpected conversion of a UTF-8 document: %s", converted)
+	}
+}
+
+func TestDetectHTML(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        []byte
+		contentType string
+		want        string
+	}{
+		{"no declaration", []byte(`<html><body>page</body></html>`), "text/html", UTF8},
+		{"meta charset", []byte(`<html><head><meta charset="Shift_JIS"></head>`), "text/html", "shift_jis"},
+		{"unquoted meta charset", []byte(`<html><head><META CHARSET=euc-jp></head>`), "", "euc-jp"},
+		{"http-equiv Content-Type", []byte(`<meta http-equiv="Content-Type" content="text/html; charset=EUC-JP">`), "", "euc-jp"},
+		{"Content-Type overrides the meta tag", []byte("<meta charset=\"euc-jp\"><p>\x93\xfa\x96\x7b</p>"), "text/html; charset=Shift_JIS", "shift_jis"},
+		{"default charset on valid UTF-8", []byte(`<meta charset="utf-8"><p>café</p>`), "text/html; charset=ISO-8859-1", UTF8},
+		{"UTF-16 meta charset is read as UTF-8", []byte(`<meta charset="utf-16"><p>page</p>`), "", UTF8},
+		{"XML declaration is ignored", []byte(`<?xml version="1.0" encoding="Shift_JIS"?><html></html>`), "", UTF8},
+		{"meta charset past the start is ignored", []byte(strings.Repeat(" ", declarationSize) + `<meta charset="Shift_JIS">`), "", UTF8},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := DetectHTML(tt.data, tt.contentType)
+			if err != nil {
+				t.Fatalf("DetectHTML() unexpected error: %v", err)
+			}
+			if got != tt.want {
+				t.Errorf("DetectHTML() = %q, want %q", got, tt.want)
+			}
+		})
+	}
+}
+
+func TestHTMLToUTF8(t *testing.T) {
+	// 日本語のページ in Shift_JIS
+	page := append([]byte(`<html><head><meta charset="Shift_JIS"><title>`), 0x93, 0xfa, 0x96, 0x7b, 0x8c, 0xea, 0x82, 0xcc, 0x83, 0x79, 0x81, 0x5b, 0x83, 0x57)
+	page = append(page, `</title></head></html>`...)
+
+	converted, err := HTMLToUTF8(page, "text/html")
+	if err != nil {
+		t.Fatalf("HTMLToUTF8() unexpected error: %v", err)
+	}
+	if !bytes.Contains(converted, []byte("<title>日本語のページ</title>")) {
+		t.Errorf("Expected the page to be decoded, got %s", converted)
+	}
+
+	converted, err = HTMLToUTF8(append([]byte{0xEF, 0xBB, 0xBF}, "<p>日本語</p>"...), "text/html; charset=Shift_JIS")
+	if err != nil {
+		t.Fatalf("HTMLToUTF8() unexpected error: %v", err)
+	}
+	if string(converted) != "<p>日本語</p>" {
+		t.Errorf("Expected the byte order mark to be removed, got %q", converted)
+	}
+
+	if _, err := HTMLToUTF8([]byte(`<meta charset="x-unknown">`), ""); err == nil {
+		t.Error("Expected an error for an unsupported encoding")
+	}
+}
+
+func TestNewReader(t *testing.T) {
+	reader, err := NewReader("Shift_JIS", bytes.NewReader([]byte{0x93, 0xfa, 0x96, 0x7b}))
+	if err != nil {
+		t.Fatalf("NewReader() unexpected error: %v", err)
+	}
+	decoded, _ := io.ReadAll(reader)
+	if string(decoded) != "日本" {
+		t.Errorf("Expected Shift_JIS to be decoded, got %q", decoded)
+	}
+
+	if _, err := NewReader("x-unknown", strings.NewReader("")); err == nil {
+		t.Error("Expected an error for an unsupported encoding")
+	}
+}
+
+func TestNewDocumentReader(t *testing.T) {
+	tests := []struct {
+		fixture     string
+		contentType string
+	}{
+		{"shift_jis.xml", ""},
+		{"euc-jp.xml", ""},
+		{"iso-8859-1.xml", "text/xml"},
+		{"shift_jis-undeclared.xml", "text/xml; charset=Shift_JIS"},
+		{"utf-16le-bom.xml", ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.fixture, func(t *testing.T) {
+			data := readFixture(t, tt.fixture)
+			want, err := ToUTF8(data, tt.contentType)
+			if err != nil {
+				t.Fatalf("ToUTF8() unexpected error: %v", err)
+			}
+
+			reader, err := NewDocumentReader(bytes.NewReader(data), tt.contentType)
+			if err != nil {
+				t.Fatalf("NewDocumentReader() unexpected error: %v", err)
+			}
+			got, err := io.ReadAll(reader)
+			if err != nil {
+				t.Fatalf("Failed to read document: %v", err)
+			}
+			if !bytes.Equal(got, want) {
+				t.Errorf("Expected the same conversion as ToUTF8:\n%s\ngot:\n%s", want, got)
+			}
+		})
+	}
+}
+
+func TestNewDocumentReader_LongDocument(t *testing.T) {
+	// A multibyte character is cut off at the end of the sniffed sample
+	data := []byte(`<?xml version="1.0"?><rss>` + strings.Repeat("a", sniffSize-27) + "日本語</rss>")
+
+	reader, err := NewDocumentReader(bytes.NewReader(data), "text/xml; charset=ISO-8859-1")
+	if err != nil {
+		t.Fatalf("NewDocumentReader() unexpected error: %v", err)
+	}
+	got, _ := io.ReadAll(reader)
+	if !bytes.Equal(got, data) {
+		t.Errorf("Expected the UTF-8 document to be kept, got %q", got[len(got)-20:])
+	}
+}
@@ -93,23 +93,31 @@ func (c *Client) GetTableName(tableName string) string {
 
 // TableNames contains all table names used by the application
 type TableNames struct {
-	Users         string
-	Bowers        string
-	Feeds         string
-	Articles      string
-	LikedArticles string
-	ChickStats    string
+	Users             string
+	Bowers            string
+	Feeds             string
+	Subscriptions     string
+	Articles          string
+	LikedArticles     string
+	ReadStates        string
+	PlaybackPositions string
+	ChickStats        string
+	SearchIndex       string
 }
 
 // GetTableNames returns all table names with the configured prefix and suffix
 func (c *Client) GetTableNames() *TableNames {
 	return &TableNames{
-		Users:         c.GetTableName("users"),
-		Bowers:        c.GetTableName("bowers"),
-		Feeds:         c.GetTableName("feeds"),
-		Articles:      c.GetTableName("articles"),
-		LikedArticles: c.GetTableName("liked-articles"),
-		ChickStats:    c.GetTableName("chick-stats"),
+		Users:             c.GetTableName("users"),
+		Bowers:            c.GetTableName("bowers"),
+		Feeds:             c.GetTableName("feeds"),
+		Subscriptions:     c.GetTableName("subscriptions"),
+		Articles:          c.GetTableName("articles"),
+		LikedArticles:     c.GetTableName("liked-articles"),
+		ReadStates:        c.GetTableName("read-states"),
+		PlaybackPositions: c.GetTableName("playback-positions"),
+		ChickStats:        c.GetTableName("chick-stats"),
+		SearchIndex:       c.GetTableName("search-index"),
 	}
 }
 
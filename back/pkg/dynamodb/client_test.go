@@ -77,6 +77,11 @@ func TestGetTableNames(t *testing.T) {
 		t.Errorf("Expected Feeds table name '%s', got '%s'", expected, tableNames.Feeds)
 	}
 
+	expected = "dev_subscriptions-test"
+	if tableNames.Subscriptions != expected {
+		t.Errorf("Expected Subscriptions table name '%s', got '%s'", expected, tableNames.Subscriptions)
+	}
+
 	expected = "dev_articles-test"
 	if tableNames.Articles != expected {
 		t.Errorf("Expected Articles table name '%s', got '%s'", expected, tableNames.Articles)
@@ -87,8 +92,23 @@ func TestGetTableNames(t *testing.T) {
 		t.Errorf("Expected LikedArticles table name '%s', got '%s'", expected, tableNames.LikedArticles)
 	}
 
+	expected = "dev_read-states-test"
+	if tableNames.ReadStates != expected {
+		t.Errorf("Expected ReadStates table name '%s', got '%s'", expected, tableNames.ReadStates)
+	}
+
+	expected = "dev_playback-positions-test"
+	if tableNames.PlaybackPositions != expected {
+		t.Errorf("Expected PlaybackPositions table name '%s', got '%s'", expected, tableNames.PlaybackPositions)
+	}
+
 	expected = "dev_chick-stats-test"
 	if tableNames.ChickStats != expected {
 		t.Errorf("Expected ChickStats table name '%s', got '%s'", expected, tableNames.ChickStats)
 	}
+
+	expected = "dev_search-index-test"
+	if tableNames.SearchIndex != expected {
+		t.Errorf("Expected SearchIndex table name '%s', got '%s'", expected, tableNames.SearchIndex)
+	}
 }
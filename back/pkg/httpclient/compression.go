@@ -0,0 +1,79 @@
+package httpclient
+
+import (
+	"bufio"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"fmt"
+	"io"
+	"net/http"
+	"strings"
+
+	"github.com/andybalholm/brotli"
+)
+
+// AcceptEncoding lists the content codings DecodeBody supports, to send as the Accept-Encoding header
+const AcceptEncoding = "gzip, br, deflate"
+
+// DecodeBody returns a reader of a response's body with its Content-Encoding removed.
+// Responses that are not encoded, or that the transport already decompressed, are returned as is.
+// Closing the reader closes the response body.
+func DecodeBody(resp *http.Response) (io.ReadCloser, error) {
+	encoding := strings.ToLower(strings.TrimSpace(resp.Header.Get("Content-Encoding")))
+	if resp.Uncompressed || encoding == "" || encoding == "identity" {
+		return resp.Body, nil
+	}
+
+	var reader io.Reader
+	switch encoding {
+	case "gzip", "x-gzip":
+		gz, err := gzip.NewReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("invalid gzip body: %w", err)
+		}
+		reader = gz
+	case "br":
+		reader = brotli.NewReader(resp.Body)
+	case "deflate":
+		deflated, err := newDeflateReader(resp.Body)
+		if err != nil {
+			return nil, fmt.Errorf("invalid deflate body: %w", err)
+		}
+		reader = deflated
+	default:
+		return nil, fmt.Errorf("unsupported content encoding %q", encoding)
+	}
+
+	return &decodedBody{Reader: reader, body: resp.Body}, nil
+}
+
+// newDeflateReader reads a deflate body. HTTP deflate is zlib-wrapped, but some servers send raw
+// deflate data, so the zlib header is checked first.
+func newDeflateReader(body io.Reader) (io.Reader, error) {
+	buffered := bufio.NewReader(body)
+	header, err := buffered.Peek(2)
+	if err != nil {
+		return nil, err
+	}
+
+	// A zlib header uses the deflate method and is a multiple of 31
+	if header[0]&0x0f == 8 && (uint16(header[0])<<8|uint16(header[1]))%31 == 0 {
+		return zlib.NewReader(buffered)
+	}
+	return flate.NewReader(buffered), nil
+}
+
+// decodedBody is a decompressing reader that closes the response body
+type decodedBody struct {
+	io.Reader
+	body io.ReadCloser
+}
+
+// Close closes the decompressor, if it needs closing, and the response body
+func (b *decodedBody) Close() error {
+	if closer, ok := b.Reader.(io.Closer); ok {
+		closer.Close()
+	}
+	return b.body.Close()
+}
@@ -0,0 +1,101 @@
+package httpclient
+
+import (
+	"bytes"
+	"compress/flate"
+	"compress/gzip"
+	"compress/zlib"
+	"io"
+	"net/http"
+	"testing"
+
+	"github.com/andybalholm/brotli"
+)
+
+func TestDecodeBody(t *testing.T) {
+	const feed = `<?xml version="1.0"?><rss><channel><title>Compressed</title></channel></rss>`
+
+	compress := func(newWriter func(io.Writer) io.WriteCloser) []byte {
+		var buf bytes.Buffer
+		w := newWriter(&buf)
+		w.Write([]byte(feed))
+		w.Close()
+		return buf.Bytes()
+	}
+
+	tests := []struct {
+		name     string
+		encoding string
+		body     []byte
+	}{
+		{"identity", "", []byte(feed)},
+		{"gzip", "gzip", compress(func(w io.Writer) io.WriteCloser { return gzip.NewWriter(w) })},
+		{"brotli", "br", compress(func(w io.Writer) io.WriteCloser { return brotli.NewWriter(w) })},
+		{"zlib deflate", "deflate", compress(func(w io.Writer) io.WriteCloser { return zlib.NewWriter(w) })},
+		{"raw deflate", "Deflate", compress(func(w io.Writer) io.WriteCloser {
+			fw, _ := flate.NewWriter(w, flate.DefaultCompression)
+			return fw
+		})},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			resp := &http.Response{
+				Header: http.Header{},
+				Body:   io.NopCloser(bytes.NewReader(tt.body)),
+			}
+			if tt.encoding != "" {
+				resp.Header.Set("Content-Encoding", tt.encoding)
+			}
+
+			body, err := DecodeBody(resp)
+			if err != nil {
+				t.Fatalf("DecodeBody() unexpected error: %v", err)
+			}
+			defer body.Close()
+
+			decoded, err := io.ReadAll(body)
+			if err != nil {
+				t.Fatalf("Failed to read decoded body: %v", err)
+			}
+			if string(decoded) != feed {
+				t.Errorf("Expected %q, got %q", feed, decoded)
+			}
+		})
+	}
+}
+
+func TestDecodeBody_Errors(t *testing.T) {
+	resp := &http.Response{
+		Header: http.Header{"Content-Encoding": []string{"compress"}},
+		Body:   io.NopCloser(bytes.NewReader(nil)),
+	}
+	if _, err := DecodeBody(resp); err == nil {
+		t.Error("Expected an error for an unsupported content encoding")
+	}
+
+	resp = &http.Response{
+		Header: http.Header{"Content-Encoding": []string{"gzip"}},
+		Body:   io.NopCloser(bytes.NewReader([]byte("not gzip"))),
+	}
+	if _, err := DecodeBody(resp); err == nil {
+		t.Error("Expected an error for an invalid gzip body")
+	}
+}
+
+func TestDecodeBody_AlreadyDecompressed(t *testing.T) {
+	resp := &http.Response{
+		Header:       http.Header{"Content-Encoding": []string{"gzip"}},
+		Body:         io.NopCloser(bytes.NewReader([]byte("plain"))),
+		Uncompressed: true,
+	}
+
+	body, err := DecodeBody(resp)
+	if err != nil {
+		t.Fatalf("DecodeBody() unexpected error: %v", err)
+	}
+	decoded, _ := io.ReadAll(body)
+	if string(decoded) != "plain" {
+		t.Errorf("Expected the body to be returned as is, got %q", decoded)
+	}
+}
@@ -3,6 +3,7 @@ package httpclient
 import (
 	"context"
 	"fmt"
+	"io"
 	"net"
 	"net/http"
 	"net/url"
@@ -99,6 +100,11 @@ func (c *SecureHTTPClient) Get(ctx context.Context, urlStr string) (*http.Respon
 
 // Do performs a secure HTTP request
 func (c *SecureHTTPClient) Do(ctx context.Context, method, urlStr string, headers map[string]string) (*http.Response, error) {
+	return c.DoWithBody(ctx, method, urlStr, headers, nil)
+}
+
+// DoWithBody performs a secure HTTP request with a request body
+func (c *SecureHTTPClient) DoWithBody(ctx context.Context, method, urlStr string, headers map[string]string, body io.Reader) (*http.Response, error) {
 	// Sanitize and validate input URL string first
 	sanitizedURL, err := sanitizeURLString(urlStr)
 	if err != nil {
@@ -117,7 +123,7 @@ func (c *SecureHTTPClient) Do(ctx context.Context, method, urlStr string, header
 	}
 
 	// Create request with the validated and sanitized URL
-	req, err := http.NewRequestWithContext(ctx, method, parsedURL.String(), nil)
+	req, err := http.NewRequestWithContext(ctx, method, parsedURL.String(), body)
 	if err != nil {
 		return nil, fmt.Errorf("failed to create request: %w", err)
 	}
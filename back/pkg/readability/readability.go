@@ -0,0 +1,409 @@
+package readability
+
+import (
+	"errors"
+	"io"
+	"math"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode/utf8"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+
+	"feed-bower-api/pkg/sanitize"
+)
+
+// Extraction thresholds
+const (
+	minParagraphLength = 25  // Shorter paragraphs are not scored
+	minContentLength   = 140 // Extractions with less text are treated as failures
+)
+
+// ErrNoContent is returned when no main content could be found in a page
+var ErrNoContent = errors.New("no readable content found")
+
+var (
+	// unlikelyCandidates match the class and id of page furniture that is removed before scoring
+	unlikelyCandidates = regexp.MustCompile(`(?i)banner|breadcrumb|combx|comment|community|cover-wrap|disqus|extra|footer|gdpr|header|legends|menu|related|remark|replies|rss|shoutbox|sidebar|skyscraper|social|sponsor|supplemental|ad-break|agegate|pagination|pager|popup|yom-remote|share|cookie|newsletter|subscribe`)
+	// maybeCandidates match the class and id of elements kept despite matching unlikelyCandidates
+	maybeCandidates = regexp.MustCompile(`(?i)and|article|body|column|content|main|shadow`)
+	// positiveNames and negativeNames adjust an element's score by its class and id
+	positiveNames = regexp.MustCompile(`(?i)article|body|content|entry|hentry|h-entry|main|page|pagination|post|text|blog|story`)
+	negativeNames = regexp.MustCompile(`(?i)-ad-|hidden|^hid$| hid$| hid |^hid |banner|combx|comment|com-|contact|foot|footer|footnote|gdpr|masthead|media|meta|outbrain|promo|related|scroll|share|shoutbox|sidebar|skyscraper|sponsor|shopping|tags|tool|widget`)
+)
+
+// removedElements never contain main content
+var removedElements = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Template: true, atom.Iframe: true,
+	atom.Nav: true, atom.Aside: true, atom.Footer: true, atom.Header: true, atom.Form: true,
+	atom.Button: true, atom.Input: true, atom.Select: true, atom.Textarea: true, atom.Svg: true,
+}
+
+// Article is the main content extracted from a page
+type Article struct {
+	Title   string
+	Content string // Sanitized HTML
+	Text    string // Plain text of the content
+}
+
+// candidate is an element that may contain the main content, with its score
+type candidate struct {
+	node  *html.Node
+	score float64
+}
+
+// Extract finds the main content of an HTML page, in the manner of Arc90's Readability:
+// paragraphs are scored by length and commas, their scores propagate to the parent and
+// grandparent elements, and the best scoring element, adjusted for link density, is kept
+// together with siblings that look like part of the same content. Links and images are
+// resolved against pageURL.
+func Extract(r io.Reader, pageURL *url.URL) (*Article, error) {
+	doc, err := html.Parse(r)
+	if err != nil {
+		return nil, err
+	}
+
+	article := &Article{Title: pageTitle(doc)}
+
+	body := findElement(doc, atom.Body)
+	if body == nil {
+		return nil, ErrNoContent
+	}
+	prune(body)
+
+	top := topCandidate(body)
+	if top == nil {
+		return nil, ErrNoContent
+	}
+
+	content := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+	for _, node := range relatedContent(top) {
+		node.Parent.RemoveChild(node)
+		content.AppendChild(node)
+	}
+
+	article.Text = collapseSpace(textContent(content))
+	if utf8.RuneCountInString(article.Text) < minContentLength {
+		return nil, ErrNoContent
+	}
+	article.Content = sanitize.Node(content, pageURL)
+
+	return article, nil
+}
+
+// pageTitle returns the page's Open Graph title, or its <title>
+func pageTitle(doc *html.Node) string {
+	var title, ogTitle string
+	walk(doc, func(node *html.Node) bool {
+		switch node.DataAtom {
+		case atom.Title:
+			if title == "" {
+				title = collapseSpace(textContent(node))
+			}
+		case atom.Meta:
+			if attr(node, "property") == "og:title" && ogTitle == "" {
+				ogTitle = strings.TrimSpace(attr(node, "content"))
+			}
+		}
+		return true
+	})
+	if ogTitle != "" {
+		return ogTitle
+	}
+	return title
+}
+
+// prune removes elements that never hold the main content, and unlikely candidates
+func prune(body *html.Node) {
+	var remove []*html.Node
+	walk(body, func(node *html.Node) bool {
+		if node.Type == html.CommentNode {
+			remove = append(remove, node)
+			return false
+		}
+		if node.Type != html.ElementNode || node == body {
+			return true
+		}
+		if removedElements[node.DataAtom] || isHidden(node) {
+			remove = append(remove, node)
+			return false
+		}
+		names := attr(node, "class") + " " + attr(node, "id")
+		if node.DataAtom != atom.Article && node.DataAtom != atom.Main &&
+			unlikelyCandidates.MatchString(names) && !maybeCandidates.MatchString(names) {
+			remove = append(remove, node)
+			return false
+		}
+		return true
+	})
+	for _, node := range remove {
+		node.Parent.RemoveChild(node)
+	}
+}
+
+// topCandidate scores paragraphs and returns the element most likely to hold the main content
+func topCandidate(body *html.Node) *candidate {
+	scores := make(map[*html.Node]*candidate)
+	var order []*candidate
+
+	initialize := func(node *html.Node) *candidate {
+		if c, ok := scores[node]; ok {
+			return c
+		}
+		c := &candidate{node: node, score: elementWeight(node) + classWeight(node)}
+		scores[node] = c
+		order = append(order, c)
+		return c
+	}
+
+	walk(body, func(node *html.Node) bool {
+		if node.Type != html.ElementNode || !isParagraph(node) {
+			return true
+		}
+		text := collapseSpace(textContent(node))
+		length := utf8.RuneCountInString(text)
+		if length < minParagraphLength || node.Parent == nil {
+			return true
+		}
+
+		score := 1 + float64(strings.Count(text, ",")+strings.Count(text, "、")+strings.Count(text, "，"))
+		score += math.Min(float64(length)/100, 3)
+
+		initialize(node.Parent).score += score
+		if grandparent := node.Parent.Parent; grandparent != nil && grandparent.Type == html.ElementNode {
+			initialize(grandparent).score += score / 2
+		}
+		return true
+	})
+
+	var top *candidate
+	for _, c := range order {
+		c.score *= 1 - linkDensity(c.node)
+		if top == nil || c.score > top.score {
+			top = c
+		}
+	}
+	if top == nil {
+		return nil
+	}
+	top.score = math.Max(top.score, 0)
+
+	// Candidates without siblings add nothing; their parent may hold more of the content
+	for top.node.Parent != nil && top.node.Parent.DataAtom != atom.Body && onlyElementChild(top.node) {
+		parent, ok := scores[top.node.Parent]
+		if !ok {
+			parent = &candidate{node: top.node.Parent, score: top.score}
+		}
+		top = parent
+	}
+
+	return top
+}
+
+// relatedContent returns the top candidate and the siblings that look like part of the same content
+func relatedContent(top *candidate) []*html.Node {
+	if top.node.Parent == nil {
+		return []*html.Node{top.node}
+	}
+
+	threshold := math.Max(10, top.score*0.2)
+	topClass := attr(top.node, "class")
+
+	var nodes []*html.Node
+	for sibling := top.node.Parent.FirstChild; sibling != nil; sibling = sibling.NextSibling {
+		if sibling == top.node {
+			nodes = append(nodes, sibling)
+			continue
+		}
+		if sibling.Type != html.ElementNode {
+			continue
+		}
+
+		bonus := 0.0
+		if topClass != "" && attr(sibling, "class") == topClass {
+			bonus = top.score * 0.2
+		}
+		siblingScore := elementWeight(sibling) + classWeight(sibling) + bonus
+
+		keep := siblingScore >= threshold
+		if !keep && sibling.DataAtom == atom.P {
+			text := collapseSpace(textContent(sibling))
+			length := utf8.RuneCountInString(text)
+			density := linkDensity(sibling)
+			keep = (length > 80 && density < 0.25) ||
+				(length > 0 && length <= 80 && density == 0 && strings.ContainsAny(text, ".。"))
+		}
+		if keep {
+			nodes = append(nodes, sibling)
+		}
+	}
+	return nodes
+}
+
+// elementWeight is a candidate's initial score by its tag
+func elementWeight(node *html.Node) float64 {
+	switch node.DataAtom {
+	case atom.Article:
+		return 10
+	case atom.Div, atom.Section, atom.Main:
+		return 5
+	case atom.Pre, atom.Td, atom.Blockquote:
+		return 3
+	case atom.Address, atom.Ol, atom.Ul, atom.Dl, atom.Dd, atom.Dt, atom.Li, atom.Form:
+		return -3
+	case atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Th:
+		return -5
+	}
+	return 0
+}
+
+// classWeight scores an element's class and id
+func classWeight(node *html.Node) float64 {
+	weight := 0.0
+	for _, name := range []string{attr(node, "class"), attr(node, "id")} {
+		if name == "" {
+			continue
+		}
+		if negativeNames.MatchString(name) {
+			weight -= 25
+		}
+		if positiveNames.MatchString(name) {
+			weight += 25
+		}
+	}
+	return weight
+}
+
+// linkDensity is the share of an element's text that is link text
+func linkDensity(node *html.Node) float64 {
+	length := utf8.RuneCountInString(collapseSpace(textContent(node)))
+	if length == 0 {
+		return 0
+	}
+
+	linkLength := 0
+	walk(node, func(n *html.Node) bool {
+		if n.DataAtom == atom.A {
+			linkLength += utf8.RuneCountInString(collapseSpace(textContent(n)))
+			return false
+		}
+		return true
+	})
+	return float64(linkLength) / float64(length)
+}
+
+// isParagraph reports whether an element is scored as a paragraph: a <p>, <pre> or <td>, or a
+// <div> holding text directly rather than through block elements
+func isParagraph(node *html.Node) bool {
+	switch node.DataAtom {
+	case atom.P, atom.Pre, atom.Td, atom.Blockquote:
+		return true
+	case atom.Div:
+		for child := node.FirstChild; child != nil; child = child.NextSibling {
+			if child.Type == html.ElementNode && isBlock(child.DataAtom) {
+				return false
+			}
+		}
+		return true
+	}
+	return false
+}
+
+// isBlock reports whether an element starts a block of its own
+func isBlock(a atom.Atom) bool {
+	switch a {
+	case atom.Address, atom.Article, atom.Aside, atom.Blockquote, atom.Div, atom.Dl, atom.Figure,
+		atom.Footer, atom.Form, atom.H1, atom.H2, atom.H3, atom.H4, atom.H5, atom.H6, atom.Header,
+		atom.Hr, atom.Main, atom.Nav, atom.Ol, atom.P, atom.Pre, atom.Section, atom.Table, atom.Ul:
+		return true
+	}
+	return false
+}
+
+// isHidden reports whether an element is hidden from readers
+func isHidden(node *html.Node) bool {
+	if _, hidden := attrValue(node, "hidden"); hidden {
+		return true
+	}
+	if attr(node, "aria-hidden") == "true" {
+		return true
+	}
+	style := strings.ReplaceAll(strings.ToLower(attr(node, "style")), " ", "")
+	return strings.Contains(style, "display:none") || strings.Contains(style, "visibility:hidden")
+}
+
+// onlyElementChild reports whether a node has no element or text siblings
+func onlyElementChild(node *html.Node) bool {
+	for sibling := node.Parent.FirstChild; sibling != nil; sibling = sibling.NextSibling {
+		if sibling == node {
+			continue
+		}
+		if sibling.Type == html.ElementNode || (sibling.Type == html.TextNode && strings.TrimSpace(sibling.Data) != "") {
+			return false
+		}
+	}
+	return true
+}
+
+// findElement returns the first element of the given type
+func findElement(root *html.Node, a atom.Atom) *html.Node {
+	var found *html.Node
+	walk(root, func(node *html.Node) bool {
+		if found != nil {
+			return false
+		}
+		if node.Type == html.ElementNode && node.DataAtom == a {
+			found = node
+			return false
+		}
+		return true
+	})
+	return found
+}
+
+// walk calls fn for root and its descendants in document order; fn returns false to skip a node's children
+func walk(root *html.Node, fn func(*html.Node) bool) {
+	if !fn(root) {
+		return
+	}
+	for child := root.FirstChild; child != nil; {
+		next := child.NextSibling // fn may detach child
+		walk(child, fn)
+		child = next
+	}
+}
+
+// textContent returns the text of a node and its descendants
+func textContent(node *html.Node) string {
+	var b strings.Builder
+	walk(node, func(n *html.Node) bool {
+		if n.Type == html.TextNode {
+			b.WriteString(n.Data)
+			b.WriteByte(' ')
+		}
+		return true
+	})
+	return b.String()
+}
+
+// collapseSpace trims text and collapses runs of whitespace into single spaces
+func collapseSpace(text string) string {
+	return strings.Join(strings.Fields(text), " ")
+}
+
+// attr returns an attribute's value, or "" if the node doesn't have it
+func attr(node *html.Node, key string) string {
+	value, _ := attrValue(node, key)
+	return value
+}
+
+func attrValue(node *html.Node, key string) (string, bool) {
+	for _, a := range node.Attr {
+		if a.Key == key {
+			return a.Val, true
+		}
+	}
+	return "", false
+}
@@ -0,0 +1,78 @@
+package readability
+
+import (
+	"errors"
+	"net/url"
+	"strings"
+	"testing"
+)
+
+const articlePage = `<!DOCTYPE html>
+<html>
+<head>
+  <title>Lorem Times | Cities are planting forests</title>
+  <meta property="og:title" content="Cities are planting tiny forests">
+  <script>var tracking = true;</script>
+</head>
+<body>
+  <header class="site-header"><a href="/">Lorem Times</a><nav><a href="/world">World</a> <a href="/tech">Tech</a></nav></header>
+  <div class="layout">
+    <div id="main-column">
+      <article class="story">
+        <h1>Cities are planting tiny forests</h1>
+        <div class="share-buttons"><a href="https://social.example/share">Share</a></div>
+        <p>Across Europe and Asia, city councils are turning parking lots, verges and school yards into dense patches of native trees, a method first used by the botanist Akira Miyawaki in the 1970s.</p>
+        <p>The forests are small, often no larger than a tennis court, but they grow quickly, attract insects and birds, and cool the streets around them by several degrees during summer heat waves.</p>
+        <figure><img src="/images/forest.jpg" alt="A tiny forest"><figcaption>A tiny forest in Utrecht</figcaption></figure>
+        <p>Critics say the projects are expensive per tree, and that maintenance in the first three years is often underfunded, but supporters point to survival rates above ninety percent.</p>
+        <p>Read the <a href="/reports/forests.pdf" onclick="track()">full report</a> for the data.</p>
+        <div style="display:none">Hidden promotional text that must not appear in the extracted article at all.</div>
+      </article>
+    </div>
+    <aside class="sidebar"><p>Most read: ten things you did not know about parking lots, and other stories, and more.</p></aside>
+  </div>
+  <div class="comments"><p>First! This is a comment that is long enough to be scored as a paragraph, with commas, commas.</p></div>
+  <footer><p>Copyright Lorem Times, all rights reserved, 2026.</p></footer>
+</body>
+</html>`
+
+func TestExtract(t *testing.T) {
+	pageURL, _ := url.Parse("https://news.example.com/2026/forests")
+
+	article, err := Extract(strings.NewReader(articlePage), pageURL)
+	if err != nil {
+		t.Fatalf("Extract() unexpected error: %v", err)
+	}
+
+	if article.Title != "Cities are planting tiny forests" {
+		t.Errorf("Expected the Open Graph title, got %q", article.Title)
+	}
+
+	for _, want := range []string{
+		"Akira Miyawaki",
+		"survival rates above ninety percent",
+		`<img src="https://news.example.com/images/forest.jpg" alt="A tiny forest">`,
+		`<a href="https://news.example.com/reports/forests.pdf" rel="nofollow noopener noreferrer">full report</a>`,
+	} {
+		if !strings.Contains(article.Content, want) {
+			t.Errorf("Expected content to contain %q, got %s", want, article.Content)
+		}
+	}
+
+	for _, unwanted := range []string{"World", "Share", "Most read", "First!", "Copyright", "Hidden promotional", "onclick", "tracking"} {
+		if strings.Contains(article.Content, unwanted) {
+			t.Errorf("Expected content not to contain %q, got %s", unwanted, article.Content)
+		}
+	}
+
+	if !strings.HasPrefix(article.Text, "Cities are planting tiny forests Across Europe") {
+		t.Errorf("Expected plain text of the article, got %q", article.Text)
+	}
+}
+
+func TestExtract_NoContent(t *testing.T) {
+	page := `<html><body><nav><a href="/">Home</a></nav><p>Short.</p></body></html>`
+	if _, err := Extract(strings.NewReader(page), nil); !errors.Is(err, ErrNoContent) {
+		t.Errorf("Expected ErrNoContent, got %v", err)
+	}
+}
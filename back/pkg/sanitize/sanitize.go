@@ -0,0 +1,398 @@
+package sanitize
+
+import (
+	"net/url"
+	"strconv"
+	"strings"
+
+	"golang.org/x/net/html"
+	"golang.org/x/net/html/atom"
+)
+
+// allowedElements maps the elements kept in sanitized HTML to their allowed attributes.
+// Other elements are unwrapped: their children are kept.
+var allowedElements = map[atom.Atom][]string{
+	atom.A:          {"href", "title"},
+	atom.Abbr:       {"title"},
+	atom.B:          nil,
+	atom.Blockquote: {"cite"},
+	atom.Br:         nil,
+	atom.Caption:    nil,
+	atom.Cite:       nil,
+	atom.Code:       nil,
+	atom.Dd:         nil,
+	atom.Del:        nil,
+	atom.Details:    nil,
+	atom.Div:        nil,
+	atom.Dl:         nil,
+	atom.Dt:         nil,
+	atom.Em:         nil,
+	atom.Figcaption: nil,
+	atom.Figure:     nil,
+	atom.H1:         nil,
+	atom.H2:         nil,
+	atom.H3:         nil,
+	atom.H4:         nil,
+	atom.H5:         nil,
+	atom.H6:         nil,
+	atom.Hr:         nil,
+	atom.I:          nil,
+	atom.Img:        {"src", "alt", "title", "width", "height"},
+	atom.Ins:        nil,
+	atom.Kbd:        nil,
+	atom.Li:         nil,
+	atom.Mark:       nil,
+	atom.Ol:         {"start"},
+	atom.P:          nil,
+	atom.Pre:        nil,
+	atom.Q:          {"cite"},
+	atom.S:          nil,
+	atom.Samp:       nil,
+	atom.Small:      nil,
+	atom.Span:       nil,
+	atom.Strong:     nil,
+	atom.Sub:        nil,
+	atom.Summary:    nil,
+	atom.Sup:        nil,
+	atom.Table:      nil,
+	atom.Tbody:      nil,
+	atom.Td:         {"colspan", "rowspan"},
+	atom.Tfoot:      nil,
+	atom.Th:         {"colspan", "rowspan"},
+	atom.Thead:      nil,
+	atom.Time:       {"datetime"},
+	atom.Tr:         nil,
+	atom.U:          nil,
+	atom.Ul:         nil,
+}
+
+// droppedElements are removed together with their content
+var droppedElements = map[atom.Atom]bool{
+	atom.Script: true, atom.Style: true, atom.Noscript: true, atom.Template: true,
+	atom.Iframe: true, atom.Frame: true, atom.Frameset: true, atom.Object: true, atom.Embed: true, atom.Applet: true,
+	atom.Form: true, atom.Input: true, atom.Button: true, atom.Select: true, atom.Textarea: true,
+	atom.Svg: true, atom.Math: true, atom.Head: true, atom.Title: true, atom.Meta: true, atom.Link: true, atom.Base: true,
+}
+
+// urlAttributes hold URLs, which are resolved and checked against the allowed schemes
+var urlAttributes = map[string]bool{"href": true, "src": true, "cite": true}
+
+// blockElements separate words in plain text
+var blockElements = map[atom.Atom]bool{
+	atom.Address: true, atom.Article: true, atom.Aside: true, atom.Blockquote: true, atom.Br: true,
+	atom.Caption: true, atom.Dd: true, atom.Details: true, atom.Div: true, atom.Dl: true, atom.Dt: true,
+	atom.Figcaption: true, atom.Figure: true, atom.Footer: true, atom.H1: true, atom.H2: true, atom.H3: true,
+	atom.H4: true, atom.H5: true, atom.H6: true, atom.Header: true, atom.Hr: true, atom.Li: true, atom.Main: true,
+	atom.Nav: true, atom.Ol: true, atom.P: true, atom.Pre: true, atom.Section: true, atom.Summary: true,
+	atom.Table: true, atom.Td: true, atom.Th: true, atom.Tr: true, atom.Ul: true,
+}
+
+// trackingHosts serve tracking pixels and web beacons rather than images. Subdomains match too.
+var trackingHosts = []string{
+	"doubleclick.net",
+	"feeds.feedburner.com",
+	"feedsportal.com",
+	"google-analytics.com",
+	"pixel.wp.com",
+	"quantserve.com",
+	"scorecardresearch.com",
+	"stats.wordpress.com",
+}
+
+// HTML returns the HTML fragment with only allowlisted elements and attributes. Relative URLs are
+// resolved against base if it is not nil; URLs with schemes other than http, https and mailto are removed.
+// Tracking pixels are removed.
+func HTML(fragment string, base *url.URL) string {
+	nodes, err := parseFragment(fragment)
+	if err != nil {
+		return html.EscapeString(fragment)
+	}
+
+	var b strings.Builder
+	for _, node := range nodes {
+		writeNode(&b, node, base)
+	}
+	return strings.TrimSpace(b.String())
+}
+
+// Text returns the plain text of an HTML fragment, with entities decoded and whitespace collapsed.
+// The content of dropped elements such as scripts is left out.
+func Text(fragment string) string {
+	nodes, err := parseFragment(fragment)
+	if err != nil {
+		return strings.Join(strings.Fields(fragment), " ")
+	}
+
+	var b strings.Builder
+	for _, node := range nodes {
+		writeText(&b, node)
+	}
+	return strings.Join(strings.Fields(b.String()), " ")
+}
+
+// ImageURL returns the URL of the first image kept by HTML, or an empty string
+func ImageURL(fragment string, base *url.URL) string {
+	nodes, err := parseFragment(fragment)
+	if err != nil {
+		return ""
+	}
+	for _, node := range nodes {
+		if src := firstImage(node, base); src != "" {
+			return src
+		}
+	}
+	return ""
+}
+
+// URL returns rawURL resolved against base if it is an http or https URL, or an empty string
+func URL(rawURL string, base *url.URL) string {
+	rawURL = strings.TrimSpace(rawURL)
+	if rawURL == "" {
+		return ""
+	}
+	return sanitizeURL(rawURL, base, false)
+}
+
+// parseFragment parses an HTML fragment as the content of a div
+func parseFragment(fragment string) ([]*html.Node, error) {
+	context := &html.Node{Type: html.ElementNode, Data: "div", DataAtom: atom.Div}
+	return html.ParseFragment(strings.NewReader(fragment), context)
+}
+
+// Node returns the sanitized HTML of a parsed node and its descendants
+func Node(node *html.Node, base *url.URL) string {
+	var b strings.Builder
+	writeNode(&b, node, base)
+	return strings.TrimSpace(b.String())
+}
+
+// writeNode writes the sanitized HTML of a node and its descendants
+func writeNode(b *strings.Builder, node *html.Node, base *url.URL) {
+	switch node.Type {
+	case html.TextNode:
+		b.WriteString(html.EscapeString(node.Data))
+		return
+	case html.ElementNode:
+	case html.DocumentNode:
+		writeChildren(b, node, base)
+		return
+	default:
+		return // Comments and doctypes
+	}
+
+	if droppedElements[node.DataAtom] {
+		return
+	}
+
+	allowedAttributes, allowed := allowedElements[node.DataAtom]
+	if !allowed {
+		writeChildren(b, node, base)
+		return
+	}
+
+	attributes, ok := sanitizeAttributes(node, allowedAttributes, base)
+	if ok && node.DataAtom == atom.Img && isTrackingPixel(node, attributes) {
+		return
+	}
+	if !ok {
+		// Images without a usable source are dropped; links without one keep their text
+		if node.DataAtom == atom.Img {
+			return
+		}
+		writeChildren(b, node, base)
+		return
+	}
+
+	b.WriteByte('<')
+	b.WriteString(node.Data)
+	for _, attr := range attributes {
+		b.WriteByte(' ')
+		b.WriteString(attr.Key)
+		b.WriteString(`="`)
+		b.WriteString(html.EscapeString(attr.Val))
+		b.WriteByte('"')
+	}
+	if node.DataAtom == atom.A {
+		b.WriteString(` rel="nofollow noopener noreferrer"`)
+	}
+	b.WriteByte('>')
+
+	if isVoidElement(node.DataAtom) {
+		return
+	}
+
+	writeChildren(b, node, base)
+	b.WriteString("</")
+	b.WriteString(node.Data)
+	b.WriteByte('>')
+}
+
+// writeChildren writes the sanitized HTML of a node's children
+func writeChildren(b *strings.Builder, node *html.Node, base *url.URL) {
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		writeNode(b, child, base)
+	}
+}
+
+// writeText writes the text of a node and its descendants, separating block elements with spaces
+func writeText(b *strings.Builder, node *html.Node) {
+	switch node.Type {
+	case html.TextNode:
+		b.WriteString(node.Data)
+		return
+	case html.ElementNode:
+		if droppedElements[node.DataAtom] {
+			return
+		}
+	case html.DocumentNode:
+	default:
+		return
+	}
+
+	block := blockElements[node.DataAtom]
+	if block {
+		b.WriteByte(' ')
+	}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		writeText(b, child)
+	}
+	if block {
+		b.WriteByte(' ')
+	}
+}
+
+// firstImage returns the source of the first image in a node and its descendants that is not
+// a tracking pixel
+func firstImage(node *html.Node, base *url.URL) string {
+	if node.Type == html.ElementNode {
+		if droppedElements[node.DataAtom] {
+			return ""
+		}
+		if node.DataAtom == atom.Img {
+			attributes, ok := sanitizeAttributes(node, allowedElements[atom.Img], base)
+			if !ok || isTrackingPixel(node, attributes) {
+				return ""
+			}
+			for _, attr := range attributes {
+				if attr.Key == "src" {
+					return attr.Val
+				}
+			}
+		}
+	}
+	for child := node.FirstChild; child != nil; child = child.NextSibling {
+		if src := firstImage(child, base); src != "" {
+			return src
+		}
+	}
+	return ""
+}
+
+// isTrackingPixel reports whether an image is a tracking pixel: an image of at most 1x1 pixels,
+// a hidden image or an image served by a known tracking host
+func isTrackingPixel(node *html.Node, attributes []html.Attribute) bool {
+	for _, attr := range node.Attr {
+		switch strings.ToLower(attr.Key) {
+		case "width", "height":
+			value := strings.TrimSuffix(strings.TrimSpace(attr.Val), "px")
+			if size, err := strconv.Atoi(value); err == nil && size <= 1 {
+				return true
+			}
+		case "style":
+			style := strings.ToLower(strings.ReplaceAll(attr.Val, " ", ""))
+			if strings.Contains(style, "display:none") || strings.Contains(style, "visibility:hidden") {
+				return true
+			}
+		}
+	}
+
+	for _, attr := range attributes {
+		if attr.Key != "src" {
+			continue
+		}
+		u, err := url.Parse(attr.Val)
+		if err != nil {
+			return false
+		}
+		host := strings.ToLower(u.Hostname())
+		for _, tracker := range trackingHosts {
+			if host == tracker || strings.HasSuffix(host, "."+tracker) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// sanitizeAttributes returns the allowed attributes of an element. It reports false for links
+// and images whose URL was removed.
+func sanitizeAttributes(node *html.Node, allowed []string, base *url.URL) ([]html.Attribute, bool) {
+	var attributes []html.Attribute
+	for _, attr := range node.Attr {
+		key := strings.ToLower(attr.Key)
+		if attr.Namespace != "" || !contains(allowed, key) {
+			continue
+		}
+
+		value := strings.TrimSpace(attr.Val)
+		if urlAttributes[key] {
+			value = sanitizeURL(value, base, node.DataAtom == atom.A)
+			if value == "" {
+				continue
+			}
+		}
+		attributes = append(attributes, html.Attribute{Key: key, Val: value})
+	}
+
+	switch node.DataAtom {
+	case atom.A:
+		return attributes, hasAttribute(attributes, "href")
+	case atom.Img:
+		return attributes, hasAttribute(attributes, "src")
+	}
+	return attributes, true
+}
+
+// sanitizeURL resolves a URL against base and returns it if its scheme is allowed
+func sanitizeURL(rawURL string, base *url.URL, allowMailto bool) string {
+	u, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	if base != nil {
+		u = base.ResolveReference(u)
+	}
+
+	switch strings.ToLower(u.Scheme) {
+	case "http", "https":
+		return u.String()
+	case "mailto":
+		if allowMailto {
+			return u.String()
+		}
+	}
+	return ""
+}
+
+// isVoidElement reports whether an allowed element has no closing tag
+func isVoidElement(a atom.Atom) bool {
+	return a == atom.Br || a == atom.Hr || a == atom.Img
+}
+
+func hasAttribute(attributes []html.Attribute, key string) bool {
+	for _, attr := range attributes {
+		if attr.Key == key {
+			return true
+		}
+	}
+	return false
+}
+
+func contains(list []string, value string) bool {
+	for _, item := range list {
+		if item == value {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,87 @@
+package sanitize
+
+import (
+	"net/url"
+	"testing"
+)
+
+func TestHTML(t *testing.T) {
+	base, _ := url.Parse("https://example.com/blog/post")
+
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{"allowed markup is kept", `<p>Hello <strong>world</strong></p>`, `<p>Hello <strong>world</strong></p>`},
+		{"scripts are dropped with content", `<p>Hi</p><script>alert(1)</script>`, `<p>Hi</p>`},
+		{"unknown elements are unwrapped", `<section><font color="red">Text</font></section>`, `Text`},
+		{"event handlers are removed", `<p onclick="x()" class="c">Text</p>`, `<p>Text</p>`},
+		{"relative links are resolved", `<a href="../about">About</a>`, `<a href="https://example.com/about" rel="nofollow noopener noreferrer">About</a>`},
+		{"javascript links keep their text", `<a href="javascript:alert(1)">Click</a>`, `Click`},
+		{"images are resolved", `<img src="/a.png" alt="A" onerror="x()">`, `<img src="https://example.com/a.png" alt="A">`},
+		{"data images are dropped", `<img src="data:image/png;base64,AAAA">`, ``},
+		{"text is escaped", `<p>1 &lt; 2 &amp; "quotes"</p>`, `<p>1 &lt; 2 &amp; &#34;quotes&#34;</p>`},
+		{"comments are dropped", `<p>A<!-- secret -->B</p>`, `<p>AB</p>`},
+		{"iframes are dropped", `<iframe src="https://evil.example"></iframe><p>After</p>`, `<p>After</p>`},
+		{"1x1 images are dropped", `<p>Text<img src="/p.gif" width="1" height="1"></p>`, `<p>Text</p>`},
+		{"hidden images are dropped", `<img src="/p.gif" style="display: none">`, ``},
+		{"tracking hosts are dropped", `<img src="https://pixel.wp.com/g.gif?blog=1">`, ``},
+		{"sized images are kept", `<img src="/a.png" width="640">`, `<img src="https://example.com/a.png" width="640">`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := HTML(tt.html, base); got != tt.want {
+				t.Errorf("HTML(%q) = %q, want %q", tt.html, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestText(t *testing.T) {
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{"tags are removed", `<p>Hello <b>world</b></p>`, `Hello world`},
+		{"blocks are separated", `<h1>Title</h1><p>First</p><ul><li>One</li><li>Two</li></ul>`, `Title First One Two`},
+		{"inline elements are not separated", `Go<em>pher</em>`, `Gopher`},
+		{"named entities are decoded", `AT&amp;T &lt;3 &copy;`, `AT&T <3 ©`},
+		{"numeric entities are decoded", `&#x65E5;&#26412; &#8212; Japan`, `日本 — Japan`},
+		{"scripts are dropped", `<script>alert(1)</script>Safe`, `Safe`},
+		{"whitespace is collapsed", "  Line\n\n  break&nbsp;&nbsp;here ", `Line break here`},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := Text(tt.html); got != tt.want {
+				t.Errorf("Text(%q) = %q, want %q", tt.html, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestImageURL(t *testing.T) {
+	base, _ := url.Parse("https://example.com/blog/post")
+
+	tests := []struct {
+		name string
+		html string
+		want string
+	}{
+		{"first image", `<p>Text</p><img src="a.png"><img src="b.png">`, "https://example.com/blog/a.png"},
+		{"tracking pixels are skipped", `<img src="/pixel.gif" width="1" height="1"><figure><img src="/photo"></figure>`, "https://example.com/photo"},
+		{"images in scripts are skipped", `<noscript><img src="/fallback.png"></noscript>`, ""},
+		{"no images", `<p>Text</p>`, ""},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if got := ImageURL(tt.html, base); got != tt.want {
+				t.Errorf("ImageURL(%q) = %q, want %q", tt.html, got, tt.want)
+			}
+		})
+	}
+}
@@ -0,0 +1,336 @@
+package search
+
+import (
+	"math"
+	"sort"
+	"sync"
+)
+
+// BM25 parameters and the weight of a title occurrence relative to a body occurrence
+const (
+	bm25K1      = 1.2
+	bm25B       = 0.75
+	titleWeight = 2.0
+)
+
+// Document is a unit of text added to the index
+type Document struct {
+	ID     string
+	Scopes []string // Groups documents so that a search can be restricted to some scopes
+	Title  string
+	Body   string
+	Time   int64 // Newer documents rank first among equal scores
+}
+
+// Hit is a document matching a search, with its relevance score
+type Hit struct {
+	ID    string
+	Score float64
+	Time  int64 // The document's time, which orders equal scores
+}
+
+// field identifies the part of a document a term occurred in
+type field int
+
+const (
+	fieldTitle field = iota
+	fieldBody
+	fieldCount
+)
+
+// Postings are the positions of a term's occurrences in one document, per field
+type Postings struct {
+	Title []int
+	Body  []int
+}
+
+// positions returns the positions of the term in a field
+func (p *Postings) positions(f field) []int {
+	if f == fieldTitle {
+		return p.Title
+	}
+	return p.Body
+}
+
+// Corpus holds the statistics of the documents a search ranks
+type Corpus struct {
+	Docs              int
+	TotalLength       float64
+	DocumentFrequency func(term string) int // Number of documents containing the term
+}
+
+// docInfo is what the index keeps about a document besides its postings
+type docInfo struct {
+	scopes []string
+	time   int64
+	length float64 // Weighted number of terms, for length normalization
+	terms  []string
+}
+
+// Index is an in-memory inverted index. It is safe for concurrent use.
+type Index struct {
+	mu          sync.RWMutex
+	docs        map[string]*docInfo
+	postings    map[string]map[string]*Postings
+	totalLength float64
+}
+
+// NewIndex creates an empty index
+func NewIndex() *Index {
+	return &Index{
+		docs:     make(map[string]*docInfo),
+		postings: make(map[string]map[string]*Postings),
+	}
+}
+
+// Add adds documents to the index, replacing any documents with the same ID
+func (idx *Index) Add(docs ...Document) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	for _, doc := range docs {
+		if doc.ID == "" {
+			continue
+		}
+		idx.remove(doc.ID)
+
+		postings, length := Analyze(doc.Title, doc.Body)
+		info := &docInfo{scopes: doc.Scopes, time: doc.Time, length: length}
+		for term, p := range postings {
+			docPostings, ok := idx.postings[term]
+			if !ok {
+				docPostings = make(map[string]*Postings)
+				idx.postings[term] = docPostings
+			}
+			docPostings[doc.ID] = p
+			info.terms = append(info.terms, term)
+		}
+
+		idx.docs[doc.ID] = info
+		idx.totalLength += info.length
+	}
+}
+
+// Remove removes documents from the index. Unknown IDs are ignored.
+func (idx *Index) Remove(ids ...string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+
+	for _, id := range ids {
+		idx.remove(id)
+	}
+}
+
+// remove removes a document. The caller must hold the write lock.
+func (idx *Index) remove(id string) {
+	info, ok := idx.docs[id]
+	if !ok {
+		return
+	}
+
+	for _, term := range info.terms {
+		delete(idx.postings[term], id)
+		if len(idx.postings[term]) == 0 {
+			delete(idx.postings, term)
+		}
+	}
+	idx.totalLength -= info.length
+	delete(idx.docs, id)
+}
+
+// Len returns the number of documents in the index
+func (idx *Index) Len() int {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	return len(idx.docs)
+}
+
+// Search returns up to limit documents matching the query, most relevant first.
+// If scopes is not empty, only documents in those scopes are returned. A limit of zero or less
+// returns all matches.
+func (idx *Index) Search(query Query, scopes []string, limit int) []Hit {
+	if query.IsEmpty() {
+		return []Hit{}
+	}
+
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+
+	var scopeSet map[string]bool
+	if len(scopes) > 0 {
+		scopeSet = make(map[string]bool, len(scopes))
+		for _, scope := range scopes {
+			scopeSet[scope] = true
+		}
+	}
+
+	// Start from the documents of the rarest required term to keep the candidate set small
+	var candidates map[string]*Postings
+	for _, phrase := range query.Required {
+		for _, term := range phrase {
+			docPostings := idx.postings[term]
+			if candidates == nil || len(docPostings) < len(candidates) {
+				candidates = docPostings
+			}
+		}
+	}
+
+	hits := make([]Hit, 0)
+	for id := range candidates {
+		info := idx.docs[id]
+		if scopeSet != nil && !inScope(info.scopes, scopeSet) {
+			continue
+		}
+
+		lookup := func(term string) *Postings { return idx.postings[term][id] }
+		score, ok := Score(query, lookup, info.length, idx.corpus())
+		if !ok {
+			continue
+		}
+		hits = append(hits, Hit{ID: id, Score: score, Time: info.time})
+	}
+
+	sort.Slice(hits, func(i, j int) bool {
+		if hits[i].Score != hits[j].Score {
+			return hits[i].Score > hits[j].Score
+		}
+		if hits[i].Time != hits[j].Time {
+			return hits[i].Time > hits[j].Time
+		}
+		return hits[i].ID < hits[j].ID
+	})
+
+	if limit > 0 && len(hits) > limit {
+		hits = hits[:limit]
+	}
+
+	return hits
+}
+
+// corpus returns the statistics of the indexed documents. The caller must hold the read lock.
+func (idx *Index) corpus() Corpus {
+	return Corpus{
+		Docs:              len(idx.docs),
+		TotalLength:       idx.totalLength,
+		DocumentFrequency: func(term string) int { return len(idx.postings[term]) },
+	}
+}
+
+// Analyze tokenizes the fields of a document into the postings of each of its terms. It also
+// returns the document's weighted number of terms, for length normalization.
+func Analyze(title, body string) (map[string]*Postings, float64) {
+	postings := make(map[string]*Postings)
+	length := 0.0
+
+	for f, text := range [fieldCount]string{fieldTitle: title, fieldBody: body} {
+		tokens := Tokenize(text)
+		length += fieldWeight(field(f)) * float64(len(tokens))
+
+		for _, token := range tokens {
+			p, ok := postings[token.Term]
+			if !ok {
+				p = &Postings{}
+				postings[token.Term] = p
+			}
+			if field(f) == fieldTitle {
+				p.Title = append(p.Title, token.Position)
+			} else {
+				p.Body = append(p.Body, token.Position)
+			}
+		}
+	}
+
+	return postings, length
+}
+
+// Score computes the BM25 score of a document for the query, with title occurrences weighted
+// higher than body occurrences. lookup returns the document's postings of a term, or nil if the
+// term does not occur in it. It reports false if the document does not match.
+func Score(query Query, lookup func(term string) *Postings, length float64, corpus Corpus) (float64, bool) {
+	for _, phrase := range query.Excluded {
+		if phraseFrequency(lookup, phrase) > 0 {
+			return 0, false
+		}
+	}
+
+	avgLength := 1.0
+	if corpus.Docs > 0 && corpus.TotalLength > 0 {
+		avgLength = corpus.TotalLength / float64(corpus.Docs)
+	}
+	norm := bm25K1 * (1 - bm25B + bm25B*length/avgLength)
+
+	score := 0.0
+	for _, phrase := range query.Required {
+		frequency := phraseFrequency(lookup, phrase)
+		if frequency == 0 {
+			return 0, false
+		}
+
+		// A phrase is as rare as its rarest term
+		documentFrequency := corpus.Docs
+		for _, term := range phrase {
+			if n := corpus.DocumentFrequency(term); n < documentFrequency {
+				documentFrequency = n
+			}
+		}
+
+		idf := math.Log(1 + (float64(corpus.Docs)-float64(documentFrequency)+0.5)/(float64(documentFrequency)+0.5))
+		score += idf * frequency * (bm25K1 + 1) / (frequency + norm) * float64(len(phrase))
+	}
+
+	return score, true
+}
+
+// phraseFrequency returns the weighted number of times the terms of a phrase occur
+// consecutively in a document, summed over its fields
+func phraseFrequency(lookup func(term string) *Postings, phrase []string) float64 {
+	postings := make([]*Postings, len(phrase))
+	for i, term := range phrase {
+		p := lookup(term)
+		if p == nil {
+			return 0
+		}
+		postings[i] = p
+	}
+
+	frequency := 0.0
+	for f := field(0); f < fieldCount; f++ {
+		count := 0
+		for _, start := range postings[0].positions(f) {
+			matched := true
+			for i := 1; i < len(postings) && matched; i++ {
+				matched = containsPosition(postings[i].positions(f), start+i)
+			}
+			if matched {
+				count++
+			}
+		}
+		frequency += fieldWeight(f) * float64(count)
+	}
+
+	return frequency
+}
+
+// fieldWeight returns how much an occurrence in a field counts
+func fieldWeight(f field) float64 {
+	if f == fieldTitle {
+		return titleWeight
+	}
+	return 1
+}
+
+// containsPosition reports whether sorted positions contain position
+func containsPosition(positions []int, position int) bool {
+	i := sort.SearchInts(positions, position)
+	return i < len(positions) && positions[i] == position
+}
+
+// inScope reports whether any of a document's scopes is in the scope set
+func inScope(scopes []string, scopeSet map[string]bool) bool {
+	for _, scope := range scopes {
+		if scopeSet[scope] {
+			return true
+		}
+	}
+	return false
+}
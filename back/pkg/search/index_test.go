@@ -0,0 +1,151 @@
+package search
+
+import (
+	"reflect"
+	"testing"
+)
+
+func TestTokenize(t *testing.T) {
+	tests := []struct {
+		name string
+		text string
+		want []string
+	}{
+		{"english words are lowercased", "Hello, Go World!", []string{"hello", "go", "world"}},
+		{"japanese is split into bigrams", "機械学習", []string{"機械", "械学", "学習"}},
+		{"single kanji is kept", "株 価", []string{"株", "価"}},
+		{"mixed scripts", "Go言語で開発", []string{"go", "言語", "語で", "で開", "開発"}},
+		{"katakana with long vowel mark", "サーバー", []string{"サー", "ーバ", "バー"}},
+		{"full-width ascii is folded", "ＧＯ１２３", []string{"go123"}},
+		{"half-width katakana is folded", "ﾃｽﾄ", []string{"テス", "スト"}},
+		{"empty", "  ...  ", []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := Terms(tt.text)
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Terms(%q) = %v, want %v", tt.text, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestParseQuery(t *testing.T) {
+	query := ParseQuery(`golang "error handling" -beginner -"hello world" 機械学習`)
+
+	wantRequired := [][]string{{"golang"}, {"error", "handling"}, {"機械", "械学", "学習"}}
+	wantExcluded := [][]string{{"beginner"}, {"hello", "world"}}
+
+	if !reflect.DeepEqual(query.Required, wantRequired) {
+		t.Errorf("Required = %v, want %v", query.Required, wantRequired)
+	}
+	if !reflect.DeepEqual(query.Excluded, wantExcluded) {
+		t.Errorf("Excluded = %v, want %v", query.Excluded, wantExcluded)
+	}
+
+	if !ParseQuery(" -only ").IsEmpty() {
+		t.Error("Expected a query without required terms to be empty")
+	}
+}
+
+func newTestIndex() *Index {
+	idx := NewIndex()
+	idx.Add(
+		Document{ID: "go-title", Scopes: []string{"feed-1"}, Title: "Error handling in Go", Body: "Wrapping errors with fmt.Errorf.", Time: 1},
+		Document{ID: "go-body", Scopes: []string{"feed-1"}, Title: "Weekly notes", Body: "Some thoughts about Go and error handling in general.", Time: 2},
+		Document{ID: "rust", Scopes: []string{"feed-2"}, Title: "Rust error handling", Body: "The question mark operator.", Time: 3},
+		Document{ID: "ml", Scopes: []string{"feed-2"}, Title: "機械学習入門", Body: "深層学習と機械学習の違いを解説します。", Time: 4},
+		Document{ID: "machine", Scopes: []string{"feed-2", "feed-3"}, Title: "機械の整備", Body: "学習用の教材です。", Time: 5},
+	)
+	return idx
+}
+
+func hitIDs(hits []Hit) []string {
+	ids := make([]string, len(hits))
+	for i, hit := range hits {
+		ids[i] = hit.ID
+	}
+	return ids
+}
+
+func TestIndex_Search(t *testing.T) {
+	idx := newTestIndex()
+
+	tests := []struct {
+		name   string
+		query  string
+		scopes []string
+		want   []string
+	}{
+		{"title match ranks first", "go error", nil, []string{"go-title", "go-body"}},
+		{"case insensitive", "RUST", nil, []string{"rust"}},
+		{"phrase requires adjacency", `"error handling"`, []string{"feed-1"}, []string{"go-title", "go-body"}},
+		{"phrase does not match scattered words", `"handling error"`, nil, []string{}},
+		{"exclusion", "error -rust", nil, []string{"go-title", "go-body"}},
+		{"scope restriction", "error", []string{"feed-2"}, []string{"rust"}},
+		{"document in several scopes", "整備", []string{"feed-3"}, []string{"machine"}},
+		{"japanese word is matched as a phrase", "機械学習", nil, []string{"ml"}},
+		{"japanese substring", "学習", nil, []string{"ml", "machine"}},
+		{"no match", "python", nil, []string{}},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := hitIDs(idx.Search(ParseQuery(tt.query), tt.scopes, 10))
+			if !reflect.DeepEqual(got, tt.want) {
+				t.Errorf("Search(%q) = %v, want %v", tt.query, got, tt.want)
+			}
+		})
+	}
+}
+
+func TestIndex_AddReplacesAndRemove(t *testing.T) {
+	idx := newTestIndex()
+
+	idx.Add(Document{ID: "rust", Scopes: []string{"feed-2"}, Title: "Rust async runtimes", Body: "Tokio and friends."})
+	if got := hitIDs(idx.Search(ParseQuery("rust error"), nil, 10)); len(got) != 0 {
+		t.Errorf("Expected replaced document to no longer match old text, got %v", got)
+	}
+	if got := hitIDs(idx.Search(ParseQuery("tokio"), nil, 10)); !reflect.DeepEqual(got, []string{"rust"}) {
+		t.Errorf("Expected replaced document to match new text, got %v", got)
+	}
+
+	idx.Remove("go-title", "unknown")
+	if idx.Len() != 4 {
+		t.Errorf("Expected 4 documents after removal, got %d", idx.Len())
+	}
+	if got := hitIDs(idx.Search(ParseQuery("error"), nil, 10)); !reflect.DeepEqual(got, []string{"go-body"}) {
+		t.Errorf("Expected removed document to no longer match, got %v", got)
+	}
+
+	if got := idx.Search(ParseQuery("error handling"), nil, 1); len(got) != 1 {
+		t.Errorf("Expected limit to apply, got %d hits", len(got))
+	}
+}
+
+func TestAnalyzeAndScore(t *testing.T) {
+	postings, length := Analyze("Error handling", "Go error values")
+	if got := postings["error"]; got == nil || !reflect.DeepEqual(got.Title, []int{0}) || !reflect.DeepEqual(got.Body, []int{1}) {
+		t.Fatalf("Analyze() postings of %q = %+v", "error", got)
+	}
+	if length <= 5 {
+		t.Errorf("Expected title terms to weigh more than body terms, got length %v", length)
+	}
+
+	lookup := func(term string) *Postings { return postings[term] }
+	corpus := Corpus{Docs: 10, TotalLength: 10 * length, DocumentFrequency: func(string) int { return 1 }}
+
+	if _, ok := Score(ParseQuery(`"error handling"`), lookup, length, corpus); !ok {
+		t.Error("Expected phrase to match")
+	}
+	if _, ok := Score(ParseQuery("error -values"), lookup, length, corpus); ok {
+		t.Error("Expected excluded term to reject the document")
+	}
+
+	titleScore, _ := Score(ParseQuery("handling"), lookup, length, corpus)
+	bodyScore, _ := Score(ParseQuery("values"), lookup, length, corpus)
+	if titleScore <= bodyScore {
+		t.Errorf("Expected a title match to score higher than a body match, got %v <= %v", titleScore, bodyScore)
+	}
+}
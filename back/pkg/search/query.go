@@ -0,0 +1,82 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Query is a parsed search query. A document matches if it contains every required phrase
+// and none of the excluded ones. A phrase is a sequence of terms that must appear consecutively
+// in the same field.
+type Query struct {
+	Required [][]string
+	Excluded [][]string
+}
+
+// ParseQuery parses a query string. Words are separated by spaces and all of them must match.
+// Double quotes or 「」 group words into a phrase, and a leading "-" excludes a word or phrase.
+// A word that tokenizes into several terms, such as Japanese text, is matched as a phrase.
+func ParseQuery(text string) Query {
+	var query Query
+
+	for _, part := range splitQuery(text) {
+		excluded := false
+		if strings.HasPrefix(part, "-") && len(part) > 1 {
+			excluded = true
+			part = part[1:]
+		}
+
+		terms := Terms(part)
+		if len(terms) == 0 {
+			continue
+		}
+
+		if excluded {
+			query.Excluded = append(query.Excluded, terms)
+		} else {
+			query.Required = append(query.Required, terms)
+		}
+	}
+
+	return query
+}
+
+// IsEmpty reports whether the query has nothing to match
+func (q Query) IsEmpty() bool {
+	return len(q.Required) == 0
+}
+
+// splitQuery splits a query into words and quoted phrases. A "-" directly before a quote
+// stays attached to the phrase.
+func splitQuery(text string) []string {
+	var parts []string
+	var current strings.Builder
+	quoted := false
+
+	for _, r := range text {
+		switch {
+		case isQuote(r):
+			if quoted || current.Len() == 0 || current.String() == "-" {
+				quoted = !quoted
+				if !quoted {
+					parts = append(parts, current.String())
+					current.Reset()
+				}
+				continue
+			}
+			current.WriteRune(r)
+		case !quoted && (unicode.IsSpace(r) || r == '　'):
+			if current.Len() > 0 {
+				parts = append(parts, current.String())
+				current.Reset()
+			}
+		default:
+			current.WriteRune(r)
+		}
+	}
+	if current.Len() > 0 {
+		parts = append(parts, current.String())
+	}
+
+	return parts
+}
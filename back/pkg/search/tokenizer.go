@@ -0,0 +1,119 @@
+package search
+
+import (
+	"strings"
+	"unicode"
+)
+
+// Token is a normalized term and its position in the tokenized text
+type Token struct {
+	Term     string
+	Position int
+}
+
+// runeClass groups runes that are tokenized the same way
+type runeClass int
+
+const (
+	classSeparator runeClass = iota
+	classWord                // Letters and digits of space-separated scripts, tokenized as words
+	classCJK                 // Kanji, kana and hangul, tokenized as overlapping bigrams
+)
+
+// Tokenize splits text into normalized tokens.
+// Words of space-separated scripts become one token each. Runs of Japanese, Chinese or Korean
+// characters, which have no spaces between words, become overlapping bigrams
+// ("機械学習" becomes "機械", "械学", "学習"), so any substring of two or more characters can be
+// found as a phrase of consecutive bigrams without a dictionary.
+func Tokenize(text string) []Token {
+	var tokens []Token
+	var run []rune
+	runClass := classSeparator
+
+	flush := func() {
+		switch runClass {
+		case classWord:
+			tokens = append(tokens, Token{Term: string(run), Position: len(tokens)})
+		case classCJK:
+			if len(run) == 1 {
+				tokens = append(tokens, Token{Term: string(run), Position: len(tokens)})
+				break
+			}
+			for i := 0; i+1 < len(run); i++ {
+				tokens = append(tokens, Token{Term: string(run[i : i+2]), Position: len(tokens)})
+			}
+		}
+		run = run[:0]
+	}
+
+	for _, r := range text {
+		r = normalizeRune(r)
+		class := classify(r)
+		if class != runClass {
+			flush()
+			runClass = class
+		}
+		if class != classSeparator {
+			run = append(run, r)
+		}
+	}
+	flush()
+
+	return tokens
+}
+
+// Terms returns the terms of the tokenized text in order
+func Terms(text string) []string {
+	tokens := Tokenize(text)
+	terms := make([]string, len(tokens))
+	for i, token := range tokens {
+		terms[i] = token.Term
+	}
+	return terms
+}
+
+// normalizeRune folds case, full-width ASCII and half-width katakana so that
+// differently typed forms of the same text produce the same tokens
+func normalizeRune(r rune) rune {
+	// Full-width ASCII variants (Ａ, １, ！) map to ASCII
+	if r >= 0xFF01 && r <= 0xFF5E {
+		r -= 0xFEE0
+	}
+	if r == '　' {
+		return ' '
+	}
+	if k, ok := halfWidthKatakana[r]; ok {
+		r = k
+	}
+	return unicode.ToLower(r)
+}
+
+// classify returns how a normalized rune is tokenized
+func classify(r rune) runeClass {
+	switch {
+	case unicode.In(r, unicode.Han, unicode.Hiragana, unicode.Katakana, unicode.Hangul),
+		r == 'ー', r == '々', r == '〆':
+		return classCJK
+	case unicode.IsLetter(r), unicode.IsDigit(r), unicode.Is(unicode.Mn, r):
+		return classWord
+	default:
+		return classSeparator
+	}
+}
+
+// halfWidthKatakana maps half-width katakana to their full-width forms.
+// Voiced sound marks are kept as separate runes, which only affects the rare half-width voiced forms.
+var halfWidthKatakana = func() map[rune]rune {
+	half := []rune("ｦｧｨｩｪｫｬｭｮｯｰｱｲｳｴｵｶｷｸｹｺｻｼｽｾｿﾀﾁﾂﾃﾄﾅﾆﾇﾈﾉﾊﾋﾌﾍﾎﾏﾐﾑﾒﾓﾔﾕﾖﾗﾘﾙﾚﾛﾜﾝ")
+	full := []rune("ヲァィゥェォャュョッーアイウエオカキクケコサシスセソタチツテトナニヌネノハヒフヘホマミムメモヤユヨラリルレロワン")
+	m := make(map[rune]rune, len(half))
+	for i, r := range half {
+		m[r] = full[i]
+	}
+	return m
+}()
+
+// isQuote reports whether r starts or ends a quoted phrase in a query
+func isQuote(r rune) bool {
+	return strings.ContainsRune("\"“”「」", r)
+}
@@ -166,7 +166,7 @@ export default function BowerEditModal({
 
   const handleRemoveFeed = async (feedId: string) => {
     try {
-      await feedApi.deleteFeed(feedId);
+      await feedApi.deleteFeed(feedId, bower?.id);
       setFeeds((prev) => prev.filter((f) => f.feed_id !== feedId));
     } catch (error) {
       console.error("Failed to remove feed:", error);
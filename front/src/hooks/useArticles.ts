@@ -1,13 +1,13 @@
 'use client'
 
-import { useState, useEffect, useCallback } from 'react'
+import { useState, useEffect, useCallback, useRef } from 'react'
 import { articleApi, chickApi, ApiError } from '@/lib/api'
 import { Article } from '@/types'
 
 interface UseArticlesParams {
   bowerId?: string
   search?: string
-  tab?: 'all' | 'important' | 'liked'
+  tab?: 'all' | 'important' | 'liked' | 'podcasts'
 }
 
 interface UseArticlesReturn {
@@ -31,6 +31,8 @@ export function useArticles({ bowerId, search, tab = 'all' }: UseArticlesParams
   const [hasMore, setHasMore] = useState(false)
   const [total, setTotal] = useState(0)
   const [offset, setOffset] = useState(0)
+  // Cursor of the next page of the article list. A ref, since loadArticles is not recreated when it changes
+  const nextCursor = useRef<string | undefined>(undefined)
   const [searchQuery, setSearchQuery] = useState(search || '')
 
   const LIMIT = 50
@@ -45,14 +47,26 @@ export function useArticles({ bowerId, search, tab = 'all' }: UseArticlesParams
     return {
       id: apiArticle.article_id || apiArticle.id,
       feedId: apiArticle.feed_id || apiArticle.feedId,
+      sourceFeedIds: apiArticle.source_feed_ids,
+      author: apiArticle.author,
       title: apiArticle.title,
       content: apiArticle.content,
+      contentHtml: apiArticle.content_html,
       url: apiArticle.url,
       publishedAt: new Date(publishedAtMs),
       liked: apiArticle.liked || false,
       bower: apiArticle.bower || 'Unknown',
       read: apiArticle.read || false,
-      image: apiArticle.image_url || apiArticle.image
+      image: apiArticle.image_url || apiArticle.image,
+      media: apiArticle.media,
+      episode: apiArticle.episode,
+      playback: apiArticle.playback && {
+        position: apiArticle.playback.position,
+        duration: apiArticle.playback.duration,
+        completed: apiArticle.playback.completed || false,
+        updatedAt: apiArticle.playback.updated_at ? new Date(apiArticle.playback.updated_at * 1000) : undefined
+      },
+      score: apiArticle.score
     };
   }
 
@@ -64,23 +78,23 @@ export function useArticles({ bowerId, search, tab = 'all' }: UseArticlesParams
       } else {
         setLoading(true)
         setOffset(0)
+        nextCursor.current = undefined
       }
       setError(null)
 
       let data
       const currentOffset = isLoadMore ? offset : 0
+      const cursor = isLoadMore ? nextCursor.current : undefined
 
       if (tab === 'liked') {
         // Load liked articles
         data = await articleApi.getLikedArticles(LIMIT, currentOffset)
-      } else if (tab === 'important') {
-        // Load important articles (same as regular for now, but could be filtered)
+      } else if (tab === 'important' || tab === 'podcasts') {
+        // Load important articles ranked by score, or podcast episodes, on the server
         const params: any = {
           limit: LIMIT,
-          offset: currentOffset,
-          sort: 'published_at',
-          order: 'desc' as const,
-          important: true // Add flag for important articles
+          cursor,
+          tab
         }
 
         if (bowerId && bowerId !== 'all') {
@@ -96,7 +110,7 @@ export function useArticles({ bowerId, search, tab = 'all' }: UseArticlesParams
         // Load regular articles
         const params: any = {
           limit: LIMIT,
-          offset: currentOffset,
+          cursor,
           sort: 'published_at',
           order: 'desc' as const
         }
@@ -132,7 +146,13 @@ export function useArticles({ bowerId, search, tab = 'all' }: UseArticlesParams
           setOffset(LIMIT)
         }
         
-        setHasMore(data.has_more || false)
+        if (tab === 'liked') {
+          setHasMore(data.has_more || false)
+        } else {
+          // Article list pages continue from the cursor the previous page returned
+          nextCursor.current = 'next_cursor' in data ? data.next_cursor : undefined
+          setHasMore(!!nextCursor.current)
+        }
         setTotal(data.total || 0)
       } else {
         if (!isLoadMore) {
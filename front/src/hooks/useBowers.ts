@@ -2,7 +2,7 @@
 
 import { useState, useEffect } from 'react'
 import { bowerApi, ApiError } from '@/lib/api'
-import { Bower } from '@/types'
+import { Bower, FilterRules } from '@/types'
 
 interface CreateBowerResult {
   bower: Bower
@@ -20,8 +20,9 @@ interface UseBowersReturn {
     is_public?: boolean
     auto_register_feeds?: boolean
     max_auto_feeds?: number
+    filter_rules?: FilterRules
   }) => Promise<CreateBowerResult | null>
-  updateBower: (id: string, bower: { name?: string; keywords?: string[]; is_public?: boolean }) => Promise<Bower | null>
+  updateBower: (id: string, bower: { name?: string; keywords?: string[]; is_public?: boolean; filter_rules?: FilterRules }) => Promise<Bower | null>
   deleteBower: (id: string) => Promise<boolean>
   refreshBowers: () => Promise<void>
 }
@@ -56,6 +57,7 @@ export function useBowers(): UseBowersReturn {
         creatorName: bower.creatorName,
         likes: bower.likes || 0,
         likedBy: bower.likedBy || [],
+        filterRules: bower.filter_rules,
         eggColors: bower.eggColors || bower.keywords?.map((_: string, i: number) => {
           const colors = ['#14b8a6', '#4ECDC4', '#45B7D1', '#96CEB4', '#DDA0DD', '#98D8C8', '#F4A460']
           return colors[i % colors.length]
@@ -85,6 +87,7 @@ export function useBowers(): UseBowersReturn {
     is_public?: boolean
     auto_register_feeds?: boolean
     max_auto_feeds?: number
+    filter_rules?: FilterRules
   }): Promise<CreateBowerResult | null> => {
     try {
       setError(null)
@@ -110,6 +113,7 @@ export function useBowers(): UseBowersReturn {
         creatorName: bowerInfo.creatorName,
         likes: bowerInfo.likes || 0,
         likedBy: bowerInfo.likedBy || [],
+        filterRules: bowerInfo.filter_rules,
         eggColors: bowerInfo.eggColors || bowerInfo.keywords?.map((_: string, i: number) => {
           const colors = ['#14b8a6', '#4ECDC4', '#45B7D1', '#96CEB4', '#DDA0DD', '#98D8C8', '#F4A460']
           return colors[i % colors.length]
@@ -135,7 +139,7 @@ export function useBowers(): UseBowersReturn {
   }
 
   // Update a bower
-  const updateBower = async (id: string, bowerData: { name?: string; keywords?: string[]; is_public?: boolean }): Promise<Bower | null> => {
+  const updateBower = async (id: string, bowerData: { name?: string; keywords?: string[]; is_public?: boolean; filter_rules?: FilterRules }): Promise<Bower | null> => {
     try {
       setError(null)
       const data = await bowerApi.updateBower(id, bowerData)
@@ -153,6 +157,7 @@ export function useBowers(): UseBowersReturn {
         creatorName: data.creatorName,
         likes: data.likes || 0,
         likedBy: data.likedBy || [],
+        filterRules: data.filter_rules,
         eggColors: data.eggColors || data.keywords?.map((_: string, i: number) => {
           const colors = ['#14b8a6', '#4ECDC4', '#45B7D1', '#96CEB4', '#DDA0DD', '#98D8C8', '#F4A460']
           return colors[i % colors.length]
@@ -1,5 +1,7 @@
 // API utility functions for Feed Bower application
 
+import { FilterRules } from '@/types'
+
 const API_BASE_URL = process.env.NEXT_PUBLIC_API_URL || 'http://localhost:8080/api'
 
 // API response types
@@ -253,7 +255,7 @@ function getMockData(endpoint: string, method: string): any {
     
     // Check if this is for important articles (recent articles from last 24 hours)
     const urlParams = new URLSearchParams(endpoint.split('?')[1] || '')
-    const isImportant = urlParams.get('important') === 'true'
+    const isImportant = urlParams.get('tab') === 'important'
     
     let filteredArticles = mockArticles
     
@@ -420,6 +422,7 @@ export const bowerApi = {
     is_public?: boolean
     auto_register_feeds?: boolean
     max_auto_feeds?: number
+    filter_rules?: FilterRules
   }) {
     return apiRequest<{
       bower: any
@@ -441,6 +444,7 @@ export const bowerApi = {
     name?: string
     keywords?: string[]
     is_public?: boolean
+    filter_rules?: FilterRules
   }) {
     return apiRequest<any>(`/bowers/${id}`, {
       method: 'PUT',
@@ -477,9 +481,23 @@ export const feedApi = {
     })
   },
 
-  // Delete a feed
-  async deleteFeed(id: string) {
-    return apiRequest<void>(`/feeds/${id}`, {
+  // Update a bower's subscription to a feed
+  async updateFeed(id: string, feed: {
+    bower_id?: string
+    title?: string
+    category?: string
+    full_text?: boolean
+  }) {
+    return apiRequest<any>(`/feeds/${id}`, {
+      method: 'PUT',
+      body: JSON.stringify(feed),
+    })
+  },
+
+  // Delete a feed from a bower
+  async deleteFeed(id: string, bowerId?: string) {
+    const query = bowerId ? `?bower_id=${encodeURIComponent(bowerId)}` : ''
+    return apiRequest<void>(`/feeds/${id}${query}`, {
       method: 'DELETE',
     })
   },
@@ -552,16 +570,18 @@ export const articleApi = {
   async getArticles(params: {
     bower_id?: string
     limit?: number
-    offset?: number
+    cursor?: string
     search?: string
     sort?: string
     order?: 'asc' | 'desc'
+    tab?: 'all' | 'important' | 'podcasts'
   } = {}) {
     const queryParams = new URLSearchParams()
     
     if (params.bower_id) queryParams.append('bower_id', params.bower_id)
+    if (params.tab) queryParams.append('tab', params.tab)
     if (params.limit) queryParams.append('limit', params.limit.toString())
-    if (params.offset) queryParams.append('offset', params.offset.toString())
+    if (params.cursor) queryParams.append('cursor', params.cursor)
     if (params.search) queryParams.append('search', params.search)
     if (params.sort) queryParams.append('sort', params.sort)
     if (params.order) queryParams.append('order', params.order)
@@ -571,12 +591,13 @@ export const articleApi = {
       articles: any[]
       total: number
       has_more: boolean
+      next_cursor?: string // Pass as cursor to get the next page
     }>(`/articles${query}`)
   },
 
   // Get a specific article
-  async getArticle(id: string) {
-    return apiRequest<any>(`/articles/${id}`)
+  async getArticle(id: string, full: boolean = false) {
+    return apiRequest<any>(`/articles/${id}${full ? '?full=true' : ''}`)
   },
 
   // Like an article
@@ -608,6 +629,33 @@ export const articleApi = {
       has_more: boolean
     }>(`/articles/liked?limit=${limit}&offset=${offset}`)
   },
+
+  // Get where to resume a podcast episode
+  async getPlaybackPosition(id: string) {
+    return apiRequest<{
+      position: number
+      duration?: number
+      completed: boolean
+      updated_at: number
+    }>(`/articles/${id}/playback`)
+  },
+
+  // Save how far a podcast episode has been played
+  async savePlaybackPosition(id: string, playback: {
+    position: number
+    duration?: number
+    completed?: boolean
+  }) {
+    return apiRequest<{
+      position: number
+      duration?: number
+      completed: boolean
+      updated_at: number
+    }>(`/articles/${id}/playback`, {
+      method: 'PUT',
+      body: JSON.stringify(playback),
+    })
+  },
 }
 
 // Auth API functions
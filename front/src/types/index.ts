@@ -7,19 +7,63 @@ export interface Feed {
   lastUpdated: Date
   isCustom?: boolean // 手動追加されたフィードかどうか
   customLabel?: string // カスタムラベル
+  fullText?: boolean // 記事ページから本文を抽出するかどうか
 }
 
 export interface Article {
   id: string
   feedId: string
+  sourceFeedIds?: string[] // 同じ記事を配信している他のフィード
   title: string
-  content: string
+  content: string // 一覧表示用のプレーンテキスト
+  contentHtml?: string // フィード本文（サニタイズ済みHTML）
   url: string
   publishedAt: Date
   liked: boolean
   bower: string
   read: boolean
   image?: string
+  media?: ArticleMedia[] // フィードの画像・音声・動画
+  episode?: Episode // ポッドキャストのエピソード情報
+  playback?: PlaybackPosition // エピソードの再生位置
+  author?: string
+  fullContent?: string // 記事ページから抽出した本文（サニタイズ済みHTML）
+  score?: ArticleScore // 重要タブでのスコアと内訳
+}
+
+export interface ArticleMedia {
+  url: string
+  type?: string // MIMEタイプ
+  medium: 'image' | 'audio' | 'video' | 'document'
+  size?: number // バイト数
+  width?: number
+  height?: number
+  duration?: number // 秒数（音声・動画）
+}
+
+export interface Episode {
+  duration?: number // 秒数
+  season?: number
+  number?: number
+  type?: 'full' | 'trailer' | 'bonus'
+  explicit?: boolean
+}
+
+export interface PlaybackPosition {
+  position: number // 再生位置（秒）
+  duration?: number // 秒数
+  completed: boolean // 最後まで再生済みかどうか
+  updatedAt?: Date
+}
+
+export interface ArticleScore {
+  total: number
+  factors: {
+    name: 'keywords' | 'likes' | 'feed_affinity' | 'recency'
+    value: number
+    weight: number
+    reason: string
+  }[]
 }
 
 export interface Bower {
@@ -35,6 +79,15 @@ export interface Bower {
   likes?: number
   likedBy?: string[]
   eggColors?: string[]
+  filterRules?: FilterRules // 記事の絞り込みルール
+}
+
+export interface FilterRules {
+  include?: string[] // いずれかに一致する記事のみ表示（/正規表現/ も可）
+  exclude?: string[] // 一致する記事を除外
+  muted_authors?: string[]
+  muted_domains?: string[]
+  apply_at_ingest: boolean // 取得時に除外して保存しない
 }
 
 export interface User {
@@ -43,12 +43,16 @@ locals {
 
   # DynamoDB テーブル名
   table_names = {
-    users          = "${local.project_name}-users-${local.environment}"
-    bowers         = "${local.project_name}-bowers-${local.environment}"
-    feeds          = "${local.project_name}-feeds-${local.environment}"
-    articles       = "${local.project_name}-articles-${local.environment}"
-    liked_articles = "${local.project_name}-liked-articles-${local.environment}"
-    chick_stats    = "${local.project_name}-chick-stats-${local.environment}"
+    users              = "${local.project_name}-users-${local.environment}"
+    bowers             = "${local.project_name}-bowers-${local.environment}"
+    feeds              = "${local.project_name}-feeds-${local.environment}"
+    subscriptions      = "${local.project_name}-subscriptions-${local.environment}"
+    articles           = "${local.project_name}-articles-${local.environment}"
+    liked_articles     = "${local.project_name}-liked-articles-${local.environment}"
+    read_states        = "${local.project_name}-read-states-${local.environment}"
+    playback_positions = "${local.project_name}-playback-positions-${local.environment}"
+    chick_stats        = "${local.project_name}-chick-stats-${local.environment}"
+    search_index       = "${local.project_name}-search-index-${local.environment}"
   }
 }
 
@@ -147,6 +151,10 @@ module "dynamodb_feeds" {
       name = "feed_id"
       type = "S"
     },
+    {
+      name = "url"
+      type = "S"
+    },
     {
       name = "bower_id"
       type = "S"
@@ -155,6 +163,12 @@ module "dynamodb_feeds" {
 
   global_secondary_indexes = [
     {
+      name            = "UrlIndex"
+      hash_key        = "url"
+      projection_type = "ALL"
+    },
+    {
+      # 共有フィード移行前の bower_id 参照用（migrate_feeds ジョブ実行後に削除可能）
       name            = "BowerIdIndex"
       hash_key        = "bower_id"
       projection_type = "ALL"
@@ -168,6 +182,41 @@ module "dynamodb_feeds" {
   tags = local.common_tags
 }
 
+# DynamoDB テーブル: Subscriptions
+module "dynamodb_subscriptions" {
+  source = "../../modules/dynamodb"
+
+  table_name   = local.table_names.subscriptions
+  hash_key     = "bower_id"
+  range_key    = "feed_id"
+  billing_mode = "PAY_PER_REQUEST"
+
+  attributes = [
+    {
+      name = "bower_id"
+      type = "S"
+    },
+    {
+      name = "feed_id"
+      type = "S"
+    }
+  ]
+
+  global_secondary_indexes = [
+    {
+      name            = "FeedIdIndex"
+      hash_key        = "feed_id"
+      projection_type = "ALL"
+    }
+  ]
+
+  ttl_enabled                    = false
+  point_in_time_recovery_enabled = false
+  stream_enabled                 = false
+
+  tags = local.common_tags
+}
+
 # DynamoDB テーブル: Articles
 module "dynamodb_articles" {
   source = "../../modules/dynamodb"
@@ -188,6 +237,14 @@ module "dynamodb_articles" {
     {
       name = "published_at"
       type = "N"
+    },
+    {
+      name = "canonical_url"
+      type = "S"
+    },
+    {
+      name = "title_hash"
+      type = "S"
     }
   ]
 
@@ -197,12 +254,25 @@ module "dynamodb_articles" {
       hash_key        = "feed_id"
       range_key       = "published_at"
       projection_type = "ALL"
+    },
+    {
+      name               = "CanonicalUrlIndex"
+      hash_key           = "canonical_url"
+      projection_type    = "INCLUDE"
+      non_key_attributes = ["feed_id", "source_feed_ids", "published_at", "content_hash"]
+    },
+    {
+      name               = "TitleHashIndex"
+      hash_key           = "title_hash"
+      projection_type    = "INCLUDE"
+      non_key_attributes = ["feed_id", "source_feed_ids", "published_at", "content_hash"]
     }
   ]
 
   ttl_enabled                    = false
   point_in_time_recovery_enabled = false
-  stream_enabled                 = false
+  stream_enabled                 = true
+  stream_view_type               = "KEYS_ONLY" # 変更された記事を検索インデックスに反映
 
   tags = local.common_tags
 }
@@ -227,7 +297,83 @@ module "dynamodb_liked_articles" {
     }
   ]
 
-  global_secondary_indexes = []
+  global_secondary_indexes = [
+    {
+      name            = "ArticleIdIndex"
+      hash_key        = "article_id"
+      projection_type = "KEYS_ONLY"
+    }
+  ]
+
+  ttl_enabled                    = false
+  point_in_time_recovery_enabled = false
+  stream_enabled                 = false
+
+  tags = local.common_tags
+}
+
+# DynamoDB テーブル: ReadStates
+module "dynamodb_read_states" {
+  source = "../../modules/dynamodb"
+
+  table_name   = local.table_names.read_states
+  hash_key     = "user_id"
+  range_key    = "article_id"
+  billing_mode = "PAY_PER_REQUEST"
+
+  attributes = [
+    {
+      name = "user_id"
+      type = "S"
+    },
+    {
+      name = "article_id"
+      type = "S"
+    }
+  ]
+
+  global_secondary_indexes = [
+    {
+      name            = "ArticleIdIndex"
+      hash_key        = "article_id"
+      projection_type = "KEYS_ONLY"
+    }
+  ]
+
+  ttl_enabled                    = false
+  point_in_time_recovery_enabled = false
+  stream_enabled                 = false
+
+  tags = local.common_tags
+}
+
+# DynamoDB テーブル: PlaybackPositions
+module "dynamodb_playback_positions" {
+  source = "../../modules/dynamodb"
+
+  table_name   = local.table_names.playback_positions
+  hash_key     = "user_id"
+  range_key    = "article_id"
+  billing_mode = "PAY_PER_REQUEST"
+
+  attributes = [
+    {
+      name = "user_id"
+      type = "S"
+    },
+    {
+      name = "article_id"
+      type = "S"
+    }
+  ]
+
+  global_secondary_indexes = [
+    {
+      name            = "ArticleIdIndex"
+      hash_key        = "article_id"
+      projection_type = "KEYS_ONLY"
+    }
+  ]
 
   ttl_enabled                    = false
   point_in_time_recovery_enabled = false
@@ -260,6 +406,35 @@ module "dynamodb_chick_stats" {
   tags = local.common_tags
 }
 
+# DynamoDB テーブル: SearchIndex（記事の全文検索インデックス）
+module "dynamodb_search_index" {
+  source = "../../modules/dynamodb"
+
+  table_name   = local.table_names.search_index
+  hash_key     = "term_key"
+  range_key    = "article_id"
+  billing_mode = "PAY_PER_REQUEST"
+
+  attributes = [
+    {
+      name = "term_key"
+      type = "S"
+    },
+    {
+      name = "article_id"
+      type = "S"
+    }
+  ]
+
+  global_secondary_indexes = []
+
+  ttl_enabled                    = false
+  point_in_time_recovery_enabled = false
+  stream_enabled                 = false
+
+  tags = local.common_tags
+}
+
 # Lambda 関数
 module "lambda" {
   source = "../../modules/lambda"
@@ -280,11 +455,18 @@ module "lambda" {
     module.dynamodb_users.table_arn,
     module.dynamodb_bowers.table_arn,
     module.dynamodb_feeds.table_arn,
+    module.dynamodb_subscriptions.table_arn,
     module.dynamodb_articles.table_arn,
     module.dynamodb_liked_articles.table_arn,
+    module.dynamodb_read_states.table_arn,
+    module.dynamodb_playback_positions.table_arn,
     module.dynamodb_chick_stats.table_arn,
+    module.dynamodb_search_index.table_arn,
   ]
 
+  # 記事の変更を検索インデックスに反映
+  dynamodb_stream_arns = [module.dynamodb_articles.stream_arn]
+
   enable_bedrock     = true
   log_retention_days = 7
   create_alias       = false
@@ -296,9 +478,13 @@ module "lambda" {
     module.dynamodb_users,
     module.dynamodb_bowers,
     module.dynamodb_feeds,
+    module.dynamodb_subscriptions,
     module.dynamodb_articles,
     module.dynamodb_liked_articles,
-    module.dynamodb_chick_stats
+    module.dynamodb_read_states,
+    module.dynamodb_playback_positions,
+    module.dynamodb_chick_stats,
+    module.dynamodb_search_index
   ]
 }
 
@@ -41,24 +41,32 @@ output "amplify_branch_urls" {
 output "dynamodb_tables" {
   description = "DynamoDB テーブル名"
   value = {
-    users          = module.dynamodb_users.table_name
-    bowers         = module.dynamodb_bowers.table_name
-    feeds          = module.dynamodb_feeds.table_name
-    articles       = module.dynamodb_articles.table_name
-    liked_articles = module.dynamodb_liked_articles.table_name
-    chick_stats    = module.dynamodb_chick_stats.table_name
+    users              = module.dynamodb_users.table_name
+    bowers             = module.dynamodb_bowers.table_name
+    feeds              = module.dynamodb_feeds.table_name
+    subscriptions      = module.dynamodb_subscriptions.table_name
+    articles           = module.dynamodb_articles.table_name
+    liked_articles     = module.dynamodb_liked_articles.table_name
+    read_states        = module.dynamodb_read_states.table_name
+    playback_positions = module.dynamodb_playback_positions.table_name
+    chick_stats        = module.dynamodb_chick_stats.table_name
+    search_index       = module.dynamodb_search_index.table_name
   }
 }
 
 output "dynamodb_table_arns" {
   description = "DynamoDB テーブル ARN"
   value = {
-    users          = module.dynamodb_users.table_arn
-    bowers         = module.dynamodb_bowers.table_arn
-    feeds          = module.dynamodb_feeds.table_arn
-    articles       = module.dynamodb_articles.table_arn
-    liked_articles = module.dynamodb_liked_articles.table_arn
-    chick_stats    = module.dynamodb_chick_stats.table_arn
+    users              = module.dynamodb_users.table_arn
+    bowers             = module.dynamodb_bowers.table_arn
+    feeds              = module.dynamodb_feeds.table_arn
+    subscriptions      = module.dynamodb_subscriptions.table_arn
+    articles           = module.dynamodb_articles.table_arn
+    liked_articles     = module.dynamodb_liked_articles.table_arn
+    read_states        = module.dynamodb_read_states.table_arn
+    playback_positions = module.dynamodb_playback_positions.table_arn
+    chick_stats        = module.dynamodb_chick_stats.table_arn
+    search_index       = module.dynamodb_search_index.table_arn
   }
 }
 
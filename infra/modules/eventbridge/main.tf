@@ -31,9 +31,9 @@ resource "aws_cloudwatch_event_target" "lambda_target" {
   target_id = "FeedFetchLambda"
   arn       = var.lambda_function_arn
 
-  # Pass scheduler mode flag to Lambda
+  # Run the fetch_feeds job
   input = jsonencode({
-    mode = "scheduler"
+    job = "fetch_feeds"
   })
 }
 
@@ -45,3 +45,44 @@ resource "aws_lambda_permission" "allow_eventbridge" {
   principal     = "events.amazonaws.com"
   source_arn    = aws_cloudwatch_event_rule.feed_fetch_schedule.arn
 }
+
+# EventBridge Rules for scheduled maintenance jobs (cleanup_orphans, purge_old_articles, expire_guests)
+resource "aws_cloudwatch_event_rule" "job_schedule" {
+  for_each = var.jobs
+
+  name                = "${var.project_name}-${replace(each.key, "_", "-")}-schedule-${var.environment}"
+  description         = "Trigger Lambda to run the ${each.key} job"
+  schedule_expression = each.value.schedule_expression
+
+  tags = merge(
+    var.tags,
+    {
+      Name        = "${var.project_name}-${replace(each.key, "_", "-")}-schedule-${var.environment}"
+      Environment = var.environment
+    }
+  )
+}
+
+resource "aws_cloudwatch_event_target" "job_target" {
+  for_each = var.jobs
+
+  rule      = aws_cloudwatch_event_rule.job_schedule[each.key].name
+  target_id = "Job-${replace(each.key, "_", "-")}"
+  arn       = var.lambda_function_arn
+
+  input = jsonencode({
+    job     = each.key
+    dry_run = each.value.dry_run
+    params  = jsondecode(each.value.params)
+  })
+}
+
+resource "aws_lambda_permission" "allow_eventbridge_job" {
+  for_each = var.jobs
+
+  statement_id  = "AllowExecutionFromEventBridge-${replace(each.key, "_", "-")}"
+  action        = "lambda:InvokeFunction"
+  function_name = var.lambda_function_name
+  principal     = "events.amazonaws.com"
+  source_arn    = aws_cloudwatch_event_rule.job_schedule[each.key].arn
+}
@@ -14,3 +14,8 @@ output "rule_id" {
   description = "ID of the EventBridge rule"
   value       = aws_cloudwatch_event_rule.feed_fetch_schedule.id
 }
+
+output "job_rule_names" {
+  description = "Names of the maintenance job EventBridge rules, by job name"
+  value       = { for job, rule in aws_cloudwatch_event_rule.job_schedule : job => rule.name }
+}
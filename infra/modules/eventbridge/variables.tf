@@ -27,6 +27,16 @@ variable "schedule_expression" {
   default     = "rate(1 hour)"
 }
 
+variable "jobs" {
+  description = "Maintenance jobs to schedule, by job name (cleanup_orphans, purge_old_articles, expire_guests), with their params as a JSON object"
+  type = map(object({
+    schedule_expression = string
+    params              = string
+    dry_run             = bool
+  }))
+  default = {}
+}
+
 variable "tags" {
   description = "Additional tags to apply to resources"
   type        = map(string)
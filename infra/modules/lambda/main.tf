@@ -69,6 +69,29 @@ resource "aws_iam_role_policy" "dynamodb_policy" {
   })
 }
 
+# DynamoDB Streams 読み取りポリシー（ストリームがある場合）
+resource "aws_iam_role_policy" "dynamodb_stream_policy" {
+  count = length(var.dynamodb_stream_arns) > 0 ? 1 : 0
+  name  = "${var.function_name}-dynamodb-stream-policy"
+  role  = aws_iam_role.lambda_role.id
+
+  policy = jsonencode({
+    Version = "2012-10-17"
+    Statement = [
+      {
+        Effect = "Allow"
+        Action = [
+          "dynamodb:DescribeStream",
+          "dynamodb:GetRecords",
+          "dynamodb:GetShardIterator",
+          "dynamodb:ListStreams"
+        ]
+        Resource = var.dynamodb_stream_arns
+      }
+    ]
+  })
+}
+
 # Bedrock アクセスポリシー（AI フィード推薦用）
 resource "aws_iam_role_policy" "bedrock_policy" {
   count = var.enable_bedrock ? 1 : 0
@@ -156,6 +179,21 @@ resource "aws_lambda_function" "function" {
   ]
 }
 
+# DynamoDB Streams イベントソース（変更された記事を検索インデックスに反映）
+# 失敗したバッチは分割して再試行し、再試行し尽くしたレコードは reindex_search ジョブで補う
+resource "aws_lambda_event_source_mapping" "dynamodb_stream" {
+  count                              = length(var.dynamodb_stream_arns)
+  event_source_arn                   = var.dynamodb_stream_arns[count.index]
+  function_name                      = aws_lambda_function.function.arn
+  starting_position                  = "LATEST"
+  batch_size                         = var.stream_batch_size
+  maximum_batching_window_in_seconds = var.stream_batching_window_seconds
+  bisect_batch_on_function_error     = true
+  maximum_retry_attempts             = var.stream_maximum_retry_attempts
+
+  depends_on = [aws_iam_role_policy.dynamodb_stream_policy]
+}
+
 # Lambda エイリアス（本番環境用）
 resource "aws_lambda_alias" "production" {
   count            = var.create_alias ? 1 : 0
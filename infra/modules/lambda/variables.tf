@@ -41,6 +41,30 @@ variable "dynamodb_table_arns" {
   default     = []
 }
 
+variable "dynamodb_stream_arns" {
+  description = "Lambda を起動する DynamoDB Streams ARN のリスト"
+  type        = list(string)
+  default     = []
+}
+
+variable "stream_batch_size" {
+  description = "DynamoDB Streams から一度に受け取るレコード数の上限"
+  type        = number
+  default     = 100
+}
+
+variable "stream_batching_window_seconds" {
+  description = "DynamoDB Streams のレコードをまとめて待つ最大秒数"
+  type        = number
+  default     = 10
+}
+
+variable "stream_maximum_retry_attempts" {
+  description = "DynamoDB Streams の失敗したバッチを再試行する回数"
+  type        = number
+  default     = 5
+}
+
 variable "enable_bedrock" {
   description = "Bedrock アクセスを有効化"
   type        = bool
@@ -156,10 +156,10 @@ print_success "Found development bower: $DEV_BOWER_ID"
 
 # Get feeds from the bower
 print_status "Getting feeds from bower..."
-FEED_IDS=$(aws dynamodb scan \
+FEED_IDS=$(aws dynamodb query \
     --endpoint-url "$DYNAMODB_ENDPOINT" \
-    --table-name "${TABLE_PREFIX}Feeds" \
-    --filter-expression "bower_id = :bower_id" \
+    --table-name "${TABLE_PREFIX}Subscriptions" \
+    --key-condition-expression "bower_id = :bower_id" \
     --expression-attribute-values "{\":bower_id\":{\"S\":\"$DEV_BOWER_ID\"}}" \
     --region ap-northeast-1 \
     --query 'Items[].feed_id.S' \
@@ -97,14 +97,23 @@ create_feed() {
         --table-name "${TABLE_PREFIX}Feeds" \
         --item "{
             \"feed_id\": {\"S\": \"$feed_id\"},
-            \"bower_id\": {\"S\": \"$bower_id\"},
             \"url\": {\"S\": \"$url\"},
             \"title\": {\"S\": \"$title\"},
             \"description\": {\"S\": \"$description\"},
             \"category\": {\"S\": \"$category\"},
             \"is_custom\": {\"BOOL\": false},
             \"created_at\": {\"N\": \"$timestamp\"},
-            \"updated_at\": {\"N\": \"$timestamp\"}
+            \"updated_at\": {\"N\": \"$timestamp\"},
+            \"subscriber_count\": {\"N\": \"1\"}
+        }" \
+        --region "$DYNAMODB_REGION" > /dev/null &&
+    aws dynamodb put-item \
+        --endpoint-url "$DYNAMODB_ENDPOINT" \
+        --table-name "${TABLE_PREFIX}Subscriptions" \
+        --item "{
+            \"bower_id\": {\"S\": \"$bower_id\"},
+            \"feed_id\": {\"S\": \"$feed_id\"},
+            \"created_at\": {\"N\": \"$timestamp\"}
         }" \
         --region "$DYNAMODB_REGION" > /dev/null
 
@@ -59,38 +59,60 @@ aws dynamodb create-table \
     --endpoint-url $ENDPOINT \
     --region $REGION >/dev/null 2>&1
 
-# 3. Feeds テーブル作成（BowerIdIndex GSI付き）
+# 3. Feeds テーブル作成（UrlIndex, BowerIdIndex GSI付き）
 aws dynamodb create-table \
     --table-name "Feeds${TABLE_SUFFIX}" \
     --attribute-definitions \
         AttributeName=feed_id,AttributeType=S \
+        AttributeName=url,AttributeType=S \
         AttributeName=bower_id,AttributeType=S \
     --key-schema \
         AttributeName=feed_id,KeyType=HASH \
     --global-secondary-indexes \
+        IndexName=UrlIndex,KeySchema='[{AttributeName=url,KeyType=HASH}]',Projection='{ProjectionType=ALL}',ProvisionedThroughput='{ReadCapacityUnits=5,WriteCapacityUnits=5}' \
         IndexName=BowerIdIndex,KeySchema='[{AttributeName=bower_id,KeyType=HASH}]',Projection='{ProjectionType=ALL}',ProvisionedThroughput='{ReadCapacityUnits=5,WriteCapacityUnits=5}' \
     --provisioned-throughput \
         ReadCapacityUnits=5,WriteCapacityUnits=5 \
     --endpoint-url $ENDPOINT \
     --region $REGION >/dev/null 2>&1
 
-# 4. Articles テーブル作成（FeedIdPublishedAtIndex GSI付き）
+# 4. Subscriptions テーブル作成（複合キー、FeedIdIndex GSI付き）
+aws dynamodb create-table \
+    --table-name "Subscriptions${TABLE_SUFFIX}" \
+    --attribute-definitions \
+        AttributeName=bower_id,AttributeType=S \
+        AttributeName=feed_id,AttributeType=S \
+    --key-schema \
+        AttributeName=bower_id,KeyType=HASH \
+        AttributeName=feed_id,KeyType=RANGE \
+    --global-secondary-indexes \
+        IndexName=FeedIdIndex,KeySchema='[{AttributeName=feed_id,KeyType=HASH}]',Projection='{ProjectionType=ALL}',ProvisionedThroughput='{ReadCapacityUnits=5,WriteCapacityUnits=5}' \
+    --provisioned-throughput \
+        ReadCapacityUnits=5,WriteCapacityUnits=5 \
+    --endpoint-url $ENDPOINT \
+    --region $REGION >/dev/null 2>&1
+
+# 5. Articles テーブル作成（FeedIdPublishedAtIndex, CanonicalUrlIndex, TitleHashIndex GSI付き）
 aws dynamodb create-table \
     --table-name "Articles${TABLE_SUFFIX}" \
     --attribute-definitions \
         AttributeName=article_id,AttributeType=S \
         AttributeName=feed_id,AttributeType=S \
         AttributeName=published_at,AttributeType=N \
+        AttributeName=canonical_url,AttributeType=S \
+        AttributeName=title_hash,AttributeType=S \
     --key-schema \
         AttributeName=article_id,KeyType=HASH \
     --global-secondary-indexes \
         IndexName=FeedIdPublishedAtIndex,KeySchema='[{AttributeName=feed_id,KeyType=HASH},{AttributeName=published_at,KeyType=RANGE}]',Projection='{ProjectionType=ALL}',ProvisionedThroughput='{ReadCapacityUnits=5,WriteCapacityUnits=5}' \
+        IndexName=CanonicalUrlIndex,KeySchema='[{AttributeName=canonical_url,KeyType=HASH}]',Projection='{ProjectionType=INCLUDE,NonKeyAttributes=[feed_id,source_feed_ids,published_at,content_hash]}',ProvisionedThroughput='{ReadCapacityUnits=5,WriteCapacityUnits=5}' \
+        IndexName=TitleHashIndex,KeySchema='[{AttributeName=title_hash,KeyType=HASH}]',Projection='{ProjectionType=INCLUDE,NonKeyAttributes=[feed_id,source_feed_ids,published_at,content_hash]}',ProvisionedThroughput='{ReadCapacityUnits=5,WriteCapacityUnits=5}' \
     --provisioned-throughput \
         ReadCapacityUnits=5,WriteCapacityUnits=5 \
     --endpoint-url $ENDPOINT \
     --region $REGION >/dev/null 2>&1
 
-# 5. LikedArticles テーブル作成（複合キー）
+# 6. LikedArticles テーブル作成（複合キー）
 aws dynamodb create-table \
     --table-name "LikedArticles${TABLE_SUFFIX}" \
     --attribute-definitions \
@@ -99,12 +121,46 @@ aws dynamodb create-table \
     --key-schema \
         AttributeName=user_id,KeyType=HASH \
         AttributeName=article_id,KeyType=RANGE \
+    --global-secondary-indexes \
+        IndexName=ArticleIdIndex,KeySchema='[{AttributeName=article_id,KeyType=HASH}]',Projection='{ProjectionType=KEYS_ONLY}',ProvisionedThroughput='{ReadCapacityUnits=5,WriteCapacityUnits=5}' \
     --provisioned-throughput \
         ReadCapacityUnits=5,WriteCapacityUnits=5 \
     --endpoint-url $ENDPOINT \
     --region $REGION >/dev/null 2>&1
 
-# 6. ChickStats テーブル作成（シンプルなハッシュキー）
+# 7. ReadStates テーブル作成（複合キー）
+aws dynamodb create-table \
+    --table-name "ReadStates${TABLE_SUFFIX}" \
+    --attribute-definitions \
+        AttributeName=user_id,AttributeType=S \
+        AttributeName=article_id,AttributeType=S \
+    --key-schema \
+        AttributeName=user_id,KeyType=HASH \
+        AttributeName=article_id,KeyType=RANGE \
+    --global-secondary-indexes \
+        IndexName=ArticleIdIndex,KeySchema='[{AttributeName=article_id,KeyType=HASH}]',Projection='{ProjectionType=KEYS_ONLY}',ProvisionedThroughput='{ReadCapacityUnits=5,WriteCapacityUnits=5}' \
+    --provisioned-throughput \
+        ReadCapacityUnits=5,WriteCapacityUnits=5 \
+    --endpoint-url $ENDPOINT \
+    --region $REGION >/dev/null 2>&1
+
+# 8. PlaybackPositions テーブル作成（複合キー）
+aws dynamodb create-table \
+    --table-name "PlaybackPositions${TABLE_SUFFIX}" \
+    --attribute-definitions \
+        AttributeName=user_id,AttributeType=S \
+        AttributeName=article_id,AttributeType=S \
+    --key-schema \
+        AttributeName=user_id,KeyType=HASH \
+        AttributeName=article_id,KeyType=RANGE \
+    --global-secondary-indexes \
+        IndexName=ArticleIdIndex,KeySchema='[{AttributeName=article_id,KeyType=HASH}]',Projection='{ProjectionType=KEYS_ONLY}',ProvisionedThroughput='{ReadCapacityUnits=5,WriteCapacityUnits=5}' \
+    --provisioned-throughput \
+        ReadCapacityUnits=5,WriteCapacityUnits=5 \
+    --endpoint-url $ENDPOINT \
+    --region $REGION >/dev/null 2>&1
+
+# 9. ChickStats テーブル作成（シンプルなハッシュキー）
 aws dynamodb create-table \
     --table-name "ChickStats${TABLE_SUFFIX}" \
     --attribute-definitions \
@@ -116,6 +172,20 @@ aws dynamodb create-table \
     --endpoint-url $ENDPOINT \
     --region $REGION >/dev/null 2>&1
 
+# 10. SearchIndex テーブル作成（記事の全文検索インデックス、複合キー）
+aws dynamodb create-table \
+    --table-name "SearchIndex${TABLE_SUFFIX}" \
+    --attribute-definitions \
+        AttributeName=term_key,AttributeType=S \
+        AttributeName=article_id,AttributeType=S \
+    --key-schema \
+        AttributeName=term_key,KeyType=HASH \
+        AttributeName=article_id,KeyType=RANGE \
+    --provisioned-throughput \
+        ReadCapacityUnits=5,WriteCapacityUnits=5 \
+    --endpoint-url $ENDPOINT \
+    --region $REGION >/dev/null 2>&1
+
 # テーブル作成の完了を待つ
 sleep 3
 
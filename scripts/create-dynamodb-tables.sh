@@ -115,16 +115,18 @@ aws dynamodb create-table \
     --region $REGION >/dev/null
 echo "✅ Bowers${TABLE_SUFFIX} テーブルを作成しました"
 
-# 3. Feeds テーブル作成（BowerIdIndex GSI付き）
+# 3. Feeds テーブル作成（UrlIndex, BowerIdIndex GSI付き）
 echo "📝 Feeds${TABLE_SUFFIX} テーブル作成中..."
 aws dynamodb create-table \
     --table-name "Feeds${TABLE_SUFFIX}" \
     --attribute-definitions \
         AttributeName=feed_id,AttributeType=S \
+        AttributeName=url,AttributeType=S \
         AttributeName=bower_id,AttributeType=S \
     --key-schema \
         AttributeName=feed_id,KeyType=HASH \
     --global-secondary-indexes \
+        IndexName=UrlIndex,KeySchema='[{AttributeName=url,KeyType=HASH}]',Projection='{ProjectionType=ALL}',ProvisionedThroughput='{ReadCapacityUnits=5,WriteCapacityUnits=5}' \
         IndexName=BowerIdIndex,KeySchema='[{AttributeName=bower_id,KeyType=HASH}]',Projection='{ProjectionType=ALL}',ProvisionedThroughput='{ReadCapacityUnits=5,WriteCapacityUnits=5}' \
     --provisioned-throughput \
         ReadCapacityUnits=5,WriteCapacityUnits=5 \
@@ -132,7 +134,25 @@ aws dynamodb create-table \
     --region $REGION >/dev/null
 echo "✅ Feeds${TABLE_SUFFIX} テーブルを作成しました"
 
-# 4. Articles テーブル作成（FeedIdPublishedAtIndex GSI付き）
+# 4. Subscriptions テーブル作成（複合キー、FeedIdIndex GSI付き）
+echo "📝 Subscriptions${TABLE_SUFFIX} テーブル作成中..."
+aws dynamodb create-table \
+    --table-name "Subscriptions${TABLE_SUFFIX}" \
+    --attribute-definitions \
+        AttributeName=bower_id,AttributeType=S \
+        AttributeName=feed_id,AttributeType=S \
+    --key-schema \
+        AttributeName=bower_id,KeyType=HASH \
+        AttributeName=feed_id,KeyType=RANGE \
+    --global-secondary-indexes \
+        IndexName=FeedIdIndex,KeySchema='[{AttributeName=feed_id,KeyType=HASH}]',Projection='{ProjectionType=ALL}',ProvisionedThroughput='{ReadCapacityUnits=5,WriteCapacityUnits=5}' \
+    --provisioned-throughput \
+        ReadCapacityUnits=5,WriteCapacityUnits=5 \
+    --endpoint-url $ENDPOINT \
+    --region $REGION >/dev/null
+echo "✅ Subscriptions${TABLE_SUFFIX} テーブルを作成しました"
+
+# 5. Articles テーブル作成（FeedIdPublishedAtIndex, CanonicalUrlIndex, TitleHashIndex GSI付き）
 echo "📝 Articles${TABLE_SUFFIX} テーブル作成中..."
 aws dynamodb create-table \
     --table-name "Articles${TABLE_SUFFIX}" \
@@ -140,17 +160,21 @@ aws dynamodb create-table \
         AttributeName=article_id,AttributeType=S \
         AttributeName=feed_id,AttributeType=S \
         AttributeName=published_at,AttributeType=N \
+        AttributeName=canonical_url,AttributeType=S \
+        AttributeName=title_hash,AttributeType=S \
     --key-schema \
         AttributeName=article_id,KeyType=HASH \
     --global-secondary-indexes \
         IndexName=FeedIdPublishedAtIndex,KeySchema='[{AttributeName=feed_id,KeyType=HASH},{AttributeName=published_at,KeyType=RANGE}]',Projection='{ProjectionType=ALL}',ProvisionedThroughput='{ReadCapacityUnits=5,WriteCapacityUnits=5}' \
+        IndexName=CanonicalUrlIndex,KeySchema='[{AttributeName=canonical_url,KeyType=HASH}]',Projection='{ProjectionType=INCLUDE,NonKeyAttributes=[feed_id,source_feed_ids,published_at,content_hash]}',ProvisionedThroughput='{ReadCapacityUnits=5,WriteCapacityUnits=5}' \
+        IndexName=TitleHashIndex,KeySchema='[{AttributeName=title_hash,KeyType=HASH}]',Projection='{ProjectionType=INCLUDE,NonKeyAttributes=[feed_id,source_feed_ids,published_at,content_hash]}',ProvisionedThroughput='{ReadCapacityUnits=5,WriteCapacityUnits=5}' \
     --provisioned-throughput \
         ReadCapacityUnits=5,WriteCapacityUnits=5 \
     --endpoint-url $ENDPOINT \
     --region $REGION >/dev/null
 echo "✅ Articles${TABLE_SUFFIX} テーブルを作成しました"
 
-# 5. LikedArticles テーブル作成（複合キー）
+# 6. LikedArticles テーブル作成（複合キー）
 echo "📝 LikedArticles${TABLE_SUFFIX} テーブル作成中..."
 aws dynamodb create-table \
     --table-name "LikedArticles${TABLE_SUFFIX}" \
@@ -160,13 +184,51 @@ aws dynamodb create-table \
     --key-schema \
         AttributeName=user_id,KeyType=HASH \
         AttributeName=article_id,KeyType=RANGE \
+    --global-secondary-indexes \
+        IndexName=ArticleIdIndex,KeySchema='[{AttributeName=article_id,KeyType=HASH}]',Projection='{ProjectionType=KEYS_ONLY}',ProvisionedThroughput='{ReadCapacityUnits=5,WriteCapacityUnits=5}' \
     --provisioned-throughput \
         ReadCapacityUnits=5,WriteCapacityUnits=5 \
     --endpoint-url $ENDPOINT \
     --region $REGION >/dev/null
 echo "✅ LikedArticles${TABLE_SUFFIX} テーブルを作成しました"
 
-# 6. ChickStats テーブル作成（シンプルなハッシュキー）
+# 7. ReadStates テーブル作成（複合キー）
+echo "📝 ReadStates${TABLE_SUFFIX} テーブル作成中..."
+aws dynamodb create-table \
+    --table-name "ReadStates${TABLE_SUFFIX}" \
+    --attribute-definitions \
+        AttributeName=user_id,AttributeType=S \
+        AttributeName=article_id,AttributeType=S \
+    --key-schema \
+        AttributeName=user_id,KeyType=HASH \
+        AttributeName=article_id,KeyType=RANGE \
+    --global-secondary-indexes \
+        IndexName=ArticleIdIndex,KeySchema='[{AttributeName=article_id,KeyType=HASH}]',Projection='{ProjectionType=KEYS_ONLY}',ProvisionedThroughput='{ReadCapacityUnits=5,WriteCapacityUnits=5}' \
+    --provisioned-throughput \
+        ReadCapacityUnits=5,WriteCapacityUnits=5 \
+    --endpoint-url $ENDPOINT \
+    --region $REGION >/dev/null
+echo "✅ ReadStates${TABLE_SUFFIX} テーブルを作成しました"
+
+# 8. PlaybackPositions テーブル作成（複合キー）
+echo "📝 PlaybackPositions${TABLE_SUFFIX} テーブル作成中..."
+aws dynamodb create-table \
+    --table-name "PlaybackPositions${TABLE_SUFFIX}" \
+    --attribute-definitions \
+        AttributeName=user_id,AttributeType=S \
+        AttributeName=article_id,AttributeType=S \
+    --key-schema \
+        AttributeName=user_id,KeyType=HASH \
+        AttributeName=article_id,KeyType=RANGE \
+    --global-secondary-indexes \
+        IndexName=ArticleIdIndex,KeySchema='[{AttributeName=article_id,KeyType=HASH}]',Projection='{ProjectionType=KEYS_ONLY}',ProvisionedThroughput='{ReadCapacityUnits=5,WriteCapacityUnits=5}' \
+    --provisioned-throughput \
+        ReadCapacityUnits=5,WriteCapacityUnits=5 \
+    --endpoint-url $ENDPOINT \
+    --region $REGION >/dev/null
+echo "✅ PlaybackPositions${TABLE_SUFFIX} テーブルを作成しました"
+
+# 9. ChickStats テーブル作成（シンプルなハッシュキー）
 echo "📝 ChickStats${TABLE_SUFFIX} テーブル作成中..."
 aws dynamodb create-table \
     --table-name "ChickStats${TABLE_SUFFIX}" \
@@ -180,6 +242,22 @@ aws dynamodb create-table \
     --region $REGION >/dev/null
 echo "✅ ChickStats${TABLE_SUFFIX} テーブルを作成しました"
 
+# 10. SearchIndex テーブル作成（記事の全文検索インデックス、複合キー）
+echo "📝 SearchIndex${TABLE_SUFFIX} テーブル作成中..."
+aws dynamodb create-table \
+    --table-name "SearchIndex${TABLE_SUFFIX}" \
+    --attribute-definitions \
+        AttributeName=term_key,AttributeType=S \
+        AttributeName=article_id,AttributeType=S \
+    --key-schema \
+        AttributeName=term_key,KeyType=HASH \
+        AttributeName=article_id,KeyType=RANGE \
+    --provisioned-throughput \
+        ReadCapacityUnits=5,WriteCapacityUnits=5 \
+    --endpoint-url $ENDPOINT \
+    --region $REGION >/dev/null
+echo "✅ SearchIndex${TABLE_SUFFIX} テーブルを作成しました"
+
 echo ""
 echo "⏳ テーブル作成の完了を待機中..."
 sleep 3
@@ -55,6 +55,8 @@ echo ""
 echo "4️⃣ Articles${TABLE_SUFFIX} テーブル:"
 echo "   - Primary Key: article_id (HASH)"
 echo "   - GSI: FeedIdPublishedAtIndex (feed_id + published_at)"
+echo "   - GSI: CanonicalUrlIndex (canonical_url)"
+echo "   - GSI: TitleHashIndex (title_hash)"
 aws dynamodb describe-table --table-name "Articles${TABLE_SUFFIX}" --endpoint-url $ENDPOINT --region $REGION \
     --query 'Table.{KeySchema:KeySchema,GSI:GlobalSecondaryIndexes[0].{IndexName:IndexName,KeySchema:KeySchema}}' \
     --output table